@@ -15,6 +15,7 @@
 package v1beta2
 
 import (
+	configv1 "github.com/openshift/api/config/v1"
 	authzv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
@@ -32,6 +33,25 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=2
 	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+	// Label selector for namespaces whose workloads Cryostat should be
+	// permitted to access and profile, in addition to any namespaces listed in
+	// targetNamespaces. Namespaces are added and removed as their labels change.
+	// Warning: All Cryostat users will be able to create and manage
+	// recordings for workloads in the selected namespaces.
+	// Using a selector requires permission to create Cryostat instances in all namespaces.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Target Namespace Selector"
+	TargetNamespaceSelector *metav1.LabelSelector `json:"targetNamespaceSelector,omitempty"`
+	// Permit Cryostat to access and profile workloads in all namespaces of the cluster.
+	// When enabled, access is granted by a single ClusterRoleBinding, and Cryostat
+	// discovers workloads in every namespace. Only namespaces containing pods labelled
+	// for agent injection by this Cryostat are added to the target namespaces.
+	// Warning: All Cryostat users will be able to create and manage
+	// recordings for workloads in every namespace.
+	// Enabling this requires permission to create Cryostat instances in all namespaces.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="All Namespaces",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	AllNamespaces bool `json:"allNamespaces,omitempty"`
 	// List of TLS certificates to trust when connecting to targets.
 	// Each entry may reference either a Secret or a ConfigMap in the local namespace.
 	// +optional
@@ -58,6 +78,10 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=3,displayName="Enable cert-manager Integration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	EnableCertManager *bool `json:"enableCertManager"`
+	// Options to configure TLS for communication between Cryostat components and agents.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TLS Options"
+	TLS *TLSOptions `json:"tls,omitempty"`
 	// Options to customize the storage provisioned for the database and object storage.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
@@ -119,6 +143,11 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Object Storage Options"
 	ObjectStorageOptions *ObjectStorageOptions `json:"objectStorageOptions,omitempty"`
+	// Periodic rotation of the credentials generated by the operator for the database, object storage
+	// and auth proxy. Credentials in secrets provided by the user are not rotated.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credential Rotation"
+	CredentialRotation *CredentialRotationOptions `json:"credentialRotation,omitempty"`
 	// Options to configure the Cryostat deployments and pods metadata
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operand metadata"
@@ -132,6 +161,80 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Logging Options"
 	LoggingOptions *LoggingOptions `json:"loggingOptions,omitempty"`
+	// Overrides for the pod templates generated by the operator for each Cryostat component.
+	// Each override is applied as a strategic merge patch, where containers and volumes are merged by name.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Template Overrides"
+	PodTemplateOverrides *PodTemplateOverridesList `json:"podTemplateOverrides,omitempty"`
+	// Options to override the container images used by each Cryostat component.
+	// By default, the images configured for the operator are used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Images"
+	Images *ImageConfigList `json:"images,omitempty"`
+	// Secrets used to pull container images for Cryostat components, and for the Cryostat agent
+	// init container injected into workloads. The secrets must exist in the namespace of each pod.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image Pull Secrets"
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+}
+
+// ImageConfigList contains image overrides for each Cryostat component.
+type ImageConfigList struct {
+	// Image for the Cryostat application container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Core *ImageConfig `json:"core,omitempty"`
+	// Image for the JFR Data Source container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	DataSource *ImageConfig `json:"dataSource,omitempty"`
+	// Image for the Grafana container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Grafana *ImageConfig `json:"grafana,omitempty"`
+	// Image for the auth proxy container. Applies to both the OAuth2 Proxy used on Kubernetes,
+	// and the OpenShift OAuth Proxy used on OpenShift.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AuthProxy *ImageConfig `json:"authProxy,omitempty"`
+	// Image for the agent proxy container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AgentProxy *ImageConfig `json:"agentProxy,omitempty"`
+	// Image for the report generator container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Reports *ImageConfig `json:"reports,omitempty"`
+	// Image for the database container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Database *ImageConfig `json:"database,omitempty"`
+	// Image for the object storage container.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Storage *ImageConfig `json:"storage,omitempty"`
+	// Image for the init container that copies the Cryostat agent into workloads.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AgentInit *ImageConfig `json:"agentInit,omitempty"`
+}
+
+// ImageConfig overrides the container image for a Cryostat component.
+type ImageConfig struct {
+	// Image reference to use for the component, such as "registry.example.com/cryostat/cryostat:4.1.0".
+	// Defaults to the image configured for the operator.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Digest used to pin the image, such as "sha256:<hex>". When specified, any tag or digest
+	// in the image reference is replaced by this digest.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest,omitempty"`
+	// Pull policy for the image. Defaults to Always for development tags and
+	// IfNotPresent otherwise.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	PullPolicy *corev1.PullPolicy `json:"pullPolicy,omitempty"`
 }
 
 type OperandMetadata struct {
@@ -160,6 +263,85 @@ type ResourceMetadata struct {
 	Labels map[string]string `json:"labels,omitempty"`
 }
 
+// PodTemplateOverridesList contains the pod template overrides for each Cryostat component.
+// Overrides for the auth proxy, agent proxy, Grafana and JFR Data Source containers
+// are applied to the Cryostat pod, which also runs these containers.
+type PodTemplateOverridesList struct {
+	// Overrides for the Cryostat pod, where the main container is named after the Cryostat CR.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Core *PodTemplateOverrides `json:"core,omitempty"`
+	// Overrides for the report generator pod, where the main container is named "<CR name>-reports".
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Reports *PodTemplateOverrides `json:"reports,omitempty"`
+	// Overrides for the database pod, where the main container is named "<CR name>-db".
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Database *PodTemplateOverrides `json:"database,omitempty"`
+	// Overrides for the object storage pod, where the main container is named "<CR name>-storage".
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Storage *PodTemplateOverrides `json:"storage,omitempty"`
+	// Overrides for the Grafana container in the Cryostat pod, named "<CR name>-grafana".
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Grafana *PodTemplateOverrides `json:"grafana,omitempty"`
+	// Overrides for the JFR Data Source container in the Cryostat pod, named "<CR name>-jfr-datasource".
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	DataSource *PodTemplateOverrides `json:"dataSource,omitempty"`
+	// Overrides for the auth proxy container in the Cryostat pod, named "<CR name>-auth-proxy".
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AuthProxy *PodTemplateOverrides `json:"authProxy,omitempty"`
+	// Overrides for the agent proxy container in the Cryostat pod, named "<CR name>-agent-proxy".
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AgentProxy *PodTemplateOverrides `json:"agentProxy,omitempty"`
+}
+
+// PodTemplateOverrides is a strategic merge patch applied to a pod template
+// generated by the operator. Containers whose names match those created by the
+// operator are merged with them, while other containers are added as sidecars.
+// Containers created by the operator may not be removed, renamed or have their
+// ports changed.
+type PodTemplateOverrides struct {
+	// Containers to merge into, or add to, the pod.
+	// +optional
+	Containers []corev1.Container `json:"containers,omitempty"`
+	// Init containers to add to the pod.
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// Volumes to merge into, or add to, the pod.
+	// +optional
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+}
+
 type ResourceConfigList struct {
 	// Resource requirements for the auth proxy.
 	// +optional
@@ -198,6 +380,16 @@ type CryostatStatus struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=status,order=3
 	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+	// Readiness of the resources managed by the operator in each target namespace.
+	// +optional
+	// +listType=map
+	// +listMapKey=namespace
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Target Namespace Statuses"
+	TargetNamespaceStatuses []TargetNamespaceStatus `json:"targetNamespaceStatuses,omitempty"`
+	// The most recent generation of the Cryostat spec that has been reconciled.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed Generation"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Conditions of the components managed by the Cryostat Operator.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Cryostat Conditions",xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
@@ -213,6 +405,115 @@ type CryostatStatus struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=status,order=2,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
 	DatabaseSecret string `json:"databaseSecret,omitempty"`
+	// Container images currently used by each Cryostat component.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Images"
+	Images *ImageStatus `json:"images,omitempty"`
+	// PostgreSQL major version of the data stored by the managed database. When the database image
+	// moves to a new major version, the operator upgrades the data before starting the new image.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Database Version"
+	DatabaseVersion string `json:"databaseVersion,omitempty"`
+	// Time the generated credentials were last rotated, or when credential rotation was enabled if they
+	// have not been rotated since.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Last Rotation Time"
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+	// Certificates used by Cryostat components and agents, with the times they expire.
+	// +optional
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Certificates"
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+	// Progress of a rotation of the Cryostat certificate authority, while the previous CA is still trusted.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="CA Rotation"
+	CARotation *CARotationStatus `json:"caRotation,omitempty"`
+}
+
+// CARotationStatus describes a rotation of the Cryostat certificate authority.
+type CARotationStatus struct {
+	// Time when the operator found that the CA had been re-issued.
+	StartTime metav1.Time `json:"startTime"`
+	// Time when the previous CA is no longer trusted.
+	OverlapEndTime metav1.Time `json:"overlapEndTime"`
+	// Workloads in target namespaces whose Cryostat agents were started before the rotation,
+	// and still use the previous CA. These workloads should be restarted before the overlap ends.
+	// +optional
+	// +listType=atomic
+	PendingWorkloads []CARotationWorkload `json:"pendingWorkloads,omitempty"`
+}
+
+// CARotationWorkload refers to a workload with Cryostat agents.
+type CARotationWorkload struct {
+	// Namespace of the workload.
+	Namespace string `json:"namespace"`
+	// Kind of the workload, such as Deployment.
+	Kind string `json:"kind"`
+	// Name of the workload.
+	Name string `json:"name"`
+	// Number of pods of the workload still using the previous CA.
+	Pods int32 `json:"pods"`
+}
+
+// CertificateStatus describes a certificate used by Cryostat components or agents.
+type CertificateStatus struct {
+	// Name of the Secret containing the certificate.
+	Name string `json:"name"`
+	// Namespace of the Secret containing the certificate.
+	Namespace string `json:"namespace"`
+	// Time when the certificate expires.
+	NotAfter metav1.Time `json:"notAfter"`
+	// Distinguished name of the certificate's issuer.
+	Issuer string `json:"issuer"`
+}
+
+// ImageStatus contains the resolved image references used by each Cryostat component.
+type ImageStatus struct {
+	// Image used by the Cryostat application container.
+	// +optional
+	Core string `json:"core,omitempty"`
+	// Image used by the JFR Data Source container.
+	// +optional
+	DataSource string `json:"dataSource,omitempty"`
+	// Image used by the Grafana container.
+	// +optional
+	Grafana string `json:"grafana,omitempty"`
+	// Image used by the auth proxy container.
+	// +optional
+	AuthProxy string `json:"authProxy,omitempty"`
+	// Image used by the agent proxy container.
+	// +optional
+	AgentProxy string `json:"agentProxy,omitempty"`
+	// Image used by the report generator container.
+	// +optional
+	Reports string `json:"reports,omitempty"`
+	// Image used by the database container.
+	// +optional
+	Database string `json:"database,omitempty"`
+	// Image used by the object storage container.
+	// +optional
+	Storage string `json:"storage,omitempty"`
+	// Image used by the init container that copies the Cryostat agent into workloads.
+	// +optional
+	AgentInit string `json:"agentInit,omitempty"`
+}
+
+// TargetNamespaceStatus describes the resources managed by the operator in a target namespace.
+type TargetNamespaceStatus struct {
+	// Name of the target namespace.
+	Namespace string `json:"namespace"`
+	// Whether Cryostat has been granted access to the namespace.
+	RBACReady bool `json:"rbacReady"`
+	// Whether the TLS certificate for Cryostat agents in the namespace is ready.
+	// Always true when cert-manager integration is disabled.
+	AgentTLSReady bool `json:"agentTLSReady"`
+	// Whether the Service used by Cryostat to connect to agents in the namespace is ready.
+	CallbackServiceReady bool `json:"callbackServiceReady"`
+	// Number of pods in the namespace configured to use the Cryostat agent.
+	InjectedAgentPods int32 `json:"injectedAgentPods"`
+	// The most recent error encountered while reconciling the namespace, if any.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
 }
 
 // CryostatConditionType refers to a Condition type that may be used in status.conditions
@@ -231,6 +532,14 @@ const (
 	ConditionTypeDatabaseDeploymentProgressing CryostatConditionType = "DatabaseDeploymentProgressing"
 	// If enabled, whether pods in the database deployment failed to be created or destroyed.
 	ConditionTypeDatabaseDeploymentReplicaFailure CryostatConditionType = "DatabaseDeploymentReplicaFailure"
+	// Whether the external database configured in .spec.databaseOptions.external can be reached.
+	ConditionTypeDatabaseReachable CryostatConditionType = "DatabaseReachable"
+	// Present when the managed database has been upgraded to a new PostgreSQL major version. True while
+	// the upgrade is in progress, and false once it has finished.
+	ConditionTypeDatabaseUpgrading CryostatConditionType = "DatabaseUpgrading"
+	// Present if an upgrade of the managed database failed. True while the database is still running the
+	// previous version after the failure.
+	ConditionTypeDatabaseUpgradeFailed CryostatConditionType = "DatabaseUpgradeFailed"
 	// If enabled, whether the storage deployment is available.
 	ConditionTypeStorageDeploymentAvailable CryostatConditionType = "StorageDeploymentAvailable"
 	// If enabled, whether the storage deployment is progressing.
@@ -245,6 +554,37 @@ const (
 	ConditionTypeReportsDeploymentReplicaFailure CryostatConditionType = "ReportsDeploymentReplicaFailure"
 	// If enabled, whether TLS setup is complete for the Cryostat components.
 	ConditionTypeTLSSetupComplete CryostatConditionType = "TLSSetupComplete"
+	// If enabled, whether any certificate used by the Cryostat components or agents has expired
+	// or expires within the warning window configured in .spec.tls.expiryWarningWindow.
+	ConditionTypeCertificatesExpiring CryostatConditionType = "CertificatesExpiring"
+	// Whether all Cryostat components are ready, aggregated from the other conditions.
+	ConditionTypeReady CryostatConditionType = "Ready"
+	// Present and true while the operator is applying the latest spec or components are rolling out.
+	ConditionTypeReconciling CryostatConditionType = "Reconciling"
+	// Present and true if a component has failed and is not expected to recover without intervention.
+	ConditionTypeStalled CryostatConditionType = "Stalled"
+	// Present and true if the operator failed to reconcile the Secrets used by Cryostat.
+	ConditionTypeSecretsReconcileFailed CryostatConditionType = "SecretsReconcileFailed"
+	// Present and true if the operator failed to reconcile the RBAC resources for Cryostat.
+	ConditionTypeRBACReconcileFailed CryostatConditionType = "RBACReconcileFailed"
+	// Present and true if the operator failed to reconcile TLS for the Cryostat components.
+	ConditionTypeTLSReconcileFailed CryostatConditionType = "TLSReconcileFailed"
+	// Present and true if the operator failed to reconcile the database.
+	ConditionTypeDatabaseReconcileFailed CryostatConditionType = "DatabaseReconcileFailed"
+	// Present and true if the operator failed to reconcile the object storage.
+	ConditionTypeStorageReconcileFailed CryostatConditionType = "StorageReconcileFailed"
+	// Present and true if the operator failed to rotate credentials or switch to another database secret.
+	ConditionTypeCredentialsReconcileFailed CryostatConditionType = "CredentialsReconcileFailed"
+	// Present and true if the operator failed to reconcile scheduled backups of the database.
+	ConditionTypeDatabaseBackupReconcileFailed CryostatConditionType = "DatabaseBackupReconcileFailed"
+	// Present and true if the operator failed to reconcile the reports generator.
+	ConditionTypeReportsReconcileFailed CryostatConditionType = "ReportsReconcileFailed"
+	// Present and true if the operator failed to reconcile the main Cryostat deployment and its services.
+	ConditionTypeCoreReconcileFailed CryostatConditionType = "CoreReconcileFailed"
+	// Present and true if the operator failed to reconcile OpenShift-specific resources.
+	ConditionTypeOpenShiftReconcileFailed CryostatConditionType = "OpenShiftReconcileFailed"
+	// Present and true while reconciliation is paused by the operator.cryostat.io/reconcile-paused annotation.
+	ConditionTypeReconcilePaused CryostatConditionType = "ReconcilePaused"
 )
 
 // StorageConfigurations provides customization to the storage provisioned for
@@ -578,7 +918,7 @@ type TargetConnectionCacheOptions struct {
 // application and its related components.
 // A Cryostat instance must be created to instruct the operator
 // to deploy the Cryostat application.
-// +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1},{Ingress,v1},{PersistentVolumeClaim,v1},{Secret,v1},{Service,v1},{Route,v1},{ConsoleLink,v1}}
+// +operator-sdk:csv:customresourcedefinitions:resources={{CronJob,v1},{Deployment,v1},{Ingress,v1},{PersistentVolumeClaim,v1},{Secret,v1},{Service,v1},{Route,v1},{ConsoleLink,v1}}
 // +kubebuilder:printcolumn:name="Application URL",type=string,JSONPath=`.status.applicationUrl`
 // +kubebuilder:printcolumn:name="Target Namespaces",type=string,JSONPath=`.status.targetNamespaces`
 // +kubebuilder:printcolumn:name="Storage Secret",type=string,JSONPath=`.status.storageSecret`
@@ -692,6 +1032,83 @@ type AuthorizationOptions struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
 	BasicAuth *SecretFile `json:"basicAuth,omitempty"`
+	// Configuration for an OpenID Connect provider, such as Keycloak, whose users may access the Cryostat application.
+	// Not supported on OpenShift, where users are authenticated by OpenShift SSO instead. Requires an ingress with a host
+	// for Cryostat, which the provider redirects users to after signing in. If basic authentication is also configured,
+	// users may sign in using either method.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OpenID Connect"
+	OIDC *OIDCConfig `json:"oidc,omitempty"`
+	// Roles restricting the requests that users may make to the Cryostat application, according to the groups
+	// they belong to. When roles are specified, users may only make requests allowed by a role of one of their
+	// groups, and other requests are denied. Requests for Grafana dashboards are authorized by their path under
+	// /grafana/. Requires an OpenID Connect provider, whose ID tokens provide the groups of users. Not supported
+	// on OpenShift, where the groups of users are not available.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Roles []AccessRole `json:"roles,omitempty"`
+}
+
+// AccessRole allows members of groups to make certain requests to the Cryostat application.
+type AccessRole struct {
+	// Name of the role.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+	// Groups whose members are granted this role, such as groups from the groups claim of an OpenID Connect ID token.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Pattern=`^[^,"'\\]+$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Groups []string `json:"groups"`
+	// HTTP methods that the role allows, such as GET. If not specified, all methods are allowed.
+	// +optional
+	// +kubebuilder:validation:items:Enum=GET;HEAD;POST;PUT;PATCH;DELETE;OPTIONS
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+	// Path prefixes that the role allows requests to, such as /api/v4/recordings. A prefix matches its own path
+	// and any path below it. If not specified, requests to all paths are allowed.
+	// +optional
+	// +kubebuilder:validation:items:Pattern=`^/[^\s"'\\]*$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AllowedPathPrefixes []string `json:"allowedPathPrefixes,omitempty"`
+}
+
+// OIDCConfig configures the OpenID Connect provider used by the auth proxy to authenticate users.
+type OIDCConfig struct {
+	// URL of the OpenID Connect issuer, used to discover the provider's endpoints.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Issuer URL",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	IssuerURL string `json:"issuerURL"`
+	// ID of the client registered with the provider for the Cryostat application.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Client ID",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ClientID string `json:"clientID"`
+	// Key within a secret containing the client secret registered with the provider.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ClientSecret corev1.SecretKeySelector `json:"clientSecret"`
+	// Scopes to request from the provider, which must include "openid". Defaults to "openid", "email" and "profile".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Scopes []string `json:"scopes,omitempty"`
+	// Groups whose members may access the Cryostat application, as listed in the groups claim of the ID token.
+	// If not specified, users are not restricted by group.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AllowedGroups []string `json:"allowedGroups,omitempty"`
+	// Email addresses of users who may access the Cryostat application.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AllowedEmails []string `json:"allowedEmails,omitempty"`
+	// Email domains, such as "example.com", of users who may access the Cryostat application.
+	// Use "*" to allow any email domain. At least one of allowedGroups, allowedEmails or allowedEmailDomains must be specified.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AllowedEmailDomains []string `json:"allowedEmailDomains,omitempty"`
+	// Secret or config map containing the CA certificate used to verify the issuer's certificate,
+	// in addition to the system's trusted CAs.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CA Certificate"
+	CACertificate *CertificateSecret `json:"caCertificate,omitempty"`
 }
 
 type OpenShiftSSOConfig struct {
@@ -813,12 +1230,289 @@ type TargetDiscoveryOptions struct {
 type DatabaseOptions struct {
 	// Name of the secret containing database keys. This secret must contain a CONNECTION_KEY secret which is the
 	// database connection password, and an ENCRYPTION_KEY secret which is the key used to encrypt sensitive data
-	// stored within the database, such as the target credentials keyring. This field cannot be updated.
-	// It is recommended that the secret should be marked as immutable to avoid accidental changes to secret's data.
+	// stored within the database, such as the target credentials keyring. This field may only be changed to
+	// a secret with the same ENCRYPTION_KEY, in which case the operator changes the password of the managed
+	// database to the new CONNECTION_KEY. It is recommended that the secret should be marked as immutable to avoid accidental changes to secret's data.
 	// More details: https://kubernetes.io/docs/concepts/configuration/secret/#secret-immutable
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
 	SecretName *string `json:"secretName,omitempty"`
+	// Connection to an external PostgreSQL database. When configured, the operator will not deploy
+	// its own database, and Cryostat will connect to this database instead. The secret in
+	// .spec.databaseOptions.secretName is still used for the ENCRYPTION_KEY.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="External Database Options"
+	External *ExternalDatabaseOptions `json:"external,omitempty"`
+	// Scheduled backups of the managed database to object storage. Backups are not taken
+	// when using an external database.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Backup Options"
+	Backup *DatabaseBackupOptions `json:"backup,omitempty"`
+	// PostgreSQL major version of the managed database image, such as "16". Required when overriding
+	// the database image with .spec.images.database, so that the operator can detect when the database
+	// must be upgraded. Defaults to the version of the database image configured for the operator.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Major Version"
+	MajorVersion *string `json:"majorVersion,omitempty"`
+}
+
+// CredentialRotationOptions configures periodic rotation of the credentials generated by the operator.
+// New credentials are applied to the managed database and object storage, which are restarted before Cryostat.
+type CredentialRotationOptions struct {
+	// Time between rotations, as a duration such as "2160h" for 90 days. Must be at least one hour.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ms|s|m|h))+$`
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1h')",message="interval must be at least 1h"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Interval metav1.Duration `json:"interval"`
+}
+
+// TLSOptions configures TLS for communication between Cryostat components and agents.
+type TLSOptions struct {
+	// Provider of the certificates for Cryostat components and agents. "CertManager" uses cert-manager,
+	// and "BuiltIn" uses a certificate authority managed by the operator, for clusters where cert-manager
+	// is not installed. "ServiceCA" uses the OpenShift service CA for the certificates of Cryostat's services,
+	// and a certificate authority managed by the operator for agent certificates. "ServiceCA" is only
+	// available on OpenShift. When not set, cert-manager is used unless .spec.enableCertManager is false.
+	// When set, .spec.enableCertManager is ignored.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Provider"
+	Provider *TLSProvider `json:"provider,omitempty"`
+	// Reference to an existing cert-manager Issuer or ClusterIssuer, which issues the certificates for
+	// Cryostat components and agents instead of a CA generated by the operator. An Issuer must be in the
+	// namespace of this Cryostat instance. The CA included in ca.crt of issued certificates is trusted by
+	// Cryostat components and agents. Only used when certificates are issued by cert-manager.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Issuer Reference"
+	IssuerRef *IssuerReference `json:"issuerRef,omitempty"`
+	// Options for the private keys of certificates issued for Cryostat components and agents.
+	// The key algorithm also determines the key type that Cryostat agents are configured to use,
+	// including with provided certificates.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Private Key"
+	PrivateKey *TLSPrivateKey `json:"privateKey,omitempty"`
+	// Durations and renewal times of the certificates issued for Cryostat components and agents.
+	// Not used with provided certificates.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Certificate Lifetimes"
+	Lifetimes *CertificateLifetimes `json:"lifetimes,omitempty"`
+	// How long before a certificate expires that the operator starts emitting Warning events
+	// about it, such as "336h" for 14 days. Defaults to 7 days.
+	// +optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ms|s|m|h))+$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expiry Warning Window"
+	ExpiryWarningWindow *metav1.Duration `json:"expiryWarningWindow,omitempty"`
+	// How long the previous certificate authority remains trusted after the Cryostat CA is re-issued,
+	// such as "72h" for 3 days. During this period, Cryostat agents started before the rotation can
+	// continue to authenticate with certificates from the previous CA. Defaults to 7 days.
+	// +optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ms|s|m|h))+$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CA Rotation Overlap"
+	CARotationOverlap *metav1.Duration `json:"caRotationOverlap,omitempty"`
+	// TLS security profile for servers managed by the operator, such as the agent proxy, authorization proxy
+	// and report generators. On OpenShift, the cluster-wide profile from the APIServer configuration is used
+	// when not set. Otherwise, the Intermediate profile is used. When the cluster is in FIPS mode, ciphers
+	// that are not FIPS-approved are removed, and the minimum TLS version is at least 1.2.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Security Profile"
+	SecurityProfile *configv1.TLSSecurityProfile `json:"securityProfile,omitempty"`
+	// TLS certificates provided by the user, to use instead of certificates issued by cert-manager.
+	// When configured, .spec.tls.provider and .spec.enableCertManager are ignored.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Provided Certificates"
+	Certificates *TLSCertificates `json:"certificates,omitempty"`
+}
+
+// TLSProvider is a source of certificates for Cryostat components and agents.
+// +kubebuilder:validation:Enum=CertManager;BuiltIn;ServiceCA
+type TLSProvider string
+
+const (
+	// Certificates are issued by cert-manager
+	TLSProviderCertManager TLSProvider = "CertManager"
+	// Certificates are issued by a certificate authority managed by the operator
+	TLSProviderBuiltIn TLSProvider = "BuiltIn"
+	// Certificates for services are issued by the OpenShift service CA, and those for agents
+	// by a certificate authority managed by the operator
+	TLSProviderServiceCA TLSProvider = "ServiceCA"
+)
+
+// IssuerReference refers to a cert-manager Issuer or ClusterIssuer, or an issuer of an external type.
+type IssuerReference struct {
+	// Name of the issuer.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+	// Kind of the issuer, such as Issuer or ClusterIssuer. Defaults to Issuer.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Kind string `json:"kind,omitempty"`
+	// API group of the issuer. Defaults to cert-manager.io. Set this to use an issuer of an external type,
+	// such as one provided by a cert-manager issuer plugin.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Group string `json:"group,omitempty"`
+}
+
+// TLSPrivateKey configures the private keys of issued certificates.
+// +kubebuilder:validation:XValidation:rule="!has(self.size) || (has(self.algorithm) && self.algorithm == 'ECDSA' ? self.size in [256, 384, 521] : self.size in [2048, 3072, 4096])",message="size must be 2048, 3072 or 4096 for RSA, and 256, 384 or 521 for ECDSA"
+type TLSPrivateKey struct {
+	// Algorithm of the private keys, either RSA or ECDSA. Defaults to RSA.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Algorithm TLSPrivateKeyAlgorithm `json:"algorithm,omitempty"`
+	// Size of the private keys in bits. Must be 2048, 3072 or 4096 for RSA, where the default is 2048,
+	// and 256, 384 or 521 for ECDSA, where the default is 256.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Size *int32 `json:"size,omitempty"`
+	// Whether a new private key is generated each time a certificate is renewed, either Always or Never.
+	// Defaults to the behaviour of the certificate provider.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;Never
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	RotationPolicy string `json:"rotationPolicy,omitempty"`
+}
+
+// TLSPrivateKeyAlgorithm is an algorithm for the private keys of issued certificates.
+// +kubebuilder:validation:Enum=RSA;ECDSA
+type TLSPrivateKeyAlgorithm string
+
+const (
+	TLSPrivateKeyAlgorithmRSA   TLSPrivateKeyAlgorithm = "RSA"
+	TLSPrivateKeyAlgorithmECDSA TLSPrivateKeyAlgorithm = "ECDSA"
+)
+
+// CertificateLifetimes configures the lifetimes of each class of issued certificate.
+type CertificateLifetimes struct {
+	// Lifetime of the certificate authority generated by the operator.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	CA *CertificateLifetime `json:"ca,omitempty"`
+	// Lifetime of the certificates for Cryostat's services.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Server *CertificateLifetime `json:"server,omitempty"`
+	// Lifetime of the certificates for Cryostat agents in each target namespace.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Agent *CertificateLifetime `json:"agent,omitempty"`
+}
+
+// CertificateLifetime configures how long a certificate is valid for, and when it is renewed.
+// +kubebuilder:validation:XValidation:rule="!has(self.duration) || !has(self.renewBefore) || duration(self.renewBefore) < duration(self.duration)",message="renewBefore must be less than duration"
+type CertificateLifetime struct {
+	// Duration that the certificate is valid for, such as "720h" for 30 days. Must be at least one hour.
+	// Defaults to 90 days.
+	// +optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ms|s|m|h))+$`
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1h')",message="duration must be at least 1h"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// How long before the certificate expires that it is renewed, such as "240h" for 10 days.
+	// Defaults to one third of the certificate's duration.
+	// +optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ms|s|m|h))+$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// TLSCertificates references user-provided Secrets of type kubernetes.io/tls in the installation namespace.
+// Each Secret must contain tls.crt, tls.key and ca.crt keys, and all certificates must be issued by the CA
+// in ca.crt of the core Secret. Each certificate must include the DNS names of the Service it is used for,
+// in the forms <service>, <service>.<namespace>.svc and <service>.<namespace>.svc.cluster.local.
+type TLSCertificates struct {
+	// Name of the Secret containing the certificate for the Cryostat service. The operator
+	// creates a PKCS12 keystore for Cryostat from this certificate.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	CoreSecretName string `json:"coreSecretName"`
+	// Name of the Secret containing the certificate for the <name>-database service.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	DatabaseSecretName string `json:"databaseSecretName"`
+	// Name of the Secret containing the certificate for the <name>-storage service.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	StorageSecretName string `json:"storageSecretName"`
+	// Name of the Secret containing the certificate for the <name>-reports service.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	ReportsSecretName string `json:"reportsSecretName"`
+	// Name of the Secret containing the certificate for the <name>-agent service, used by the agent proxy.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	AgentProxySecretName string `json:"agentProxySecretName"`
+	// Name of the Secret containing the certificate for Cryostat agents, which is copied to each
+	// target namespace. The certificate must include the DNS name *.<service>.<namespace>.svc for the
+	// agent callback service in each target namespace.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	AgentSecretName string `json:"agentSecretName"`
+}
+
+// DatabaseBackupOptions configures scheduled backups of the managed database. Each backup
+// is a pg_dump archive uploaded to the object storage used by Cryostat, using the credentials
+// in the object storage secret.
+type DatabaseBackupOptions struct {
+	// Schedule for database backups, in cron format. For example, "0 2 * * *" runs a backup daily at 02:00.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Schedule string `json:"schedule"`
+	// Number of most recent backups to keep. Older backups are deleted after each successful backup. Defaults to 7.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	Retention *int32 `json:"retention,omitempty"`
+	// Name of the object storage bucket to store backups in. The bucket is created if it does not exist.
+	// Defaults to "cryostat-database-backups".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Bucket Name"
+	BucketName *string `json:"bucketName,omitempty"`
+}
+
+// ExternalDatabaseOptions describes how to connect to an external PostgreSQL database.
+// +kubebuilder:validation:XValidation:rule="!has(self.sslMode) || !(self.sslMode in ['verify-ca', 'verify-full']) || has(self.caCertificate)",message="caCertificate must be specified when sslMode is verify-ca or verify-full"
+type ExternalDatabaseOptions struct {
+	// Hostname of the external database server.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Host string `json:"host"`
+	// Port of the external database server. Defaults to 5432.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	Port *int32 `json:"port,omitempty"`
+	// Name of the database that Cryostat should use. Defaults to "cryostat".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Database Name"
+	DatabaseName *string `json:"databaseName,omitempty"`
+	// Name of the database user that Cryostat should connect as. Defaults to "cryostat".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Username *string `json:"username,omitempty"`
+	// The SSL mode used to connect to the database. See https://www.postgresql.org/docs/current/libpq-ssl.html
+	// for a description of each mode. Defaults to the PostgreSQL JDBC driver's default.
+	// +optional
+	// +kubebuilder:validation:Enum=disable;allow;prefer;require;verify-ca;verify-full
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="SSL Mode",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:disable","urn:alm:descriptor:com.tectonic.ui:select:allow","urn:alm:descriptor:com.tectonic.ui:select:prefer","urn:alm:descriptor:com.tectonic.ui:select:require","urn:alm:descriptor:com.tectonic.ui:select:verify-ca","urn:alm:descriptor:com.tectonic.ui:select:verify-full"}
+	SSLMode *string `json:"sslMode,omitempty"`
+	// Secret or config map containing the CA certificate used to verify the database server's certificate.
+	// Required when sslMode is verify-ca or verify-full.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CA Certificate"
+	CACertificate *CertificateSecret `json:"caCertificate,omitempty"`
+	// Key within a secret containing the password for the database user. If not specified, the
+	// CONNECTION_KEY from the secret in .spec.databaseOptions.secretName is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty"`
 }
 
 // ObjectStorageOptions provides configuration options to the Cryostat application's object storage.
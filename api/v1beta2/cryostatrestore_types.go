@@ -0,0 +1,89 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CryostatRestoreSpec defines the desired state of CryostatRestore.
+type CryostatRestoreSpec struct {
+	// Name of the Cryostat CR in this namespace whose database should be restored.
+	// The Cryostat CR must have database backups configured in .spec.databaseOptions.backup.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cryostatName is immutable"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cryostat Name"
+	CryostatName string `json:"cryostatName"`
+	// Name of the backup to restore, such as "20260102T030405Z.dump". Backup names are
+	// the object names within the backup bucket, without the "<namespace>/<name>/" prefix.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="backupName is immutable"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Backup Name"
+	BackupName string `json:"backupName"`
+}
+
+// CryostatRestoreStatus defines the observed state of CryostatRestore.
+type CryostatRestoreStatus struct {
+	// Conditions describing the progress of the restore.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Restore Conditions",xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// CryostatRestoreConditionType refers to a Condition type that may be used in status.conditions
+// of a CryostatRestore
+type CryostatRestoreConditionType string
+
+const (
+	// Whether the main Cryostat deployment has been stopped for the restore.
+	ConditionTypeRestoreCoreStopped CryostatRestoreConditionType = "CoreStopped"
+	// Whether the backup has been restored into the database.
+	ConditionTypeRestoreDatabaseRestored CryostatRestoreConditionType = "DatabaseRestored"
+	// Present once the restore has finished. True if the restore succeeded and the main
+	// Cryostat deployment has been restarted, false if the restore failed.
+	ConditionTypeRestoreComplete CryostatRestoreConditionType = "Complete"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=cryostatrestores,scope=Namespaced
+
+// CryostatRestore restores the database of a Cryostat CR from a backup taken according to
+// .spec.databaseOptions.backup. The main Cryostat deployment is stopped while the backup
+// is restored, and restarted afterwards.
+// +operator-sdk:csv:customresourcedefinitions:resources={{Job,v1}}
+// +kubebuilder:printcolumn:name="Cryostat",type=string,JSONPath=`.spec.cryostatName`
+// +kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.spec.backupName`
+// +kubebuilder:printcolumn:name="Complete",type=string,JSONPath=`.status.conditions[?(@.type=="Complete")].status`
+type CryostatRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CryostatRestoreSpec   `json:"spec,omitempty"`
+	Status CryostatRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CryostatRestoreList contains a list of CryostatRestore
+type CryostatRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CryostatRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CryostatRestore{}, &CryostatRestoreList{})
+}
@@ -19,6 +19,7 @@
 package v1beta2
 
 import (
+	configv1 "github.com/openshift/api/config/v1"
 	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -26,6 +27,36 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessRole) DeepCopyInto(out *AccessRole) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedMethods != nil {
+		in, out := &in.AllowedMethods, &out.AllowedMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedPathPrefixes != nil {
+		in, out := &in.AllowedPathPrefixes, &out.AllowedPathPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessRole.
+func (in *AccessRole) DeepCopy() *AccessRole {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Affinity) DeepCopyInto(out *Affinity) {
 	*out = *in
@@ -122,6 +153,18 @@ func (in *AuthorizationOptions) DeepCopyInto(out *AuthorizationOptions) {
 		*out = new(SecretFile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]AccessRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationOptions.
@@ -164,6 +207,98 @@ func (in *AutomatedRuleConfigMap) DeepCopy() *AutomatedRuleConfigMap {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARotationStatus) DeepCopyInto(out *CARotationStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.OverlapEndTime.DeepCopyInto(&out.OverlapEndTime)
+	if in.PendingWorkloads != nil {
+		in, out := &in.PendingWorkloads, &out.PendingWorkloads
+		*out = make([]CARotationWorkload, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARotationStatus.
+func (in *CARotationStatus) DeepCopy() *CARotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CARotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARotationWorkload) DeepCopyInto(out *CARotationWorkload) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARotationWorkload.
+func (in *CARotationWorkload) DeepCopy() *CARotationWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(CARotationWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateLifetime) DeepCopyInto(out *CertificateLifetime) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateLifetime.
+func (in *CertificateLifetime) DeepCopy() *CertificateLifetime {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateLifetime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateLifetimes) DeepCopyInto(out *CertificateLifetimes) {
+	*out = *in
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(CertificateLifetime)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(CertificateLifetime)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(CertificateLifetime)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateLifetimes.
+func (in *CertificateLifetimes) DeepCopy() *CertificateLifetimes {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateLifetimes)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateSecret) DeepCopyInto(out *CertificateSecret) {
 	*out = *in
@@ -184,6 +319,22 @@ func (in *CertificateSecret) DeepCopy() *CertificateSecret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CoreServiceConfig) DeepCopyInto(out *CoreServiceConfig) {
 	*out = *in
@@ -205,6 +356,22 @@ func (in *CoreServiceConfig) DeepCopy() *CoreServiceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialRotationOptions) DeepCopyInto(out *CredentialRotationOptions) {
+	*out = *in
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRotationOptions.
+func (in *CredentialRotationOptions) DeepCopy() *CredentialRotationOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialRotationOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cryostat) DeepCopyInto(out *Cryostat) {
 	*out = *in
@@ -264,6 +431,102 @@ func (in *CryostatList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestore) DeepCopyInto(out *CryostatRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestore.
+func (in *CryostatRestore) DeepCopy() *CryostatRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestoreList) DeepCopyInto(out *CryostatRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CryostatRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestoreList.
+func (in *CryostatRestoreList) DeepCopy() *CryostatRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestoreSpec) DeepCopyInto(out *CryostatRestoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestoreSpec.
+func (in *CryostatRestoreSpec) DeepCopy() *CryostatRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestoreStatus) DeepCopyInto(out *CryostatRestoreStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestoreStatus.
+func (in *CryostatRestoreStatus) DeepCopy() *CryostatRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CryostatSpec) DeepCopyInto(out *CryostatSpec) {
 	*out = *in
@@ -272,6 +535,11 @@ func (in *CryostatSpec) DeepCopyInto(out *CryostatSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TargetNamespaceSelector != nil {
+		in, out := &in.TargetNamespaceSelector, &out.TargetNamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TrustedCertSecrets != nil {
 		in, out := &in.TrustedCertSecrets, &out.TrustedCertSecrets
 		*out = make([]CertificateSecret, len(*in))
@@ -304,6 +572,11 @@ func (in *CryostatSpec) DeepCopyInto(out *CryostatSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StorageOptions != nil {
 		in, out := &in.StorageOptions, &out.StorageOptions
 		*out = new(StorageConfigurations)
@@ -374,6 +647,11 @@ func (in *CryostatSpec) DeepCopyInto(out *CryostatSpec) {
 		*out = new(ObjectStorageOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CredentialRotation != nil {
+		in, out := &in.CredentialRotation, &out.CredentialRotation
+		*out = new(CredentialRotationOptions)
+		**out = **in
+	}
 	if in.OperandMetadata != nil {
 		in, out := &in.OperandMetadata, &out.OperandMetadata
 		*out = new(OperandMetadata)
@@ -389,6 +667,21 @@ func (in *CryostatSpec) DeepCopyInto(out *CryostatSpec) {
 		*out = new(LoggingOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodTemplateOverrides != nil {
+		in, out := &in.PodTemplateOverrides, &out.PodTemplateOverrides
+		*out = new(PodTemplateOverridesList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = new(ImageConfigList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatSpec.
@@ -409,6 +702,11 @@ func (in *CryostatStatus) DeepCopyInto(out *CryostatStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TargetNamespaceStatuses != nil {
+		in, out := &in.TargetNamespaceStatuses, &out.TargetNamespaceStatuses
+		*out = make([]TargetNamespaceStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -416,6 +714,27 @@ func (in *CryostatStatus) DeepCopyInto(out *CryostatStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = new(ImageStatus)
+		**out = **in
+	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CARotation != nil {
+		in, out := &in.CARotation, &out.CARotation
+		*out = new(CARotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatStatus.
@@ -428,6 +747,31 @@ func (in *CryostatStatus) DeepCopy() *CryostatStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseBackupOptions) DeepCopyInto(out *DatabaseBackupOptions) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BucketName != nil {
+		in, out := &in.BucketName, &out.BucketName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseBackupOptions.
+func (in *DatabaseBackupOptions) DeepCopy() *DatabaseBackupOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseBackupOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseOptions) DeepCopyInto(out *DatabaseOptions) {
 	*out = *in
@@ -436,6 +780,21 @@ func (in *DatabaseOptions) DeepCopyInto(out *DatabaseOptions) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalDatabaseOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(DatabaseBackupOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MajorVersion != nil {
+		in, out := &in.MajorVersion, &out.MajorVersion
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseOptions.
@@ -499,6 +858,161 @@ func (in *EmptyDirConfig) DeepCopy() *EmptyDirConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDatabaseOptions) DeepCopyInto(out *ExternalDatabaseOptions) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DatabaseName != nil {
+		in, out := &in.DatabaseName, &out.DatabaseName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Username != nil {
+		in, out := &in.Username, &out.Username
+		*out = new(string)
+		**out = **in
+	}
+	if in.SSLMode != nil {
+		in, out := &in.SSLMode, &out.SSLMode
+		*out = new(string)
+		**out = **in
+	}
+	if in.CACertificate != nil {
+		in, out := &in.CACertificate, &out.CACertificate
+		*out = new(CertificateSecret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDatabaseOptions.
+func (in *ExternalDatabaseOptions) DeepCopy() *ExternalDatabaseOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDatabaseOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageConfig) DeepCopyInto(out *ImageConfig) {
+	*out = *in
+	if in.PullPolicy != nil {
+		in, out := &in.PullPolicy, &out.PullPolicy
+		*out = new(corev1.PullPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfig.
+func (in *ImageConfig) DeepCopy() *ImageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageConfigList) DeepCopyInto(out *ImageConfigList) {
+	*out = *in
+	if in.Core != nil {
+		in, out := &in.Core, &out.Core
+		*out = new(ImageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataSource != nil {
+		in, out := &in.DataSource, &out.DataSource
+		*out = new(ImageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(ImageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthProxy != nil {
+		in, out := &in.AuthProxy, &out.AuthProxy
+		*out = new(ImageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AgentProxy != nil {
+		in, out := &in.AgentProxy, &out.AgentProxy
+		*out = new(ImageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reports != nil {
+		in, out := &in.Reports, &out.Reports
+		*out = new(ImageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(ImageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(ImageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AgentInit != nil {
+		in, out := &in.AgentInit, &out.AgentInit
+		*out = new(ImageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfigList.
+func (in *ImageConfigList) DeepCopy() *ImageConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
+func (in *ImageStatus) DeepCopy() *ImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IssuerReference) DeepCopyInto(out *IssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerReference.
+func (in *IssuerReference) DeepCopy() *IssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LegacyStorageConfiguration) DeepCopyInto(out *LegacyStorageConfiguration) {
 	*out = *in
@@ -655,6 +1169,47 @@ func (in *NetworkPolicyConfig) DeepCopy() *NetworkPolicyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCConfig) DeepCopyInto(out *OIDCConfig) {
+	*out = *in
+	in.ClientSecret.DeepCopyInto(&out.ClientSecret)
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedGroups != nil {
+		in, out := &in.AllowedGroups, &out.AllowedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedEmails != nil {
+		in, out := &in.AllowedEmails, &out.AllowedEmails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedEmailDomains != nil {
+		in, out := &in.AllowedEmailDomains, &out.AllowedEmailDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CACertificate != nil {
+		in, out := &in.CACertificate, &out.CACertificate
+		*out = new(CertificateSecret)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCConfig.
+func (in *OIDCConfig) DeepCopy() *OIDCConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectStorageOptions) DeepCopyInto(out *ObjectStorageOptions) {
 	*out = *in
@@ -806,6 +1361,97 @@ func (in *PersistentVolumeClaimConfig) DeepCopy() *PersistentVolumeClaimConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplateOverrides) DeepCopyInto(out *PodTemplateOverrides) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateOverrides.
+func (in *PodTemplateOverrides) DeepCopy() *PodTemplateOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplateOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplateOverridesList) DeepCopyInto(out *PodTemplateOverridesList) {
+	*out = *in
+	if in.Core != nil {
+		in, out := &in.Core, &out.Core
+		*out = new(PodTemplateOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reports != nil {
+		in, out := &in.Reports, &out.Reports
+		*out = new(PodTemplateOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(PodTemplateOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(PodTemplateOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(PodTemplateOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataSource != nil {
+		in, out := &in.DataSource, &out.DataSource
+		*out = new(PodTemplateOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthProxy != nil {
+		in, out := &in.AuthProxy, &out.AuthProxy
+		*out = new(PodTemplateOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AgentProxy != nil {
+		in, out := &in.AgentProxy, &out.AgentProxy
+		*out = new(PodTemplateOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateOverridesList.
+func (in *PodTemplateOverridesList) DeepCopy() *PodTemplateOverridesList {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplateOverridesList)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProbeTemplateConfigMap) DeepCopyInto(out *ProbeTemplateConfigMap) {
 	*out = *in
@@ -1246,6 +1892,96 @@ func (in *StorageServiceConfig) DeepCopy() *StorageServiceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertificates) DeepCopyInto(out *TLSCertificates) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificates.
+func (in *TLSCertificates) DeepCopy() *TLSCertificates {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertificates)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSOptions) DeepCopyInto(out *TLSOptions) {
+	*out = *in
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(TLSProvider)
+		**out = **in
+	}
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(IssuerReference)
+		**out = **in
+	}
+	if in.PrivateKey != nil {
+		in, out := &in.PrivateKey, &out.PrivateKey
+		*out = new(TLSPrivateKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lifetimes != nil {
+		in, out := &in.Lifetimes, &out.Lifetimes
+		*out = new(CertificateLifetimes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpiryWarningWindow != nil {
+		in, out := &in.ExpiryWarningWindow, &out.ExpiryWarningWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CARotationOverlap != nil {
+		in, out := &in.CARotationOverlap, &out.CARotationOverlap
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SecurityProfile != nil {
+		in, out := &in.SecurityProfile, &out.SecurityProfile
+		*out = new(configv1.TLSSecurityProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(TLSCertificates)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSOptions.
+func (in *TLSOptions) DeepCopy() *TLSOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSPrivateKey) DeepCopyInto(out *TLSPrivateKey) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSPrivateKey.
+func (in *TLSPrivateKey) DeepCopy() *TLSPrivateKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSPrivateKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetConnectionCacheOptions) DeepCopyInto(out *TargetConnectionCacheOptions) {
 	*out = *in
@@ -1286,6 +2022,21 @@ func (in *TargetDiscoveryOptions) DeepCopy() *TargetDiscoveryOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetNamespaceStatus) DeepCopyInto(out *TargetNamespaceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetNamespaceStatus.
+func (in *TargetNamespaceStatus) DeepCopy() *TargetNamespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetNamespaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateConfigMap) DeepCopyInto(out *TemplateConfigMap) {
 	*out = *in
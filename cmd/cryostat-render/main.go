@@ -0,0 +1,263 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// cryostat-render prints the Kubernetes objects the operator would create for a Cryostat CR,
+// without access to a cluster. If a second CR is provided, the differences between the
+// objects rendered for each CR are printed instead.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/render"
+)
+
+// Exit codes, following the convention of diff(1)
+const (
+	exitOK          = 0
+	exitDifferences = 1
+	exitError       = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[0], os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run renders the Cryostat CRs named by args, and returns the exit code of the command
+func run(name string, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	opts := &render.Options{}
+	var verbose bool
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.BoolVar(&opts.OpenShift, "openshift", false, "Render for OpenShift")
+	flags.BoolVar(&opts.CertManager, "cert-manager", true, "Render as if cert-manager is installed")
+	flags.BoolVar(&opts.FIPS, "fips", false, "Render as if the cluster is running in FIPS mode")
+	flags.BoolVar(&verbose, "v", false, "Print reconciler logs to stderr")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s [flags] CRYOSTAT_YAML [NEW_CRYOSTAT_YAML]\n", name)
+		fmt.Fprintln(flags.Output(), "Use - to read a Cryostat CR from standard input.")
+		fmt.Fprintln(flags.Output(), "Exits with 1 if the objects rendered for two CRs differ, or 2 on error.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitError
+	}
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		flags.Usage()
+		return exitError
+	}
+	if verbose {
+		logger := zap.New(zap.WriteTo(stderr))
+		opts.Log = &logger
+	}
+
+	rendered := make([][]*unstructured.Unstructured, flags.NArg())
+	for i, path := range flags.Args() {
+		objs, err := renderFile(path, stdin, opts)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to render %s: %v\n", path, err)
+			return exitError
+		}
+		rendered[i] = objs
+	}
+
+	if len(rendered) == 1 {
+		err := printObjects(stdout, rendered[0])
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+	changed, err := printDiff(stdout, rendered[0], rendered[1])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if changed {
+		return exitDifferences
+	}
+	return exitOK
+}
+
+func renderFile(path string, stdin io.Reader, opts *render.Options) ([]*unstructured.Unstructured, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := serializer.NewCodecFactory(render.NewScheme()).UniversalDeserializer()
+	obj, gvk, err := decoder.Decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	cr, ok := obj.(*operatorv1beta2.Cryostat)
+	if !ok {
+		return nil, fmt.Errorf("expected a %s Cryostat, but found %s", operatorv1beta2.GroupVersion, gvk)
+	}
+	return render.Render(context.Background(), cr, opts)
+}
+
+func printObjects(out io.Writer, objs []*unstructured.Unstructured) error {
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "---\n%s", data)
+	}
+	return nil
+}
+
+// printDiff prints the differences between each object in the old and new renders,
+// and returns whether any objects were changed, added or removed
+func printDiff(out io.Writer, oldObjs []*unstructured.Unstructured, newObjs []*unstructured.Unstructured) (bool, error) {
+	// Compare objects in the order they were first rendered
+	keys := []string{}
+	oldYAML, err := renderYAML(oldObjs, &keys)
+	if err != nil {
+		return false, err
+	}
+	newYAML, err := renderYAML(newObjs, &keys)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	for _, key := range keys {
+		diff := diffLines(oldYAML[key], newYAML[key])
+		if len(diff) == 0 {
+			continue
+		}
+		changed = true
+		fmt.Fprintf(out, "--- %s\n%s", key, diff)
+	}
+	return changed, nil
+}
+
+func renderYAML(objs []*unstructured.Unstructured, keys *[]string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		key := objectKey(obj)
+		if !slices.Contains(*keys, key) {
+			*keys = append(*keys, key)
+		}
+		result[key] = string(data)
+	}
+	return result, nil
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	kind := obj.GetKind()
+	if len(obj.GetNamespace()) == 0 {
+		return fmt.Sprintf("%s %s", kind, obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", kind, obj.GetNamespace(), obj.GetName())
+}
+
+// Number of unchanged lines to print around each change
+const diffContext = 3
+
+// diffLines returns the lines removed from a and added in b, prefixed by "-" and "+",
+// along with nearby unchanged lines. Returns an empty string if a and b are equal.
+func diffLines(a string, b string) string {
+	if a == b {
+		return ""
+	}
+	oldLines := splitLines(a)
+	newLines := splitLines(b)
+
+	// Longest common subsequence of lines, computed from the end
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := []string{}
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			lines = append(lines, " "+oldLines[i])
+			i++
+			j++
+		case i < len(oldLines) && (j == len(newLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "-"+oldLines[i])
+			i++
+		default:
+			lines = append(lines, "+"+newLines[j])
+			j++
+		}
+	}
+
+	// Only print unchanged lines near a change
+	show := make([]bool, len(lines))
+	for idx, line := range lines {
+		if line[0] != ' ' {
+			for k := max(idx-diffContext, 0); k <= min(idx+diffContext, len(lines)-1); k++ {
+				show[k] = true
+			}
+		}
+	}
+	var sb strings.Builder
+	for idx, line := range lines {
+		if show[idx] {
+			sb.WriteString(line + "\n")
+		} else if idx > 0 && show[idx-1] {
+			sb.WriteString("...\n")
+		}
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if len(s) == 0 {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,119 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const cryostatYAML = `apiVersion: operator.cryostat.io/v1beta2
+kind: Cryostat
+metadata:
+  name: cryostat
+  namespace: test
+spec:
+  enableCertManager: true
+`
+
+var _ = Describe("run", func() {
+	var dir string
+	var stdin string
+	var stdout *bytes.Buffer
+	var stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		stdin = ""
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+	})
+
+	writeFile := func(name string, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(contents), 0o600)).To(Succeed())
+		return path
+	}
+
+	runRender := func(args ...string) int {
+		return run("cryostat-render", args, strings.NewReader(stdin), stdout, stderr)
+	}
+
+	It("should exit with 0 after rendering a CR", func() {
+		Expect(runRender(writeFile("cryostat.yaml", cryostatYAML))).To(Equal(0))
+		Expect(stdout.String()).To(ContainSubstring("kind: Deployment"))
+	})
+
+	It("should exit with 0 when there are no differences", func() {
+		path := writeFile("cryostat.yaml", cryostatYAML)
+		stdin = cryostatYAML
+		Expect(runRender(path, "-")).To(Equal(0))
+		Expect(stdout.String()).To(BeEmpty())
+	})
+
+	It("should exit with 1 when there are differences", func() {
+		path := writeFile("cryostat.yaml", cryostatYAML)
+		newPath := writeFile("new-cryostat.yaml", cryostatYAML+"  reportOptions:\n    replicas: 1\n")
+		Expect(runRender(path, newPath)).To(Equal(1))
+		Expect(stdout.String()).To(ContainSubstring("--- Deployment test/cryostat-reports"))
+	})
+
+	It("should exit with 2 when a CR cannot be read", func() {
+		Expect(runRender(filepath.Join(dir, "missing.yaml"))).To(Equal(2))
+		Expect(stderr.String()).To(ContainSubstring("failed to render"))
+	})
+
+	It("should exit with 2 when a CR cannot be decoded", func() {
+		path := writeFile("cryostat.yaml", cryostatYAML)
+		newPath := writeFile("new-cryostat.yaml", "kind: Cryostat\n")
+		Expect(runRender(path, newPath)).To(Equal(2))
+		Expect(stdout.String()).To(BeEmpty())
+	})
+
+	It("should exit with 2 when given too many arguments", func() {
+		path := writeFile("cryostat.yaml", cryostatYAML)
+		Expect(runRender(path, path, path)).To(Equal(2))
+	})
+
+	It("should exit with 2 when given an unknown flag", func() {
+		Expect(runRender("-unknown")).To(Equal(2))
+	})
+})
+
+var _ = Describe("diffLines", func() {
+	DescribeTable("should report the changed lines",
+		func(a string, b string, expected string) {
+			Expect(diffLines(a, b)).To(Equal(expected))
+		},
+		Entry("with equal inputs", "a\nb\n", "a\nb\n", ""),
+		Entry("with empty inputs", "", "", ""),
+		Entry("with an empty old input", "", "a\nb\n", "+a\n+b\n"),
+		Entry("with an empty new input", "a\nb\n", "", "-a\n-b\n"),
+		Entry("with an insertion", "a\nb\nc\n", "a\nb\nx\nc\n", " a\n b\n+x\n c\n"),
+		Entry("with a deletion", "a\nb\nc\n", "a\nc\n", " a\n-b\n c\n"),
+		Entry("with a replacement", "a\nb\nc\n", "a\nx\nc\n", " a\n-b\n+x\n c\n"),
+		Entry("with unchanged lines before a change", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "1\n2\n3\n4\n5\n6\n7\n8\n9\nx\n",
+			" 7\n 8\n 9\n-10\n+x\n"),
+		Entry("with unchanged lines after a change", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "x\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			"-1\n+x\n 2\n 3\n 4\n...\n"),
+		Entry("with unchanged lines between changes", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n",
+			"-1\n+x\n 2\n 3\n 4\n...\n 7\n 8\n 9\n-10\n+y\n"),
+	)
+})
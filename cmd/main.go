@@ -314,7 +314,7 @@ func main() {
 	}
 
 	config := newReconcilerConfig(mgr, "Cryostat", "cryostat-controller", openShift, certManager,
-		insightsURL)
+		fipsEnabled, insightsURL)
 	cryostatController, err := controller.NewCryostatReconciler(config)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cryostat")
@@ -324,14 +324,23 @@ func main() {
 		setupLog.Error(err, "unable to add controller to manager", "controller", "Cryostat")
 		os.Exit(1)
 	}
+	restoreConfig := newReconcilerConfig(mgr, "CryostatRestore", "cryostatrestore-controller", openShift, certManager,
+		fipsEnabled, insightsURL)
+	if err = controller.NewCryostatRestoreReconciler(restoreConfig).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to add controller to manager", "controller", "CryostatRestore")
+		os.Exit(1)
+	}
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err = webhook.SetupWebhookWithManager(mgr, &operatorv1beta2.Cryostat{}); err != nil {
+		if err = webhook.SetupWebhookWithManager(mgr, &operatorv1beta2.Cryostat{}, &webhook.CryostatWebhookConfig{
+			IsOpenShift: openShift,
+		}); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Cryostat")
 			os.Exit(1)
 		}
 		agentWebhook := agent.NewAgentWebhook(&agent.AgentWebhookConfig{
 			FIPSEnabled: fipsEnabled,
+			IsOpenShift: openShift,
 		})
 		if err = agentWebhook.SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
@@ -384,7 +393,7 @@ func isCertManagerInstalled(client discovery.DiscoveryInterface) (bool, error) {
 }
 
 func newReconcilerConfig(mgr ctrl.Manager, logName string, eventRecorderName string, openShift bool,
-	certManager bool, insightsURL *url.URL) *controller.ReconcilerConfig {
+	certManager bool, fipsEnabled bool, insightsURL *url.URL) *controller.ReconcilerConfig {
 	return &controller.ReconcilerConfig{
 		Client:                 mgr.GetClient(),
 		Log:                    ctrl.Log.WithName("controller").WithName(logName),
@@ -394,6 +403,7 @@ func newReconcilerConfig(mgr ctrl.Manager, logName string, eventRecorderName str
 		EventRecorder:          mgr.GetEventRecorderFor(eventRecorderName),
 		RESTMapper:             mgr.GetRESTMapper(),
 		InsightsProxy:          insightsURL,
+		FIPSEnabled:            fipsEnabled,
 		NewControllerBuilder:   common.NewControllerBuilder,
 		ReconcilerTLS: common.NewReconcilerTLS(&common.ReconcilerTLSConfig{
 			Client: mgr.GetClient(),
@@ -0,0 +1,240 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// Field manager used by the operator when applying its resources
+	fieldManager = "cryostat-operator"
+	// Field manager recorded for resources created or updated by earlier versions of the
+	// operator, before they were applied. Derived by the API server from the operator binary name.
+	legacyFieldManager = "manager"
+	// Event type to inform users that another field manager modified fields owned by the operator
+	eventApplyConflictType = "ApplyConflict"
+)
+
+// applyObject applies the desired state of obj using server-side apply. Only the fields
+// set in obj are owned by the operator, so fields added by other controllers are preserved.
+// If another field manager has changed fields owned by the operator, the conflict is reported
+// with a warning event naming the fields and their managers. The fields are then reverted,
+// and the operator takes back ownership of them. If owner is not nil, it is set as the
+// controller of obj. Once applied, obj contains the object returned by the API server.
+func (r *Reconciler) applyObject(ctx context.Context, obj client.Object, owner metav1.Object) error {
+	existing := obj.DeepCopyObject().(client.Object)
+	err := r.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, existing)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		existing = nil
+	}
+
+	if owner != nil {
+		// Fail if the object is already controlled by something else
+		if existing != nil {
+			if err := controllerutil.SetControllerReference(owner, existing.DeepCopyObject().(client.Object), r.Scheme); err != nil {
+				return err
+			}
+		}
+		// Set the Cryostat CR as controller
+		if err := controllerutil.SetControllerReference(owner, obj, r.Scheme); err != nil {
+			return err
+		}
+	}
+
+	if existing != nil {
+		// Take ownership of the fields set by earlier versions of the operator, so they can be
+		// changed or removed without conflicts
+		if err := r.upgradeManagedFields(ctx, existing); err != nil {
+			return err
+		}
+		// Leave fields that are commonly managed by other controllers to them
+		omitExternallyManagedFields(obj, existing)
+		// Avoid removing or changing fields that cannot be modified after creation
+		keepImmutableFields(obj, existing)
+	}
+
+	// Apply requires type information, and must not include server-managed metadata
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+
+	err = r.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager))
+	if kerrors.IsConflict(err) {
+		// Another field manager has changed fields we manage. Report the conflict, then take
+		// back ownership. Fields delegated to other managers have been omitted from the
+		// desired state above, so only fields required by Cryostat are forced.
+		var eventObj runtime.Object = obj
+		if ownerObj, ok := owner.(runtime.Object); ok {
+			eventObj = ownerObj
+		}
+		r.EventRecorder.Event(eventObj, corev1.EventTypeWarning, eventApplyConflictType,
+			fmt.Sprintf("%s %s/%s: %s", gvk.Kind, obj.GetNamespace(), obj.GetName(), describeApplyConflict(err)))
+		err = r.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+	}
+	if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("%s applied", gvk.Kind), "name", obj.GetName(), "namespace", obj.GetNamespace())
+	return nil
+}
+
+// upgradeManagedFields transfers the fields owned by the legacy field manager
+// to the operator's field manager, updating existing in place
+func (r *Reconciler) upgradeManagedFields(ctx context.Context, existing client.Object) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(existing, sets.New(legacyFieldManager), fieldManager)
+	if err != nil || patch == nil {
+		return err
+	}
+	return r.Patch(ctx, existing, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// omitExternallyManagedFields removes fields from the desired object that have been taken
+// over by another field manager, and that the operator only sets as a default. All other
+// fields set by the operator are forcibly applied.
+func omitExternallyManagedFields(obj client.Object, existing client.Object) {
+	switch desired := obj.(type) {
+	case *appsv1.Deployment:
+		// The replica count may be managed by a HorizontalPodAutoscaler or by scaling the
+		// deployment manually. Components must still be stopped when required by the operator,
+		// in which case the replica count is taken back from the other field manager.
+		if desired.Spec.Replicas != nil && *desired.Spec.Replicas > 0 &&
+			isFieldManagedByOthers(existing, "spec", "replicas") {
+			desired.Spec.Replicas = nil
+		}
+	}
+}
+
+// keepImmutableFields copies the existing values of immutable fields into the desired object,
+// if the operator sets or already owns them
+func keepImmutableFields(obj client.Object, existing client.Object) {
+	switch desired := obj.(type) {
+	case *corev1.PersistentVolumeClaim:
+		keepImmutablePVCFields(desired, existing.(*corev1.PersistentVolumeClaim))
+	}
+}
+
+// isFieldManagedByOthers returns whether a field manager other than the operator
+// owns the field with the given path
+func isFieldManagedByOthers(obj client.Object, path ...string) bool {
+	return slices.ContainsFunc(fieldManagersOf(obj, path...), func(manager string) bool {
+		return manager != fieldManager
+	})
+}
+
+// isFieldManagedByOperator returns whether the operator owns the field with the given path
+func isFieldManagedByOperator(obj client.Object, path ...string) bool {
+	return slices.Contains(fieldManagersOf(obj, path...), fieldManager)
+}
+
+// fieldManagersOf returns the field managers that own the field with the given path
+func fieldManagersOf(obj client.Object, path ...string) []string {
+	managers := []string{}
+	for _, entry := range obj.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		found := true
+		for _, name := range path {
+			// Fields are encoded with an "f:" prefix
+			child, ok := fields["f:"+name].(map[string]interface{})
+			if !ok {
+				found = false
+				break
+			}
+			fields = child
+		}
+		if found {
+			managers = append(managers, entry.Manager)
+		}
+	}
+	return managers
+}
+
+// describeApplyConflict lists the conflicting fields of an apply request, grouped by the
+// field manager that owns them
+func describeApplyConflict(err error) string {
+	var statusErr *kerrors.StatusError
+	if !errors.As(err, &statusErr) || statusErr.ErrStatus.Details == nil {
+		return err.Error()
+	}
+	managers := []string{}
+	fields := map[string][]string{}
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		// The message has the form: conflict with "<manager>"[ using <apiVersion>]
+		manager := cause.Message
+		if _, quoted, ok := strings.Cut(cause.Message, `"`); ok {
+			manager, _, _ = strings.Cut(quoted, `"`)
+		}
+		if _, ok := fields[manager]; !ok {
+			managers = append(managers, manager)
+		}
+		fields[manager] = append(fields[manager], cause.Field)
+	}
+	if len(managers) == 0 {
+		return err.Error()
+	}
+	conflicts := make([]string, 0, len(managers))
+	for _, manager := range managers {
+		conflicts = append(conflicts, fmt.Sprintf("%s changed by %q", strings.Join(fields[manager], ", "), manager))
+	}
+	return fmt.Sprintf("reverting fields managed by the operator: %s", strings.Join(conflicts, "; "))
+}
+
+// isImmutableFieldChanged returns whether the API server rejected a change to one of
+// the specified immutable fields, in which case the object must be recreated.
+func isImmutableFieldChanged(err error, fields ...string) bool {
+	var statusErr *kerrors.StatusError
+	if !errors.As(err, &statusErr) || !kerrors.IsInvalid(err) || statusErr.ErrStatus.Details == nil {
+		return false
+	}
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		if slices.Contains(fields, cause.Field) {
+			return true
+		}
+	}
+	return false
+}
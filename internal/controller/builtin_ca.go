@@ -0,0 +1,596 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/cryostatio/cryostat-operator/internal/controller/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controller/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// Unless configured otherwise, certificates issued by the built-in CA have
+	// the same lifetime and key size as cert-manager's defaults
+	builtInCertDuration = 90 * 24 * time.Hour
+	builtInKeySize      = 2048
+)
+
+// builtInCA is a certificate authority managed by the operator, which issues
+// the certificates that would otherwise be issued by cert-manager
+type builtInCA struct {
+	cert    *x509.Certificate
+	key     crypto.Signer
+	certPEM []byte
+	renewal time.Time
+}
+
+func (r *Reconciler) setupBuiltInTLS(ctx context.Context, cr *model.CryostatInstance) (*resources.TLSConfig, time.Duration, error) {
+	ca, err := r.reconcileBuiltInCA(ctx, cr)
+	if err != nil {
+		return nil, 0, err
+	}
+	renewal := ca.renewal
+
+	keystoreSecret, keystorePass, err := r.reconcileKeystorePassword(ctx, cr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Issue the same certificates that would be requested from cert-manager
+	cryostatCert := resources.NewCryostatCert(cr, keystoreSecret.Name)
+	reportsCert := resources.NewReportsCert(cr)
+	databaseCert := resources.NewDatabaseCert(cr)
+	storageCert := resources.NewStorageCert(cr)
+	agentProxyCert := resources.NewAgentProxyCert(cr)
+	for _, cert := range []*certv1.Certificate{cryostatCert, reportsCert, databaseCert, storageCert, agentProxyCert} {
+		certRenewal, err := r.reconcileBuiltInCertificate(ctx, cr, ca, cert, keystorePass)
+		if err != nil {
+			return nil, 0, err
+		}
+		renewal = earliestTime(renewal, certRenewal)
+	}
+
+	tlsConfig := &resources.TLSConfig{
+		CryostatSecret:     cryostatCert.Spec.SecretName,
+		DatabaseSecret:     databaseCert.Spec.SecretName,
+		StorageSecret:      storageCert.Spec.SecretName,
+		ReportsSecret:      reportsCert.Spec.SecretName,
+		AgentProxySecret:   agentProxyCert.Spec.SecretName,
+		KeystorePassSecret: keystoreSecret.Name,
+		CACert:             ca.certPEM,
+	}
+	return r.setupBuiltInAgentTLS(ctx, cr, ca, tlsConfig, renewal)
+}
+
+// setupBuiltInAgentTLS issues certificates for agents in each target namespace using the built-in CA,
+// and returns the TLS config along with the time until the earliest certificate should be renewed
+func (r *Reconciler) setupBuiltInAgentTLS(ctx context.Context, cr *model.CryostatInstance, ca *builtInCA,
+	tlsConfig *resources.TLSConfig, renewal time.Time) (*resources.TLSConfig, time.Duration, error) {
+	// Keep trusting the previous CA for a while after the CA is renewed, so that running agents
+	// can still authenticate
+	var rotationRequeue time.Duration
+	var err error
+	tlsConfig.CATrustBundle, rotationRequeue, err = r.reconcileCATrustBundle(ctx, cr, ca.certPEM)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(tlsConfig.CATrustBundle) > 0 {
+		tlsConfig.PreviousCASecret = newCATrustBundleSecret(cr).Name
+	}
+
+	for _, ns := range cr.TargetNamespaces {
+		// Set up agent TLS in each target namespace, continuing with other namespaces on failure
+		certRenewal, err := r.reconcileBuiltInAgentTLS(ctx, cr, ca, tlsConfig.CATrustBundle, ns)
+		if err != nil {
+			r.recordTargetNamespaceError(cr, ns, err)
+		} else {
+			renewal = earliestTime(renewal, certRenewal)
+		}
+		targetNamespaceStatus(cr, ns).AgentTLSReady = err == nil
+	}
+
+	// Clean up resources from target namespaces that are no longer requested
+	for _, ns := range toDelete(cr) {
+		err := r.cleanUpBuiltInAgentTLS(ctx, cr, ns)
+		if err != nil {
+			r.recordTargetNamespaceError(cr, ns, err)
+		}
+	}
+
+	// Remove any cert-manager resources from before switching away from cert-manager,
+	// now that their secrets are managed by the operator
+	err = r.deleteCertManagerResources(ctx, cr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tlsConfig, earliestRequeue(time.Until(renewal), rotationRequeue), nil
+}
+
+// reconcileKeystorePassword creates the secret holding the password for Cryostat's keystore,
+// and returns the secret along with the password
+func (r *Reconciler) reconcileKeystorePassword(ctx context.Context, cr *model.CryostatInstance) (*corev1.Secret, string, error) {
+	keystoreSecret := newKeystoreSecret(cr)
+	err := r.createOrUpdateKeystoreSecret(ctx, keystoreSecret, cr.Object)
+	if err != nil {
+		return nil, "", err
+	}
+	err = r.Get(ctx, types.NamespacedName{Name: keystoreSecret.Name, Namespace: keystoreSecret.Namespace}, keystoreSecret)
+	if err != nil {
+		return nil, "", err
+	}
+	return keystoreSecret, string(keystoreSecret.Data[constants.KeystorePassSecretKey]), nil
+}
+
+func (r *Reconciler) reconcileBuiltInCA(ctx context.Context, cr *model.CryostatInstance) (*builtInCA, error) {
+	caCert := resources.NewCryostatCACert(r.gvk, cr)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      caCert.Spec.SecretName,
+			Namespace: caCert.Namespace,
+		},
+	}
+	var ca *builtInCA
+	err := r.createOrUpdateBuiltInCertSecret(ctx, cr, secret, func() error {
+		ca = parseBuiltInCA(secret, &caCert.Spec)
+		if ca != nil {
+			return nil
+		}
+
+		// Create a new self-signed CA, which causes all certificates to be reissued
+		template := &x509.Certificate{
+			Subject:               pkix.Name{CommonName: caCert.Spec.CommonName},
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+		cert, certPEM, keyPEM, err := signBuiltInCertificate(template, nil, &caCert.Spec, reusableBuiltInKey(secret, &caCert.Spec))
+		if err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+			constants.CAKey:         certPEM,
+		}
+		ca = parseBuiltInCA(secret, &caCert.Spec)
+		if ca == nil {
+			return fmt.Errorf("failed to parse CA certificate %s", cert.Subject.CommonName)
+		}
+		r.Log.Info("Issued CA certificate", "name", secret.Name, "namespace", secret.Namespace)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+// parseBuiltInCA returns the CA stored in the secret, or nil if it is invalid, no longer
+// matches its spec or is due for renewal
+func parseBuiltInCA(secret *corev1.Secret, spec *certv1.CertificateSpec) *builtInCA {
+	keyPair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil || !keyPair.Leaf.IsCA || !builtInCertificateMatches(keyPair.Leaf, spec) {
+		return nil
+	}
+	renewal := builtInRenewalTime(keyPair.Leaf, spec)
+	if !time.Now().Before(renewal) {
+		return nil
+	}
+	key, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil
+	}
+	return &builtInCA{
+		cert:    keyPair.Leaf,
+		key:     key,
+		certPEM: secret.Data[corev1.TLSCertKey],
+		renewal: renewal,
+	}
+}
+
+// reconcileBuiltInCertificate issues the certificate using the built-in CA, unless the certificate
+// in its secret is still valid, and returns the time when the certificate should be renewed
+func (r *Reconciler) reconcileBuiltInCertificate(ctx context.Context, cr *model.CryostatInstance, ca *builtInCA,
+	cert *certv1.Certificate, keystorePass string) (time.Time, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cert.Spec.SecretName,
+			Namespace: cert.Namespace,
+		},
+	}
+	keystore := cert.Spec.Keystores != nil && cert.Spec.Keystores.PKCS12 != nil
+	var renewal time.Time
+	err := r.createOrUpdateBuiltInCertSecret(ctx, cr, secret, func() error {
+		leaf := validBuiltInCertificate(secret, ca, cert, keystore, keystorePass)
+		if leaf != nil {
+			renewal = builtInRenewalTime(leaf, &cert.Spec)
+			return nil
+		}
+
+		template := &x509.Certificate{
+			Subject:     pkix.Name{CommonName: cert.Spec.CommonName},
+			DNSNames:    cert.Spec.DNSNames,
+			KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			ExtKeyUsage: builtInExtKeyUsages(cert.Spec.Usages),
+		}
+		leaf, certPEM, keyPEM, err := signBuiltInCertificate(template, ca, &cert.Spec, reusableBuiltInKey(secret, &cert.Spec))
+		if err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+			constants.CAKey:         ca.certPEM,
+		}
+		if keystore {
+			keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+			if err != nil {
+				return err
+			}
+			secret.Data[constants.KeyStoreFile], err = encodeKeystore(&keyPair, ca.certPEM, keystorePass)
+			if err != nil {
+				return err
+			}
+			common.MergeLabelsAndAnnotations(&secret.ObjectMeta, map[string]string{},
+				map[string]string{annotationKeystoreSourceHash: keystoreSourceHash(secret, keystorePass)})
+		}
+		renewal = builtInRenewalTime(leaf, &cert.Spec)
+		r.Log.Info("Issued certificate", "name", secret.Name, "namespace", secret.Namespace, "renewal", renewal)
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return renewal, nil
+}
+
+// validBuiltInCertificate returns the certificate stored in the secret, or nil if it must be reissued
+func validBuiltInCertificate(secret *corev1.Secret, ca *builtInCA, cert *certv1.Certificate, keystore bool,
+	keystorePass string) *x509.Certificate {
+	keyPair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil
+	}
+	leaf := keyPair.Leaf
+	// Reissue certificates from a previous CA, or whose requested names, key or lifetime have changed
+	if leaf.CheckSignatureFrom(ca.cert) != nil || !bytes.Equal(secret.Data[constants.CAKey], ca.certPEM) ||
+		leaf.Subject.CommonName != cert.Spec.CommonName || !slices.Equal(leaf.DNSNames, cert.Spec.DNSNames) ||
+		!builtInCertificateMatches(leaf, &cert.Spec) {
+		return nil
+	}
+	if !time.Now().Before(builtInRenewalTime(leaf, &cert.Spec)) {
+		return nil
+	}
+	if keystore && (len(secret.Data[constants.KeyStoreFile]) == 0 ||
+		secret.Annotations[annotationKeystoreSourceHash] != keystoreSourceHash(secret, keystorePass)) {
+		return nil
+	}
+	return leaf
+}
+
+func (r *Reconciler) reconcileBuiltInAgentTLS(ctx context.Context, cr *model.CryostatInstance, ca *builtInCA,
+	caBundle []byte, namespace string) (time.Time, error) {
+	// Copy Cryostat CA secret in each target namespace
+	if namespace != cr.InstallNamespace {
+		namespaceSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resources.NewCryostatCACert(r.gvk, cr).Spec.SecretName,
+				Namespace: namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+		err := r.createOrUpdateCertSecret(ctx, namespaceSecret, trustedCABytes(ca.certPEM, caBundle),
+			common.LabelsForTargetNamespaceObject(cr))
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	// Issue a certificate for Cryostat agents in the install namespace
+	agentCert := resources.NewAgentCert(cr, namespace, r.gvk)
+	renewal, err := r.reconcileBuiltInCertificate(ctx, cr, ca, agentCert, "")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Create a copy in the target namespace (if not the install namespace)
+	if namespace != cr.InstallNamespace {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: agentCert.Spec.SecretName, Namespace: agentCert.Namespace}, secret)
+		if err != nil {
+			return time.Time{}, err
+		}
+		targetSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secret.Name,
+				Namespace: namespace,
+			},
+		}
+		err = r.createOrUpdateSecret(ctx, targetSecret, nil, func() error {
+			common.MergeLabelsAndAnnotations(&targetSecret.ObjectMeta,
+				common.LabelsForTargetNamespaceObject(cr), map[string]string{})
+			targetSecret.Data = withCABundle(secret.Data, caBundle)
+			return nil
+		})
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return renewal, nil
+}
+
+func (r *Reconciler) cleanUpBuiltInAgentTLS(ctx context.Context, cr *model.CryostatInstance, namespace string) error {
+	// Delete any copies in the removed namespace
+	err := r.cleanUpProvidedAgentTLS(ctx, cr, resources.NewCryostatCACert(r.gvk, cr).Spec.SecretName, namespace)
+	if err != nil {
+		return err
+	}
+
+	// Delete the original agent certificate secret
+	agentCert := resources.NewAgentCert(cr, namespace, r.gvk)
+	return r.deleteSecret(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      agentCert.Spec.SecretName,
+			Namespace: agentCert.Namespace,
+		},
+	})
+}
+
+// createOrUpdateBuiltInCertSecret applies a TLS secret whose contents are generated by the operator.
+// The delegate is called with the existing contents of secret, if any, so it can keep a certificate
+// that is still valid.
+func (r *Reconciler) createOrUpdateBuiltInCertSecret(ctx context.Context, cr *model.CryostatInstance, secret *corev1.Secret,
+	delegate func() error) error {
+	desired := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+		},
+		Type: corev1.SecretTypeTLS,
+	}
+	err := r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		// The type of a secret cannot be changed
+		desired.Type = secret.Type
+		// Take over secrets previously issued by cert-manager
+		ref := metav1.GetControllerOf(secret)
+		if ref != nil && ref.Kind == certv1.CertificateKind {
+			secret.OwnerReferences = deleteControllerRefrerence(secret.OwnerReferences)
+			if err := r.Update(ctx, secret); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := delegate(); err != nil {
+		return err
+	}
+	desired.Data = secret.Data
+	if hash, ok := secret.Annotations[annotationKeystoreSourceHash]; ok {
+		desired.Annotations = map[string]string{annotationKeystoreSourceHash: hash}
+	}
+	if err := r.applyObject(ctx, desired, cr.Object); err != nil {
+		return err
+	}
+	*secret = *desired
+	return nil
+}
+
+func (r *Reconciler) deleteCertManagerResources(ctx context.Context, cr *model.CryostatInstance) error {
+	available, err := r.certManagerAvailable()
+	if err != nil || !available {
+		return err
+	}
+
+	certs := []*certv1.Certificate{
+		resources.NewCryostatCACert(r.gvk, cr),
+		resources.NewCryostatCert(cr, newKeystoreSecret(cr).Name),
+		resources.NewReportsCert(cr),
+		resources.NewDatabaseCert(cr),
+		resources.NewStorageCert(cr),
+		resources.NewAgentProxyCert(cr),
+	}
+	for _, ns := range append(cr.TargetNamespaces, toDelete(cr)...) {
+		certs = append(certs, resources.NewAgentCert(cr, ns, r.gvk))
+	}
+	for _, cert := range certs {
+		err := r.deleteCertificate(ctx, cert)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.deleteOwnedIssuers(ctx, cr)
+}
+
+// signBuiltInCertificate creates a certificate from the template, signed by the CA, with the lifetime and
+// key requested in the spec. The certificate is self-signed if the CA is nil, and a new key is generated
+// if no key is given.
+func signBuiltInCertificate(template *x509.Certificate, ca *builtInCA, spec *certv1.CertificateSpec,
+	key crypto.Signer) (*x509.Certificate, []byte, []byte, error) {
+	var err error
+	if key == nil {
+		key, err = generateBuiltInKey(spec.PrivateKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	now := time.Now()
+	template.SerialNumber = serial
+	template.NotBefore = now
+	template.NotAfter = now.Add(builtInDuration(spec))
+
+	parent, parentKey := template, key
+	if ca != nil {
+		parent, parentKey = ca.cert, ca.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	keyPEM, err := encodeBuiltInKey(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return cert, certPEM, keyPEM, nil
+}
+
+func generateBuiltInKey(opts *certv1.CertificatePrivateKey) (crypto.Signer, error) {
+	if opts != nil && opts.Algorithm == certv1.ECDSAKeyAlgorithm {
+		return ecdsa.GenerateKey(builtInCurve(opts), rand.Reader)
+	}
+	size := builtInKeySize
+	if opts != nil && opts.Size != 0 {
+		size = opts.Size
+	}
+	return rsa.GenerateKey(rand.Reader, size)
+}
+
+func encodeBuiltInKey(key crypto.Signer) ([]byte, error) {
+	// Use the same PKCS#1 (or SEC 1 for ECDSA) encoding as cert-manager's default
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", key)
+}
+
+func builtInCurve(opts *certv1.CertificatePrivateKey) elliptic.Curve {
+	switch opts.Size {
+	case 384:
+		return elliptic.P384()
+	case 521:
+		return elliptic.P521()
+	}
+	return elliptic.P256()
+}
+
+// builtInKeyMatches returns whether the public key has the algorithm and size requested in the options
+func builtInKeyMatches(key crypto.PublicKey, opts *certv1.CertificatePrivateKey) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		size := builtInKeySize
+		if opts != nil && opts.Size != 0 {
+			size = opts.Size
+		}
+		return (opts == nil || opts.Algorithm != certv1.ECDSAKeyAlgorithm) && k.N.BitLen() == size
+	case *ecdsa.PublicKey:
+		return opts != nil && opts.Algorithm == certv1.ECDSAKeyAlgorithm && k.Curve == builtInCurve(opts)
+	}
+	return false
+}
+
+// builtInCertificateMatches returns whether the certificate has the key and lifetime requested in the spec
+func builtInCertificateMatches(cert *x509.Certificate, spec *certv1.CertificateSpec) bool {
+	// Validity times are encoded with a precision of seconds
+	diff := cert.NotAfter.Sub(cert.NotBefore) - builtInDuration(spec)
+	return builtInKeyMatches(cert.PublicKey, spec.PrivateKey) && diff > -time.Second && diff < time.Second
+}
+
+// reusableBuiltInKey returns the existing private key in the secret if the spec requests that keys
+// are not rotated, and the key still has the requested algorithm and size
+func reusableBuiltInKey(secret *corev1.Secret, spec *certv1.CertificateSpec) crypto.Signer {
+	if spec.PrivateKey == nil || spec.PrivateKey.RotationPolicy != certv1.RotationPolicyNever {
+		return nil
+	}
+	keyPair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil || !builtInKeyMatches(keyPair.Leaf.PublicKey, spec.PrivateKey) {
+		return nil
+	}
+	key, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil
+	}
+	return key
+}
+
+func builtInDuration(spec *certv1.CertificateSpec) time.Duration {
+	if spec.Duration != nil {
+		return spec.Duration.Duration
+	}
+	return builtInCertDuration
+}
+
+func builtInExtKeyUsages(usages []certv1.KeyUsage) []x509.ExtKeyUsage {
+	extUsages := []x509.ExtKeyUsage{}
+	for _, usage := range usages {
+		switch usage {
+		case certv1.UsageServerAuth:
+			extUsages = append(extUsages, x509.ExtKeyUsageServerAuth)
+		case certv1.UsageClientAuth:
+			extUsages = append(extUsages, x509.ExtKeyUsageClientAuth)
+		}
+	}
+	return extUsages
+}
+
+// builtInRenewalTime returns when the certificate should be renewed. Unless the spec sets when to
+// renew the certificate, it is renewed once two thirds of its lifetime has passed, like cert-manager.
+func builtInRenewalTime(cert *x509.Certificate, spec *certv1.CertificateSpec) time.Time {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	// As with cert-manager, renewBefore is ignored if it is not shorter than the certificate's lifetime
+	if spec.RenewBefore != nil && spec.RenewBefore.Duration < lifetime {
+		return cert.NotAfter.Add(-spec.RenewBefore.Duration)
+	}
+	return cert.NotAfter.Add(-lifetime / 3)
+}
+
+func earliestTime(first time.Time, second time.Time) time.Time {
+	if second.Before(first) {
+		return second
+	}
+	return first
+}
@@ -0,0 +1,213 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controller/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controller/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Unless configured otherwise, Warning events are emitted for certificates
+// that expire within this window
+const defaultExpiryWarningWindow = 7 * 24 * time.Hour
+
+const (
+	eventCertificateExpiringType = "CertificateExpiring"
+	eventCertificateExpiredType  = "CertificateExpired"
+	reasonCertificatesValid      = "CertificatesValid"
+)
+
+// Separates the certificates listed in the message of the CertificatesExpiring condition
+const certificateExpiryMessageSeparator = "; "
+
+// certificateExpirySeconds is exported on the manager's metrics endpoint
+var certificateExpirySeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "cryostat_operator_certificate_expiry_seconds",
+	Help: "Time when a certificate used by Cryostat components or agents expires, in seconds since the Unix epoch.",
+}, []string{"cryostat", "cryostat_namespace", "secret", "namespace"})
+
+func init() {
+	metrics.Registry.MustRegister(certificateExpirySeconds)
+}
+
+// reconcileCertificateStatus reads the expiry of each certificate managed for the CR, and publishes
+// it in the CR status and in metrics. Certificates that have expired or expire within the warning
+// window are reported by the CertificatesExpiring condition, and a Warning event is emitted when
+// a certificate is first reported. Only the secrets of certificates are read, so existing
+// certificates are reported even while others are not ready, or could not be set up. Returns
+// the time until a certificate next enters the warning window or expires, or zero if none will.
+func (r *Reconciler) reconcileCertificateStatus(ctx context.Context, cr *model.CryostatInstance) (time.Duration, error) {
+	if !r.IsTLSEnabled(cr) {
+		deleteCertificateExpiryMetrics(cr)
+		cr.Status.Certificates = nil
+		removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeCertificatesExpiring)
+		return 0, nil
+	}
+
+	statuses := []operatorv1beta2.CertificateStatus{}
+	expired := []string{}
+	expiring := []string{}
+	window := certificateExpiryWarningWindow(cr)
+	var requeue time.Duration
+	for _, name := range r.managedCertificateSecrets(cr) {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, name, secret)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				// Not yet created, or not used with this TLS provider
+				continue
+			}
+			return 0, err
+		}
+		certs, err := parseCertificates(secret.Data[corev1.TLSCertKey])
+		if err != nil || len(certs) == 0 {
+			r.Log.V(1).Info("Could not parse certificate", "name", secret.Name, "namespace", secret.Namespace)
+			continue
+		}
+		leaf := certs[0]
+
+		statuses = append(statuses, operatorv1beta2.CertificateStatus{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+			NotAfter:  metav1.NewTime(leaf.NotAfter),
+			Issuer:    leaf.Issuer.String(),
+		})
+		certificateExpirySeconds.With(certificateExpiryLabels(cr, secret.Name, secret.Namespace)).
+			Set(float64(leaf.NotAfter.Unix()))
+
+		remaining := time.Until(leaf.NotAfter)
+		requeue = earliestRequeue(requeue, remaining-window, remaining)
+		if remaining <= 0 {
+			expired = append(expired, fmt.Sprintf("Certificate in secret %s/%s expired at %s", secret.Namespace,
+				secret.Name, leaf.NotAfter.UTC().Format(time.RFC3339)))
+		} else if remaining <= window {
+			expiring = append(expiring, fmt.Sprintf("Certificate in secret %s/%s expires at %s", secret.Namespace,
+				secret.Name, leaf.NotAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	// Stop exporting metrics for certificates that are no longer used
+	for _, previous := range cr.Status.Certificates {
+		if !slices.ContainsFunc(statuses, func(status operatorv1beta2.CertificateStatus) bool {
+			return status.Name == previous.Name && status.Namespace == previous.Namespace
+		}) {
+			certificateExpirySeconds.Delete(certificateExpiryLabels(cr, previous.Name, previous.Namespace))
+		}
+	}
+	cr.Status.Certificates = statuses
+
+	r.setCertificatesExpiringCondition(cr, expired, expiring)
+	return requeue, nil
+}
+
+// setCertificatesExpiringCondition reports the expired and expiring certificates in the
+// CertificatesExpiring condition, and emits a Warning event for each one that the condition
+// did not already report
+func (r *Reconciler) setCertificatesExpiringCondition(cr *model.CryostatInstance, expired []string, expiring []string) {
+	reported := []string{}
+	condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeCertificatesExpiring))
+	if condition != nil && condition.Status == metav1.ConditionTrue {
+		reported = strings.Split(condition.Message, certificateExpiryMessageSeparator)
+	}
+	for _, message := range expired {
+		if !slices.Contains(reported, message) {
+			r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, eventCertificateExpiredType, message)
+		}
+	}
+	for _, message := range expiring {
+		if !slices.Contains(reported, message) {
+			r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, eventCertificateExpiringType, message)
+		}
+	}
+
+	status := metav1.ConditionTrue
+	reason := eventCertificateExpiredType
+	if len(expired) == 0 {
+		reason = eventCertificateExpiringType
+		if len(expiring) == 0 {
+			status = metav1.ConditionFalse
+			reason = reasonCertificatesValid
+		}
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeCertificatesExpiring),
+		Status:             status,
+		ObservedGeneration: cr.Object.GetGeneration(),
+		Reason:             reason,
+		Message:            strings.Join(append(expired, expiring...), certificateExpiryMessageSeparator),
+	})
+}
+
+// managedCertificateSecrets returns the secrets containing the certificates used by Cryostat
+// components and agents, including the copies of agent certificates in each target namespace
+func (r *Reconciler) managedCertificateSecrets(cr *model.CryostatInstance) []types.NamespacedName {
+	secrets := []string{resources.NewCryostatCACert(r.gvk, cr).Spec.SecretName}
+	if certs := common.ProvidedTLSCertificates(cr); certs != nil {
+		// Report the secret provided by the user, rather than the copy containing the keystore
+		secrets = append(secrets, certs.CoreSecretName, certs.DatabaseSecretName, certs.StorageSecretName,
+			certs.ReportsSecretName, certs.AgentProxySecretName)
+	} else {
+		// Other TLS providers use the secret names of the cert-manager certificates
+		secrets = append(secrets, resources.NewCryostatCert(cr, "").Spec.SecretName,
+			resources.NewDatabaseCert(cr).Spec.SecretName, resources.NewStorageCert(cr).Spec.SecretName,
+			resources.NewReportsCert(cr).Spec.SecretName, resources.NewAgentProxyCert(cr).Spec.SecretName)
+	}
+	names := []types.NamespacedName{}
+	for _, name := range secrets {
+		names = append(names, types.NamespacedName{Name: name, Namespace: cr.InstallNamespace})
+	}
+	for _, ns := range cr.TargetNamespaces {
+		names = append(names, types.NamespacedName{Name: common.AgentCertificateName(r.gvk, cr, ns), Namespace: ns})
+	}
+	return names
+}
+
+func certificateExpiryWarningWindow(cr *model.CryostatInstance) time.Duration {
+	if cr.Spec.TLS == nil || cr.Spec.TLS.ExpiryWarningWindow == nil {
+		return defaultExpiryWarningWindow
+	}
+	return cr.Spec.TLS.ExpiryWarningWindow.Duration
+}
+
+func certificateExpiryLabels(cr *model.CryostatInstance, secretName string, namespace string) prometheus.Labels {
+	return prometheus.Labels{
+		"cryostat":           cr.Name,
+		"cryostat_namespace": cr.InstallNamespace,
+		"secret":             secretName,
+		"namespace":          namespace,
+	}
+}
+
+func deleteCertificateExpiryMetrics(cr *model.CryostatInstance) {
+	certificateExpirySeconds.DeletePartialMatch(prometheus.Labels{
+		"cryostat":           cr.Name,
+		"cryostat_namespace": cr.InstallNamespace,
+	})
+}
@@ -15,22 +15,27 @@
 package controller
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controller/common"
 	resources "github.com/cryostatio/cryostat-operator/internal/controller/common/resource_definitions"
 	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
 	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -42,85 +47,106 @@ var errCertManagerMissing = errors.New("cert-manager integration is enabled, but
 const eventCertManagerUnavailableMsg = "cert-manager is not detected in the cluster, please install cert-manager or disable it by setting " +
 	"\"enableCertManager\" in this Cryostat custom resource to false."
 
-func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (*resources.TLSConfig, error) {
+func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (*resources.TLSConfig, time.Duration, error) {
 	// If cert-manager is not available, emit an Event to inform the user
 	available, err := r.certManagerAvailable()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	if !available {
 		r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, eventCertManagerUnavailableType, eventCertManagerUnavailableMsg)
-		return nil, errCertManagerMissing
-	}
-
-	// Create self-signed issuer used to bootstrap CA
-	err = r.createOrUpdateIssuer(ctx, resources.NewSelfSignedIssuer(cr), cr.Object)
-	if err != nil {
-		return nil, err
+		return nil, 0, errCertManagerMissing
 	}
 
-	// Create CA certificate for Cryostat using the self-signed issuer
+	var requeue time.Duration
 	caCert := resources.NewCryostatCACert(r.gvk, cr)
-	err = r.createOrUpdateCertificate(ctx, caCert, cr.Object)
-	if err != nil {
-		return nil, err
-	}
+	externalIssuer := common.TLSIssuerRef(cr) != nil
+	if !externalIssuer {
+		// Create self-signed issuer used to bootstrap CA
+		err = r.createOrUpdateIssuer(ctx, resources.NewSelfSignedIssuer(cr), cr.Object)
+		if err != nil {
+			return nil, 0, err
+		}
 
-	// Create CA issuer using the CA cert just created
-	err = r.createOrUpdateIssuer(ctx, resources.NewCryostatCAIssuer(r.gvk, cr), cr.Object)
-	if err != nil {
-		return nil, err
+		// Create CA certificate for Cryostat using the self-signed issuer
+		err = r.createOrUpdateCertificate(ctx, caCert, cr.Object)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// Create CA issuer using the CA cert just created
+		err = r.createOrUpdateIssuer(ctx, resources.NewCryostatCAIssuer(r.gvk, cr), cr.Object)
+		if err != nil {
+			return nil, 0, err
+		}
+	} else {
+		// Certificates are issued by an external issuer, remove any CA previously created for this CR
+		err = r.deleteCryostatCA(ctx, cr)
+		if err != nil {
+			return nil, 0, err
+		}
 	}
 
 	// Create secret to hold keystore password
 	keystoreSecret := newKeystoreSecret(cr)
 	err = r.createOrUpdateKeystoreSecret(ctx, keystoreSecret, cr.Object)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// Create a certificate for Cryostat signed by the CA just created
 	cryostatCert := resources.NewCryostatCert(cr, keystoreSecret.Name)
 	err = r.createOrUpdateCertificate(ctx, cryostatCert, cr.Object)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// Create a certificate for the reports generator signed by the Cryostat CA
 	reportsCert := resources.NewReportsCert(cr)
 	err = r.createOrUpdateCertificate(ctx, reportsCert, cr.Object)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// Create a certificate for the Cryostat database signed by the Cryostat CA
 	databaseCert := resources.NewDatabaseCert(cr)
 	err = r.createOrUpdateCertificate(ctx, databaseCert, cr.Object)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// Create a certificate for Cryostat storage signed by the Cryostat CA
 	storageCert := resources.NewStorageCert(cr)
 	err = r.createOrUpdateCertificate(ctx, storageCert, cr.Object)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// Create a certificate for the agent proxy signed by the Cryostat CA
 	agentProxyCert := resources.NewAgentProxyCert(cr)
 	err = r.createOrUpdateCertificate(ctx, agentProxyCert, cr.Object)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// List of certificates whose secrets should be owned by this CR
-	certificates := []*certv1.Certificate{caCert, cryostatCert, reportsCert, databaseCert, storageCert, agentProxyCert}
+	certificates := []*certv1.Certificate{cryostatCert, reportsCert, databaseCert, storageCert, agentProxyCert}
 
-	// Get the Cryostat CA certificate bytes from certificate secret
-	caBytes, err := r.getCertficateBytes(ctx, caCert)
-	if err != nil {
-		return nil, err
+	var caBytes []byte
+	if !externalIssuer {
+		certificates = append(certificates, caCert)
+
+		// Get the Cryostat CA certificate bytes from certificate secret
+		caBytes, err = r.getCertficateBytes(ctx, caCert)
+		if err != nil {
+			return nil, 0, err
+		}
+	} else {
+		// Trust the CA of the external issuer, which cert-manager includes with issued certificates
+		caBytes, err = r.getIssuerCABytes(ctx, cryostatCert)
+		if err != nil {
+			return nil, 0, err
+		}
 	}
 
 	tlsConfig := &resources.TLSConfig{
@@ -132,92 +158,120 @@ func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (
 		KeystorePassSecret: cryostatCert.Spec.Keystores.PKCS12.PasswordSecretRef.Name,
 		CACert:             caBytes,
 	}
+	if externalIssuer {
+		tlsConfig.AgentOrganizationalUnit = common.AgentCertificateOrganizationalUnit(r.gvk, cr)
+	}
+
+	// Update owner references of TLS secrets created by cert-manager to ensure proper cleanup
+	err = r.setCertSecretOwner(ctx, cr, certificates...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Keep trusting the previous CA for a while after the CA is re-issued, so that running agents
+	// can still authenticate
+	tlsConfig.CATrustBundle, requeue, err = r.reconcileCATrustBundle(ctx, cr, caBytes)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(tlsConfig.CATrustBundle) > 0 {
+		tlsConfig.PreviousCASecret = newCATrustBundleSecret(cr).Name
+	}
 
 	agentCertsNotReady := []string{}
 	for _, ns := range cr.TargetNamespaces {
-		// Copy Cryostat CA secret in each target namespace
-		if ns != cr.InstallNamespace {
-			namespaceSecret := &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      caCert.Spec.SecretName,
-					Namespace: ns,
-				},
-				Type: corev1.SecretTypeOpaque,
-			}
-			err = r.createOrUpdateCertSecret(ctx, namespaceSecret, caBytes,
-				common.LabelsForTargetNamespaceObject(cr))
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		// Create a certificate for Cryostat agents in each target namespace
+		// Set up agent TLS in each target namespace, continuing with other namespaces on failure
 		agentCert := resources.NewAgentCert(cr, ns, r.gvk)
-		err := r.reconcileAgentCertificate(ctx, agentCert, cr, ns)
+		err := r.reconcileAgentTLS(ctx, cr, agentCert, caCert.Spec.SecretName, caBytes, tlsConfig.CATrustBundle, ns)
 		if err != nil {
 			if err == common.ErrCertNotReady {
-				// Continue with other namespaces if the cert isn't ready
 				agentCertsNotReady = append(agentCertsNotReady, agentCert.Name)
 			} else {
-				return nil, err
+				r.recordTargetNamespaceError(cr, ns, err)
 			}
 		}
-		certificates = append(certificates, agentCert)
+		targetNamespaceStatus(cr, ns).AgentTLSReady = err == nil
 	}
 
 	if len(agentCertsNotReady) > 0 {
-		// One or more agent certificates weren't ready, so log a message and return
+		// Not an error, the namespace status reflects these until they are ready
 		r.Log.Info("Not all agent certificates were ready", "not ready", strings.Join(agentCertsNotReady, ", "))
-		return nil, common.ErrCertNotReady
-	}
-
-	// Update owner references of TLS secrets created by cert-manager to ensure proper cleanup
-	err = r.setCertSecretOwner(ctx, cr, certificates...)
-	if err != nil {
-		return nil, err
 	}
 
 	// Clean up resources from target namespaces that are no longer requested
 	for _, ns := range toDelete(cr) {
-		// Delete any Cryostat CA secret copies in removed namespaces
-		if ns != cr.InstallNamespace {
-			namespaceSecret := &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      caCert.Spec.SecretName,
-					Namespace: ns,
-				},
-			}
-			err = r.deleteSecret(ctx, namespaceSecret)
-			if err != nil {
-				return nil, err
-			}
+		err := r.cleanUpAgentTLS(ctx, cr, caCert.Spec.SecretName, ns)
+		if err != nil {
+			r.recordTargetNamespaceError(cr, ns, err)
 		}
+	}
 
-		// Delete any agent certificates removed target namespaces
-		agentCert := resources.NewAgentCert(cr, ns, r.gvk)
+	return tlsConfig, requeue, nil
+}
 
-		// Delete namespace copy
-		if ns != cr.InstallNamespace {
-			namespaceAgentSecret := &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      agentCert.Spec.SecretName,
-					Namespace: ns,
-				},
-			}
-			err = r.deleteSecret(ctx, namespaceAgentSecret)
-			if err != nil {
-				return nil, err
-			}
+func (r *Reconciler) reconcileAgentTLS(ctx context.Context, cr *model.CryostatInstance, agentCert *certv1.Certificate,
+	caSecretName string, caBytes []byte, caBundle []byte, namespace string) error {
+	// Copy Cryostat CA secret in each target namespace
+	if namespace != cr.InstallNamespace {
+		namespaceSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      caSecretName,
+				Namespace: namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
 		}
+		err := r.createOrUpdateCertSecret(ctx, namespaceSecret, trustedCABytes(caBytes, caBundle),
+			common.LabelsForTargetNamespaceObject(cr))
+		if err != nil {
+			return err
+		}
+	}
 
-		// Delete certificate with original secret
-		err := r.deleteCertWithSecret(ctx, agentCert)
+	// Create a certificate for Cryostat agents in each target namespace
+	err := r.reconcileAgentCertificate(ctx, agentCert, cr, namespace, caBundle)
+	if err != nil {
+		return err
+	}
+
+	// Update owner reference of the TLS secret created by cert-manager to ensure proper cleanup
+	return r.setCertSecretOwner(ctx, cr, agentCert)
+}
+
+func (r *Reconciler) cleanUpAgentTLS(ctx context.Context, cr *model.CryostatInstance, caSecretName string,
+	namespace string) error {
+	// Delete any Cryostat CA secret copies in removed namespaces
+	if namespace != cr.InstallNamespace {
+		namespaceSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      caSecretName,
+				Namespace: namespace,
+			},
+		}
+		err := r.deleteSecret(ctx, namespaceSecret)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return tlsConfig, nil
+	// Delete any agent certificates removed target namespaces
+	agentCert := resources.NewAgentCert(cr, namespace, r.gvk)
+
+	// Delete namespace copy
+	if namespace != cr.InstallNamespace {
+		namespaceAgentSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      agentCert.Spec.SecretName,
+				Namespace: namespace,
+			},
+		}
+		err := r.deleteSecret(ctx, namespaceAgentSecret)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Delete certificate with original secret
+	return r.deleteCertWithSecret(ctx, agentCert)
 }
 
 func (r *Reconciler) finalizeTLS(ctx context.Context, cr *model.CryostatInstance) error {
@@ -318,28 +372,19 @@ func (r *Reconciler) certManagerAvailable() (bool, error) {
 }
 
 func (r *Reconciler) createOrUpdateIssuer(ctx context.Context, issuer *certv1.Issuer, owner metav1.Object) error {
-	issuerSpec := issuer.Spec.DeepCopy()
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, issuer, func() error {
-		if err := controllerutil.SetControllerReference(owner, issuer, r.Scheme); err != nil {
+	// Check if the issuer's CA has changed
+	existing := &certv1.Issuer{}
+	err := r.Get(ctx, types.NamespacedName{Name: issuer.Name, Namespace: issuer.Namespace}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	} else if err == nil && r.issuerCAChanged(existing.Spec.CA, issuer.Spec.CA) {
+		// Issuer CA has changed, delete all certificates the previous CA issued
+		err := r.deleteCertChain(ctx, issuer, issuer.Spec.CA.SecretName, owner)
+		if err != nil {
 			return err
 		}
-		// Check if the issuer's CA has changed
-		if !issuer.CreationTimestamp.IsZero() && r.issuerCAChanged(issuer.Spec.CA, issuerSpec.CA) {
-			// Issuer CA has changed, delete all certificates the previous CA issued
-			err := r.deleteCertChain(ctx, issuer.Namespace, issuerSpec.CA.SecretName, owner)
-			if err != nil {
-				return err
-			}
-		}
-		// Update Issuer spec
-		issuer.Spec = *issuerSpec
-		return nil
-	})
-	if err != nil {
-		return err
 	}
-	r.Log.Info(fmt.Sprintf("Issuer %s", op), "name", issuer.Name, "namespace", issuer.Namespace)
-	return nil
+	return r.applyObject(ctx, issuer, owner)
 }
 
 func (r *Reconciler) issuerCAChanged(current *certv1.CAIssuer, updated *certv1.CAIssuer) bool {
@@ -361,19 +406,21 @@ func (r *Reconciler) issuerCAChanged(current *certv1.CAIssuer, updated *certv1.C
 	return false
 }
 
-func (r *Reconciler) deleteCertChain(ctx context.Context, namespace string, caSecretName string, owner metav1.Object) error {
+func (r *Reconciler) deleteCertChain(ctx context.Context, issuer *certv1.Issuer, caSecretName string, owner metav1.Object) error {
 	// Look up all certificates in this namespace
 	certs := &certv1.CertificateList{}
 	err := r.List(ctx, certs, &ctrlclient.ListOptions{
-		Namespace: namespace,
+		Namespace: issuer.Namespace,
 	})
 	if err != nil {
 		return err
 	}
 
 	for i, cert := range certs.Items {
-		// Is the certificate owned by this CR, and not the CA itself?
-		if metav1.IsControlledBy(&certs.Items[i], owner) && cert.Spec.SecretName != caSecretName {
+		// Is the certificate owned by this CR, issued by this issuer, and not the CA itself?
+		// Certificates from external issuers are left untouched.
+		if metav1.IsControlledBy(&certs.Items[i], owner) && issuedBy(&certs.Items[i], issuer) &&
+			cert.Spec.SecretName != caSecretName {
 			err := r.deleteCertWithSecret(ctx, &certs.Items[i])
 			if err != nil {
 				return err
@@ -384,6 +431,12 @@ func (r *Reconciler) deleteCertChain(ctx context.Context, namespace string, caSe
 	return nil
 }
 
+func issuedBy(cert *certv1.Certificate, issuer *certv1.Issuer) bool {
+	ref := cert.Spec.IssuerRef
+	return ref.Name == issuer.Name && (ref.Kind == "" || ref.Kind == certv1.IssuerKind) &&
+		(ref.Group == "" || ref.Group == certv1.SchemeGroupVersion.Group)
+}
+
 func (r *Reconciler) deleteCertWithSecret(ctx context.Context, cert *certv1.Certificate) error {
 	// Clean up secret referenced by the cert
 	secret := &corev1.Secret{
@@ -406,7 +459,8 @@ func (r *Reconciler) deleteCertWithSecret(ctx context.Context, cert *certv1.Cert
 	return nil
 }
 
-func (r *Reconciler) reconcileAgentCertificate(ctx context.Context, cert *certv1.Certificate, cr *model.CryostatInstance, namespace string) error {
+func (r *Reconciler) reconcileAgentCertificate(ctx context.Context, cert *certv1.Certificate, cr *model.CryostatInstance, namespace string,
+	caBundle []byte) error {
 	// Create the Agent certificate in the install namespace
 	err := r.createOrUpdateCertificate(ctx, cert, cr.Object)
 	if err != nil {
@@ -429,7 +483,7 @@ func (r *Reconciler) reconcileAgentCertificate(ctx context.Context, cert *certv1
 		err = r.createOrUpdateSecret(ctx, targetSecret, nil, func() error {
 			common.MergeLabelsAndAnnotations(&targetSecret.ObjectMeta,
 				common.LabelsForTargetNamespaceObject(cr), map[string]string{})
-			targetSecret.Data = secret.Data
+			targetSecret.Data = withCABundle(secret.Data, caBundle)
 			return nil
 		})
 		if err != nil {
@@ -439,8 +493,6 @@ func (r *Reconciler) reconcileAgentCertificate(ctx context.Context, cert *certv1
 	return nil
 }
 
-var errCertificateModified error = errors.New("certificate has been modified")
-
 func (r *Reconciler) createOrUpdateCertificate(ctx context.Context, cert *certv1.Certificate, owner metav1.Object) error {
 	supportsFields, err := r.supportsPKCS12Profile(ctx, cert)
 	if err != nil {
@@ -450,33 +502,16 @@ func (r *Reconciler) createOrUpdateCertificate(ctx context.Context, cert *certv1
 		cert = legacyCertificate(cert)
 	}
 	certCopy := cert.DeepCopy()
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, cert, func() error {
-		common.MergeLabelsAndAnnotations(&cert.ObjectMeta, certCopy.Labels, certCopy.Annotations)
-
-		if owner != nil {
-			if err := controllerutil.SetControllerReference(owner, cert, r.Scheme); err != nil {
-				return err
-			}
-		}
-
-		if cert.CreationTimestamp.IsZero() {
-			cert.Spec = certCopy.Spec
-		} else {
-			if !cmp.Equal(cert.Spec, certCopy.Spec) {
-				return errCertificateModified
-			}
-		}
 
-		return nil
-	})
-	if err != nil {
-		if err == errCertificateModified {
-			return r.recreateCertificate(ctx, certCopy, owner)
-		}
+	// Recreate the certificate along with its secret if the spec has been modified
+	existing := &certv1.Certificate{}
+	err = r.Get(ctx, types.NamespacedName{Name: cert.Name, Namespace: cert.Namespace}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
 		return err
+	} else if err == nil && !cmp.Equal(existing.Spec, cert.Spec) {
+		return r.recreateCertificate(ctx, certCopy, owner)
 	}
-	r.Log.Info(fmt.Sprintf("Certificate %s", op), "name", cert.Name, "namespace", cert.Namespace)
-	return nil
+	return r.applyObject(ctx, cert, owner)
 }
 
 func (r *Reconciler) supportsPKCS12Profile(ctx context.Context, cert *certv1.Certificate) (bool, error) {
@@ -527,43 +562,74 @@ func newKeystoreSecret(cr *model.CryostatInstance) *corev1.Secret {
 }
 
 func (r *Reconciler) createOrUpdateKeystoreSecret(ctx context.Context, secret *corev1.Secret, owner metav1.Object) error {
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
-		if err := controllerutil.SetControllerReference(owner, secret, r.Scheme); err != nil {
-			return err
-		}
-
-		// Don't modify secret data, since the password is pseudorandomly generated
-		if secret.CreationTimestamp.IsZero() {
-			secret.StringData = map[string]string{
-				constants.KeystorePassSecretKey: r.GenPasswd(20),
-			}
-		}
-		return nil
-	})
-	if err != nil {
+	// Don't modify the password once created, since it is pseudorandomly generated
+	existing := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
 		return err
 	}
-	r.Log.Info(fmt.Sprintf("Secret %s", op), "name", secret.Name, "namespace", secret.Namespace)
-	return nil
+	password, ok := existing.Data[constants.KeystorePassSecretKey]
+	if !ok {
+		password = []byte(r.GenPasswd(20))
+	}
+	secret.Data = map[string][]byte{
+		constants.KeystorePassSecretKey: password,
+	}
+	return r.applyObject(ctx, secret, owner)
 }
 
 func (r *Reconciler) createOrUpdateCertSecret(ctx context.Context, secret *corev1.Secret, cert []byte,
 	labels map[string]string) error {
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
-		common.MergeLabelsAndAnnotations(&secret.ObjectMeta, labels, map[string]string{})
-		if secret.Data == nil {
-			secret.Data = map[string][]byte{}
-		}
-		secret.Data[corev1.TLSCertKey] = cert
-		return nil
-	})
+	common.MergeLabelsAndAnnotations(&secret.ObjectMeta, labels, map[string]string{})
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey: cert,
+	}
+	return r.applyObject(ctx, secret, nil)
+}
+
+func (r *Reconciler) deleteCryostatCA(ctx context.Context, cr *model.CryostatInstance) error {
+	err := r.deleteOwnedIssuers(ctx, cr)
 	if err != nil {
 		return err
 	}
-	r.Log.Info(fmt.Sprintf("Secret %s", op), "name", secret.Name, "namespace", secret.Namespace)
+	return r.deleteCertWithSecret(ctx, resources.NewCryostatCACert(r.gvk, cr))
+}
+
+// deleteOwnedIssuers deletes the issuers created by the operator for the CR. Issuers that
+// are not controlled by the CR, such as one referenced in the CR, are left untouched.
+func (r *Reconciler) deleteOwnedIssuers(ctx context.Context, cr *model.CryostatInstance) error {
+	for _, issuer := range []*certv1.Issuer{resources.NewCryostatCAIssuer(r.gvk, cr), resources.NewSelfSignedIssuer(cr)} {
+		err := r.Get(ctx, types.NamespacedName{Name: issuer.Name, Namespace: issuer.Namespace}, issuer)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !metav1.IsControlledBy(issuer, cr.Object) {
+			continue
+		}
+		err = r.Delete(ctx, issuer)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+		r.Log.Info("deleted Issuer", "name", issuer.Name, "namespace", issuer.Namespace)
+	}
 	return nil
 }
 
+func (r *Reconciler) getIssuerCABytes(ctx context.Context, cert *certv1.Certificate) ([]byte, error) {
+	secret, err := r.GetCertificateSecret(ctx, cert)
+	if err != nil {
+		return nil, err
+	}
+	caBytes := secret.Data[constants.CAKey]
+	if len(caBytes) == 0 {
+		return nil, fmt.Errorf("certificate secret %s does not contain the issuer's CA in %s", secret.Name, constants.CAKey)
+	}
+	return caBytes, nil
+}
+
 func (r *Reconciler) getCertficateBytes(ctx context.Context, cert *certv1.Certificate) ([]byte, error) {
 	secret, err := r.GetCertificateSecret(ctx, cert)
 	if err != nil {
@@ -584,3 +650,201 @@ func (r *Reconciler) deleteCertificate(ctx context.Context, cert *certv1.Certifi
 	r.Log.Info("deleted Certificate", "name", cert.Name, "namespace", cert.Namespace)
 	return nil
 }
+
+// Unless configured otherwise, the previous CA is trusted for this long after the CA is re-issued
+const defaultCARotationOverlap = 7 * 24 * time.Hour
+
+// How often the workloads still using the previous CA are checked during a CA rotation
+const caRotationRefreshInterval = 5 * time.Minute
+
+const (
+	// Keys of the CA trust bundle secret holding the current and previous CAs
+	caBundleCurrentKey  = "current-ca.crt"
+	caBundlePreviousKey = constants.PreviousCAKey
+	// Annotation on the CA trust bundle secret with the time the current CA was first seen
+	annotationCARotationStart = "io.cryostat/ca-rotation-start"
+)
+
+const (
+	eventCARotationStartedType   = "CARotationStarted"
+	eventCARotationCompletedType = "CARotationCompleted"
+)
+
+func newCATrustBundleSecret(cr *model.CryostatInstance) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-ca-bundle",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+}
+
+// reconcileCATrustBundle tracks the CA that issues certificates for Cryostat components and agents. When
+// the CA is re-issued, the previous CA remains trusted alongside the new one until the overlap period ends.
+// This returns the PEM-encoded bundle of both CAs while a rotation is in progress, or nil otherwise, along
+// with when the rotation should next be checked.
+func (r *Reconciler) reconcileCATrustBundle(ctx context.Context, cr *model.CryostatInstance,
+	caBytes []byte) ([]byte, time.Duration, error) {
+	secret := newCATrustBundleSecret(cr)
+	var start time.Time
+	var bundle []byte
+	var started, completed bool
+	err := r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
+		now := time.Now()
+		current := secret.Data[caBundleCurrentKey]
+		previous := secret.Data[caBundlePreviousKey]
+		start, _ = time.Parse(time.RFC3339, secret.Annotations[annotationCARotationStart])
+		if len(current) > 0 && !bytes.Equal(current, caBytes) {
+			// The CA has been re-issued, keep trusting the CA it replaces
+			previous = current
+			start = now
+			started = true
+		} else if len(current) == 0 {
+			start = now
+		}
+
+		if len(previous) > 0 && (!now.Before(start.Add(caRotationOverlap(cr))) || caExpired(previous)) {
+			// The overlap has ended, stop trusting the previous CA
+			previous = nil
+			completed = true
+		}
+
+		secret.Data = map[string][]byte{
+			caBundleCurrentKey: caBytes,
+			constants.CAKey:    caBytes,
+		}
+		if len(previous) > 0 {
+			// Keep the current CA first, for clients that only read one certificate
+			bundle = append(append(bytes.TrimSpace(bytes.Clone(caBytes)), '\n'), previous...)
+			secret.Data[caBundlePreviousKey] = previous
+			secret.Data[constants.CAKey] = bundle
+		}
+		common.MergeLabelsAndAnnotations(&secret.ObjectMeta, map[string]string{},
+			map[string]string{annotationCARotationStart: start.UTC().Format(time.RFC3339)})
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if started {
+		r.EventRecorder.Event(cr.Object, corev1.EventTypeNormal, eventCARotationStartedType,
+			fmt.Sprintf("The Cryostat CA has been re-issued. The previous CA is trusted until %s.",
+				start.Add(caRotationOverlap(cr)).UTC().Format(time.RFC3339)))
+	} else if completed {
+		r.EventRecorder.Event(cr.Object, corev1.EventTypeNormal, eventCARotationCompletedType,
+			"The previous Cryostat CA is no longer trusted.")
+	}
+
+	if bundle == nil {
+		cr.Status.CARotation = nil
+		return nil, 0, nil
+	}
+	pending, err := r.caRotationPendingWorkloads(ctx, cr, start)
+	if err != nil {
+		return nil, 0, err
+	}
+	overlapEnd := start.Add(caRotationOverlap(cr))
+	cr.Status.CARotation = &operatorv1beta2.CARotationStatus{
+		StartTime:        metav1.NewTime(start),
+		OverlapEndTime:   metav1.NewTime(overlapEnd),
+		PendingWorkloads: pending,
+	}
+	return bundle, earliestRequeue(time.Until(overlapEnd), caRotationRefreshInterval), nil
+}
+
+// caRotationPendingWorkloads returns the workloads in target namespaces with Cryostat agents
+// that were injected before the CA rotation started
+func (r *Reconciler) caRotationPendingWorkloads(ctx context.Context, cr *model.CryostatInstance,
+	start time.Time) ([]operatorv1beta2.CARotationWorkload, error) {
+	workloads := []operatorv1beta2.CARotationWorkload{}
+	for _, ns := range cr.TargetNamespaces {
+		// Only fetch metadata, to avoid caching entire pods
+		pods := &metav1.PartialObjectMetadataList{}
+		pods.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+		err := r.List(ctx, pods, ctrlclient.InNamespace(ns), ctrlclient.MatchingLabels{
+			constants.AgentLabelCryostatName:      cr.Name,
+			constants.AgentLabelCryostatNamespace: cr.InstallNamespace,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		indices := map[string]int{}
+		for _, pod := range pods.Items {
+			if !pod.CreationTimestamp.Time.Before(start) {
+				continue
+			}
+			kind, name := podWorkload(&pod)
+			key := kind + "/" + name
+			if i, ok := indices[key]; ok {
+				workloads[i].Pods++
+				continue
+			}
+			indices[key] = len(workloads)
+			workloads = append(workloads, operatorv1beta2.CARotationWorkload{
+				Namespace: ns,
+				Kind:      kind,
+				Name:      name,
+				Pods:      1,
+			})
+		}
+	}
+	return workloads, nil
+}
+
+// podWorkload returns the kind and name of the workload that created the pod. Pods created
+// by a Deployment's ReplicaSet are reported as part of the Deployment.
+func podWorkload(pod *metav1.PartialObjectMetadata) (string, string) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "Pod", pod.Name
+	}
+	hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	if ref.Kind == "ReplicaSet" && len(hash) > 0 && strings.HasSuffix(ref.Name, "-"+hash) {
+		return "Deployment", strings.TrimSuffix(ref.Name, "-"+hash)
+	}
+	return ref.Kind, ref.Name
+}
+
+// deleteCATrustBundle stops tracking rotations of the Cryostat CA, for when the operator
+// no longer manages the CA
+func (r *Reconciler) deleteCATrustBundle(ctx context.Context, cr *model.CryostatInstance) error {
+	cr.Status.CARotation = nil
+	return r.deleteSecret(ctx, newCATrustBundleSecret(cr))
+}
+
+func caRotationOverlap(cr *model.CryostatInstance) time.Duration {
+	if cr.Spec.TLS == nil || cr.Spec.TLS.CARotationOverlap == nil {
+		return defaultCARotationOverlap
+	}
+	return cr.Spec.TLS.CARotationOverlap.Duration
+}
+
+func caExpired(caBytes []byte) bool {
+	certs, err := parseCertificates(caBytes)
+	if err != nil || len(certs) == 0 {
+		return false
+	}
+	return !time.Now().Before(certs[0].NotAfter)
+}
+
+// trustedCABytes returns the CAs that agents should trust, including the previous CA during a rotation
+func trustedCABytes(caBytes []byte, bundle []byte) []byte {
+	if len(bundle) > 0 {
+		return bundle
+	}
+	return caBytes
+}
+
+// withCABundle returns a copy of the certificate secret data that trusts the previous CA during a rotation
+func withCABundle(data map[string][]byte, bundle []byte) map[string][]byte {
+	if len(bundle) == 0 {
+		return data
+	}
+	result := make(map[string][]byte, len(data))
+	for k, v := range data {
+		result[k] = v
+	}
+	result[constants.CAKey] = bundle
+	return result
+}
@@ -22,11 +22,13 @@ import (
 	"fmt"
 	"hash/fnv"
 	"math/rand"
+	"net"
 	"os"
 	"regexp"
 	"slices"
 	"strings"
 
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
 	corev1 "k8s.io/api/core/v1"
@@ -50,6 +52,11 @@ type OSUtils interface {
 
 type DefaultOSUtils struct{}
 
+// Dialer is an abstraction on establishing network connections, implemented by net.Dialer
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
 // GetEnv returns the value of the environment variable with the provided name. If no such
 // variable exists, the empty string is returned.
 func (o *DefaultOSUtils) GetEnv(name string) string {
@@ -178,6 +185,43 @@ func GetPullPolicy(imageTag string) corev1.PullPolicy {
 	return corev1.PullIfNotPresent
 }
 
+// ResolveImage returns the image reference to use for a component, applying the
+// overrides in config, if any, to the default image reference.
+func ResolveImage(config *operatorv1beta2.ImageConfig, defaultImage string) string {
+	if config == nil {
+		return defaultImage
+	}
+	image := defaultImage
+	if len(config.Image) > 0 {
+		image = config.Image
+	}
+	if len(config.Digest) > 0 {
+		image = imageRepository(image) + "@" + config.Digest
+	}
+	return image
+}
+
+// imageRepository returns the image reference without any tag or digest
+func imageRepository(image string) string {
+	if idx := strings.Index(image, "@"); idx >= 0 {
+		image = image[:idx]
+	}
+	// A colon after the last slash separates the tag, otherwise it may be a registry port
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		image = image[:idx]
+	}
+	return image
+}
+
+// GetImagePullPolicy returns the pull policy specified in config, if any,
+// and otherwise a pull policy based on the image provided.
+func GetImagePullPolicy(config *operatorv1beta2.ImageConfig, image string) corev1.PullPolicy {
+	if config != nil && config.PullPolicy != nil {
+		return *config.PullPolicy
+	}
+	return GetPullPolicy(image)
+}
+
 // PopulateResourceRequest configures ResourceRequirements, applying defaults and checking that
 // requests are not larger than limits
 func PopulateResourceRequest(resources *corev1.ResourceRequirements, defaultCpuRequest, defaultMemoryRequest,
@@ -292,6 +336,13 @@ func AnnotateWithObjRefHashes(ctx context.Context, client ctrlclient.Client, nam
 	return nil
 }
 
+// ObjRefHashesEqual returns whether two pod templates were annotated by AnnotateWithObjRefHashes
+// with the same hashes of their referenced secrets and config maps
+func ObjRefHashesEqual(template *corev1.PodTemplateSpec, other *corev1.PodTemplateSpec) bool {
+	return template.Annotations[annotationSecretHash] == other.Annotations[annotationSecretHash] &&
+		template.Annotations[annotationConfigMapHash] == other.Annotations[annotationConfigMapHash]
+}
+
 func hashSecrets(ctx context.Context, client ctrlclient.Client, namespace string, secrets *objectSet[string]) (*string, error) {
 	// Collect the JSON of all secret data, sorted by object name
 	combinedJSON := []byte{}
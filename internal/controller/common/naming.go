@@ -30,3 +30,9 @@ func AgentGatewayServiceName(cr *model.CryostatInstance) string {
 func AgentCertificateName(gvk *schema.GroupVersionKind, cr *model.CryostatInstance, targetNamespace string) string {
 	return ClusterUniqueNameWithPrefixTargetNS(gvk, "agent", cr.Name, cr.InstallNamespace, targetNamespace)
 }
+
+// AgentCertificateOrganizationalUnit returns the organizational unit in the subject of agent
+// certificates from an external issuer, which identifies the Cryostat instance they authenticate to
+func AgentCertificateOrganizationalUnit(gvk *schema.GroupVersionKind, cr *model.CryostatInstance) string {
+	return AgentCallbackServiceName(gvk, cr)
+}
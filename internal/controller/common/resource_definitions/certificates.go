@@ -19,6 +19,7 @@ import (
 
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	certMeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controller/common"
 	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
@@ -68,7 +69,10 @@ func NewCryostatCACert(gvk *schema.GroupVersionKind, cr *model.CryostatInstance)
 			IssuerRef: certMeta.ObjectReference{
 				Name: cr.Name + "-self-signed",
 			},
-			IsCA: true,
+			IsCA:        true,
+			PrivateKey:  certificatePrivateKey(cr),
+			Duration:    certificateLifetime(cr, caCertificate).Duration,
+			RenewBefore: certificateLifetime(cr, caCertificate).RenewBefore,
 		},
 	}
 }
@@ -99,9 +103,10 @@ func NewCryostatCert(cr *model.CryostatInstance, keystoreSecretName string) *cer
 					Profile: certv1.Modern2023PKCS12Profile,
 				},
 			},
-			IssuerRef: certMeta.ObjectReference{
-				Name: cr.Name + "-ca",
-			},
+			IssuerRef:   componentIssuerRef(cr),
+			PrivateKey:  certificatePrivateKey(cr),
+			Duration:    certificateLifetime(cr, serverCertificate).Duration,
+			RenewBefore: certificateLifetime(cr, serverCertificate).RenewBefore,
 			Usages: append(certv1.DefaultKeyUsages(),
 				certv1.UsageServerAuth,
 				certv1.UsageClientAuth,
@@ -123,10 +128,11 @@ func NewReportsCert(cr *model.CryostatInstance) *certv1.Certificate {
 				fmt.Sprintf("%s-reports.%s.svc", cr.Name, cr.InstallNamespace),
 				fmt.Sprintf("%s-reports.%s.svc.cluster.local", cr.Name, cr.InstallNamespace),
 			},
-			SecretName: cr.Name + "-reports-tls",
-			IssuerRef: certMeta.ObjectReference{
-				Name: cr.Name + "-ca",
-			},
+			SecretName:  cr.Name + "-reports-tls",
+			IssuerRef:   componentIssuerRef(cr),
+			PrivateKey:  certificatePrivateKey(cr),
+			Duration:    certificateLifetime(cr, serverCertificate).Duration,
+			RenewBefore: certificateLifetime(cr, serverCertificate).RenewBefore,
 			Usages: append(certv1.DefaultKeyUsages(),
 				certv1.UsageServerAuth,
 			),
@@ -147,10 +153,11 @@ func NewDatabaseCert(cr *model.CryostatInstance) *certv1.Certificate {
 				fmt.Sprintf("%s-database.%s.svc", cr.Name, cr.InstallNamespace),
 				fmt.Sprintf("%s-database.%s.svc.cluster.local", cr.Name, cr.InstallNamespace),
 			},
-			SecretName: cr.Name + "-database-tls",
-			IssuerRef: certMeta.ObjectReference{
-				Name: cr.Name + "-ca",
-			},
+			SecretName:  cr.Name + "-database-tls",
+			IssuerRef:   componentIssuerRef(cr),
+			PrivateKey:  certificatePrivateKey(cr),
+			Duration:    certificateLifetime(cr, serverCertificate).Duration,
+			RenewBefore: certificateLifetime(cr, serverCertificate).RenewBefore,
 			Usages: append(certv1.DefaultKeyUsages(),
 				certv1.UsageServerAuth,
 			),
@@ -171,10 +178,11 @@ func NewStorageCert(cr *model.CryostatInstance) *certv1.Certificate {
 				fmt.Sprintf("%s-storage.%s.svc", cr.Name, cr.InstallNamespace),
 				fmt.Sprintf("%s-storage.%s.svc.cluster.local", cr.Name, cr.InstallNamespace),
 			},
-			SecretName: cr.Name + "-storage-tls",
-			IssuerRef: certMeta.ObjectReference{
-				Name: cr.Name + "-ca",
-			},
+			SecretName:  cr.Name + "-storage-tls",
+			IssuerRef:   componentIssuerRef(cr),
+			PrivateKey:  certificatePrivateKey(cr),
+			Duration:    certificateLifetime(cr, serverCertificate).Duration,
+			RenewBefore: certificateLifetime(cr, serverCertificate).RenewBefore,
 			Usages: append(certv1.DefaultKeyUsages(),
 				certv1.UsageServerAuth,
 				certv1.UsageClientAuth,
@@ -186,6 +194,14 @@ func NewStorageCert(cr *model.CryostatInstance) *certv1.Certificate {
 func NewAgentCert(cr *model.CryostatInstance, namespace string, gvk *schema.GroupVersionKind) *certv1.Certificate {
 	svcName := common.AgentCallbackServiceName(gvk, cr)
 	name := common.AgentCertificateName(gvk, cr, namespace)
+	var subject *certv1.X509Subject
+	if common.TLSIssuerRef(cr) != nil {
+		// An external issuer may also issue certificates to clients other than agents, so the
+		// agent proxy only accepts those with this subject
+		subject = &certv1.X509Subject{
+			OrganizationalUnits: []string{common.AgentCertificateOrganizationalUnit(gvk, cr)},
+		}
+	}
 	return &certv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -193,13 +209,15 @@ func NewAgentCert(cr *model.CryostatInstance, namespace string, gvk *schema.Grou
 		},
 		Spec: certv1.CertificateSpec{
 			CommonName: constants.AgentsTLSCommonName,
+			Subject:    subject,
 			DNSNames: []string{
 				fmt.Sprintf("*.%s.%s.svc", svcName, namespace),
 			},
-			SecretName: name,
-			IssuerRef: certMeta.ObjectReference{
-				Name: cr.Name + "-ca",
-			},
+			SecretName:  name,
+			IssuerRef:   componentIssuerRef(cr),
+			PrivateKey:  certificatePrivateKey(cr),
+			Duration:    certificateLifetime(cr, agentCertificate).Duration,
+			RenewBefore: certificateLifetime(cr, agentCertificate).RenewBefore,
 			Usages: append(certv1.DefaultKeyUsages(),
 				certv1.UsageServerAuth,
 				certv1.UsageClientAuth,
@@ -222,13 +240,78 @@ func NewAgentProxyCert(cr *model.CryostatInstance) *certv1.Certificate {
 				fmt.Sprintf("%s.%s.svc", svcName, cr.InstallNamespace),
 				fmt.Sprintf("%s.%s.svc.cluster.local", svcName, cr.InstallNamespace),
 			},
-			SecretName: cr.Name + "-agent-tls",
-			IssuerRef: certMeta.ObjectReference{
-				Name: cr.Name + "-ca",
-			},
+			SecretName:  cr.Name + "-agent-tls",
+			IssuerRef:   componentIssuerRef(cr),
+			PrivateKey:  certificatePrivateKey(cr),
+			Duration:    certificateLifetime(cr, serverCertificate).Duration,
+			RenewBefore: certificateLifetime(cr, serverCertificate).RenewBefore,
 			Usages: append(certv1.DefaultKeyUsages(),
 				certv1.UsageServerAuth,
 			),
 		},
 	}
 }
+
+// componentIssuerRef returns the issuer of certificates for Cryostat components and agents,
+// which is either the issuer referenced in the CR or the Cryostat CA issuer
+func componentIssuerRef(cr *model.CryostatInstance) certMeta.ObjectReference {
+	if ref := common.TLSIssuerRef(cr); ref != nil {
+		return certMeta.ObjectReference{
+			Name:  ref.Name,
+			Kind:  ref.Kind,
+			Group: ref.Group,
+		}
+	}
+	return certMeta.ObjectReference{
+		Name: cr.Name + "-ca",
+	}
+}
+
+type certificateClass int
+
+const (
+	caCertificate certificateClass = iota
+	serverCertificate
+	agentCertificate
+)
+
+// certificatePrivateKey returns the private key options for certificates,
+// or nil to use the defaults of the certificate provider
+func certificatePrivateKey(cr *model.CryostatInstance) *certv1.CertificatePrivateKey {
+	opts := common.TLSPrivateKeyOptions(cr)
+	if opts == nil {
+		return nil
+	}
+	key := &certv1.CertificatePrivateKey{
+		RotationPolicy: certv1.PrivateKeyRotationPolicy(opts.RotationPolicy),
+	}
+	switch opts.Algorithm {
+	case operatorv1beta2.TLSPrivateKeyAlgorithmECDSA:
+		key.Algorithm = certv1.ECDSAKeyAlgorithm
+	case operatorv1beta2.TLSPrivateKeyAlgorithmRSA:
+		key.Algorithm = certv1.RSAKeyAlgorithm
+	}
+	if opts.Size != nil {
+		key.Size = int(*opts.Size)
+	}
+	return key
+}
+
+// certificateLifetime returns the lifetime configured in the CR for the class of certificate
+func certificateLifetime(cr *model.CryostatInstance, class certificateClass) *operatorv1beta2.CertificateLifetime {
+	var lifetime *operatorv1beta2.CertificateLifetime
+	if cr.Spec.TLS != nil && cr.Spec.TLS.Lifetimes != nil {
+		switch class {
+		case caCertificate:
+			lifetime = cr.Spec.TLS.Lifetimes.CA
+		case serverCertificate:
+			lifetime = cr.Spec.TLS.Lifetimes.Server
+		case agentCertificate:
+			lifetime = cr.Spec.TLS.Lifetimes.Agent
+		}
+	}
+	if lifetime == nil {
+		return &operatorv1beta2.CertificateLifetime{}
+	}
+	return lifetime
+}
@@ -0,0 +1,423 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource_definitions
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	common "github.com/cryostatio/cryostat-operator/internal/controller/common"
+	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	DefaultDatabaseBackupRetention int32  = 7
+	DefaultDatabaseBackupBucket    string = "cryostat-database-backups"
+	databaseBackupScratchPath      string = "/backup"
+	databaseBackupTLSName          string = "database-backup-tls"
+)
+
+// Shell functions shared by the backup and restore scripts. Object storage requests
+// are signed by curl, so the database image is sufficient to run these jobs. Signing
+// requires curl 7.75.0 or later, so the scripts fail early if the image has an older curl.
+const databaseBackupScriptCommon = `set -eu
+curl_version="$(curl --version 2>/dev/null | head -n 1 | cut -d ' ' -f 2)"
+case "${curl_version}" in
+  [0-9]*.[0-9]*) ;;
+  *) curl_version="" ;;
+esac
+curl_major="${curl_version%%[!0-9]*}"
+curl_minor="${curl_version#*.}"
+curl_minor="${curl_minor%%[!0-9]*}"
+if [ -z "${curl_version}" ] || [ "${curl_major}" -lt 7 ] || { [ "${curl_major}" -eq 7 ] && [ "${curl_minor}" -lt 75 ]; }; then
+  echo "curl 7.75.0 or later is required to sign object storage requests, but found ${curl_version:-none}" >&2
+  exit 1
+fi
+s3() {
+  method="$1"
+  object="$2"
+  shift 2
+  set -- --fail --silent --show-error --aws-sigv4 "aws:amz:${S3_REGION}:s3" \
+    --user "${S3_ACCESS_KEY}:${S3_SECRET_KEY}" -X "${method}" "$@" "${S3_BUCKET_URL}${object}"
+  if [ "${S3_TLS_TRUST_ALL}" = "true" ]; then
+    set -- --insecure "$@"
+  fi
+  curl "$@"
+}
+`
+
+// DatabaseBackupScript dumps the database, uploads the dump to object storage, then
+// deletes all but the most recent backups. Backups are listed one page at a time,
+// following the continuation token until the listing is no longer truncated.
+const DatabaseBackupScript = databaseBackupScriptCommon + `name="$(date -u +%Y%m%dT%H%M%SZ).dump"
+pg_dump --format=custom --file="` + databaseBackupScratchPath + `/${name}"
+s3 PUT "" >/dev/null 2>&1 || true
+s3 PUT "/${BACKUP_PREFIX}${name}" --upload-file "` + databaseBackupScratchPath + `/${name}"
+echo "Uploaded backup ${name}"
+: >"` + databaseBackupScratchPath + `/keys"
+set --
+while :; do
+  s3 GET "?list-type=2&prefix=${BACKUP_PREFIX_QUERY}" "$@" >"` + databaseBackupScratchPath + `/list.xml"
+  grep -o '<Key>[^<]*</Key>' "` + databaseBackupScratchPath + `/list.xml" | sed -e 's/^<Key>//' -e 's/<\/Key>$//' \
+    >>"` + databaseBackupScratchPath + `/keys" || true
+  grep -q '<IsTruncated>true</IsTruncated>' "` + databaseBackupScratchPath + `/list.xml" || break
+  token="$(grep -o '<NextContinuationToken>[^<]*</NextContinuationToken>' "` + databaseBackupScratchPath + `/list.xml" |
+    sed -e 's/^<NextContinuationToken>//' -e 's/<\/NextContinuationToken>$//')"
+  [ -n "${token}" ] || break
+  set -- -G --data-urlencode "continuation-token=${token}"
+done
+sort -r "` + databaseBackupScratchPath + `/keys" | tail -n "+$((BACKUP_RETENTION + 1))" |
+  while read -r key; do
+    s3 DELETE "/${key}"
+    echo "Deleted backup ${key#"${BACKUP_PREFIX}"}"
+  done
+`
+
+// DatabaseRestoreScript downloads a backup from object storage and restores it
+// into the database, replacing its contents
+const DatabaseRestoreScript = databaseBackupScriptCommon + `s3 GET "/${BACKUP_PREFIX}${BACKUP_NAME}" --output "` + databaseBackupScratchPath + `/${BACKUP_NAME}"
+pg_restore --clean --if-exists --no-owner --single-transaction --dbname="${PGDATABASE}" "` + databaseBackupScratchPath + `/${BACKUP_NAME}"
+echo "Restored backup ${BACKUP_NAME}"
+`
+
+// DeployDatabaseBackup returns whether scheduled backups of the managed database are configured
+func DeployDatabaseBackup(cr *model.CryostatInstance) bool {
+	return DeployManagedDatabase(cr) && cr.Spec.DatabaseOptions != nil && cr.Spec.DatabaseOptions.Backup != nil
+}
+
+func DatabaseBackupPodLabels(cr *model.CryostatInstance) map[string]string {
+	return map[string]string{
+		"app":       cr.Name,
+		"component": "database-backup",
+	}
+}
+
+// DatabaseBackupCronJobName returns the name of the backup CronJob for a Cryostat CR with the provided name
+func DatabaseBackupCronJobName(crName string) string {
+	return crName + "-database-backup"
+}
+
+// DatabaseBackupPrefix returns the prefix of the object names for backups of this Cryostat CR
+func DatabaseBackupPrefix(cr *model.CryostatInstance) string {
+	return cr.InstallNamespace + "/" + cr.Name + "/"
+}
+
+// NewCronJobForDatabaseBackup returns a CronJob that periodically backs up the managed database
+// to object storage. If suspend is true, no new backups are started.
+func NewCronJobForDatabaseBackup(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLSConfig,
+	specs *ServiceSpecs, openshift bool, suspend bool) *batchv1.CronJob {
+	backup := cr.Spec.DatabaseOptions.Backup
+	retention := DefaultDatabaseBackupRetention
+	if backup.Retention != nil {
+		retention = *backup.Retention
+	}
+
+	pod := newPodForDatabaseBackup(cr, imageTags, tls, specs, openshift)
+	container := &pod.Containers[0]
+	container.Command = []string{"/bin/sh", "-c", DatabaseBackupScript}
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "BACKUP_RETENTION",
+		Value: strconv.Itoa(int(retention)),
+	})
+
+	backoffLimit := int32(2)
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DatabaseBackupCronJobName(cr.Name),
+			Namespace: cr.InstallNamespace,
+			Labels:    DatabaseBackupPodLabels(cr),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          backup.Schedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			Suspend:           &suspend,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: DatabaseBackupPodLabels(cr),
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: DatabaseBackupPodLabels(cr),
+						},
+						Spec: *pod,
+					},
+				},
+			},
+		},
+	}
+}
+
+// NewJobForDatabaseRestore returns a Job that restores the requested backup, using the
+// configuration of the pods created by the backup CronJob
+func NewJobForDatabaseRestore(restore *operatorv1beta2.CryostatRestore, cronJob *batchv1.CronJob) *batchv1.Job {
+	template := cronJob.Spec.JobTemplate.Spec.Template.DeepCopy()
+	container := &template.Spec.Containers[0]
+	container.Name = "database-restore"
+	container.Command = []string{"/bin/sh", "-c", DatabaseRestoreScript}
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "BACKUP_NAME",
+		Value: restore.Spec.BackupName,
+	})
+
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restore.Name + "-restore",
+			Namespace: restore.Namespace,
+			Labels:    cronJob.Spec.JobTemplate.Labels,
+		},
+		Spec: batchv1.JobSpec{
+			// Restoring a partial backup twice could fail in confusing ways, so don't retry
+			BackoffLimit: &backoffLimit,
+			Template:     *template,
+		},
+	}
+}
+
+func newPodForDatabaseBackup(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLSConfig,
+	specs *ServiceSpecs, openshift bool) *corev1.PodSpec {
+	readOnlyMode := int32(0440)
+	volumes := []corev1.Volume{
+		{
+			Name: "backup",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      "backup",
+			MountPath: databaseBackupScratchPath,
+		},
+	}
+
+	optional := false
+	envs := []corev1.EnvVar{
+		{
+			Name:  "PGHOST",
+			Value: fmt.Sprintf("%s-database.%s.svc.cluster.local", cr.Name, cr.InstallNamespace),
+		},
+		{
+			Name:  "PGPORT",
+			Value: specs.DatabaseURL.Port(),
+		},
+		{
+			Name:  "PGUSER",
+			Value: DatabaseUsername,
+		},
+		{
+			Name:  "PGDATABASE",
+			Value: DatabaseName,
+		},
+		{
+			Name: "PGPASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: getDatabaseSecret(cr),
+					},
+					Key:      constants.DatabaseSecretConnectionKey,
+					Optional: &optional,
+				},
+			},
+		},
+		{
+			Name: "S3_ACCESS_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: getStorageSecret(cr),
+					},
+					Key:      "ACCESS_KEY",
+					Optional: &optional,
+				},
+			},
+		},
+		{
+			Name: "S3_SECRET_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: getStorageSecret(cr),
+					},
+					Key:      "SECRET_KEY",
+					Optional: &optional,
+				},
+			},
+		},
+		{
+			Name:  "S3_BUCKET_URL",
+			Value: newDatabaseBackupBucketURL(cr, specs),
+		},
+		{
+			Name:  "S3_REGION",
+			Value: getDatabaseBackupRegion(cr),
+		},
+		{
+			Name:  "S3_TLS_TRUST_ALL",
+			Value: strconv.FormatBool(!DeployManagedStorage(cr) && cr.Spec.ObjectStorageOptions.Provider.TLSTrustAll != nil && *cr.Spec.ObjectStorageOptions.Provider.TLSTrustAll),
+		},
+		{
+			Name:  "BACKUP_PREFIX",
+			Value: DatabaseBackupPrefix(cr),
+		},
+		{
+			Name:  "BACKUP_PREFIX_QUERY",
+			Value: url.QueryEscape(DatabaseBackupPrefix(cr)),
+		},
+	}
+
+	if tls != nil {
+		// The database and managed storage certificates are both signed by the Cryostat CA
+		tlsPath := path.Join(SecretMountPrefix, databaseBackupTLSName)
+		volumes = append(volumes, corev1.Volume{
+			Name: databaseBackupTLSName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: tls.DatabaseSecret,
+					Items: []corev1.KeyToPath{
+						{
+							Key:  constants.CAKey,
+							Path: constants.CAKey,
+							Mode: &readOnlyMode,
+						},
+					},
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      databaseBackupTLSName,
+			MountPath: tlsPath,
+			ReadOnly:  true,
+		})
+		envs = append(envs,
+			corev1.EnvVar{
+				Name:  "PGSSLMODE",
+				Value: "verify-full",
+			},
+			corev1.EnvVar{
+				Name:  "PGSSLROOTCERT",
+				Value: path.Join(tlsPath, constants.CAKey),
+			},
+		)
+		if DeployManagedStorage(cr) {
+			envs = append(envs, corev1.EnvVar{
+				Name:  "CURL_CA_BUNDLE",
+				Value: path.Join(tlsPath, constants.CAKey),
+			})
+		}
+	}
+
+	return newPodForDatabaseJob(cr, corev1.Container{
+		Name:            cr.Name + "-database-backup",
+		Image:           imageTags.DatabaseImageTag,
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).Database, imageTags.DatabaseImageTag),
+		Env:             envs,
+		VolumeMounts:    mounts,
+	}, volumes, openshift)
+}
+
+// newPodForDatabaseJob returns a pod spec for a Job running the provided container, which connects to
+// the managed database using the database image. The security context and scheduling options match
+// those of the database.
+func newPodForDatabaseJob(cr *model.CryostatInstance, container corev1.Container, volumes []corev1.Volume,
+	openshift bool) *corev1.PodSpec {
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.DatabaseSecurityContext != nil {
+		container.SecurityContext = cr.Spec.SecurityOptions.DatabaseSecurityContext
+	} else {
+		privEscalation := false
+		container.SecurityContext = &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &privEscalation,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{constants.CapabilityAll},
+			},
+		}
+	}
+
+	var podSc *corev1.PodSecurityContext
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.PodSecurityContext != nil {
+		podSc = cr.Spec.SecurityOptions.PodSecurityContext
+	} else {
+		nonRoot := true
+		podSc = &corev1.PodSecurityContext{
+			RunAsNonRoot:   &nonRoot,
+			SeccompProfile: common.SeccompProfile(openshift),
+		}
+	}
+
+	var nodeSelector map[string]string
+	var affinity *corev1.Affinity
+	var tolerations []corev1.Toleration
+	if cr.Spec.SchedulingOptions != nil {
+		nodeSelector = cr.Spec.SchedulingOptions.NodeSelector
+		if cr.Spec.SchedulingOptions.Affinity != nil {
+			affinity = &corev1.Affinity{
+				NodeAffinity:    cr.Spec.SchedulingOptions.Affinity.NodeAffinity,
+				PodAffinity:     cr.Spec.SchedulingOptions.Affinity.PodAffinity,
+				PodAntiAffinity: cr.Spec.SchedulingOptions.Affinity.PodAntiAffinity,
+			}
+		}
+		tolerations = cr.Spec.SchedulingOptions.Tolerations
+	}
+
+	automountSAToken := false
+	return &corev1.PodSpec{
+		Containers:                   []corev1.Container{container},
+		RestartPolicy:                corev1.RestartPolicyNever,
+		AutomountServiceAccountToken: &automountSAToken,
+		NodeSelector:                 nodeSelector,
+		Affinity:                     affinity,
+		Tolerations:                  tolerations,
+		SecurityContext:              podSc,
+		Volumes:                      volumes,
+		ImagePullSecrets:             cr.Spec.ImagePullSecrets,
+	}
+}
+
+func getDatabaseBackupBucket(cr *model.CryostatInstance) string {
+	if cr.Spec.DatabaseOptions.Backup.BucketName != nil {
+		return *cr.Spec.DatabaseOptions.Backup.BucketName
+	}
+	return DefaultDatabaseBackupBucket
+}
+
+func getDatabaseBackupRegion(cr *model.CryostatInstance) string {
+	if !DeployManagedStorage(cr) && cr.Spec.ObjectStorageOptions.Provider.Region != nil {
+		return *cr.Spec.ObjectStorageOptions.Provider.Region
+	}
+	return "us-east-1"
+}
+
+func newDatabaseBackupBucketURL(cr *model.CryostatInstance, specs *ServiceSpecs) string {
+	bucketURL := *specs.StorageURL
+	bucket := getDatabaseBackupBucket(cr)
+	if !DeployManagedStorage(cr) && cr.Spec.ObjectStorageOptions.Provider.UseVirtualHostAccess != nil &&
+		*cr.Spec.ObjectStorageOptions.Provider.UseVirtualHostAccess {
+		bucketURL.Host = bucket + "." + bucketURL.Host
+	} else {
+		bucketURL.Path = path.Join("/", bucketURL.Path, bucket)
+	}
+	return bucketURL.String()
+}
@@ -0,0 +1,166 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource_definitions
+
+import (
+	"fmt"
+	"path"
+
+	common "github.com/cryostatio/cryostat-operator/internal/controller/common"
+	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const databasePasswordTLSName string = "database-password-tls"
+
+// DatabasePasswordScript connects to the managed database using the current password, and
+// changes the password of the Cryostat database user. The new password is passed as a psql
+// variable, so that it is quoted correctly and does not appear in the command line.
+const DatabasePasswordScript = `set -eu
+echo "ALTER ROLE CURRENT_USER PASSWORD :'password';" |
+  psql --no-psqlrc --quiet --set=ON_ERROR_STOP=1 --set=password="${NEW_PASSWORD}"
+echo "Changed the password of database user ${PGUSER}"
+`
+
+func DatabasePasswordPodLabels(cr *model.CryostatInstance) map[string]string {
+	return map[string]string{
+		"app":       cr.Name,
+		"component": "database-password",
+	}
+}
+
+// DatabasePasswordJobName returns the name of the Job that changes the database password
+// for a Cryostat CR with the provided name
+func DatabasePasswordJobName(crName string) string {
+	return crName + "-database-password"
+}
+
+// NewJobForDatabasePassword returns a Job that changes the password of the Cryostat user in the
+// managed database, from the CONNECTION_KEY in currentSecret to the CONNECTION_KEY in newSecret
+func NewJobForDatabasePassword(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLSConfig,
+	specs *ServiceSpecs, openshift bool, currentSecret string, newSecret string) *batchv1.Job {
+	readOnlyMode := int32(0440)
+	volumes := []corev1.Volume{}
+	mounts := []corev1.VolumeMount{}
+
+	optional := false
+	envs := []corev1.EnvVar{
+		{
+			Name:  "PGHOST",
+			Value: fmt.Sprintf("%s-database.%s.svc.cluster.local", cr.Name, cr.InstallNamespace),
+		},
+		{
+			Name:  "PGPORT",
+			Value: specs.DatabaseURL.Port(),
+		},
+		{
+			Name:  "PGUSER",
+			Value: DatabaseUsername,
+		},
+		{
+			Name:  "PGDATABASE",
+			Value: DatabaseName,
+		},
+		{
+			Name: "PGPASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: currentSecret,
+					},
+					Key:      constants.DatabaseSecretConnectionKey,
+					Optional: &optional,
+				},
+			},
+		},
+		{
+			Name: "NEW_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: newSecret,
+					},
+					Key:      constants.DatabaseSecretConnectionKey,
+					Optional: &optional,
+				},
+			},
+		},
+	}
+
+	if tls != nil {
+		tlsPath := path.Join(SecretMountPrefix, databasePasswordTLSName)
+		volumes = append(volumes, corev1.Volume{
+			Name: databasePasswordTLSName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: tls.DatabaseSecret,
+					Items: []corev1.KeyToPath{
+						{
+							Key:  constants.CAKey,
+							Path: constants.CAKey,
+							Mode: &readOnlyMode,
+						},
+					},
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      databasePasswordTLSName,
+			MountPath: tlsPath,
+			ReadOnly:  true,
+		})
+		envs = append(envs,
+			corev1.EnvVar{
+				Name:  "PGSSLMODE",
+				Value: "verify-full",
+			},
+			corev1.EnvVar{
+				Name:  "PGSSLROOTCERT",
+				Value: path.Join(tlsPath, constants.CAKey),
+			},
+		)
+	}
+
+	pod := newPodForDatabaseJob(cr, corev1.Container{
+		Name:            cr.Name + "-database-password",
+		Image:           imageTags.DatabaseImageTag,
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).Database, imageTags.DatabaseImageTag),
+		Command:         []string{"/bin/sh", "-c", DatabasePasswordScript},
+		Env:             envs,
+		VolumeMounts:    mounts,
+	}, volumes, openshift)
+
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DatabasePasswordJobName(cr.Name),
+			Namespace: cr.InstallNamespace,
+			Labels:    DatabasePasswordPodLabels(cr),
+		},
+		Spec: batchv1.JobSpec{
+			// The current password no longer works if a previous attempt succeeded, so don't retry
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: DatabasePasswordPodLabels(cr),
+				},
+				Spec: *pod,
+			},
+		},
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource_definitions
+
+import (
+	common "github.com/cryostatio/cryostat-operator/internal/controller/common"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	databaseMountPath          string = "/var/lib/pgsql"
+	databaseDataDir            string = databaseMountPath + "/data"
+	databaseUpgradeScratchPath string = "/upgrade"
+)
+
+// Shell setup shared by the upgrade scripts. Any failed command aborts the upgrade, leaving the
+// previous data directory in place. The PostgreSQL tools require the current user to have
+// a passwd entry, which is not the case for the arbitrary user IDs used by OpenShift.
+const databaseUpgradeScriptCommon = `set -euo pipefail
+if ! id -un >/dev/null 2>&1; then
+  export NSS_WRAPPER_PASSWD="` + databaseUpgradeScratchPath + `/passwd" NSS_WRAPPER_GROUP=/etc/group LD_PRELOAD=libnss_wrapper.so
+  { cat /etc/passwd; echo "postgres:x:$(id -u):$(id -g):PostgreSQL:/var/lib/pgsql:/bin/sh"; } > "${NSS_WRAPPER_PASSWD}"
+fi
+server_opts="-c listen_addresses='' -c unix_socket_directories=` + databaseUpgradeScratchPath + `"
+`
+
+// DatabaseDumpScript runs with the previous database image, and dumps the existing data
+// using a server that only listens on a local socket. Roles are dumped separately from
+// each database, so that each database can be restored in a single transaction.
+const DatabaseDumpScript = databaseUpgradeScriptCommon + `if [ ! -s "${PGDATA}/PG_VERSION" ]; then
+  echo "No existing database found"
+  exit 0
+fi
+pg_ctl start --wait --pgdata="${PGDATA}" --options="${server_opts}"
+psql --host="` + databaseUpgradeScratchPath + `" --dbname=postgres --no-psqlrc -v ON_ERROR_STOP=1 \
+  --tuples-only --no-align --field-separator="|" --output="` + databaseUpgradeScratchPath + `/databases" \
+  --command="SELECT datname, pg_get_userbyid(datdba) FROM pg_database WHERE datallowconn AND NOT datistemplate ORDER BY datname"
+i=0
+while IFS="|" read -r name owner; do
+  pg_dump --host="` + databaseUpgradeScratchPath + `" --dbname="${name}" --file="` + databaseUpgradeScratchPath + `/database-${i}.sql"
+  i=$((i + 1))
+done < "` + databaseUpgradeScratchPath + `/databases"
+pg_dumpall --host="` + databaseUpgradeScratchPath + `" --globals-only --no-tablespaces --file="` + databaseUpgradeScratchPath + `/globals.sql"
+pg_ctl stop --wait --pgdata="${PGDATA}"
+echo "Dumped PostgreSQL $(cat "${PGDATA}/PG_VERSION") database"
+`
+
+// DatabaseUpgradeScript runs with the new database image, and restores the dump into a new
+// data directory. Each database is restored in a single transaction, stopping at the first
+// error, and the new data directory only replaces the previous one once everything is restored.
+// The previous data directory is kept alongside the new one with its version as suffix, so that
+// it can be recovered manually if needed. The operator reports its location once the upgrade succeeds.
+const DatabaseUpgradeScript = databaseUpgradeScriptCommon + `if [ ! -s "` + databaseUpgradeScratchPath + `/globals.sql" ]; then
+  echo "No existing database to upgrade"
+  exit 0
+fi
+old="$(cat "${PGDATA}/PG_VERSION")"
+new="$(postgres --version | sed -e 's/^[^0-9]*\([0-9]*\).*$/\1/')"
+if [ "${old}" = "${new}" ]; then
+  echo "Database already uses PostgreSQL ${new}"
+  exit 0
+fi
+rm -rf "${PGDATA}.new" "${PGDATA}-${old}"
+initdb --pgdata="${PGDATA}.new"
+cp "${PGDATA}/pg_hba.conf" "${PGDATA}.new/pg_hba.conf"
+grep '^include' "${PGDATA}/postgresql.conf" >> "${PGDATA}.new/postgresql.conf" || true
+pg_ctl start --wait --pgdata="${PGDATA}.new" --options="${server_opts}"
+# The bootstrap superuser already exists in the new data directory
+grep -v -x -F "CREATE ROLE $(id -un);" "` + databaseUpgradeScratchPath + `/globals.sql" > "` + databaseUpgradeScratchPath + `/roles.sql"
+psql --host="` + databaseUpgradeScratchPath + `" --dbname=postgres --quiet --no-psqlrc -v ON_ERROR_STOP=1 \
+  --single-transaction --file="` + databaseUpgradeScratchPath + `/roles.sql"
+i=0
+while IFS="|" read -r name owner; do
+  if [ "${name}" != "postgres" ]; then
+    createdb --host="` + databaseUpgradeScratchPath + `" --owner="${owner}" "${name}"
+  fi
+  psql --host="` + databaseUpgradeScratchPath + `" --dbname="${name}" --quiet --no-psqlrc -v ON_ERROR_STOP=1 \
+    --single-transaction --file="` + databaseUpgradeScratchPath + `/database-${i}.sql"
+  i=$((i + 1))
+done < "` + databaseUpgradeScratchPath + `/databases"
+pg_ctl stop --wait --pgdata="${PGDATA}.new"
+mv "${PGDATA}" "${PGDATA}-${old}"
+mv "${PGDATA}.new" "${PGDATA}"
+echo "Upgraded database from PostgreSQL ${old} to ${new}"
+`
+
+// DatabaseVersionScript reports the PostgreSQL major version of the existing data in the
+// termination message of its container. The message is empty if there is no existing data.
+const DatabaseVersionScript = `set -eu
+if [ -s "${PGDATA}/PG_VERSION" ]; then
+  cat "${PGDATA}/PG_VERSION" > /dev/termination-log
+fi
+`
+
+// Gives up on reading the version of the existing data if the Job cannot be scheduled,
+// such as when the database volume cannot be shared with the running database pod
+const databaseVersionJobDeadlineSeconds int64 = 300
+
+func DatabaseVersionPodLabels(cr *model.CryostatInstance) map[string]string {
+	return map[string]string{
+		"app":       cr.Name,
+		"component": "database-version",
+	}
+}
+
+// DatabaseVersionJobName returns the name of the Job that reads the PostgreSQL major version
+// of the existing database of a Cryostat CR with the provided name
+func DatabaseVersionJobName(crName string) string {
+	return crName + "-database-version"
+}
+
+// NewJobForDatabaseVersion returns a Job that reads the PostgreSQL major version of the existing data
+// of the managed database, without stopping the database. The data volume is mounted read-only, and
+// the pod is scheduled next to the running database pod, so that a ReadWriteOnce volume can be shared.
+func NewJobForDatabaseVersion(cr *model.CryostatInstance, previousImage string, imageTags *ImageTags,
+	databaseRunning bool, openshift bool, fsGroup int64) *batchv1.Job {
+	pod := NewPodForDatabase(cr, imageTags, nil, openshift, fsGroup)
+	// Use the same environment, data volume and security context as the database container
+	container := NewDatabaseContainer(cr, previousImage, nil)
+	mounts := make([]corev1.VolumeMount, 0, len(container.VolumeMounts))
+	for _, mount := range container.VolumeMounts {
+		mount.ReadOnly = true
+		mounts = append(mounts, mount)
+	}
+	pod.Containers = []corev1.Container{
+		{
+			Name:                     "database-version",
+			Image:                    previousImage,
+			ImagePullPolicy:          common.GetImagePullPolicy(getImageConfigs(cr).Database, previousImage),
+			Command:                  []string{"/bin/sh", "-c", DatabaseVersionScript},
+			Env:                      container.Env,
+			VolumeMounts:             mounts,
+			SecurityContext:          container.SecurityContext,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+	}
+	if databaseRunning {
+		if pod.Affinity == nil {
+			pod.Affinity = &corev1.Affinity{}
+		} else {
+			pod.Affinity = pod.Affinity.DeepCopy()
+		}
+		if pod.Affinity.PodAffinity == nil {
+			pod.Affinity.PodAffinity = &corev1.PodAffinity{}
+		}
+		pod.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			pod.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution, corev1.PodAffinityTerm{
+				LabelSelector: metav1.SetAsLabelSelector(DatabasePodLabels(cr)),
+				TopologyKey:   corev1.LabelHostname,
+			})
+	}
+	pod.RestartPolicy = corev1.RestartPolicyNever
+	automountSAToken := false
+	pod.AutomountServiceAccountToken = &automountSAToken
+
+	backoffLimit := int32(0)
+	deadline := databaseVersionJobDeadlineSeconds
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DatabaseVersionJobName(cr.Name),
+			Namespace: cr.InstallNamespace,
+			Labels:    DatabaseVersionPodLabels(cr),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &deadline,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: DatabaseVersionPodLabels(cr),
+				},
+				Spec: *pod,
+			},
+		},
+	}
+}
+
+func DatabaseUpgradePodLabels(cr *model.CryostatInstance) map[string]string {
+	return map[string]string{
+		"app":       cr.Name,
+		"component": "database-upgrade",
+	}
+}
+
+// DatabaseUpgradeJobName returns the name of the Job that upgrades the database of a Cryostat CR
+// with the provided name to a PostgreSQL major version
+func DatabaseUpgradeJobName(crName string, version string) string {
+	return crName + "-database-upgrade-" + version
+}
+
+// NewJobForDatabaseUpgrade returns a Job that upgrades the data of the managed database to a new
+// PostgreSQL major version. The data is dumped using the previous database image, then restored
+// using the new database image. The database deployment must be stopped while this Job runs.
+// The dump is written to an EmptyDir limited to dumpSizeLimit, if provided, so that a large
+// database fails the upgrade rather than exhausting the ephemeral storage of the node.
+func NewJobForDatabaseUpgrade(cr *model.CryostatInstance, previousImage string, imageTags *ImageTags,
+	version string, dumpSizeLimit *resource.Quantity, openshift bool, fsGroup int64) *batchv1.Job {
+	pod := NewPodForDatabase(cr, imageTags, nil, openshift, fsGroup)
+	pod.Volumes = append(pod.Volumes, corev1.Volume{
+		Name: "upgrade",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				SizeLimit: dumpSizeLimit,
+			},
+		},
+	})
+	pod.InitContainers = []corev1.Container{
+		newDatabaseUpgradeContainer(cr, "database-dump", previousImage, DatabaseDumpScript),
+	}
+	pod.Containers = []corev1.Container{
+		newDatabaseUpgradeContainer(cr, "database-upgrade", imageTags.DatabaseImageTag, DatabaseUpgradeScript),
+	}
+	pod.RestartPolicy = corev1.RestartPolicyNever
+	automountSAToken := false
+	pod.AutomountServiceAccountToken = &automountSAToken
+
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DatabaseUpgradeJobName(cr.Name, version),
+			Namespace: cr.InstallNamespace,
+			Labels:    DatabaseUpgradePodLabels(cr),
+		},
+		Spec: batchv1.JobSpec{
+			// The previous data is kept on failure, so let the user decide when to retry
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: DatabaseUpgradePodLabels(cr),
+				},
+				Spec: *pod,
+			},
+		},
+	}
+}
+
+func newDatabaseUpgradeContainer(cr *model.CryostatInstance, name string, image string, script string) corev1.Container {
+	// Use the same environment, data volume and security context as the database container
+	container := NewDatabaseContainer(cr, image, nil)
+	return corev1.Container{
+		Name:            name,
+		Image:           image,
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).Database, image),
+		Command:         []string{"/bin/sh", "-c", script},
+		Env:             container.Env,
+		VolumeMounts: append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "upgrade",
+			MountPath: databaseUpgradeScratchPath,
+		}),
+		SecurityContext: container.SecurityContext,
+		Resources:       container.Resources,
+	}
+}
+
+// PreviousDatabaseDataDir returns where the upgrade Job keeps the data directory of the
+// provided PostgreSQL major version, once the data has been upgraded to a new version
+func PreviousDatabaseDataDir(version string) string {
+	return databaseDataDir + "-" + version
+}
+
+// DatabaseUsesPersistentVolume returns whether the managed database stores its data in
+// a PersistentVolumeClaim, rather than an EmptyDir that does not outlive the database pod
+func DatabaseUsesPersistentVolume(cr *model.CryostatInstance) bool {
+	volumes := newVolumeForDatabase(cr)
+	return volumes[0].PersistentVolumeClaim != nil
+}
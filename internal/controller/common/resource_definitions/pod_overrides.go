@@ -0,0 +1,186 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource_definitions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// PodTemplateOverridesTarget associates a section of spec.podTemplateOverrides
+// with the operator-owned container and pod it applies to
+type PodTemplateOverridesTarget struct {
+	// Name of the section in spec.podTemplateOverrides
+	Section string
+	// Overrides from this section, may be nil
+	Overrides *operatorv1beta2.PodTemplateOverrides
+	// Name of the operator-owned container targeted by this section
+	ContainerName string
+	// Ports used by the operator-owned containers in the same pod
+	PodPorts []int32
+	// Volumes that the operator may add to the same pod, which overrides must not replace
+	PodVolumes []string
+	// Prefixes of the volumes that the operator adds to the same pod for each configured
+	// event template, automated rule and probe template
+	PodVolumePrefixes []string
+}
+
+// NewPodTemplateOverridesTargets returns a target for each section of spec.podTemplateOverrides
+// for a Cryostat CR with the provided name and declarative credentials. The reserved volumes and
+// ports are those of the operator-owned pods, with every optional volume and port enabled.
+func NewPodTemplateOverridesTargets(crName string, overrides *operatorv1beta2.PodTemplateOverridesList,
+	credentials []operatorv1beta2.DeclarativeCredential) ([]PodTemplateOverridesTarget, error) {
+	if overrides == nil {
+		overrides = &operatorv1beta2.PodTemplateOverridesList{}
+	}
+	cr := newCryostatWithReservedResources(crName, credentials)
+	specs := &ServiceSpecs{}
+	for _, serviceURL := range []**url.URL{&specs.AuthProxyURL, &specs.CoreURL, &specs.ReportsURL,
+		&specs.InsightsURL, &specs.StorageURL, &specs.DatabaseURL} {
+		*serviceURL = &url.URL{Scheme: "https", Host: crName}
+	}
+	tls := &TLSConfig{}
+	corePod, err := NewPodForCR(cr, specs, &ImageTags{}, tls, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	reportsPod := NewPodForReports(cr, &ImageTags{}, specs, tls, false)
+	databasePod := NewPodForDatabase(cr, &ImageTags{}, tls, false, 0)
+	storagePod := NewPodForStorage(cr, &ImageTags{}, tls, false, 0)
+
+	corePorts, coreVolumes := podPortsAndVolumes(corePod)
+	reportsPorts, reportsVolumes := podPortsAndVolumes(reportsPod)
+	databasePorts, databaseVolumes := podPortsAndVolumes(databasePod)
+	storagePorts, storageVolumes := podPortsAndVolumes(storagePod)
+	// Volumes added for each configured event template, automated rule and probe template
+	coreVolumePrefixes := []string{"template-", "rule-", "probe-template-"}
+	return []PodTemplateOverridesTarget{
+		{"core", overrides.Core, crName, corePorts, coreVolumes, coreVolumePrefixes},
+		{"authProxy", overrides.AuthProxy, crName + "-auth-proxy", corePorts, coreVolumes, coreVolumePrefixes},
+		{"agentProxy", overrides.AgentProxy, crName + "-agent-proxy", corePorts, coreVolumes, coreVolumePrefixes},
+		{"grafana", overrides.Grafana, crName + "-grafana", corePorts, coreVolumes, coreVolumePrefixes},
+		{"dataSource", overrides.DataSource, crName + "-jfr-datasource", corePorts, coreVolumes, coreVolumePrefixes},
+		{"reports", overrides.Reports, crName + "-reports", reportsPorts, reportsVolumes, nil},
+		{"database", overrides.Database, crName + "-db", databasePorts, databaseVolumes, nil},
+		{"storage", overrides.Storage, crName + "-storage", storagePorts, storageVolumes, nil},
+	}, nil
+}
+
+// newCryostatWithReservedResources returns a Cryostat CR that enables every feature adding
+// volumes or ports to the operator-owned pods, so that none of them can be overridden
+func newCryostatWithReservedResources(crName string, credentials []operatorv1beta2.DeclarativeCredential) *model.CryostatInstance {
+	reserved := "reserved"
+	return &model.CryostatInstance{
+		Name: crName,
+		Spec: &operatorv1beta2.CryostatSpec{
+			AuthorizationOptions: &operatorv1beta2.AuthorizationOptions{
+				OIDC: &operatorv1beta2.OIDCConfig{
+					ClientSecret: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: reserved,
+						},
+						Key: reserved,
+					},
+					CACertificate: &operatorv1beta2.CertificateSecret{
+						SecretName: reserved,
+					},
+				},
+				BasicAuth: &operatorv1beta2.SecretFile{
+					SecretName: &reserved,
+					Filename:   &reserved,
+				},
+				Roles: []operatorv1beta2.AccessRole{
+					{Name: reserved},
+				},
+			},
+			DatabaseOptions: &operatorv1beta2.DatabaseOptions{
+				External: &operatorv1beta2.ExternalDatabaseOptions{
+					CACertificate: &operatorv1beta2.CertificateSecret{
+						SecretName: reserved,
+					},
+				},
+			},
+			DeclarativeCredentials: credentials,
+		},
+		Status: &operatorv1beta2.CryostatStatus{},
+	}
+}
+
+func podPortsAndVolumes(pod *corev1.PodSpec) ([]int32, []string) {
+	ports := []int32{}
+	for _, container := range pod.Containers {
+		for _, port := range container.Ports {
+			ports = append(ports, port.ContainerPort)
+		}
+	}
+	volumes := []string{}
+	for _, volume := range pod.Volumes {
+		volumes = append(volumes, volume.Name)
+	}
+	return ports, volumes
+}
+
+// IsReservedVolumeName returns whether the operator may add a volume with the provided
+// name to the pod targeted by this section
+func (t *PodTemplateOverridesTarget) IsReservedVolumeName(name string) bool {
+	if slices.Contains(t.PodVolumes, name) {
+		return true
+	}
+	return slices.ContainsFunc(t.PodVolumePrefixes, func(prefix string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+func getPodTemplateOverrides(cr *model.CryostatInstance) *operatorv1beta2.PodTemplateOverridesList {
+	if cr.Spec.PodTemplateOverrides == nil {
+		return &operatorv1beta2.PodTemplateOverridesList{}
+	}
+	return cr.Spec.PodTemplateOverrides
+}
+
+// applyPodTemplateOverrides applies each of the overrides to the pod spec as a strategic merge patch
+func applyPodTemplateOverrides(pod *corev1.PodSpec, overrides ...*operatorv1beta2.PodTemplateOverrides) (*corev1.PodSpec, error) {
+	for _, override := range overrides {
+		if override == nil {
+			continue
+		}
+		original, err := json.Marshal(pod)
+		if err != nil {
+			return nil, err
+		}
+		patch, err := json.Marshal(override)
+		if err != nil {
+			return nil, err
+		}
+		patched, err := strategicpatch.StrategicMergePatch(original, patch, corev1.PodSpec{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply pod template overrides: %w", err)
+		}
+		result := &corev1.PodSpec{}
+		if err := json.Unmarshal(patched, result); err != nil {
+			return nil, err
+		}
+		pod = result
+	}
+	return pod, nil
+}
@@ -16,7 +16,9 @@ package resource_definitions
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"path"
 	"slices"
@@ -27,6 +29,7 @@ import (
 	common "github.com/cryostatio/cryostat-operator/internal/controller/common"
 	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	configv1 "github.com/openshift/api/config/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	authzv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -35,6 +38,15 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+var (
+	// Returned when an OpenID Connect provider is configured on OpenShift,
+	// where the OpenShift OAuth proxy is used instead
+	errOIDCUnavailable = errors.New("OpenID Connect authentication is not available on OpenShift")
+	// Returned when an OpenID Connect provider is configured, but Cryostat
+	// has no externally accessible URL for the provider to redirect users to
+	errOIDCMissingURL = errors.New("OpenID Connect authentication requires an Ingress with a host for Cryostat")
+)
+
 // ImageTags contains container image tags for each of the images to deploy
 type ImageTags struct {
 	OAuth2ProxyImageTag         string
@@ -73,6 +85,20 @@ type TLSConfig struct {
 	KeystorePassSecret string
 	// PEM-encoded X.509 certificate for the Cryostat CA
 	CACert []byte
+	// PEM-encoded X.509 certificates for the current and previous Cryostat CAs,
+	// only set while the CA is being rotated
+	CATrustBundle []byte
+	// Name of the secret containing the previous Cryostat CA under constants.PreviousCAKey,
+	// only set while the CA is being rotated
+	PreviousCASecret string
+	// Organizational unit required in the subject of agent client certificates, only set when
+	// they are issued by an external issuer that may also issue certificates to other clients
+	AgentOrganizationalUnit string
+	// Name of the config map containing the OpenShift service CA, only set when
+	// the service CA issues the certificates of Cryostat's services
+	ServiceCAConfigMap string
+	// TLS protocol versions and ciphers allowed by servers using these certificates
+	SecurityProfile *configv1.TLSProfileSpec
 }
 
 const (
@@ -110,10 +136,23 @@ const (
 	defaultAgentProxyMemoryLimit      string = "200Mi"
 	OAuth2ConfigFileName              string = "alpha_config.json"
 	OAuth2ConfigFilePath              string = "/etc/oauth2_proxy/alpha_config"
+	OAuth2AuthenticatedEmailsFileName string = "authenticated_emails.txt"
+	OIDCClientSecretName              string = "oidc-client-secret"
+	OIDCClientSecretFileName          string = "client-secret"
+	OIDCCAName                        string = "oidc-ca"
 	DatabaseName                      string = "cryostat"
+	DatabaseUsername                  string = "cryostat"
+	externalDatabaseCAName            string = "external-database-ca"
 	SecretMountPrefix                 string = "/var/run/secrets/operator.cryostat.io"
 )
 
+func getImageConfigs(cr *model.CryostatInstance) *operatorv1beta2.ImageConfigList {
+	if cr.Spec.Images == nil {
+		return &operatorv1beta2.ImageConfigList{}
+	}
+	return cr.Spec.Images
+}
+
 func createMapCopy(in map[string]string) map[string]string {
 	copy := make(map[string]string)
 	for k, v := range in {
@@ -189,6 +228,12 @@ func NewDeploymentForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTa
 	if err != nil {
 		return nil, err
 	}
+	overrides := getPodTemplateOverrides(cr)
+	pod, err = applyPodTemplateOverrides(pod, overrides.Core, overrides.AuthProxy, overrides.AgentProxy,
+		overrides.Grafana, overrides.DataSource)
+	if err != nil {
+		return nil, err
+	}
 	return &appsv1.Deployment{
 		ObjectMeta: deploymentMeta,
 		Spec: appsv1.DeploymentSpec{
@@ -221,7 +266,7 @@ func DatabasePodLabels(cr *model.CryostatInstance) map[string]string {
 }
 
 func NewDeploymentForDatabase(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLSConfig,
-	openshift bool, fsGroup int64) *appsv1.Deployment {
+	openshift bool, fsGroup int64) (*appsv1.Deployment, error) {
 	replicas := int32(1)
 
 	defaultDeploymentLabels := map[string]string{
@@ -264,6 +309,10 @@ func NewDeploymentForDatabase(cr *model.CryostatInstance, imageTags *ImageTags,
 	}
 	common.MergeLabelsAndAnnotations(&podTemplateMeta, defaultPodLabels, nil)
 
+	pod, err := applyPodTemplateOverrides(NewPodForDatabase(cr, imageTags, tls, openshift, fsGroup), getPodTemplateOverrides(cr).Database)
+	if err != nil {
+		return nil, err
+	}
 	return &appsv1.Deployment{
 		ObjectMeta: deploymentMeta,
 		Spec: appsv1.DeploymentSpec{
@@ -277,14 +326,14 @@ func NewDeploymentForDatabase(cr *model.CryostatInstance, imageTags *ImageTags,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: podTemplateMeta,
-				Spec:       *NewPodForDatabase(cr, imageTags, tls, openshift, fsGroup),
+				Spec:       *pod,
 			},
 			Replicas: &replicas,
 			Strategy: appsv1.DeploymentStrategy{
 				Type: appsv1.RecreateDeploymentStrategyType,
 			},
 		},
-	}
+	}, nil
 }
 
 func StoragePodLabels(cr *model.CryostatInstance) map[string]string {
@@ -295,7 +344,7 @@ func StoragePodLabels(cr *model.CryostatInstance) map[string]string {
 	}
 }
 
-func NewDeploymentForStorage(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLSConfig, openshift bool, fsGroup int64) *appsv1.Deployment {
+func NewDeploymentForStorage(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLSConfig, openshift bool, fsGroup int64) (*appsv1.Deployment, error) {
 	replicas := int32(1)
 
 	defaultDeploymentLabels := map[string]string{
@@ -338,6 +387,10 @@ func NewDeploymentForStorage(cr *model.CryostatInstance, imageTags *ImageTags, t
 	}
 	common.MergeLabelsAndAnnotations(&podTemplateMeta, defaultPodLabels, nil)
 
+	pod, err := applyPodTemplateOverrides(NewPodForStorage(cr, imageTags, tls, openshift, fsGroup), getPodTemplateOverrides(cr).Storage)
+	if err != nil {
+		return nil, err
+	}
 	return &appsv1.Deployment{
 		ObjectMeta: deploymentMeta,
 		Spec: appsv1.DeploymentSpec{
@@ -351,14 +404,14 @@ func NewDeploymentForStorage(cr *model.CryostatInstance, imageTags *ImageTags, t
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: podTemplateMeta,
-				Spec:       *NewPodForStorage(cr, imageTags, tls, openshift, fsGroup),
+				Spec:       *pod,
 			},
 			Replicas: &replicas,
 			Strategy: appsv1.DeploymentStrategy{
 				Type: appsv1.RecreateDeploymentStrategyType,
 			},
 		},
-	}
+	}, nil
 }
 
 func ReportsPodLabels(cr *model.CryostatInstance) map[string]string {
@@ -370,7 +423,7 @@ func ReportsPodLabels(cr *model.CryostatInstance) map[string]string {
 }
 
 func NewDeploymentForReports(cr *model.CryostatInstance, imageTags *ImageTags, serviceSpecs *ServiceSpecs, tls *TLSConfig,
-	openshift bool) *appsv1.Deployment {
+	openshift bool) (*appsv1.Deployment, error) {
 	replicas := int32(0)
 	if cr.Spec.ReportOptions != nil {
 		replicas = cr.Spec.ReportOptions.Replicas
@@ -416,6 +469,10 @@ func NewDeploymentForReports(cr *model.CryostatInstance, imageTags *ImageTags, s
 	}
 	common.MergeLabelsAndAnnotations(&podTemplateMeta, defaultPodLabels, nil)
 
+	pod, err := applyPodTemplateOverrides(NewPodForReports(cr, imageTags, serviceSpecs, tls, openshift), getPodTemplateOverrides(cr).Reports)
+	if err != nil {
+		return nil, err
+	}
 	return &appsv1.Deployment{
 		ObjectMeta: deploymentMeta,
 		Spec: appsv1.DeploymentSpec{
@@ -429,11 +486,11 @@ func NewDeploymentForReports(cr *model.CryostatInstance, imageTags *ImageTags, s
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: podTemplateMeta,
-				Spec:       *NewPodForReports(cr, imageTags, serviceSpecs, tls, openshift),
+				Spec:       *pod,
 			},
 			Replicas: &replicas,
 		},
-	}
+	}, nil
 }
 
 func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *ImageTags,
@@ -520,6 +577,42 @@ func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *Ima
 			},
 		})
 
+		if len(tls.PreviousCASecret) > 0 {
+			// Keep trusting the previous Cryostat CA while it is being rotated
+			volSources = append(volSources, corev1.VolumeProjection{
+				Secret: &corev1.SecretProjection{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: tls.PreviousCASecret,
+					},
+					Items: []corev1.KeyToPath{
+						{
+							Key:  constants.PreviousCAKey,
+							Path: fmt.Sprintf("%s-%s", cr.Name, constants.PreviousCAKey),
+							Mode: &readOnlyMode,
+						},
+					},
+				},
+			})
+		}
+
+		if len(tls.ServiceCAConfigMap) > 0 {
+			// Add the OpenShift service CA, which issues the certificates of the other components
+			volSources = append(volSources, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: tls.ServiceCAConfigMap,
+					},
+					Items: []corev1.KeyToPath{
+						{
+							Key:  operatorv1beta2.DefaultConfigMapCertificateKey,
+							Path: fmt.Sprintf("%s-%s", cr.Name, operatorv1beta2.DefaultConfigMapCertificateKey),
+							Mode: &readOnlyMode,
+						},
+					},
+				},
+			})
+		}
+
 		volumes = append(volumes,
 			corev1.Volume{
 				Name: "auth-proxy-tls-secret",
@@ -619,6 +712,10 @@ func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *Ima
 		volumes = append(volumes, dbTlsVolume)
 	}
 
+	if caVolume := newExternalDatabaseCAVolume(cr); caVolume != nil {
+		volumes = append(volumes, *caVolume)
+	}
+
 	// Project certificate secrets into deployment
 	certVolume := corev1.Volume{
 		Name: "cert-secrets",
@@ -646,6 +743,20 @@ func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *Ima
 
 	if !openshift {
 		// if not deploying openshift oauth-proxy then we must be deploying oauth2_proxy instead
+		configItems := []corev1.KeyToPath{
+			{
+				Key:  OAuth2ConfigFileName,
+				Path: OAuth2ConfigFileName,
+				Mode: &readOnlyMode,
+			},
+		}
+		if IsOIDCEnabled(cr) && len(cr.Spec.AuthorizationOptions.OIDC.AllowedEmails) > 0 {
+			configItems = append(configItems, corev1.KeyToPath{
+				Key:  OAuth2AuthenticatedEmailsFileName,
+				Path: OAuth2AuthenticatedEmailsFileName,
+				Mode: &readOnlyMode,
+			})
+		}
 		volumes = append(volumes, corev1.Volume{
 			Name: cr.Name + "-oauth2-proxy-cfg",
 			VolumeSource: corev1.VolumeSource{
@@ -653,16 +764,32 @@ func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *Ima
 					LocalObjectReference: corev1.LocalObjectReference{
 						Name: cr.Name + "-oauth2-proxy-cfg",
 					},
-					Items: []corev1.KeyToPath{
-						{
-							Key:  OAuth2ConfigFileName,
-							Path: OAuth2ConfigFileName,
-							Mode: &readOnlyMode,
-						},
-					},
+					Items: configItems,
 				},
 			},
 		})
+
+		if IsOIDCEnabled(cr) {
+			oidc := cr.Spec.AuthorizationOptions.OIDC
+			volumes = append(volumes, corev1.Volume{
+				Name: OIDCClientSecretName,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: oidc.ClientSecret.Name,
+						Items: []corev1.KeyToPath{
+							{
+								Key:  oidc.ClientSecret.Key,
+								Path: OIDCClientSecretFileName,
+								Mode: &readOnlyMode,
+							},
+						},
+					},
+				},
+			})
+			if oidc.CACertificate != nil {
+				volumes = append(volumes, *newCACertificateVolume(OIDCCAName, oidc.CACertificate))
+			}
+		}
 	}
 
 	if isBasicAuthEnabled(cr) {
@@ -799,6 +926,7 @@ func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *Ima
 		NodeSelector:                 nodeSelector,
 		Affinity:                     affinity,
 		Tolerations:                  tolerations,
+		ImagePullSecrets:             cr.Spec.ImagePullSecrets,
 	}, nil
 }
 
@@ -852,13 +980,98 @@ func NewPodForDatabase(cr *model.CryostatInstance, imageTags *ImageTags, tls *TL
 	}
 
 	return &corev1.PodSpec{
-		Containers:      container,
-		NodeSelector:    nodeSelector,
-		Affinity:        affinity,
-		Tolerations:     tolerations,
-		SecurityContext: podSc,
-		Volumes:         volumes,
+		Containers:       container,
+		NodeSelector:     nodeSelector,
+		Affinity:         affinity,
+		Tolerations:      tolerations,
+		SecurityContext:  podSc,
+		Volumes:          volumes,
+		ImagePullSecrets: cr.Spec.ImagePullSecrets,
+	}
+}
+
+// DeployManagedDatabase returns whether the operator should deploy its own database,
+// as opposed to Cryostat using an external database
+func DeployManagedDatabase(cr *model.CryostatInstance) bool {
+	return cr.Spec.DatabaseOptions == nil || cr.Spec.DatabaseOptions.External == nil
+}
+
+// ExternalDatabaseAddress returns the host and port of the external database
+// in the form "host:port". Only valid when DeployManagedDatabase returns false.
+func ExternalDatabaseAddress(cr *model.CryostatInstance) string {
+	external := cr.Spec.DatabaseOptions.External
+	port := int32(constants.DatabasePort)
+	if external.Port != nil {
+		port = *external.Port
+	}
+	return net.JoinHostPort(external.Host, strconv.Itoa(int(port)))
+}
+
+// ExternalDatabaseName returns the name of the database to use within the external
+// database server. Only valid when DeployManagedDatabase returns false.
+func ExternalDatabaseName(cr *model.CryostatInstance) string {
+	external := cr.Spec.DatabaseOptions.External
+	if external.DatabaseName != nil {
+		return *external.DatabaseName
+	}
+	return DatabaseName
+}
+
+func getDatabaseUsername(cr *model.CryostatInstance) string {
+	if !DeployManagedDatabase(cr) && cr.Spec.DatabaseOptions.External.Username != nil {
+		return *cr.Spec.DatabaseOptions.External.Username
 	}
+	return DatabaseUsername
+}
+
+func newExternalDatabaseCAVolume(cr *model.CryostatInstance) *corev1.Volume {
+	if DeployManagedDatabase(cr) || cr.Spec.DatabaseOptions.External.CACertificate == nil {
+		return nil
+	}
+	return newCACertificateVolume(externalDatabaseCAName, cr.Spec.DatabaseOptions.External.CACertificate)
+}
+
+// newCACertificateVolume creates a volume containing the CA certificate from the referenced
+// secret or config map, stored in the ca.crt file
+func newCACertificateVolume(name string, cert *operatorv1beta2.CertificateSecret) *corev1.Volume {
+	readOnlyMode := int32(0440)
+	volume := &corev1.Volume{
+		Name: name,
+	}
+	if cert.SecretName != "" {
+		key := operatorv1beta2.DefaultCertificateKey
+		if cert.CertificateKey != nil {
+			key = *cert.CertificateKey
+		}
+		volume.Secret = &corev1.SecretVolumeSource{
+			SecretName: cert.SecretName,
+			Items: []corev1.KeyToPath{
+				{
+					Key:  key,
+					Path: constants.CAKey,
+					Mode: &readOnlyMode,
+				},
+			},
+		}
+	} else {
+		key := operatorv1beta2.DefaultConfigMapCertificateKey
+		if cert.CertificateKey != nil {
+			key = *cert.CertificateKey
+		}
+		volume.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: cert.ConfigMapName,
+			},
+			Items: []corev1.KeyToPath{
+				{
+					Key:  key,
+					Path: constants.CAKey,
+					Mode: &readOnlyMode,
+				},
+			},
+		}
+	}
+	return volume
 }
 
 func DeployManagedStorage(cr *model.CryostatInstance) bool {
@@ -917,12 +1130,13 @@ func NewPodForStorage(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLS
 	}
 
 	return &corev1.PodSpec{
-		Containers:      container,
-		NodeSelector:    nodeSelector,
-		Affinity:        affinity,
-		Tolerations:     tolerations,
-		SecurityContext: podSc,
-		Volumes:         volumes,
+		Containers:       container,
+		NodeSelector:     nodeSelector,
+		Affinity:         affinity,
+		Tolerations:      tolerations,
+		SecurityContext:  podSc,
+		Volumes:          volumes,
+		ImagePullSecrets: cr.Spec.ImagePullSecrets,
 	}
 }
 
@@ -1026,6 +1240,13 @@ func NewPodForReports(cr *model.CryostatInstance, imageTags *ImageTags, serviceS
 			tlsConfigName,
 			path.Join(SecretMountPrefix, tls.ReportsSecret, corev1.TLSPrivateKeyKey),
 		)
+		if tls.SecurityProfile != nil {
+			javaOpts += fmt.Sprintf(" -Dquarkus.tls.%s.protocols=%s", tlsConfigName,
+				strings.Join(common.TLSProtocols(tls.SecurityProfile), ","))
+			if suites := common.IANACipherSuites(tls.SecurityProfile); len(suites) > 0 {
+				javaOpts += fmt.Sprintf(" -Dquarkus.tls.%s.cipher-suites=%s", tlsConfigName, strings.Join(suites, ","))
+			}
+		}
 		tlsSecretMount := corev1.VolumeMount{
 			Name:      "reports-tls-secret",
 			MountPath: path.Join(SecretMountPrefix, tls.ReportsSecret),
@@ -1113,7 +1334,7 @@ func NewPodForReports(cr *model.CryostatInstance, imageTags *ImageTags, serviceS
 			{
 				Name:            cr.Name + "-reports",
 				Image:           imageTags.ReportsImageTag,
-				ImagePullPolicy: common.GetPullPolicy(imageTags.ReportsImageTag),
+				ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).Reports, imageTags.ReportsImageTag),
 				Ports: []corev1.ContainerPort{
 					{
 						ContainerPort: constants.ReportsContainerPort,
@@ -1131,11 +1352,12 @@ func NewPodForReports(cr *model.CryostatInstance, imageTags *ImageTags, serviceS
 				SecurityContext: containerSc,
 			},
 		},
-		Volumes:         volumes,
-		NodeSelector:    nodeSelector,
-		Affinity:        affinity,
-		Tolerations:     tolerations,
-		SecurityContext: podSc,
+		Volumes:          volumes,
+		NodeSelector:     nodeSelector,
+		Affinity:         affinity,
+		Tolerations:      tolerations,
+		SecurityContext:  podSc,
+		ImagePullSecrets: cr.Spec.ImagePullSecrets,
 	}
 }
 
@@ -1159,6 +1381,9 @@ func NewAuthProxyContainer(cr *model.CryostatInstance, specs *ServiceSpecs, oaut
 
 func NewOpenShiftAuthProxyContainer(cr *model.CryostatInstance, specs *ServiceSpecs, imageTag string,
 	tls *TLSConfig) (*corev1.Container, error) {
+	if IsOIDCEnabled(cr) {
+		return nil, errOIDCUnavailable
+	}
 	var containerSc *corev1.SecurityContext
 	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.AuthProxySecurityContext != nil {
 		containerSc = cr.Spec.SecurityOptions.AuthProxySecurityContext
@@ -1224,6 +1449,12 @@ func NewOpenShiftAuthProxyContainer(cr *model.CryostatInstance, specs *ServiceSp
 			fmt.Sprintf("--tls-cert=%s", path.Join(SecretMountPrefix, tls.CryostatSecret, corev1.TLSCertKey)),
 			fmt.Sprintf("--tls-key=%s", path.Join(SecretMountPrefix, tls.CryostatSecret, corev1.TLSPrivateKeyKey)),
 		)
+		if tls.SecurityProfile != nil {
+			args = append(args, fmt.Sprintf("--tls-min-version=%s", tls.SecurityProfile.MinTLSVersion))
+			for _, suite := range common.TLS12CipherSuites(tls.SecurityProfile) {
+				args = append(args, fmt.Sprintf("--tls-cipher-suite=%s", suite))
+			}
+		}
 
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      "auth-proxy-tls-secret",
@@ -1243,7 +1474,7 @@ func NewOpenShiftAuthProxyContainer(cr *model.CryostatInstance, specs *ServiceSp
 	return &corev1.Container{
 		Name:            cr.Name + "-auth-proxy",
 		Image:           imageTag,
-		ImagePullPolicy: common.GetPullPolicy(imageTag),
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).AuthProxy, imageTag),
 		VolumeMounts:    volumeMounts,
 		Ports: []corev1.ContainerPort{
 			{
@@ -1303,6 +1534,10 @@ func getDefaultOpenShiftAccessRole(cr *model.CryostatInstance) authzv1.ResourceA
 
 func NewOAuth2ProxyContainer(cr *model.CryostatInstance, specs *ServiceSpecs, imageTag string,
 	tls *TLSConfig) (*corev1.Container, error) {
+	if IsOIDCEnabled(cr) && specs.AuthProxyURL == nil {
+		// The provider must be able to redirect users back to Cryostat after signing in
+		return nil, errOIDCMissingURL
+	}
 	var containerSc *corev1.SecurityContext
 	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.AuthProxySecurityContext != nil {
 		containerSc = cr.Spec.SecurityOptions.AuthProxySecurityContext
@@ -1316,14 +1551,15 @@ func NewOAuth2ProxyContainer(cr *model.CryostatInstance, specs *ServiceSpecs, im
 		}
 	}
 
+	redirectURL := fmt.Sprintf("http://localhost:%d/oauth2/callback", constants.AuthProxyHttpContainerPort)
+	if IsOIDCEnabled(cr) {
+		// The provider redirects users back to the externally accessible URL after signing in
+		redirectURL = specs.AuthProxyURL.JoinPath("oauth2", "callback").String()
+	}
 	envs := []corev1.EnvVar{
 		{
 			Name:  "OAUTH2_PROXY_REDIRECT_URL",
-			Value: fmt.Sprintf("http://localhost:%d/oauth2/callback", constants.AuthProxyHttpContainerPort),
-		},
-		{
-			Name:  "OAUTH2_PROXY_EMAIL_DOMAINS",
-			Value: "*",
+			Value: redirectURL,
 		},
 	}
 
@@ -1335,6 +1571,54 @@ func NewOAuth2ProxyContainer(cr *model.CryostatInstance, specs *ServiceSpecs, im
 		},
 	}
 
+	if IsOIDCEnabled(cr) {
+		oidc := cr.Spec.AuthorizationOptions.OIDC
+		// Without any allowed emails or domains, users are only restricted by their groups,
+		// since the webhook requires at least one of these to be specified
+		if len(oidc.AllowedEmailDomains) > 0 {
+			envs = append(envs, corev1.EnvVar{
+				Name:  "OAUTH2_PROXY_EMAIL_DOMAINS",
+				Value: strings.Join(oidc.AllowedEmailDomains, ","),
+			})
+		} else if len(oidc.AllowedEmails) == 0 {
+			envs = append(envs, corev1.EnvVar{
+				Name:  "OAUTH2_PROXY_EMAIL_DOMAINS",
+				Value: "*",
+			})
+		}
+		if len(oidc.AllowedEmails) > 0 {
+			envs = append(envs, corev1.EnvVar{
+				Name:  "OAUTH2_PROXY_AUTHENTICATED_EMAILS_FILE",
+				Value: path.Join(OAuth2ConfigFilePath, OAuth2AuthenticatedEmailsFileName),
+			})
+		}
+		if !isBasicAuthEnabled(cr) {
+			// Send users directly to the provider, since there is no sign in form to show
+			envs = append(envs, corev1.EnvVar{
+				Name:  "OAUTH2_PROXY_SKIP_PROVIDER_BUTTON",
+				Value: "true",
+			})
+		}
+
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      OIDCClientSecretName,
+			MountPath: path.Join(SecretMountPrefix, OIDCClientSecretName),
+			ReadOnly:  true,
+		})
+		if oidc.CACertificate != nil {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      OIDCCAName,
+				MountPath: path.Join(SecretMountPrefix, OIDCCAName),
+				ReadOnly:  true,
+			})
+		}
+	} else {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "OAUTH2_PROXY_EMAIL_DOMAINS",
+			Value: "*",
+		})
+	}
+
 	livenessProbeScheme := corev1.URISchemeHTTP
 	if tls != nil {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
@@ -1361,11 +1645,13 @@ func NewOAuth2ProxyContainer(cr *model.CryostatInstance, specs *ServiceSpecs, im
 				Name:  "OAUTH2_PROXY_HTPASSWD_USER_GROUP",
 				Value: "write",
 			},
-			{
-				Name:  "OAUTH2_PROXY_SKIP_AUTH_ROUTES",
-				Value: "^/health(/liveness)?$",
-			},
 		}...)
+	}
+	if isBasicAuthEnabled(cr) || IsOIDCEnabled(cr) {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "OAUTH2_PROXY_SKIP_AUTH_ROUTES",
+			Value: "^/health(/liveness)?$",
+		})
 	} else {
 		envs = append(envs, corev1.EnvVar{
 			Name:  "OAUTH2_PROXY_SKIP_AUTH_ROUTES",
@@ -1377,7 +1663,7 @@ func NewOAuth2ProxyContainer(cr *model.CryostatInstance, specs *ServiceSpecs, im
 	return &corev1.Container{
 		Name:            cr.Name + "-auth-proxy",
 		Image:           imageTag,
-		ImagePullPolicy: common.GetPullPolicy(imageTag),
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).AuthProxy, imageTag),
 		VolumeMounts:    volumeMounts,
 		Ports: []corev1.ContainerPort{
 			{
@@ -1514,6 +1800,14 @@ func NewCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs, imageTag
 		mounts = append(mounts, tlsSecretMount)
 	}
 
+	if caVolume := newExternalDatabaseCAVolume(cr); caVolume != nil {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      caVolume.Name,
+			MountPath: path.Join(SecretMountPrefix, externalDatabaseCAName),
+			ReadOnly:  true,
+		})
+	}
+
 	probeHandler := corev1.ProbeHandler{
 		Exec: &corev1.ExecAction{
 			Command: []string{
@@ -1545,7 +1839,7 @@ func NewCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs, imageTag
 	return &corev1.Container{
 		Name:            cr.Name,
 		Image:           imageTag,
-		ImagePullPolicy: common.GetPullPolicy(imageTag),
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).Core, imageTag),
 		VolumeMounts:    mounts,
 		Ports: []corev1.ContainerPort{
 			{
@@ -1612,7 +1906,7 @@ func newEnvForCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs, tls
 		},
 		{
 			Name:  "QUARKUS_DATASOURCE_USERNAME",
-			Value: "cryostat",
+			Value: getDatabaseUsername(cr),
 		},
 		{
 			Name:  "CRYOSTAT_CONFIG_PATH",
@@ -1627,11 +1921,6 @@ func newEnvForCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs, tls
 			// do not present a TLS client certificate
 			Value: "none",
 		},
-		{
-			Name: "QUARKUS_S3_SYNC_CLIENT_TLS_TRUST_MANAGERS_PROVIDER_TYPE",
-			// cryostat's truststore should include the storage certificate, so the S3 client should be able to load it from the system
-			Value: "system-property",
-		},
 		{
 			Name:  "QUARKUS_S3_AWS_CREDENTIALS_TYPE",
 			Value: "static",
@@ -1678,21 +1967,30 @@ func newDatabaseEnvForCoreContainer(cr *model.CryostatInstance, tls *TLSConfig)
 	optional := false
 	secretName := getDatabaseSecret(cr)
 
+	passwordRef := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: secretName,
+		},
+		Key:      constants.DatabaseSecretConnectionKey,
+		Optional: &optional,
+	}
+	if !DeployManagedDatabase(cr) && cr.Spec.DatabaseOptions.External.PasswordSecret != nil {
+		passwordRef = cr.Spec.DatabaseOptions.External.PasswordSecret
+	}
 	envs := []corev1.EnvVar{
 		{
 			Name: "QUARKUS_DATASOURCE_PASSWORD",
 			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: secretName,
-					},
-					Key:      constants.DatabaseSecretConnectionKey,
-					Optional: &optional,
-				},
+				SecretKeyRef: passwordRef,
 			},
 		},
 	}
-	if tls != nil {
+	if !DeployManagedDatabase(cr) {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "QUARKUS_DATASOURCE_JDBC_URL",
+			Value: newExternalDatabaseJDBCURL(cr),
+		})
+	} else if tls != nil {
 		tlsPath := path.Join(SecretMountPrefix, tls.DatabaseSecret)
 		envs = append(envs, corev1.EnvVar{
 			Name:  "QUARKUS_DATASOURCE_JDBC_URL",
@@ -1708,8 +2006,26 @@ func newDatabaseEnvForCoreContainer(cr *model.CryostatInstance, tls *TLSConfig)
 	return envs
 }
 
+func newExternalDatabaseJDBCURL(cr *model.CryostatInstance) string {
+	external := cr.Spec.DatabaseOptions.External
+	params := []string{}
+	if external.SSLMode != nil {
+		params = append(params, "sslmode="+*external.SSLMode)
+	}
+	if external.CACertificate != nil {
+		params = append(params, "sslrootcert="+path.Join(SecretMountPrefix, externalDatabaseCAName, constants.CAKey))
+	}
+	jdbcURL := fmt.Sprintf("jdbc:postgresql://%s/%s", ExternalDatabaseAddress(cr), url.PathEscape(ExternalDatabaseName(cr)))
+	if len(params) > 0 {
+		jdbcURL += "?" + strings.Join(params, "&")
+	}
+	return jdbcURL
+}
+
 func newStorageEnvForCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs) ([]corev1.EnvVar, error) {
 	optional := false
+	// cryostat's truststore should include the storage certificate, so the S3 client should be able to load it from the system
+	trustManagersProvider := "system-property"
 	secretName := getStorageSecret(cr)
 	envs := []corev1.EnvVar{
 		{
@@ -1866,17 +2182,16 @@ func newStorageEnvForCoreContainer(cr *model.CryostatInstance, specs *ServiceSpe
 			}
 		}
 
-		tlsTrustAll := false
-		if cr.Spec.ObjectStorageOptions.Provider != nil && cr.Spec.ObjectStorageOptions.Provider.TLSTrustAll != nil {
-			tlsTrustAll = *cr.Spec.ObjectStorageOptions.Provider.TLSTrustAll
-		}
-		if tlsTrustAll {
-			envs = append(envs, corev1.EnvVar{
-				Name:  "QUARKUS_S3_SYNC_CLIENT_TLS_TRUST_MANAGERS_PROVIDER_TYPE",
-				Value: "trust-all",
-			})
+		if cr.Spec.ObjectStorageOptions.Provider != nil && cr.Spec.ObjectStorageOptions.Provider.TLSTrustAll != nil &&
+			*cr.Spec.ObjectStorageOptions.Provider.TLSTrustAll {
+			trustManagersProvider = "trust-all"
 		}
 	}
+	// Env var names must be unique to be applied
+	envs = append(envs, corev1.EnvVar{
+		Name:  "QUARKUS_S3_SYNC_CLIENT_TLS_TRUST_MANAGERS_PROVIDER_TYPE",
+		Value: trustManagersProvider,
+	})
 
 	return envs, nil
 }
@@ -1944,6 +2259,8 @@ const (
 	defaultK8SDiscoveryEnabled     bool   = true
 	defaultK8SDiscoveryPortNames   string = "jfr-jmx"
 	defaultK8SDiscoveryPortNumbers string = "9091"
+	// Discovers workloads in every namespace
+	allNamespacesDiscovery string = "*"
 )
 
 func newK8SDiscoveryEnvForCoreContainer(cr *model.CryostatInstance) []corev1.EnvVar {
@@ -1972,10 +2289,14 @@ func newK8SDiscoveryEnvForCoreContainer(cr *model.CryostatInstance) []corev1.Env
 		},
 	}
 	if k8sDiscoveryEnabled {
+		discoveryNamespaces := strings.Join(cr.TargetNamespaces, ",")
+		if cr.Spec.AllNamespaces {
+			discoveryNamespaces = allNamespacesDiscovery
+		}
 		vars = append(vars,
 			corev1.EnvVar{
 				Name:  "CRYOSTAT_DISCOVERY_KUBERNETES_NAMESPACES",
-				Value: strings.Join(cr.TargetNamespaces, ","),
+				Value: discoveryNamespaces,
 			},
 			corev1.EnvVar{
 				Name:  "CRYOSTAT_DISCOVERY_KUBERNETES_PORT_NAMES",
@@ -2086,7 +2407,7 @@ func NewGrafanaContainer(cr *model.CryostatInstance, imageTag string, tls *TLSCo
 	return corev1.Container{
 		Name:            cr.Name + "-grafana",
 		Image:           imageTag,
-		ImagePullPolicy: common.GetPullPolicy(imageTag),
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).Grafana, imageTag),
 		Ports: []corev1.ContainerPort{
 			{
 				ContainerPort: constants.GrafanaContainerPort,
@@ -2231,7 +2552,7 @@ func NewStorageContainer(cr *model.CryostatInstance, imageTag string, tls *TLSCo
 	return corev1.Container{
 		Name:            cr.Name + "-storage",
 		Image:           imageTag,
-		ImagePullPolicy: common.GetPullPolicy(imageTag),
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).Storage, imageTag),
 		VolumeMounts:    mounts,
 		SecurityContext: containerSc,
 		Env:             envs,
@@ -2278,8 +2599,8 @@ func NewDatabaseContainer(cr *model.CryostatInstance, imageTag string, tls *TLSC
 
 	optional := false
 	secretName := getDatabaseSecret(cr)
-	mountPath := "/var/lib/pgsql"
-	dataDir := path.Join(mountPath, "data")
+	mountPath := databaseMountPath
+	dataDir := databaseDataDir
 	envs := []corev1.EnvVar{
 		{
 			Name:  "PGDATA",
@@ -2347,7 +2668,7 @@ func NewDatabaseContainer(cr *model.CryostatInstance, imageTag string, tls *TLSC
 	return corev1.Container{
 		Name:            cr.Name + "-db",
 		Image:           imageTag,
-		ImagePullPolicy: common.GetPullPolicy(imageTag),
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).Database, imageTag),
 		VolumeMounts:    mounts,
 		SecurityContext: containerSc,
 		Env:             envs,
@@ -2437,7 +2758,7 @@ func NewJfrDatasourceContainer(cr *model.CryostatInstance, imageTag string, serv
 	return corev1.Container{
 		Name:            cr.Name + "-jfr-datasource",
 		Image:           imageTag,
-		ImagePullPolicy: common.GetPullPolicy(imageTag),
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).DataSource, imageTag),
 		Ports: []corev1.ContainerPort{
 			{
 				ContainerPort: constants.DatasourceContainerPort,
@@ -2489,18 +2810,26 @@ func newAgentProxyContainer(cr *model.CryostatInstance, imageTag string, tls *TL
 		})
 	}
 
+	ports := []corev1.ContainerPort{
+		{
+			ContainerPort: constants.AgentProxyContainerPort,
+		},
+		{
+			ContainerPort: constants.AgentProxyHealthPort,
+		},
+	}
+	if IsRoleAuthorizationEnabled(cr) {
+		// Authorizes requests from the auth proxy, only listening within the pod
+		ports = append(ports, corev1.ContainerPort{
+			ContainerPort: constants.AuthorizationProxyPort,
+		})
+	}
+
 	return corev1.Container{
 		Name:            cr.Name + "-agent-proxy",
 		Image:           imageTag,
-		ImagePullPolicy: common.GetPullPolicy(imageTag),
-		Ports: []corev1.ContainerPort{
-			{
-				ContainerPort: constants.AgentProxyContainerPort,
-			},
-			{
-				ContainerPort: constants.AgentProxyHealthPort,
-			},
-		},
+		ImagePullPolicy: common.GetImagePullPolicy(getImageConfigs(cr).AgentProxy, imageTag),
+		Ports:           ports,
 		// Override the command to run nginx pointed at our config file. See:
 		// https://github.com/sclorg/nginx-container/blob/e7d8db9bc5299a4c4e254f8a82e917c7c136468b/1.24/README.md#direct-usage-with-a-mounted-directory
 		Command: []string{
@@ -2621,7 +2950,23 @@ func isBasicAuthEnabled(cr *model.CryostatInstance) bool {
 	return cr.Spec.AuthorizationOptions != nil && cr.Spec.AuthorizationOptions.BasicAuth != nil && cr.Spec.AuthorizationOptions.BasicAuth.SecretName != nil && cr.Spec.AuthorizationOptions.BasicAuth.Filename != nil
 }
 
+// IsRoleAuthorizationEnabled returns whether requests to Cryostat are restricted by the roles of
+// the user's groups, which is only enforced behind the OAuth2 Proxy when not deploying on OpenShift
+func IsRoleAuthorizationEnabled(cr *model.CryostatInstance) bool {
+	return cr.Spec.AuthorizationOptions != nil && len(cr.Spec.AuthorizationOptions.Roles) > 0
+}
+
+// IsOIDCEnabled returns whether users are authenticated by an OpenID Connect provider,
+// which is only used by the OAuth2 Proxy when not deploying on OpenShift
+func IsOIDCEnabled(cr *model.CryostatInstance) bool {
+	return cr.Spec.AuthorizationOptions != nil && cr.Spec.AuthorizationOptions.OIDC != nil
+}
+
 func getDatabaseSecret(cr *model.CryostatInstance) string {
+	// Keep using the current secret until the database password has been changed to match a newly configured one
+	if len(cr.Status.DatabaseSecret) > 0 {
+		return cr.Status.DatabaseSecret
+	}
 	if cr.Spec.DatabaseOptions != nil && cr.Spec.DatabaseOptions.SecretName != nil {
 		return *cr.Spec.DatabaseOptions.SecretName
 	}
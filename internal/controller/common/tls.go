@@ -21,6 +21,7 @@ import (
 
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	certMeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -31,6 +32,7 @@ import (
 // TLS-related functionality
 type ReconcilerTLS interface {
 	IsCertManagerEnabled(cr *model.CryostatInstance) bool
+	IsTLSEnabled(cr *model.CryostatInstance) bool
 	GetCertificateSecret(ctx context.Context, cert *certv1.Certificate) (*corev1.Secret, error)
 }
 
@@ -68,7 +70,17 @@ func NewReconcilerTLS(config *ReconcilerTLSConfig) ReconcilerTLS {
 // IsCertManagerEnabled returns whether TLS using cert-manager is enabled
 // for this operator
 func (r *reconcilerTLS) IsCertManagerEnabled(cr *model.CryostatInstance) bool {
-	// First check if cert-manager is explicitly enabled or disabled in CR
+	// Certificates provided by the user take the place of cert-manager
+	if ProvidedTLSCertificates(cr) != nil {
+		return false
+	}
+
+	// Next check if a provider is chosen in the CR
+	if provider := tlsProvider(cr); provider != nil {
+		return *provider == operatorv1beta2.TLSProviderCertManager
+	}
+
+	// Next check if cert-manager is explicitly enabled or disabled in CR
 	if cr.Spec.EnableCertManager != nil {
 		return *cr.Spec.EnableCertManager
 	}
@@ -77,6 +89,61 @@ func (r *reconcilerTLS) IsCertManagerEnabled(cr *model.CryostatInstance) bool {
 	return strings.ToLower(r.OS.GetEnv(disableServiceTLS)) != "true"
 }
 
+// IsTLSEnabled returns whether TLS is enabled for communication between
+// Cryostat components, using cert-manager, the operator's built-in CA, the
+// OpenShift service CA or certificates provided by the user
+func (r *reconcilerTLS) IsTLSEnabled(cr *model.CryostatInstance) bool {
+	return ProvidedTLSCertificates(cr) != nil || IsBuiltInCAEnabled(cr) || IsServiceCAEnabled(cr) ||
+		r.IsCertManagerEnabled(cr)
+}
+
+// IsBuiltInCAEnabled returns whether certificates are issued by a CA
+// managed by the operator, instead of cert-manager
+func IsBuiltInCAEnabled(cr *model.CryostatInstance) bool {
+	provider := tlsProvider(cr)
+	return ProvidedTLSCertificates(cr) == nil && provider != nil && *provider == operatorv1beta2.TLSProviderBuiltIn
+}
+
+// IsServiceCAEnabled returns whether the certificates of Cryostat's services
+// are issued by the OpenShift service CA, instead of cert-manager
+func IsServiceCAEnabled(cr *model.CryostatInstance) bool {
+	provider := tlsProvider(cr)
+	return ProvidedTLSCertificates(cr) == nil && provider != nil && *provider == operatorv1beta2.TLSProviderServiceCA
+}
+
+// ProvidedTLSCertificates returns the TLS certificates provided by the user
+// in the CR, or nil if cert-manager should issue them instead
+func ProvidedTLSCertificates(cr *model.CryostatInstance) *operatorv1beta2.TLSCertificates {
+	if cr.Spec.TLS == nil {
+		return nil
+	}
+	return cr.Spec.TLS.Certificates
+}
+
+// TLSIssuerRef returns the cert-manager issuer referenced in the CR, or nil
+// if certificates should be issued by the Cryostat CA instead
+func TLSIssuerRef(cr *model.CryostatInstance) *operatorv1beta2.IssuerReference {
+	if cr.Spec.TLS == nil {
+		return nil
+	}
+	return cr.Spec.TLS.IssuerRef
+}
+
+// TLSPrivateKeyOptions returns the private key options in the CR, or nil if not set
+func TLSPrivateKeyOptions(cr *model.CryostatInstance) *operatorv1beta2.TLSPrivateKey {
+	if cr.Spec.TLS == nil {
+		return nil
+	}
+	return cr.Spec.TLS.PrivateKey
+}
+
+func tlsProvider(cr *model.CryostatInstance) *operatorv1beta2.TLSProvider {
+	if cr.Spec.TLS == nil {
+		return nil
+	}
+	return cr.Spec.TLS.Provider
+}
+
 // ErrCertNotReady is returned when cert-manager has not marked the certificate
 // as ready, and no TLS secret has been populated yet.
 var ErrCertNotReady error = errors.New("certificate secret not yet ready")
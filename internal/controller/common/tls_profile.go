@@ -0,0 +1,233 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	cryptotls "crypto/tls"
+	"slices"
+	"strings"
+
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	configv1 "github.com/openshift/api/config/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The canonical name of an APIServer instance
+const apiServerName = "cluster"
+
+// TLS protocol versions in increasing order, with their names used by OpenSSL and Java
+var tlsProtocolVersions = []struct {
+	version configv1.TLSProtocolVersion
+	name    string
+}{
+	{configv1.VersionTLS10, "TLSv1"},
+	{configv1.VersionTLS11, "TLSv1.1"},
+	{configv1.VersionTLS12, "TLSv1.2"},
+	{configv1.VersionTLS13, "TLSv1.3"},
+}
+
+// IANA names of the ciphers used in TLS security profiles, which are specified in OpenSSL format.
+// TLS 1.3 cipher suites use the same name in both formats.
+var ianaCipherSuites = map[string]string{
+	"TLS_AES_128_GCM_SHA256":        "TLS_AES_128_GCM_SHA256",
+	"TLS_AES_256_GCM_SHA384":        "TLS_AES_256_GCM_SHA384",
+	"TLS_CHACHA20_POLY1305_SHA256":  "TLS_CHACHA20_POLY1305_SHA256",
+	"ECDHE-ECDSA-AES128-GCM-SHA256": "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"ECDHE-RSA-AES128-GCM-SHA256":   "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"ECDHE-ECDSA-AES256-GCM-SHA384": "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE-RSA-AES256-GCM-SHA384":   "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE-ECDSA-CHACHA20-POLY1305": "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+	"ECDHE-RSA-CHACHA20-POLY1305":   "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	"DHE-RSA-AES128-GCM-SHA256":     "TLS_DHE_RSA_WITH_AES_128_GCM_SHA256",
+	"DHE-RSA-AES256-GCM-SHA384":     "TLS_DHE_RSA_WITH_AES_256_GCM_SHA384",
+	"DHE-RSA-CHACHA20-POLY1305":     "TLS_DHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	"ECDHE-ECDSA-AES128-SHA256":     "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256",
+	"ECDHE-RSA-AES128-SHA256":       "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256",
+	"ECDHE-ECDSA-AES128-SHA":        "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
+	"ECDHE-RSA-AES128-SHA":          "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+	"ECDHE-ECDSA-AES256-SHA384":     "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA384",
+	"ECDHE-RSA-AES256-SHA384":       "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA384",
+	"ECDHE-ECDSA-AES256-SHA":        "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
+	"ECDHE-RSA-AES256-SHA":          "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+	"DHE-RSA-AES128-SHA256":         "TLS_DHE_RSA_WITH_AES_128_CBC_SHA256",
+	"DHE-RSA-AES256-SHA256":         "TLS_DHE_RSA_WITH_AES_256_CBC_SHA256",
+	"AES128-GCM-SHA256":             "TLS_RSA_WITH_AES_128_GCM_SHA256",
+	"AES256-GCM-SHA384":             "TLS_RSA_WITH_AES_256_GCM_SHA384",
+	"AES128-SHA256":                 "TLS_RSA_WITH_AES_128_CBC_SHA256",
+	"AES256-SHA256":                 "TLS_RSA_WITH_AES_256_CBC_SHA256",
+	"AES128-SHA":                    "TLS_RSA_WITH_AES_128_CBC_SHA",
+	"AES256-SHA":                    "TLS_RSA_WITH_AES_256_CBC_SHA",
+	"DES-CBC3-SHA":                  "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+}
+
+// FIPS-approved ciphers that may be used from TLS security profiles, in OpenSSL format
+var fipsCiphers = []string{
+	"TLS_AES_128_GCM_SHA256",
+	"TLS_AES_256_GCM_SHA384",
+	"ECDHE-ECDSA-AES128-GCM-SHA256",
+	"ECDHE-RSA-AES128-GCM-SHA256",
+	"ECDHE-ECDSA-AES256-GCM-SHA384",
+	"ECDHE-RSA-AES256-GCM-SHA384",
+	"DHE-RSA-AES128-GCM-SHA256",
+	"DHE-RSA-AES256-GCM-SHA384",
+	"ECDHE-ECDSA-AES128-SHA256",
+	"ECDHE-RSA-AES128-SHA256",
+	"ECDHE-ECDSA-AES256-SHA384",
+	"ECDHE-RSA-AES256-SHA384",
+}
+
+// TLSSecurityProfile returns the TLS security profile for servers managed by the operator.
+// The profile in the CR takes precedence, followed by the cluster-wide profile on OpenShift,
+// and otherwise the Intermediate profile is used. In FIPS mode, the profile is limited to
+// FIPS-approved ciphers and TLS 1.2 or later.
+func TLSSecurityProfile(ctx context.Context, reader client.Reader, cr *model.CryostatInstance,
+	openshift bool, fips bool) (*configv1.TLSProfileSpec, error) {
+	if openshift && (cr.Spec.TLS == nil || cr.Spec.TLS.SecurityProfile == nil) {
+		apiServer := &configv1.APIServer{}
+		err := reader.Get(ctx, types.NamespacedName{Name: apiServerName}, apiServer)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+		return restrictTLSProfile(tlsProfileSpec(apiServer.Spec.TLSSecurityProfile), fips), nil
+	}
+	return DefaultTLSSecurityProfile(cr, fips), nil
+}
+
+// DefaultTLSSecurityProfile returns the TLS security profile in the CR, or the Intermediate
+// profile if none is specified, without consulting the cluster-wide profile. In FIPS mode,
+// the profile is limited as in TLSSecurityProfile.
+func DefaultTLSSecurityProfile(cr *model.CryostatInstance, fips bool) *configv1.TLSProfileSpec {
+	var profile *configv1.TLSSecurityProfile
+	if cr.Spec.TLS != nil {
+		profile = cr.Spec.TLS.SecurityProfile
+	}
+	return restrictTLSProfile(tlsProfileSpec(profile), fips)
+}
+
+func restrictTLSProfile(spec *configv1.TLSProfileSpec, fips bool) *configv1.TLSProfileSpec {
+	if fips {
+		ciphers := []string{}
+		for _, cipher := range spec.Ciphers {
+			if slices.Contains(fipsCiphers, cipher) {
+				ciphers = append(ciphers, cipher)
+			}
+		}
+		spec.Ciphers = ciphers
+		if tlsVersionIndex(spec.MinTLSVersion) < tlsVersionIndex(configv1.VersionTLS12) {
+			spec.MinTLSVersion = configv1.VersionTLS12
+		}
+	}
+	return spec
+}
+
+func tlsProfileSpec(profile *configv1.TLSSecurityProfile) *configv1.TLSProfileSpec {
+	intermediate := configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+	if profile == nil {
+		return intermediate.DeepCopy()
+	}
+	if profile.Type == configv1.TLSProfileCustomType {
+		if profile.Custom == nil {
+			return intermediate.DeepCopy()
+		}
+		return profile.Custom.TLSProfileSpec.DeepCopy()
+	}
+	spec, ok := configv1.TLSProfiles[profile.Type]
+	if !ok {
+		return intermediate.DeepCopy()
+	}
+	return spec.DeepCopy()
+}
+
+// tlsVersionIndex returns the position of the TLS version in increasing order,
+// treating an unknown version as TLS 1.2
+func tlsVersionIndex(version configv1.TLSProtocolVersion) int {
+	for i, v := range tlsProtocolVersions {
+		if v.version == version {
+			return i
+		}
+	}
+	return tlsVersionIndex(configv1.VersionTLS12)
+}
+
+// TLSProtocols returns the names of the TLS protocol versions allowed by the profile,
+// as used by OpenSSL and Java, in increasing order
+func TLSProtocols(spec *configv1.TLSProfileSpec) []string {
+	names := []string{}
+	for _, v := range tlsProtocolVersions[tlsVersionIndex(spec.MinTLSVersion):] {
+		names = append(names, v.name)
+	}
+	return names
+}
+
+// OpenSSLCiphers returns the ciphers in the profile for TLS 1.2 and earlier, in OpenSSL format.
+// TLS 1.3 cipher suites are configured separately by OpenSSL.
+func OpenSSLCiphers(spec *configv1.TLSProfileSpec) []string {
+	ciphers := []string{}
+	for _, cipher := range spec.Ciphers {
+		if !isTLS13CipherSuite(cipher) {
+			ciphers = append(ciphers, cipher)
+		}
+	}
+	return ciphers
+}
+
+// TLS13CipherSuites returns the TLS 1.3 cipher suites in the profile
+func TLS13CipherSuites(spec *configv1.TLSProfileSpec) []string {
+	suites := []string{}
+	for _, cipher := range spec.Ciphers {
+		if isTLS13CipherSuite(cipher) {
+			suites = append(suites, cipher)
+		}
+	}
+	return suites
+}
+
+func isTLS13CipherSuite(cipher string) bool {
+	// Only TLS 1.3 cipher suites use IANA names in OpenSSL format
+	return strings.HasPrefix(cipher, "TLS_")
+}
+
+// TLS12CipherSuites returns the IANA names of the cipher suites in the profile that Go supports
+// for TLS 1.2, since cipher suites for TLS 1.3 are not configurable in Go servers
+func TLS12CipherSuites(spec *configv1.TLSProfileSpec) []string {
+	supported := []string{}
+	for _, suite := range append(cryptotls.CipherSuites(), cryptotls.InsecureCipherSuites()...) {
+		if slices.Contains(suite.SupportedVersions, cryptotls.VersionTLS12) {
+			supported = append(supported, suite.Name)
+		}
+	}
+	var suites []string
+	for _, suite := range IANACipherSuites(spec) {
+		if slices.Contains(supported, suite) {
+			suites = append(suites, suite)
+		}
+	}
+	return suites
+}
+
+// IANACipherSuites returns the ciphers in the profile using their IANA names, as used by Go and Java.
+// Ciphers without a known IANA name are omitted.
+func IANACipherSuites(spec *configv1.TLSProfileSpec) []string {
+	suites := []string{}
+	for _, cipher := range spec.Ciphers {
+		if suite, ok := ianaCipherSuites[cipher]; ok {
+			suites = append(suites, suite)
+		}
+	}
+	return suites
+}
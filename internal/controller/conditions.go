@@ -0,0 +1,179 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	reasonAllComponentsReady     = "AllComponentsReady"
+	reasonComponentsNotReady     = "ComponentsNotReady"
+	reasonComponentsUpdating     = "ComponentsUpdating"
+	reasonComponentFailed        = "ComponentFailed"
+	reasonNewGeneration          = "NewGeneration"
+	reasonNewReplicaSetAvailable = "NewReplicaSetAvailable"
+	reasonRolloutInProgress      = "RolloutInProgress"
+)
+
+// Conditions reporting that a component failed to reconcile, in the order components are reconciled
+var componentReconcileFailedConditions = []operatorv1beta2.CryostatConditionType{
+	operatorv1beta2.ConditionTypeSecretsReconcileFailed,
+	operatorv1beta2.ConditionTypeRBACReconcileFailed,
+	operatorv1beta2.ConditionTypeTLSReconcileFailed,
+	operatorv1beta2.ConditionTypeDatabaseReconcileFailed,
+	operatorv1beta2.ConditionTypeStorageReconcileFailed,
+	operatorv1beta2.ConditionTypeCredentialsReconcileFailed,
+	operatorv1beta2.ConditionTypeDatabaseBackupReconcileFailed,
+	operatorv1beta2.ConditionTypeReportsReconcileFailed,
+	operatorv1beta2.ConditionTypeCoreReconcileFailed,
+	operatorv1beta2.ConditionTypeOpenShiftReconcileFailed,
+}
+
+// Component conditions that are aggregated into the Ready, Reconciling and Stalled conditions
+var componentDeploymentConditions = []deploymentConditionTypeMap{
+	mainDeploymentConditions,
+	databaseDeploymentConditions,
+	storageDeploymentConditions,
+	reportsDeploymentConditions,
+}
+
+// setAggregateConditions computes the top-level Ready, Reconciling and Stalled conditions
+// from the component conditions, following the kstatus conventions used by tools such as
+// Argo CD and Flux. Reconciling and Stalled are only present when true.
+func setAggregateConditions(cr *model.CryostatInstance) {
+	generation := cr.Object.GetGeneration()
+	notReady := []string{}
+	updating := []string{}
+	failed := []string{}
+	reconcileFailed := []string{}
+
+	for _, condType := range componentReconcileFailedConditions {
+		// Retried with backoff, so these do not stall the CR
+		if meta.IsStatusConditionTrue(cr.Status.Conditions, string(condType)) {
+			reconcileFailed = append(reconcileFailed, string(condType))
+		}
+	}
+	for _, mapping := range componentDeploymentConditions {
+		for condType, deployCondType := range mapping {
+			condition := meta.FindStatusCondition(cr.Status.Conditions, string(condType))
+			if condition == nil {
+				continue
+			}
+			switch deployCondType {
+			case appsv1.DeploymentAvailable:
+				if condition.Status != metav1.ConditionTrue {
+					notReady = append(notReady, condition.Type)
+				}
+			case appsv1.DeploymentProgressing:
+				if condition.Status == metav1.ConditionFalse {
+					// The deployment has exceeded its progress deadline
+					failed = append(failed, condition.Type)
+				} else if condition.Reason != reasonNewReplicaSetAvailable {
+					updating = append(updating, condition.Type)
+				}
+			case appsv1.DeploymentReplicaFailure:
+				if condition.Status == metav1.ConditionTrue {
+					failed = append(failed, condition.Type)
+				}
+			}
+		}
+	}
+
+	// The main deployment is always required
+	if meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeMainDeploymentAvailable)) == nil {
+		notReady = append(notReady, string(operatorv1beta2.ConditionTypeMainDeploymentAvailable))
+	}
+	tlsCondition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeTLSSetupComplete))
+	if tlsCondition != nil && tlsCondition.Status != metav1.ConditionTrue {
+		if tlsCondition.Reason == reasonCertManagerUnavailable {
+			// Requires the user to install cert-manager or disable the integration
+			failed = append(failed, tlsCondition.Type)
+		} else {
+			notReady = append(notReady, tlsCondition.Type)
+		}
+	}
+	if isDatabaseUnreachable(cr) {
+		notReady = append(notReady, string(operatorv1beta2.ConditionTypeDatabaseReachable))
+	}
+	if isDatabaseUpgrading(cr) {
+		notReady = append(notReady, string(operatorv1beta2.ConditionTypeDatabaseUpgrading))
+	} else if meta.IsStatusConditionTrue(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeDatabaseUpgradeFailed)) {
+		// Requires the user to investigate the failed upgrade before retrying
+		failed = append(failed, string(operatorv1beta2.ConditionTypeDatabaseUpgradeFailed))
+	}
+	slices.Sort(notReady)
+	slices.Sort(updating)
+	slices.Sort(failed)
+
+	newGeneration := cr.Status.ObservedGeneration != generation
+	switch {
+	case newGeneration:
+		setAggregateCondition(cr, operatorv1beta2.ConditionTypeReconciling, metav1.ConditionTrue, reasonNewGeneration,
+			fmt.Sprintf("Reconciling generation %d.", generation))
+	case len(updating) > 0:
+		setAggregateCondition(cr, operatorv1beta2.ConditionTypeReconciling, metav1.ConditionTrue, reasonComponentsUpdating,
+			fmt.Sprintf("Components are being updated: %s.", strings.Join(updating, ", ")))
+	default:
+		removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeReconciling)
+	}
+
+	if len(failed) > 0 {
+		setAggregateCondition(cr, operatorv1beta2.ConditionTypeStalled, metav1.ConditionTrue, reasonComponentFailed,
+			fmt.Sprintf("Components have failed: %s.", strings.Join(failed, ", ")))
+	} else {
+		removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeStalled)
+	}
+
+	switch {
+	case len(failed) > 0:
+		setAggregateCondition(cr, operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse, reasonComponentFailed,
+			fmt.Sprintf("Components have failed: %s.", strings.Join(failed, ", ")))
+	case len(reconcileFailed) > 0:
+		setAggregateCondition(cr, operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse, reasonReconcileFailed,
+			fmt.Sprintf("Components failed to reconcile: %s.", strings.Join(reconcileFailed, ", ")))
+	case len(notReady) > 0:
+		setAggregateCondition(cr, operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse, reasonComponentsNotReady,
+			fmt.Sprintf("Components are not ready: %s.", strings.Join(notReady, ", ")))
+	case newGeneration:
+		setAggregateCondition(cr, operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse, reasonNewGeneration,
+			fmt.Sprintf("Reconciling generation %d.", generation))
+	case len(updating) > 0:
+		setAggregateCondition(cr, operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse, reasonComponentsUpdating,
+			fmt.Sprintf("Components are being updated: %s.", strings.Join(updating, ", ")))
+	default:
+		setAggregateCondition(cr, operatorv1beta2.ConditionTypeReady, metav1.ConditionTrue, reasonAllComponentsReady,
+			"All Cryostat components are ready.")
+	}
+}
+
+func setAggregateCondition(cr *model.CryostatInstance, condType operatorv1beta2.CryostatConditionType,
+	status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               string(condType),
+		Status:             status,
+		ObservedGeneration: cr.Object.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -21,15 +21,19 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"regexp"
+	"strings"
 	"text/template"
 
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controller/common"
 	resources "github.com/cryostatio/cryostat-operator/internal/controller/common/resource_definitions"
 	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	configv1 "github.com/openshift/api/config/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func (r *Reconciler) reconcileLockConfigMap(ctx context.Context, cr *model.CryostatInstance) error {
@@ -43,9 +47,19 @@ func (r *Reconciler) reconcileLockConfigMap(ctx context.Context, cr *model.Cryos
 }
 
 type oauth2ProxyAlphaConfig struct {
-	Server         alphaConfigServer         `json:"server,omitempty"`
-	UpstreamConfig alphaConfigUpstreamConfig `json:"upstreamConfig,omitempty"`
-	Providers      []alphaConfigProvider     `json:"providers,omitempty"`
+	Server               alphaConfigServer         `json:"server,omitempty"`
+	UpstreamConfig       alphaConfigUpstreamConfig `json:"upstreamConfig,omitempty"`
+	InjectRequestHeaders []alphaConfigHeader       `json:"injectRequestHeaders,omitempty"`
+	Providers            []alphaConfigProvider     `json:"providers,omitempty"`
+}
+
+type alphaConfigHeader struct {
+	Name   string                   `json:"name,omitempty"`
+	Values []alphaConfigHeaderValue `json:"values,omitempty"`
+}
+
+type alphaConfigHeaderValue struct {
+	Claim string `json:"claim,omitempty"`
 }
 
 type alphaConfigServer struct {
@@ -55,8 +69,10 @@ type alphaConfigServer struct {
 }
 
 type proxyTLS struct {
-	Key  tlsSecretSource `json:"Key,omitempty"`
-	Cert tlsSecretSource `json:"Cert,omitempty"`
+	Key          tlsSecretSource `json:"Key,omitempty"`
+	Cert         tlsSecretSource `json:"Cert,omitempty"`
+	MinVersion   string          `json:"MinVersion,omitempty"`
+	CipherSuites []string        `json:"CipherSuites,omitempty"`
 }
 
 type tlsSecretSource struct {
@@ -69,11 +85,25 @@ type alphaConfigUpstreamConfig struct {
 }
 
 type alphaConfigProvider struct {
-	Id           string `json:"id,omitempty"`
-	Name         string `json:"name,omitempty"`
-	ClientId     string `json:"clientId,omitempty"`
-	ClientSecret string `json:"clientSecret,omitempty"`
-	Provider     string `json:"provider,omitempty"`
+	Id                  string                 `json:"id,omitempty"`
+	Name                string                 `json:"name,omitempty"`
+	ClientId            string                 `json:"clientId,omitempty"`
+	ClientSecret        string                 `json:"clientSecret,omitempty"`
+	ClientSecretFile    string                 `json:"clientSecretFile,omitempty"`
+	Provider            string                 `json:"provider,omitempty"`
+	Scope               string                 `json:"scope,omitempty"`
+	AllowedGroups       []string               `json:"allowedGroups,omitempty"`
+	CAFiles             []string               `json:"caFiles,omitempty"`
+	UseSystemTrustStore bool                   `json:"useSystemTrustStore,omitempty"`
+	OIDCConfig          *alphaConfigOIDCConfig `json:"oidcConfig,omitempty"`
+}
+
+type alphaConfigOIDCConfig struct {
+	IssuerURL      string   `json:"issuerURL,omitempty"`
+	EmailClaim     string   `json:"emailClaim,omitempty"`
+	GroupsClaim    string   `json:"groupsClaim,omitempty"`
+	UserIDClaim    string   `json:"userIDClaim,omitempty"`
+	AudienceClaims []string `json:"audienceClaims,omitempty"`
 }
 
 type alphaConfigUpstream struct {
@@ -111,6 +141,21 @@ func (r *Reconciler) reconcileOAuth2ProxyConfig(ctx context.Context, cr *model.C
 		}},
 		Providers: []alphaConfigProvider{{Id: "dummy", Name: "Unused - Sign In Below", ClientId: "CLIENT_ID", ClientSecret: "CLIENT_SECRET", Provider: "google"}},
 	}
+	if resources.IsOIDCEnabled(cr) {
+		cfg.Providers = []alphaConfigProvider{newOIDCProvider(cr.Spec.AuthorizationOptions.OIDC)}
+	}
+	if resources.IsRoleAuthorizationEnabled(cr) {
+		// Send requests for Cryostat and Grafana through the agent proxy's nginx, which allows
+		// or denies them according to the roles of the user's groups
+		cfg.UpstreamConfig.Upstreams[0].Uri = fmt.Sprintf("http://localhost:%d", constants.AuthorizationProxyPort)
+		cfg.UpstreamConfig.Upstreams[1].Uri = fmt.Sprintf("http://localhost:%d", constants.AuthorizationProxyPort)
+		cfg.InjectRequestHeaders = []alphaConfigHeader{
+			{
+				Name:   roleGroupsHeader,
+				Values: []alphaConfigHeaderValue{{Claim: "groups"}},
+			},
+		}
+	}
 
 	if tls != nil {
 		cfg.Server.SecureBindAddress = fmt.Sprintf("https://%s:%d", bindHost, constants.AuthProxyHttpContainerPort)
@@ -122,6 +167,10 @@ func (r *Reconciler) reconcileOAuth2ProxyConfig(ctx context.Context, cr *model.C
 				FromFile: path.Join(resources.SecretMountPrefix, tls.CryostatSecret, corev1.TLSCertKey),
 			},
 		}
+		if tls.SecurityProfile != nil {
+			cfg.Server.TLS.MinVersion = oauth2ProxyMinTLSVersion(tls.SecurityProfile)
+			cfg.Server.TLS.CipherSuites = common.TLS12CipherSuites(tls.SecurityProfile)
+		}
 	} else {
 		cfg.Server.BindAddress = fmt.Sprintf("http://%s:%d", bindHost, constants.AuthProxyHttpContainerPort)
 	}
@@ -143,11 +192,56 @@ func (r *Reconciler) reconcileOAuth2ProxyConfig(ctx context.Context, cr *model.C
 		data := map[string]string{
 			resources.OAuth2ConfigFileName: string(encoded),
 		}
+		if resources.IsOIDCEnabled(cr) && len(cr.Spec.AuthorizationOptions.OIDC.AllowedEmails) > 0 {
+			data[resources.OAuth2AuthenticatedEmailsFileName] = strings.Join(cr.Spec.AuthorizationOptions.OIDC.AllowedEmails, "\n") + "\n"
+		}
 
 		return r.createOrUpdateConfigMap(ctx, cm, cr.Object, data)
 	}
 }
 
+// Scopes requested from an OpenID Connect provider, if not specified in the CR
+var defaultOIDCScopes = []string{"openid", "email", "profile"}
+
+// newOIDCProvider creates the OAuth2 Proxy configuration for the OpenID Connect provider in the CR.
+// The client secret and CA certificate are read from files mounted into the auth proxy container.
+func newOIDCProvider(oidc *operatorv1beta2.OIDCConfig) alphaConfigProvider {
+	scopes := oidc.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultOIDCScopes
+	}
+	provider := alphaConfigProvider{
+		Id:               "oidc",
+		Name:             "OpenID Connect",
+		ClientId:         oidc.ClientID,
+		ClientSecretFile: path.Join(resources.SecretMountPrefix, resources.OIDCClientSecretName, resources.OIDCClientSecretFileName),
+		Provider:         "oidc",
+		Scope:            strings.Join(scopes, " "),
+		AllowedGroups:    oidc.AllowedGroups,
+		OIDCConfig: &alphaConfigOIDCConfig{
+			IssuerURL:      oidc.IssuerURL,
+			EmailClaim:     "email",
+			GroupsClaim:    "groups",
+			UserIDClaim:    "email",
+			AudienceClaims: []string{"aud"},
+		},
+	}
+	if oidc.CACertificate != nil {
+		provider.CAFiles = []string{path.Join(resources.SecretMountPrefix, resources.OIDCCAName, constants.CAKey)}
+		provider.UseSystemTrustStore = true
+	}
+	return provider
+}
+
+// oauth2ProxyMinTLSVersion returns the minimum TLS version for oauth2-proxy,
+// which supports only TLS 1.2 and 1.3
+func oauth2ProxyMinTLSVersion(profile *configv1.TLSProfileSpec) string {
+	if profile.MinTLSVersion == configv1.VersionTLS13 {
+		return "TLS1.3"
+	}
+	return "TLS1.2"
+}
+
 type nginxConfParams struct {
 	// Hostname of the server
 	ServerName string
@@ -159,8 +253,16 @@ type nginxConfParams struct {
 	TLSKeyFile string
 	// Path to CA certificate
 	CACertFile string
+	// Organizational unit required in the subject of client certificates, if any
+	ClientOrganizationalUnit string
 	// Diffie-Hellman parameters file
 	DHParamFile string
+	// Allowed TLS protocol versions, separated by spaces
+	TLSProtocols string
+	// Allowed ciphers for TLS 1.2 and earlier, separated by colons
+	TLSCiphers string
+	// Allowed cipher suites for TLS 1.3, separated by colons
+	TLSCipherSuites string
 	// Nginx proxy container port
 	ContainerPort int32
 	// Nginx health container port
@@ -169,8 +271,28 @@ type nginxConfParams struct {
 	CryostatPort int32
 	// Only these path prefixes will be proxied, others will return 404
 	AllowedPathPrefixes []string
+	// Port for requests from the auth proxy to Cryostat and Grafana, which are authorized using the roles
+	AuthorizationPort int32
+	// Grafana container port, for requests authorized using the roles
+	GrafanaPort int32
+	// Roles allowing requests from the auth proxy to Cryostat, if any
+	Roles []nginxRoleParams
 }
 
+type nginxRoleParams struct {
+	// Allowed HTTP methods, or all methods if empty
+	Methods []string
+	// Regular expressions matching the allowed paths, or all paths if empty
+	PathPatterns []string
+	// Regular expressions matching the groups header of a member of the role's groups
+	GroupPatterns []string
+}
+
+// Header containing the groups of the user, which is set by the auth proxy
+const roleGroupsHeader = "X-Forwarded-Groups"
+
+var errRolesUnavailable = errors.New("authorization roles are not available on OpenShift")
+
 // Reference: https://ssl-config.mozilla.org
 var nginxConfTemplate = template.Must(template.New("").Parse(`worker_processes auto;
 error_log stderr notice;
@@ -198,6 +320,56 @@ http {
 
 	include             /etc/nginx/mime.types;
 	default_type        application/octet-stream;
+	{{- range $i, $role := .Roles }}
+
+	# Role {{ $i }}: allow requests matching the method, path and groups of the role
+	map $request_method $cryostat_role_{{ $i }}_method {
+		{{- if $role.Methods }}
+		default 0;
+		{{- range $role.Methods }}
+		{{ . }} 1;
+		{{- end }}
+		{{- else }}
+		default 1;
+		{{- end }}
+	}
+
+	map $uri $cryostat_role_{{ $i }}_path {
+		{{- if $role.PathPatterns }}
+		default 0;
+		{{- range $role.PathPatterns }}
+		"~{{ . }}" 1;
+		{{- end }}
+		{{- else }}
+		default 1;
+		{{- end }}
+	}
+
+	map $http_x_forwarded_groups $cryostat_role_{{ $i }}_group {
+		default 0;
+		{{- range $role.GroupPatterns }}
+		"~{{ . }}" 1;
+		{{- end }}
+	}
+
+	map "$cryostat_role_{{ $i }}_method$cryostat_role_{{ $i }}_path$cryostat_role_{{ $i }}_group" $cryostat_role_{{ $i }} {
+		default 0;
+		111 1;
+	}
+	{{- end }}
+	{{- if .Roles }}
+
+	# Deny requests not allowed by any role
+	map "{{ range $i, $role := .Roles }}$cryostat_role_{{ $i }}{{ end }}" $cryostat_role_allowed {
+		default 1;
+		"~^0+$" 0;
+	}
+
+	map $http_upgrade $connection_upgrade {
+		default upgrade;
+		'' close;
+	}
+	{{- end }}
 
 	server {
 		server_name {{ .ServerName }};
@@ -215,9 +387,14 @@ http {
 
 		ssl_dhparam {{ .DHParamFile }};
 
-		# intermediate configuration
-		ssl_protocols TLSv1.2 TLSv1.3;
-		ssl_ciphers ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384:ECDHE-ECDSA-CHACHA20-POLY1305:ECDHE-RSA-CHACHA20-POLY1305:DHE-RSA-AES128-GCM-SHA256:DHE-RSA-AES256-GCM-SHA384:DHE-RSA-CHACHA20-POLY1305;
+		# TLS security profile
+		ssl_protocols {{ .TLSProtocols }};
+		{{- if .TLSCiphers }}
+		ssl_ciphers {{ .TLSCiphers }};
+		{{- end }}
+		{{- if .TLSCipherSuites }}
+		ssl_conf_command Ciphersuites {{ .TLSCipherSuites }};
+		{{- end }}
 		ssl_prefer_server_ciphers off;
 
 		# HSTS (ngx_http_headers_module is required) (63072000 seconds)
@@ -232,6 +409,13 @@ http {
 		# Client certificate authentication
 		ssl_client_certificate {{ .CACertFile }};
 		ssl_verify_client on;
+		{{- if .ClientOrganizationalUnit }}
+
+		# Only accept client certificates issued for agents of this Cryostat instance
+		if ($ssl_client_s_dn !~ "(^|,)OU={{ .ClientOrganizationalUnit }}(,|$)") {
+			return 403;
+		}
+		{{- end }}
 
 		{{- else -}}
 
@@ -269,10 +453,49 @@ http {
 			return 404;
 		}
 	}
+	{{- if .Roles }}
+
+	# Role-based authorization of requests from the auth proxy to Cryostat
+	server {
+		listen 127.0.0.1:{{ .AuthorizationPort }};
+
+		proxy_http_version 1.1;
+		proxy_set_header Host $http_host;
+		proxy_set_header Upgrade $http_upgrade;
+		proxy_set_header Connection $connection_upgrade;
+		# Keep idle notification WebSockets open
+		proxy_read_timeout 1h;
+
+		# Forward the normalized URI that was authorized, rather than the URI sent by the client.
+		# Giving proxy_pass a URI makes nginx encode the normalized URI again when forwarding it.
+		location = /health {
+			proxy_pass http://127.0.0.1:{{ .CryostatPort }}/health;
+		}
+
+		location = /health/liveness {
+			proxy_pass http://127.0.0.1:{{ .CryostatPort }}/health/liveness;
+		}
+
+		location /grafana/ {
+			if ($cryostat_role_allowed = 0) {
+				return 403;
+			}
+			proxy_pass http://127.0.0.1:{{ .GrafanaPort }}/grafana/;
+		}
+
+		location / {
+			if ($cryostat_role_allowed = 0) {
+				return 403;
+			}
+			proxy_pass http://127.0.0.1:{{ .CryostatPort }}/;
+		}
+	}
+	{{- end }}
 }`))
 
 const (
-	dhFileName = "dhparam.pem"
+	caBundleFileName = "ca-bundle.crt"
+	dhFileName       = "dhparam.pem"
 	// From https://ssl-config.mozilla.org/ffdhe2048.txt
 	dhParams = `-----BEGIN DH PARAMETERS-----
 MIIBCAKCAQEA//////////+t+FRYortKmq/cViAnPTzx2LnFg84tNpWp4TZBFGQz
@@ -309,20 +532,43 @@ func (r *Reconciler) reconcileAgentProxyConfig(ctx context.Context, cr *model.Cr
 			"/api/beta/targets",
 		},
 	}
+	if resources.IsRoleAuthorizationEnabled(cr) {
+		// The OpenShift OAuth proxy does not forward the groups of the user
+		if r.IsOpenShift {
+			return errRolesUnavailable
+		}
+		params.AuthorizationPort = constants.AuthorizationProxyPort
+		params.GrafanaPort = constants.GrafanaContainerPort
+		params.Roles = newNginxRoleParams(cr.Spec.AuthorizationOptions.Roles)
+	}
 	if tls != nil {
 		params.TLSEnabled = true
 		params.TLSCertFile = path.Join(resources.SecretMountPrefix, tls.AgentProxySecret, corev1.TLSCertKey)
 		params.TLSKeyFile = path.Join(resources.SecretMountPrefix, tls.AgentProxySecret, corev1.TLSPrivateKeyKey)
 		params.CACertFile = path.Join(resources.SecretMountPrefix, tls.AgentProxySecret, constants.CAKey)
 		params.DHParamFile = path.Join(constants.AgentProxyConfigFilePath, dhFileName)
+		params.ClientOrganizationalUnit = tls.AgentOrganizationalUnit
+		if tls.SecurityProfile != nil {
+			params.TLSProtocols = strings.Join(common.TLSProtocols(tls.SecurityProfile), " ")
+			params.TLSCiphers = strings.Join(common.OpenSSLCiphers(tls.SecurityProfile), ":")
+			params.TLSCipherSuites = strings.Join(common.TLS13CipherSuites(tls.SecurityProfile), ":")
+		}
 
 		// Add Diffie-Hellman parameters to config map
 		data[dhFileName] = dhParams
+
+		// While the CA is being rotated, accept client certificates issued by either CA.
+		// Changing the config map restarts nginx to pick up the trusted CAs.
+		if len(tls.CATrustBundle) > 0 {
+			params.CACertFile = path.Join(constants.AgentProxyConfigFilePath, caBundleFileName)
+			data[caBundleFileName] = string(tls.CATrustBundle)
+		}
 	}
 
 	// Create an nginx.conf where:
 	// 1. If TLS is enabled, requires client certificate authentication against our CA
 	// 2. Proxies only those API endpoints required by the agent
+	// 3. If roles are specified, authorizes requests from the auth proxy to Cryostat
 	err := nginxConfTemplate.Execute(buf, params)
 	if err != nil {
 		return err
@@ -334,30 +580,42 @@ func (r *Reconciler) reconcileAgentProxyConfig(ctx context.Context, cr *model.Cr
 	return r.createOrUpdateConfigMap(ctx, cm, cr.Object, data)
 }
 
-var errConfigMapImmutableModified error = errors.New("config map is immutable and should not be")
+// newNginxRoleParams converts the roles into regular expressions matched by nginx.
+// Group names and path prefixes are restricted by the CRD to characters that are safe
+// to quote within the nginx configuration.
+func newNginxRoleParams(roles []operatorv1beta2.AccessRole) []nginxRoleParams {
+	params := make([]nginxRoleParams, 0, len(roles))
+	for _, role := range roles {
+		param := nginxRoleParams{
+			Methods: role.AllowedMethods,
+		}
+		for _, prefix := range role.AllowedPathPrefixes {
+			// Match the prefix itself, and any path below it
+			param.PathPatterns = append(param.PathPatterns,
+				fmt.Sprintf("^%s(/.*)?$", regexp.QuoteMeta(strings.TrimSuffix(prefix, "/"))))
+		}
+		for _, group := range role.Groups {
+			// The header contains a comma-separated list of the user's groups
+			param.GroupPatterns = append(param.GroupPatterns,
+				fmt.Sprintf(`(^|,)\s*%s\s*(,|$)`, regexp.QuoteMeta(group)))
+		}
+		params = append(params, param)
+	}
+	return params
+}
 
 func (r *Reconciler) createOrUpdateConfigMap(ctx context.Context, cm *corev1.ConfigMap, owner metav1.Object,
 	data map[string]string) error {
+	cm.Data = data
 	cmCopy := cm.DeepCopy()
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
-		// Check if we need to revert Immutable property
-		if isImmutable(cm) && !isImmutable(cmCopy) {
-			return errConfigMapImmutableModified
-		}
-		// Set the Cryostat CR as controller
-		if err := controllerutil.SetControllerReference(owner, cm, r.Scheme); err != nil {
-			return err
-		}
-		cm.Data = data
-		return nil
-	})
+	err := r.applyObject(ctx, cm, owner)
 	if err != nil {
-		if err == errConfigMapImmutableModified {
+		// An immutable config map cannot be modified, so it must be recreated
+		if isImmutableFieldChanged(err, "data", "binaryData", "immutable") {
 			return r.recreateConfigMap(ctx, cmCopy, owner, data)
 		}
 		return err
 	}
-	r.Log.Info(fmt.Sprintf("Config Map %s", op), "name", cm.Name, "namespace", cm.Namespace)
 	return nil
 }
 
@@ -370,10 +628,6 @@ func (r *Reconciler) recreateConfigMap(ctx context.Context, cm *corev1.ConfigMap
 	return r.createOrUpdateConfigMap(ctx, cm, owner, data)
 }
 
-func isImmutable(cm *corev1.ConfigMap) bool {
-	return cm.Immutable != nil && *cm.Immutable
-}
-
 func (r *Reconciler) deleteConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
 	err := r.Delete(ctx, cm)
 	if err != nil && !kerrors.IsNotFound(err) {
@@ -31,6 +31,9 @@ const DefaultStorageImageTag = "quay.io/cryostat/cryostat-storage:latest"
 // Default image tag for the Database image
 const DefaultDatabaseImageTag = "quay.io/cryostat/cryostat-db:latest"
 
+// PostgreSQL major version of the default Database image
+const DefaultDatabaseMajorVersion = "16"
+
 // Default image tag for the agent proxy image
 const DefaultAgentProxyImageTag = "registry.access.redhat.com/ubi9/nginx-124:latest"
 
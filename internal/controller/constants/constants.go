@@ -32,6 +32,7 @@ const (
 	DatabasePort               int32  = 5432
 	AgentProxyContainerPort    int32  = 8282
 	AgentProxyHealthPort       int32  = 8281
+	AuthorizationProxyPort     int32  = 8283
 	AgentCallbackContainerPort int32  = 9977
 	AgentCallbackPortName      string = "cryostat-cb" // Max 15 characters
 	LoopbackAddress            string = "127.0.0.1"
@@ -44,6 +45,8 @@ const (
 	LabelAppName               string = "cryostat"
 	// CAKey is the key for a CA certificate within a TLS secret
 	CAKey = certMeta.TLSCAKey
+	// PreviousCAKey is the key for the CA certificate being replaced within the CA trust bundle secret
+	PreviousCAKey = "previous-ca.crt"
 	// ALL capability to drop for restricted pod security. See:
 	// https://kubernetes.io/docs/concepts/security/pod-security-standards/#restricted
 	CapabilityAll corev1.Capability = "ALL"
@@ -70,6 +73,13 @@ const (
 	TargetNamespaceCRNameLabel      = targetNamespaceCRLabelPrefix + "name"
 	TargetNamespaceCRNamespaceLabel = targetNamespaceCRLabelPrefix + "namespace"
 
+	// Annotation that pauses reconciliation of a Cryostat CR when set to "true"
+	ReconcilePausedAnnotation = "operator.cryostat.io/reconcile-paused"
+	// Annotation set on a Cryostat CR by the CryostatRestore controller, whose value
+	// is the name of the restore in progress. The core deployment is scaled down
+	// and scheduled database backups are suspended while present.
+	RestoreInProgressAnnotation = "operator.cryostat.io/restore-in-progress"
+
 	// Labels for agent auto-configuration
 	AgentLabelPrefix                  = "cryostat.io/"
 	AgentLabelCryostatName            = AgentLabelPrefix + "name"
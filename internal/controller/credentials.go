@@ -0,0 +1,385 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	common "github.com/cryostatio/cryostat-operator/internal/controller/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controller/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// The suffix to be appended to the name of a Cryostat CR to name the secret
+	// containing the new credentials while they are being rotated
+	credentialRotationSecretNameSuffix = "-credential-rotation"
+	eventCredentialsRotatedType        = "CredentialsRotated"
+	eventDatabaseSecretChangedType     = "DatabaseSecretChanged"
+	// authProxyCookieSecretKey indexes the cookie secret within the auth proxy cookie Secret
+	authProxyCookieSecretKey = "OAUTH2_PROXY_COOKIE_SECRET"
+	// How often to check the progress of a credential change
+	credentialPollInterval = PendingRequeueAfter
+)
+
+// reconcileCredentials changes the password of the managed database when another database secret is
+// configured, and rotates the generated credentials when credential rotation is enabled. New credentials
+// are staged in a separate secret, which is only deleted once they are in use by every component. Returns
+// how long to wait before checking the credentials again, or zero if there is nothing to wait for.
+func (r *Reconciler) reconcileCredentials(ctx context.Context, cr *model.CryostatInstance, tls *resources.TLSConfig,
+	imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs) (time.Duration, error) {
+	rotation, err := r.getCredentialRotationSecret(ctx, cr)
+	if err != nil {
+		return 0, err
+	}
+	// Finish a rotation that is in progress, even if rotation has since been disabled
+	if rotation != nil {
+		done, err := r.rotateCredentials(ctx, cr, rotation, tls, imageTags, serviceSpecs)
+		if err != nil || !done {
+			return credentialPollInterval, err
+		}
+		return r.nextCredentialRotation(cr), nil
+	}
+
+	secretName := getDatabaseSecretName(cr)
+	if len(cr.Status.DatabaseSecret) > 0 && cr.Status.DatabaseSecret != secretName {
+		done, err := r.changeDatabaseSecret(ctx, cr, secretName, tls, imageTags, serviceSpecs)
+		if err != nil || !done {
+			return credentialPollInterval, err
+		}
+	}
+
+	if cr.Spec.CredentialRotation == nil {
+		cr.Status.LastRotationTime = nil
+		return 0, nil
+	}
+	if cr.Status.LastRotationTime == nil {
+		// Start counting from when rotation was enabled
+		now := metav1.Now()
+		cr.Status.LastRotationTime = &now
+	}
+	if wait := r.nextCredentialRotation(cr); wait > 0 {
+		return wait, nil
+	}
+
+	if err := r.createCredentialRotationSecret(ctx, cr); err != nil {
+		return 0, err
+	}
+	r.Log.Info("Started credential rotation", "name", cr.Name, "namespace", cr.InstallNamespace)
+	return credentialPollInterval, nil
+}
+
+// nextCredentialRotation returns the time until the credentials are next due to be rotated
+func (r *Reconciler) nextCredentialRotation(cr *model.CryostatInstance) time.Duration {
+	if cr.Spec.CredentialRotation == nil || cr.Status.LastRotationTime == nil {
+		return 0
+	}
+	return time.Until(cr.Status.LastRotationTime.Add(cr.Spec.CredentialRotation.Interval.Duration))
+}
+
+// createCredentialRotationSecret generates new values for each of the credentials that the operator
+// is able to rotate, and stores them in a secret until they have been applied
+func (r *Reconciler) createCredentialRotationSecret(ctx context.Context, cr *model.CryostatInstance) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + credentialRotationSecretNameSuffix,
+			Namespace: cr.InstallNamespace,
+		},
+	}
+	current := &corev1.Secret{}
+	if rotatesDatabaseSecret(cr) {
+		err := r.Get(ctx, types.NamespacedName{Name: cr.Name + databaseSecretNameSuffix, Namespace: cr.InstallNamespace}, current)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
+		secret.StringData = map[string]string{
+			authProxyCookieSecretKey: r.GenPasswd(32),
+		}
+		if rotatesDatabaseSecret(cr) {
+			// The encryption key cannot change without re-encrypting the data, so keep it
+			secret.StringData[constants.DatabaseSecretConnectionKey] = r.GenPasswd(32)
+			secret.StringData[constants.DatabaseSecretEncryptionKey] = string(current.Data[constants.DatabaseSecretEncryptionKey])
+		}
+		if rotatesStorageSecret(cr) {
+			secret.StringData[storageSecretPassKey] = r.GenPasswd(32)
+		}
+		return nil
+	})
+}
+
+// rotateCredentials applies the new credentials in the rotation secret. The database password is
+// changed before the database secret is updated, and object storage is restarted with its new
+// credentials before Cryostat. Returns whether the rotation has completed.
+func (r *Reconciler) rotateCredentials(ctx context.Context, cr *model.CryostatInstance, rotation *corev1.Secret,
+	tls *resources.TLSConfig, imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs) (bool, error) {
+	changed := false
+	if password, ok := rotation.Data[constants.DatabaseSecretConnectionKey]; ok {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: cr.Name + databaseSecretNameSuffix, Namespace: cr.InstallNamespace}, secret)
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(secret.Data[constants.DatabaseSecretConnectionKey], password) {
+			done, err := r.reconcileDatabasePasswordJob(ctx, cr, rotation.Name, tls, imageTags, serviceSpecs)
+			if err != nil || !done {
+				return false, err
+			}
+			// The generated database secret is immutable, so it must be replaced. If the operator stops
+			// before the secret is recreated, it is regenerated from the rotation secret.
+			if err := r.deleteSecret(ctx, secret); err != nil {
+				return false, err
+			}
+			if err := r.reconcileDatabaseConnectionSecret(ctx, cr); err != nil {
+				return false, err
+			}
+			changed = true
+		}
+		if err := r.deleteDatabasePasswordJob(ctx, cr); err != nil {
+			return false, err
+		}
+	}
+
+	storageRotated := false
+	if key, ok := rotation.Data[storageSecretPassKey]; ok {
+		updated, err := r.updateSecretKey(ctx, cr, cr.Name+storageSecretNameSuffix, storageSecretPassKey, key)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || updated
+		storageRotated = true
+	}
+	if cookie, ok := rotation.Data[authProxyCookieSecretKey]; ok {
+		updated, err := r.updateSecretKey(ctx, cr, cr.Name+"-oauth2-cookie", authProxyCookieSecretKey, cookie)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || updated
+	}
+	if changed {
+		// Wait for the deployments to be updated with the new credentials
+		return false, nil
+	}
+
+	// Cryostat is restarted with the new credentials once object storage has rolled out
+	if storageRotated && resources.DeployManagedStorage(cr) {
+		rolledOut, err := r.isStorageRolledOut(ctx, cr, tls, imageTags)
+		if err != nil || !rolledOut {
+			return false, err
+		}
+	}
+
+	if err := r.deleteSecret(ctx, rotation); err != nil {
+		return false, err
+	}
+	now := metav1.Now()
+	cr.Status.LastRotationTime = &now
+	r.EventRecorder.Event(cr.Object, corev1.EventTypeNormal, eventCredentialsRotatedType,
+		"Rotated the credentials generated by the operator")
+	r.Log.Info("Rotated credentials", "name", cr.Name, "namespace", cr.InstallNamespace)
+	return true, nil
+}
+
+// updateSecretKey replaces the value of a key within a secret, and returns whether it was changed
+func (r *Reconciler) updateSecretKey(ctx context.Context, cr *model.CryostatInstance, name string, key string,
+	value []byte) (bool, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.InstallNamespace,
+		},
+	}
+	updated := false
+	err := r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
+		if !bytes.Equal(secret.Data[key], value) {
+			secret.StringData = map[string]string{
+				key: string(value),
+			}
+			updated = true
+		}
+		return nil
+	})
+	return updated, err
+}
+
+// changeDatabaseSecret changes the password of the managed database to the CONNECTION_KEY of a newly
+// configured database secret, then switches to that secret. Returns whether the switch has completed.
+func (r *Reconciler) changeDatabaseSecret(ctx context.Context, cr *model.CryostatInstance, secretName string,
+	tls *resources.TLSConfig, imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs) (bool, error) {
+	if resources.DeployManagedDatabase(cr) {
+		done, err := r.reconcileDatabasePasswordJob(ctx, cr, secretName, tls, imageTags, serviceSpecs)
+		if err != nil || !done {
+			return false, err
+		}
+		if err := r.deleteDatabasePasswordJob(ctx, cr); err != nil {
+			return false, err
+		}
+	}
+
+	previous := cr.Status.DatabaseSecret
+	cr.Status.DatabaseSecret = secretName
+	r.EventRecorder.Event(cr.Object, corev1.EventTypeNormal, eventDatabaseSecretChangedType,
+		fmt.Sprintf("Changed the database secret from %s to %s", previous, secretName))
+	r.Log.Info("Changed database secret", "name", cr.Name, "namespace", cr.InstallNamespace,
+		"from", previous, "to", secretName)
+	return true, nil
+}
+
+// reconcileDatabasePasswordJob runs a Job that changes the password of the managed database from
+// the CONNECTION_KEY of the current database secret to the CONNECTION_KEY of newSecret. Returns
+// whether the password has been changed.
+func (r *Reconciler) reconcileDatabasePasswordJob(ctx context.Context, cr *model.CryostatInstance, newSecret string,
+	tls *resources.TLSConfig, imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs) (bool, error) {
+	job := resources.NewJobForDatabasePassword(cr, imageTags, tls, serviceSpecs, r.IsOpenShift,
+		cr.Status.DatabaseSecret, newSecret)
+	found := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, found)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, err
+		}
+		if err := controllerutil.SetControllerReference(cr.Object, job, r.Scheme); err != nil {
+			return false, err
+		}
+		if err := r.Create(ctx, job); err != nil {
+			return false, err
+		}
+		r.Log.Info("Started database password change", "name", job.Name, "namespace", job.Namespace)
+		return false, nil
+	}
+
+	switch {
+	case isJobConditionTrue(found, batchv1.JobComplete):
+		return true, nil
+	case isJobConditionTrue(found, batchv1.JobFailed):
+		// The database still uses the current password, so Cryostat is unaffected
+		return false, fmt.Errorf("failed to change the database password, check the logs of Job %s then delete it to retry",
+			found.Name)
+	default:
+		return false, nil
+	}
+}
+
+func (r *Reconciler) deleteDatabasePasswordJob(ctx context.Context, cr *model.CryostatInstance) error {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.DatabasePasswordJobName(cr.Name),
+			Namespace: cr.InstallNamespace,
+		},
+	}
+	err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isStorageRolledOut returns whether every pod of the managed object storage uses the current
+// contents of its secrets
+func (r *Reconciler) isStorageRolledOut(ctx context.Context, cr *model.CryostatInstance, tls *resources.TLSConfig,
+	imageTags *resources.ImageTags) (bool, error) {
+	fsGroup, err := r.getFSGroup(ctx, cr.InstallNamespace)
+	if err != nil {
+		return false, err
+	}
+	desired, err := resources.NewDeploymentForStorage(cr, imageTags, tls, r.IsOpenShift, *fsGroup)
+	if err != nil {
+		return false, err
+	}
+	err = common.AnnotateWithObjRefHashes(ctx, r.Client, desired.Namespace, &desired.Spec.Template)
+	if err != nil {
+		return false, err
+	}
+	deploy := &appsv1.Deployment{}
+	err = r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, deploy)
+	if err != nil {
+		return false, err
+	}
+	return common.ObjRefHashesEqual(&deploy.Spec.Template, &desired.Spec.Template) && isDeploymentRolledOut(deploy), nil
+}
+
+// isCoreRolloutHeld returns whether updates to the main deployment should wait, because object storage
+// has not yet rolled out with rotated credentials
+func (r *Reconciler) isCoreRolloutHeld(ctx context.Context, cr *model.CryostatInstance) (bool, error) {
+	rotation, err := r.getCredentialRotationSecret(ctx, cr)
+	if err != nil || rotation == nil {
+		return false, err
+	}
+	key, ok := rotation.Data[storageSecretPassKey]
+	if !ok {
+		return false, nil
+	}
+	secret := &corev1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Name: cr.Name + storageSecretNameSuffix, Namespace: cr.InstallNamespace}, secret)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(secret.Data[storageSecretPassKey], key), nil
+}
+
+func (r *Reconciler) getCredentialRotationSecret(ctx context.Context, cr *model.CryostatInstance) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: cr.Name + credentialRotationSecretNameSuffix, Namespace: cr.InstallNamespace}, secret)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+// rotatesDatabaseSecret returns whether the database password is rotated, which is only the case
+// for the generated secret of the managed database
+func rotatesDatabaseSecret(cr *model.CryostatInstance) bool {
+	return resources.DeployManagedDatabase(cr) && cr.Status.DatabaseSecret == cr.Name+databaseSecretNameSuffix &&
+		(cr.Spec.DatabaseOptions == nil || cr.Spec.DatabaseOptions.SecretName == nil)
+}
+
+// rotatesStorageSecret returns whether the object storage secret key is rotated, which is only the case
+// for the generated secret of the managed object storage
+func rotatesStorageSecret(cr *model.CryostatInstance) bool {
+	return resources.DeployManagedStorage(cr) &&
+		(cr.Spec.ObjectStorageOptions == nil || cr.Spec.ObjectStorageOptions.SecretName == nil)
+}
+
+// isDeploymentRolledOut returns whether all pods of the deployment are up to date and available
+func isDeploymentRolledOut(deploy *appsv1.Deployment) bool {
+	replicas := desiredReplicas(deploy)
+	return deploy.Status.ObservedGeneration >= deploy.Generation && deploy.Status.Replicas == replicas &&
+		deploy.Status.UpdatedReplicas == replicas && deploy.Status.AvailableReplicas == replicas
+}
+
+// desiredReplicas returns the number of replicas requested for the deployment
+func desiredReplicas(deploy *appsv1.Deployment) int32 {
+	if deploy.Spec.Replicas != nil {
+		return *deploy.Spec.Replicas
+	}
+	return 1
+}
@@ -0,0 +1,357 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	resources "github.com/cryostatio/cryostat-operator/internal/controller/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	reasonDatabaseConnected        = "DatabaseConnected"
+	reasonDatabaseUnreachable      = "DatabaseUnreachable"
+	reasonDatabaseUpgradeStarted   = "DatabaseUpgradeStarted"
+	reasonDatabaseUpgraded         = "DatabaseUpgraded"
+	reasonDatabaseUpgradeJobFailed = "DatabaseUpgradeJobFailed"
+)
+
+// Environment variable to override the PostgreSQL major version of the cryostat-database image
+const databaseMajorVersionEnv = "DATABASE_MAJOR_VERSION"
+
+// How long to wait for a connection to the external database
+const databaseDialTimeout = 5 * time.Second
+
+// checkDatabaseReachable attempts a TCP connection to the external database,
+// and reports the result in the DatabaseReachable condition
+func (r *Reconciler) checkDatabaseReachable(ctx context.Context, cr *model.CryostatInstance) {
+	address := resources.ExternalDatabaseAddress(cr)
+	dialCtx, cancel := context.WithTimeout(ctx, databaseDialTimeout)
+	defer cancel()
+
+	condition := metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeDatabaseReachable),
+		ObservedGeneration: cr.Object.GetGeneration(),
+	}
+	conn, err := r.Dialer.DialContext(dialCtx, "tcp", address)
+	if err != nil {
+		r.Log.Error(err, "failed to connect to external database", "address", address)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonDatabaseUnreachable
+		condition.Message = fmt.Sprintf("Unable to connect to the database at %s: %s", address, err.Error())
+	} else {
+		conn.Close()
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonDatabaseConnected
+		condition.Message = fmt.Sprintf("Connected to the database at %s.", address)
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, condition)
+}
+
+// isDatabaseUnreachable returns whether the last connection attempt to an external database failed
+func isDatabaseUnreachable(cr *model.CryostatInstance) bool {
+	return meta.IsStatusConditionFalse(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeDatabaseReachable))
+}
+
+// reconcileDatabaseUpgrade compares the PostgreSQL major version of the database image with the
+// version of the existing data, and runs a Job to upgrade the data if they differ. Until the upgrade
+// succeeds, the database deployment keeps using the previous image. Returns the image the database
+// deployment should use, and whether the database must be stopped.
+func (r *Reconciler) reconcileDatabaseUpgrade(ctx context.Context, cr *model.CryostatInstance,
+	imageTags *resources.ImageTags, fsGroup int64) (string, bool, error) {
+	version := r.getDatabaseMajorVersion(cr)
+	if cr.Status.DatabaseVersion == version {
+		// The database image may have been reverted after a failed upgrade
+		removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeDatabaseUpgradeFailed)
+		return imageTags.DatabaseImageTag, false, nil
+	}
+
+	deploy := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: cr.Name + "-database", Namespace: cr.InstallNamespace}, deploy)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return "", false, err
+	}
+	previousImage := ""
+	for _, container := range deploy.Spec.Template.Spec.Containers {
+		if container.Name == cr.Name+"-db" {
+			previousImage = container.Image
+		}
+	}
+	// Nothing to upgrade for a new database, or one whose data does not outlive its pod. Databases
+	// created before the version was recorded are only upgraded if the image has changed.
+	if len(previousImage) == 0 || !resources.DatabaseUsesPersistentVolume(cr) ||
+		(len(cr.Status.DatabaseVersion) == 0 && previousImage == imageTags.DatabaseImageTag) {
+		cr.Status.DatabaseVersion = version
+		return imageTags.DatabaseImageTag, false, nil
+	}
+
+	if len(cr.Status.DatabaseVersion) == 0 {
+		// The new image may use the same major version, so check the existing data before stopping the database
+		dataVersion, finished, err := r.readDatabaseVersion(ctx, cr, deploy, previousImage, imageTags, fsGroup)
+		if err != nil {
+			return "", false, err
+		}
+		if !finished {
+			return previousImage, false, nil
+		}
+		if dataVersion == version {
+			cr.Status.DatabaseVersion = version
+			return imageTags.DatabaseImageTag, false, nil
+		}
+		// Upgrade from an unknown version if the existing data could not be read
+		cr.Status.DatabaseVersion = dataVersion
+	}
+
+	previousVersion := cr.Status.DatabaseVersion
+	if len(previousVersion) == 0 {
+		previousVersion = "unknown"
+	}
+	dumpSizeLimit, err := r.getDatabaseStorageSize(ctx, cr)
+	if err != nil {
+		return "", false, err
+	}
+	job := resources.NewJobForDatabaseUpgrade(cr, previousImage, imageTags, version, dumpSizeLimit, r.IsOpenShift, fsGroup)
+	found := &batchv1.Job{}
+	err = r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, found)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return "", false, err
+		}
+		// Any previous failure is being retried
+		removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeDatabaseUpgradeFailed)
+		r.setDatabaseUpgradeCondition(cr, operatorv1beta2.ConditionTypeDatabaseUpgrading, metav1.ConditionTrue,
+			reasonDatabaseUpgradeStarted, fmt.Sprintf("Upgrading the database from PostgreSQL %s to %s.", previousVersion, version))
+		// The data can only be upgraded once the database has stopped using it
+		if !isDeploymentStopped(deploy) {
+			r.Log.Info("Waiting for the database to stop before upgrading", "name", deploy.Name,
+				"namespace", deploy.Namespace)
+			return previousImage, true, nil
+		}
+		if err := controllerutil.SetControllerReference(cr.Object, job, r.Scheme); err != nil {
+			return "", false, err
+		}
+		if err := r.Create(ctx, job); err != nil {
+			return "", false, err
+		}
+		r.Log.Info("Started database upgrade", "name", job.Name, "namespace", job.Namespace,
+			"from", previousVersion, "to", version)
+		return previousImage, true, nil
+	}
+
+	switch {
+	case isJobConditionTrue(found, batchv1.JobComplete):
+		cr.Status.DatabaseVersion = version
+		// The upgrade Job keeps the previous data directory, which is only safe to remove
+		// once the upgraded database is known to work
+		previousDataDir := resources.PreviousDatabaseDataDir(previousVersion)
+		if previousVersion == "unknown" {
+			previousDataDir = resources.PreviousDatabaseDataDir("<previous version>")
+		}
+		cleanup := fmt.Sprintf("The previous data is kept in %s on PersistentVolumeClaim %s-database. "+
+			"Once the upgraded database works as expected, free up its space with: kubectl exec -n %s deployment/%s-database -- rm -rf %s",
+			previousDataDir, cr.Name, cr.InstallNamespace, cr.Name, previousDataDir)
+		r.setDatabaseUpgradeCondition(cr, operatorv1beta2.ConditionTypeDatabaseUpgrading, metav1.ConditionFalse,
+			reasonDatabaseUpgraded, fmt.Sprintf("The database was upgraded from PostgreSQL %s to %s. %s", previousVersion, version, cleanup))
+		r.EventRecorder.Event(cr.Object, corev1.EventTypeNormal, reasonDatabaseUpgraded,
+			fmt.Sprintf("Upgraded the database to PostgreSQL %s. %s", version, cleanup))
+		err := r.Delete(ctx, found, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !kerrors.IsNotFound(err) {
+			return "", false, err
+		}
+		// A version check that could not read the previous version is kept until the upgrade is done
+		err = r.deleteDatabaseVersionJob(ctx, cr)
+		if err != nil {
+			return "", false, err
+		}
+		return imageTags.DatabaseImageTag, false, nil
+	case isJobConditionTrue(found, batchv1.JobFailed):
+		// Keep running the previous version, with its data left untouched
+		if !meta.IsStatusConditionTrue(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeDatabaseUpgradeFailed)) {
+			r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, string(operatorv1beta2.ConditionTypeDatabaseUpgradeFailed),
+				fmt.Sprintf("Failed to upgrade the database to PostgreSQL %s, see Job %s", version, found.Name))
+		}
+		r.setDatabaseUpgradeCondition(cr, operatorv1beta2.ConditionTypeDatabaseUpgrading, metav1.ConditionFalse,
+			reasonDatabaseUpgradeJobFailed, fmt.Sprintf("The database upgrade from PostgreSQL %s to %s failed.", previousVersion, version))
+		r.setDatabaseUpgradeCondition(cr, operatorv1beta2.ConditionTypeDatabaseUpgradeFailed, metav1.ConditionTrue,
+			reasonDatabaseUpgradeJobFailed, fmt.Sprintf("The database upgrade from PostgreSQL %s to %s failed, and the database is still using PostgreSQL %s. "+
+				"Check the logs of Job %s, then delete it to retry the upgrade.", previousVersion, version, previousVersion, found.Name))
+		return previousImage, false, nil
+	default:
+		return previousImage, true, nil
+	}
+}
+
+// readDatabaseVersion runs a Job that reads the PostgreSQL major version of the existing data, while
+// the database keeps running. Returns the version, and whether the Job has finished. The version is
+// empty if it could not be read, in which case the Job is kept so that it is not run again.
+func (r *Reconciler) readDatabaseVersion(ctx context.Context, cr *model.CryostatInstance, deploy *appsv1.Deployment,
+	previousImage string, imageTags *resources.ImageTags, fsGroup int64) (string, bool, error) {
+	job := resources.NewJobForDatabaseVersion(cr, previousImage, imageTags, !isDeploymentStopped(deploy), r.IsOpenShift, fsGroup)
+	found := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, found)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return "", false, err
+		}
+		if err := controllerutil.SetControllerReference(cr.Object, job, r.Scheme); err != nil {
+			return "", false, err
+		}
+		if err := r.Create(ctx, job); err != nil {
+			return "", false, err
+		}
+		r.Log.Info("Checking the version of the existing database", "name", job.Name, "namespace", job.Namespace)
+		return "", false, nil
+	}
+
+	switch {
+	case isJobConditionTrue(found, batchv1.JobComplete):
+		pods := &corev1.PodList{}
+		err := r.List(ctx, pods, client.InNamespace(found.Namespace),
+			client.MatchingLabels(resources.DatabaseVersionPodLabels(cr)))
+		if err != nil {
+			return "", false, err
+		}
+		version := ""
+		for _, pod := range pods.Items {
+			for _, status := range pod.Status.ContainerStatuses {
+				if status.Name == "database-version" && status.State.Terminated != nil &&
+					status.State.Terminated.ExitCode == 0 {
+					version = strings.TrimSpace(status.State.Terminated.Message)
+				}
+			}
+		}
+		if len(version) == 0 {
+			r.Log.Info("Unable to read the version of the existing database", "name", found.Name, "namespace", found.Namespace)
+			return "", true, nil
+		}
+		r.Log.Info("Found existing database", "version", version)
+		return version, true, r.deleteDatabaseVersionJob(ctx, cr)
+	case isJobConditionTrue(found, batchv1.JobFailed):
+		r.Log.Info("Unable to read the version of the existing database", "name", found.Name, "namespace", found.Namespace)
+		return "", true, nil
+	default:
+		return "", false, nil
+	}
+}
+
+func (r *Reconciler) deleteDatabaseVersionJob(ctx context.Context, cr *model.CryostatInstance) error {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.DatabaseVersionJobName(cr.Name),
+			Namespace: cr.InstallNamespace,
+		},
+	}
+	err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// getDatabaseStorageSize returns the size of the database PersistentVolumeClaim, if known
+func (r *Reconciler) getDatabaseStorageSize(ctx context.Context, cr *model.CryostatInstance) (*resource.Quantity, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, types.NamespacedName{Name: cr.Name + "-database", Namespace: cr.InstallNamespace}, pvc)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	size, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok {
+		size, ok = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+	if !ok || size.IsZero() {
+		return nil, nil
+	}
+	return &size, nil
+}
+
+func (r *Reconciler) setDatabaseUpgradeCondition(cr *model.CryostatInstance, condType operatorv1beta2.CryostatConditionType,
+	status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               string(condType),
+		Status:             status,
+		ObservedGeneration: cr.Object.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// getDatabaseMajorVersion returns the PostgreSQL major version of the database image
+func (r *Reconciler) getDatabaseMajorVersion(cr *model.CryostatInstance) string {
+	if cr.Spec.DatabaseOptions != nil && cr.Spec.DatabaseOptions.MajorVersion != nil {
+		return *cr.Spec.DatabaseOptions.MajorVersion
+	}
+	return r.GetEnvOrDefault(databaseMajorVersionEnv, constants.DefaultDatabaseMajorVersion)
+}
+
+// isDatabaseUpgrading returns whether the managed database is being upgraded to a new PostgreSQL version
+func isDatabaseUpgrading(cr *model.CryostatInstance) bool {
+	return meta.IsStatusConditionTrue(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeDatabaseUpgrading))
+}
+
+// reconcileDatabaseBackup creates a CronJob that periodically backs up the managed database
+// to object storage, or deletes it if backups are not configured
+func (r *Reconciler) reconcileDatabaseBackup(ctx context.Context, cr *model.CryostatInstance, tls *resources.TLSConfig,
+	imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs) error {
+	if !resources.DeployDatabaseBackup(cr) {
+		cronJob := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resources.DatabaseBackupCronJobName(cr.Name),
+				Namespace: cr.InstallNamespace,
+			},
+		}
+		return r.deleteCronJob(ctx, cronJob)
+	}
+
+	// Don't start a backup while the database is being restored or upgraded
+	cronJob := resources.NewCronJobForDatabaseBackup(cr, imageTags, tls, serviceSpecs, r.IsOpenShift,
+		isRestoreInProgress(cr) || isDatabaseUpgrading(cr))
+	return r.applyObject(ctx, cronJob, cr.Object)
+}
+
+func (r *Reconciler) deleteCronJob(ctx context.Context, cronJob *batchv1.CronJob) error {
+	err := r.Delete(ctx, cronJob)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.Log.Error(err, "Could not delete cron job", "name", cronJob.Name, "namespace", cronJob.Namespace)
+		return err
+	}
+	r.Log.Info("Cron job deleted", "name", cronJob.Name, "namespace", cronJob.Namespace)
+	return nil
+}
+
+// isRestoreInProgress returns whether a CryostatRestore is restoring the database of this Cryostat
+func isRestoreInProgress(cr *model.CryostatInstance) bool {
+	_, found := cr.Object.GetAnnotations()[constants.RestoreInProgressAnnotation]
+	return found
+}
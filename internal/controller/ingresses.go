@@ -16,7 +16,6 @@ package controller
 
 import (
 	"context"
-	"fmt"
 	"net/url"
 
 	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
@@ -26,7 +25,6 @@ import (
 	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func (r *Reconciler) reconcileCoreIngress(ctx context.Context, cr *model.CryostatInstance,
@@ -77,22 +75,16 @@ func (r *Reconciler) reconcileIngress(ctx context.Context, ingress *netv1.Ingres
 
 func (r *Reconciler) createOrUpdateIngress(ctx context.Context, ingress *netv1.Ingress, owner metav1.Object,
 	config *operatorv1beta2.NetworkConfiguration) (*netv1.Ingress, error) {
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, ingress, func() error {
-		// Set labels and annotations from CR
-		common.MergeLabelsAndAnnotations(&ingress.ObjectMeta, config.Labels, config.Annotations)
-
-		// Set the Cryostat CR as controller
-		if err := controllerutil.SetControllerReference(owner, ingress, r.Scheme); err != nil {
-			return err
-		}
-		// Update Ingress spec
-		ingress.Spec = *config.IngressSpec
-		return nil
-	})
+	// Set labels and annotations from CR
+	common.MergeLabelsAndAnnotations(&ingress.ObjectMeta, config.Labels, config.Annotations)
+
+	// Update Ingress spec
+	ingress.Spec = *config.IngressSpec
+
+	err := r.applyObject(ctx, ingress, owner)
 	if err != nil {
 		return nil, err
 	}
-	r.Log.Info(fmt.Sprintf("Ingress %s", op), "name", ingress.Name, "namespace", ingress.Namespace)
 	return ingress, nil
 }
 
@@ -0,0 +1,251 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controller/common"
+	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// resolveTargetNamespaces computes the effective set of target namespaces for the CR,
+// combining the namespaces listed in the spec with those matching the target namespace selector.
+// In all namespaces mode, namespaces containing agent pods for the CR are also targeted, so that
+// the objects used by agents are only created where they run.
+func (r *Reconciler) resolveTargetNamespaces(ctx context.Context, cr *model.CryostatInstance) error {
+	// Copy to avoid modifying the spec
+	targetNamespaces := slices.Clone(cr.TargetNamespaces)
+
+	if cr.Spec.TargetNamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(cr.Spec.TargetNamespaceSelector)
+		if err != nil {
+			return err
+		}
+
+		namespaces := &corev1.NamespaceList{}
+		err = r.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return err
+		}
+		for _, ns := range namespaces.Items {
+			// Skip namespaces that are being deleted, so their resources are cleaned up
+			if ns.DeletionTimestamp != nil {
+				continue
+			}
+			if !containsNamespace(targetNamespaces, ns.Name) {
+				targetNamespaces = append(targetNamespaces, ns.Name)
+			}
+		}
+	}
+
+	if cr.Spec.AllNamespaces {
+		agentNamespaces, err := r.agentNamespaces(ctx, cr)
+		if err != nil {
+			return err
+		}
+		for _, ns := range agentNamespaces {
+			if !containsNamespace(targetNamespaces, ns) {
+				targetNamespaces = append(targetNamespaces, ns)
+			}
+		}
+	}
+
+	cr.TargetNamespaces = targetNamespaces
+	return nil
+}
+
+// addStatusTargetNamespaces adds the namespaces recorded in the CR's status to its
+// effective target namespaces, without resolving the target namespace selector again
+func addStatusTargetNamespaces(cr *model.CryostatInstance) {
+	// Copy to avoid modifying the spec
+	targetNamespaces := slices.Clone(cr.TargetNamespaces)
+	for _, ns := range *cr.TargetNamespaceStatus {
+		if !containsNamespace(targetNamespaces, ns) {
+			targetNamespaces = append(targetNamespaces, ns)
+		}
+	}
+	cr.TargetNamespaces = targetNamespaces
+}
+
+// agentNamespaces returns the sorted namespaces of pods labelled for agent injection by the CR
+func (r *Reconciler) agentNamespaces(ctx context.Context, cr *model.CryostatInstance) ([]string, error) {
+	// Only fetch metadata, to avoid caching entire pods
+	pods := &metav1.PartialObjectMetadataList{}
+	pods.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+	err := r.List(ctx, pods, client.MatchingLabels{
+		constants.AgentLabelCryostatName:      cr.Name,
+		constants.AgentLabelCryostatNamespace: cr.InstallNamespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{}
+	for _, pod := range pods.Items {
+		// Skip pods that are being deleted, so resources in their namespace can be cleaned up
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		namespaces = append(namespaces, pod.Namespace)
+	}
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces), nil
+}
+
+func (r *Reconciler) watchNamespaces(c common.ControllerBuilder) common.ControllerBuilder {
+	// Only label changes affect whether a namespace is selected
+	return c.Watches(&corev1.Namespace{}, c.EnqueueRequestsFromMapFunc(r.mapFromNamespace()),
+		c.WithPredicates(predicate.LabelChangedPredicate{}))
+}
+
+func (r *Reconciler) mapFromNamespace() func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		// Enqueue each CR whose selector matches the namespace, or that currently
+		// targets the namespace and may need to release it
+		crs := &operatorv1beta2.CryostatList{}
+		err := r.List(ctx, crs)
+		if err != nil {
+			r.Log.Error(err, "Failed to list Cryostats", "namespace", obj.GetName())
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for _, cr := range crs.Items {
+			if cr.Spec.TargetNamespaceSelector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(cr.Spec.TargetNamespaceSelector)
+			if err != nil {
+				r.Log.Error(err, "Invalid target namespace selector", "name", cr.Name, "namespace", cr.Namespace)
+				continue
+			}
+			if selector.Matches(labels.Set(obj.GetLabels())) ||
+				containsNamespace(cr.Status.TargetNamespaces, obj.GetName()) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+// initTargetNamespaceStatuses prepares a status entry for each target namespace, carrying over
+// the readiness reported by the previous reconcile until it is updated.
+func initTargetNamespaceStatuses(cr *model.CryostatInstance) {
+	statuses := make([]operatorv1beta2.TargetNamespaceStatus, 0, len(cr.TargetNamespaces))
+	for _, ns := range cr.TargetNamespaces {
+		status := operatorv1beta2.TargetNamespaceStatus{Namespace: ns}
+		if previous := getTargetNamespaceStatus(cr.Status.TargetNamespaceStatuses, ns); previous != nil {
+			status = *previous
+			status.LastError = ""
+		}
+		statuses = append(statuses, status)
+	}
+	cr.Status.TargetNamespaceStatuses = statuses
+}
+
+func getTargetNamespaceStatus(statuses []operatorv1beta2.TargetNamespaceStatus, namespace string) *operatorv1beta2.TargetNamespaceStatus {
+	for i := range statuses {
+		if statuses[i].Namespace == namespace {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// targetNamespaceStatus returns the status entry for a target namespace, adding one
+// if the namespace has been removed but still has resources to clean up
+func targetNamespaceStatus(cr *model.CryostatInstance, namespace string) *operatorv1beta2.TargetNamespaceStatus {
+	status := getTargetNamespaceStatus(cr.Status.TargetNamespaceStatuses, namespace)
+	if status == nil {
+		cr.Status.TargetNamespaceStatuses = append(cr.Status.TargetNamespaceStatuses,
+			operatorv1beta2.TargetNamespaceStatus{Namespace: namespace})
+		status = &cr.Status.TargetNamespaceStatuses[len(cr.Status.TargetNamespaceStatuses)-1]
+	}
+	return status
+}
+
+// recordTargetNamespaceError reports a failure in a single target namespace, so
+// that reconciliation may continue with the other namespaces
+func (r *Reconciler) recordTargetNamespaceError(cr *model.CryostatInstance, namespace string, err error) {
+	r.Log.Error(err, "Failed to reconcile target namespace", "name", cr.Name, "namespace", cr.InstallNamespace,
+		"targetNamespace", namespace)
+	status := targetNamespaceStatus(cr, namespace)
+	if len(status.LastError) > 0 {
+		status.LastError += "; "
+	}
+	status.LastError += err.Error()
+}
+
+// failedTargetNamespaces returns the namespaces where an error occurred during this reconcile
+func failedTargetNamespaces(cr *model.CryostatInstance) []string {
+	failed := []string{}
+	for _, status := range cr.Status.TargetNamespaceStatuses {
+		if len(status.LastError) > 0 {
+			failed = append(failed, status.Namespace)
+		}
+	}
+	return failed
+}
+
+// statusTargetNamespaces returns the namespaces to list in the status, including
+// removed namespaces whose resources could not yet be cleaned up
+func statusTargetNamespaces(cr *model.CryostatInstance) []string {
+	namespaces := slices.Clone(cr.TargetNamespaces)
+	for _, status := range cr.Status.TargetNamespaceStatuses {
+		if !containsNamespace(namespaces, status.Namespace) {
+			namespaces = append(namespaces, status.Namespace)
+		}
+	}
+	return namespaces
+}
+
+func (r *Reconciler) countInjectedAgentPods(ctx context.Context, cr *model.CryostatInstance) {
+	for _, ns := range cr.TargetNamespaces {
+		// Only fetch metadata, to avoid caching entire pods
+		pods := &metav1.PartialObjectMetadataList{}
+		pods.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+		err := r.List(ctx, pods, client.InNamespace(ns), client.MatchingLabels{
+			constants.AgentLabelCryostatName:      cr.Name,
+			constants.AgentLabelCryostatNamespace: cr.InstallNamespace,
+		})
+		if err != nil {
+			r.recordTargetNamespaceError(cr, ns, err)
+			continue
+		}
+		targetNamespaceStatus(cr, ns).InjectedAgentPods = int32(len(pods.Items))
+	}
+}
+
+func allAgentTLSReady(cr *model.CryostatInstance) bool {
+	for _, status := range cr.Status.TargetNamespaceStatuses {
+		if !status.AgentTLSReady {
+			return false
+		}
+	}
+	return true
+}
@@ -49,6 +49,27 @@ func installationNamespaceSelector(cr *model.CryostatInstance) *metav1.LabelSele
 
 const namespaceNameLabel = "kubernetes.io/metadata.name"
 
+func targetNamespacesSelector(cr *model.CryostatInstance) networkingv1.NetworkPolicyPeer {
+	if cr.Spec.AllNamespaces {
+		return AllNamespacesSelector
+	}
+	return namespacesSelector(cr.TargetNamespaces)
+}
+
+func namespacesSelector(namespaces []string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      namespaceNameLabel,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   namespaces,
+				},
+			},
+		},
+	}
+}
+
 func namespaceOriginSelector(namespace string) *metav1.LabelSelector {
 	return &metav1.LabelSelector{
 		MatchLabels: map[string]string{
@@ -91,46 +112,40 @@ func (r *Reconciler) reconcileCoreNetworkPolicy(ctx context.Context, cr *model.C
 
 	if !ingressDisabled {
 		err = r.createOrUpdatePolicy(ctx, ingressPolicy, cr.Object, func() error {
-			ingressPolicy.Spec = networkingv1.NetworkPolicySpec{
-				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
-				PodSelector: metav1.LabelSelector{
-					MatchLabels: resources.CorePodLabels(cr),
-				},
-				Ingress: []networkingv1.NetworkPolicyIngressRule{
-					// allow ingress to the authproxy/cryostat HTTP(S) port from any namespace or from the Route
-					{
-						From: []networkingv1.NetworkPolicyPeer{
-							AllNamespacesSelector,
-							RouteSelector,
-						},
-						Ports: []networkingv1.NetworkPolicyPort{
-							{
-								Port: &intstr.IntOrString{IntVal: constants.AuthProxyHttpContainerPort},
-							},
-						},
+			ingressRules := []networkingv1.NetworkPolicyIngressRule{
+				// allow ingress to the authproxy/cryostat HTTP(S) port from any namespace or from the Route
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						AllNamespacesSelector,
+						RouteSelector,
 					},
-					// allow ingress to the agent gateway from the target namespaces
-					{
-						From: []networkingv1.NetworkPolicyPeer{
-							{
-								NamespaceSelector: &metav1.LabelSelector{
-									MatchExpressions: []metav1.LabelSelectorRequirement{
-										{
-											Key:      namespaceNameLabel,
-											Operator: metav1.LabelSelectorOpIn,
-											Values:   cr.Spec.TargetNamespaces,
-										},
-									},
-								},
-							},
+					Ports: []networkingv1.NetworkPolicyPort{
+						{
+							Port: &intstr.IntOrString{IntVal: constants.AuthProxyHttpContainerPort},
 						},
-						Ports: []networkingv1.NetworkPolicyPort{
-							{
-								Port: &intstr.IntOrString{IntVal: constants.AgentProxyContainerPort},
-							},
+					},
+				},
+			}
+			// allow ingress to the agent gateway from the target namespaces, if there are any.
+			// A rule without peers would allow ingress from everywhere.
+			if cr.Spec.AllNamespaces || len(cr.TargetNamespaces) > 0 {
+				ingressRules = append(ingressRules, networkingv1.NetworkPolicyIngressRule{
+					From: []networkingv1.NetworkPolicyPeer{
+						targetNamespacesSelector(cr),
+					},
+					Ports: []networkingv1.NetworkPolicyPort{
+						{
+							Port: &intstr.IntOrString{IntVal: constants.AgentProxyContainerPort},
 						},
 					},
+				})
+			}
+			ingressPolicy.Spec = networkingv1.NetworkPolicySpec{
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+				PodSelector: metav1.LabelSelector{
+					MatchLabels: resources.CorePodLabels(cr),
 				},
+				Ingress: ingressRules,
 			}
 			return nil
 		})
@@ -152,21 +167,16 @@ func (r *Reconciler) reconcileCoreNetworkPolicy(ctx context.Context, cr *model.C
 			egressNamespaces = append(egressNamespaces, "openshift")
 		}
 
-		// allow outgoing connections to Pods in the TargetNamespaces
-		egressNamespaces = append(egressNamespaces, cr.TargetNamespaces...)
+		if cr.Spec.AllNamespaces {
+			// allow outgoing connections to Pods in any namespace
+			egressDestinations = append(egressDestinations, AllNamespacesSelector)
+		} else {
+			// allow outgoing connections to Pods in the TargetNamespaces
+			egressNamespaces = append(egressNamespaces, cr.TargetNamespaces...)
 
-		slices.Sort(egressNamespaces)
-		egressDestinations = append(egressDestinations, networkingv1.NetworkPolicyPeer{
-			NamespaceSelector: &metav1.LabelSelector{
-				MatchExpressions: []metav1.LabelSelectorRequirement{
-					{
-						Key:      namespaceNameLabel,
-						Operator: metav1.LabelSelectorOpIn,
-						Values:   slices.Compact(egressNamespaces),
-					},
-				},
-			},
-		})
+			slices.Sort(egressNamespaces)
+			egressDestinations = append(egressDestinations, namespacesSelector(slices.Compact(egressNamespaces)))
+		}
 		k8sApiEndpoint := discoveryv1.EndpointSlice{}
 		err = r.Get(ctx, types.NamespacedName{Namespace: "default", Name: "kubernetes"}, &k8sApiEndpoint)
 		if err != nil {
@@ -214,8 +224,9 @@ func (r *Reconciler) reconcileDatabaseNetworkPolicy(ctx context.Context, cr *mod
 		},
 	}
 	allDisabled := cr.Spec.NetworkPolicies != nil && cr.Spec.NetworkPolicies.DatabaseConfig != nil && cr.Spec.NetworkPolicies.DatabaseConfig.Disabled != nil && *cr.Spec.NetworkPolicies.DatabaseConfig.Disabled
+	deployManagedDatabase := resources.DeployManagedDatabase(cr)
 	ingressDisabled := cr.Spec.NetworkPolicies != nil && cr.Spec.NetworkPolicies.DatabaseConfig != nil && cr.Spec.NetworkPolicies.DatabaseConfig.IngressDisabled != nil && *cr.Spec.NetworkPolicies.DatabaseConfig.IngressDisabled
-	if allDisabled || ingressDisabled {
+	if allDisabled || !deployManagedDatabase || ingressDisabled {
 		return r.deletePolicy(ctx, ingressPolicy)
 	}
 
@@ -234,6 +245,12 @@ func (r *Reconciler) reconcileDatabaseNetworkPolicy(ctx context.Context, cr *mod
 								MatchLabels: resources.CorePodLabels(cr),
 							},
 						},
+						{
+							NamespaceSelector: installationNamespaceSelector(cr),
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: resources.DatabaseBackupPodLabels(cr),
+							},
+						},
 					},
 					Ports: []networkingv1.NetworkPolicyPort{
 						{
@@ -282,6 +299,12 @@ func (r *Reconciler) reconcileStorageNetworkPolicy(ctx context.Context, cr *mode
 								MatchLabels: resources.ReportsPodLabels(cr),
 							},
 						},
+						{
+							NamespaceSelector: installationNamespaceSelector(cr),
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: resources.DatabaseBackupPodLabels(cr),
+							},
+						},
 					},
 					Ports: []networkingv1.NetworkPolicyPort{
 						{
@@ -338,21 +361,12 @@ func (r *Reconciler) reconcileReportsNetworkPolicy(ctx context.Context, cr *mode
 
 func (r *Reconciler) createOrUpdatePolicy(ctx context.Context, networkPolicy *networkingv1.NetworkPolicy, owner metav1.Object,
 	delegate controllerutil.MutateFn) error {
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, networkPolicy, func() error {
-		// Set the Cryostat CR as controller
-		if owner != nil {
-			if err := controllerutil.SetControllerReference(owner, networkPolicy, r.Scheme); err != nil {
-				return err
-			}
-		}
-		// Call the delegate for specific mutations
-		return delegate()
-	})
+	// Call the delegate for specific mutations
+	err := delegate()
 	if err != nil {
 		return err
 	}
-	r.Log.Info(fmt.Sprintf("Network policy %s", op), "name", networkPolicy.Name, "namespace", networkPolicy.Namespace)
-	return nil
+	return r.applyObject(ctx, networkPolicy, owner)
 }
 
 func (r *Reconciler) deletePolicy(ctx context.Context, networkPolicy *networkingv1.NetworkPolicy) error {
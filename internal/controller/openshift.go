@@ -19,16 +19,21 @@ import (
 	"fmt"
 	"regexp"
 
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controller/common"
 	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
 	"github.com/go-logr/logr"
 	configv1 "github.com/openshift/api/config/v1"
 	consolev1 "github.com/openshift/api/console/v1"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func (r *Reconciler) reconcileOpenShift(ctx context.Context, cr *model.CryostatInstance) error {
@@ -55,6 +60,70 @@ func (r *Reconciler) finalizeOpenShift(ctx context.Context, cr *model.CryostatIn
 	return r.deleteCorsAllowedOrigins(ctx, cr)
 }
 
+// watchAPIServer reconciles each Cryostat when the cluster's TLS security profile changes
+func (r *Reconciler) watchAPIServer(c common.ControllerBuilder) common.ControllerBuilder {
+	return c.Watches(&configv1.APIServer{}, c.EnqueueRequestsFromMapFunc(r.mapFromAPIServer()),
+		c.WithPredicates(predicate.GenerationChangedPredicate{}))
+}
+
+func (r *Reconciler) mapFromAPIServer() func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if obj.GetName() != apiServerName {
+			return nil
+		}
+		crs := &operatorv1beta2.CryostatList{}
+		err := r.List(ctx, crs)
+		if err != nil {
+			r.Log.Error(err, "Failed to list Cryostats", "apiserver", obj.GetName())
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for _, cr := range crs.Items {
+			// The cluster's profile is not used when the CR specifies its own
+			if cr.Spec.TLS != nil && cr.Spec.TLS.SecurityProfile != nil {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name},
+			})
+		}
+		return requests
+	}
+}
+
+// watchServingCertSecrets reconciles the Cryostat owning a service when the OpenShift service CA
+// issues or renews its serving certificate
+func (r *Reconciler) watchServingCertSecrets(c common.ControllerBuilder) common.ControllerBuilder {
+	return c.Watches(&corev1.Secret{}, c.EnqueueRequestsFromMapFunc(r.mapFromServingCertSecret()),
+		c.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			_, ok := obj.GetAnnotations()[annotationServingCertOriginatingService]
+			return ok
+		})))
+}
+
+func (r *Reconciler) mapFromServingCertSecret() func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		svc := &corev1.Service{}
+		err := r.Get(ctx, types.NamespacedName{Name: obj.GetAnnotations()[annotationServingCertOriginatingService],
+			Namespace: obj.GetNamespace()}, svc)
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				r.Log.Error(err, "Failed to get service for serving certificate", "name", obj.GetName(),
+					"namespace", obj.GetNamespace())
+			}
+			return nil
+		}
+		owner := metav1.GetControllerOf(svc)
+		if owner == nil || owner.Kind != r.gvk.Kind {
+			return nil
+		}
+		return []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Namespace: svc.Namespace, Name: owner.Name}},
+		}
+	}
+}
+
 func (r *Reconciler) newConsoleLink(cr *model.CryostatInstance) *consolev1.ConsoleLink {
 	// Cluster scoped, so use a unique name to avoid conflicts
 	return &consolev1.ConsoleLink{
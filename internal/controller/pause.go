@@ -0,0 +1,114 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	reasonPausedByAnnotation  = "PausedByAnnotation"
+	eventReconcilePausedType  = "ReconcilePaused"
+	eventReconcileResumedType = "ReconcileResumed"
+)
+
+var eventReconcilePausedMsg = fmt.Sprintf("Reconciliation is paused by the %s annotation. "+
+	"Changes to Cryostat resources will not be reverted until it is removed.", constants.ReconcilePausedAnnotation)
+
+// isReconcilePaused returns whether the user has paused reconciliation of the CR,
+// e.g. to manually modify Cryostat resources during a maintenance window
+func isReconcilePaused(cr *model.CryostatInstance) bool {
+	return cr.Object.GetAnnotations()[constants.ReconcilePausedAnnotation] == "true"
+}
+
+// reconcilePaused updates the status of a paused CR without modifying any Cryostat resources
+func (r *Reconciler) reconcilePaused(ctx context.Context, cr *model.CryostatInstance) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", cr.InstallNamespace, "Request.Name", cr.Name)
+
+	pausing := !meta.IsStatusConditionTrue(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeReconcilePaused))
+	if pausing {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:               string(operatorv1beta2.ConditionTypeReconcilePaused),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: cr.Object.GetGeneration(),
+			Reason:             reasonPausedByAnnotation,
+			Message:            eventReconcilePausedMsg,
+		})
+	}
+
+	// Continue to report the status of any existing deployments
+	deployments := []struct {
+		name    string
+		mapping deploymentConditionTypeMap
+	}{
+		{cr.Name, mainDeploymentConditions},
+		{cr.Name + "-database", databaseDeploymentConditions},
+		{cr.Name + "-storage", storageDeploymentConditions},
+		{cr.Name + "-reports", reportsDeploymentConditions},
+	}
+	for _, d := range deployments {
+		deploy := &appsv1.Deployment{}
+		err := r.Get(ctx, types.NamespacedName{Name: d.name, Namespace: cr.InstallNamespace}, deploy)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return reconcile.Result{}, err
+		}
+		setConditionsFromDeployment(cr, deploy, d.mapping)
+	}
+	setAggregateConditions(cr)
+
+	err := r.Status().Update(ctx, cr.Object)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if pausing {
+		reqLogger.Info("Pausing reconciliation", "annotation", constants.ReconcilePausedAnnotation)
+		r.EventRecorder.Event(cr.Object, corev1.EventTypeNormal, eventReconcilePausedType, eventReconcilePausedMsg)
+	}
+	// Removing the annotation triggers another reconcile
+	return reconcile.Result{}, nil
+}
+
+// resumeIfPaused removes the ReconcilePaused condition once the annotation has been removed
+func (r *Reconciler) resumeIfPaused(ctx context.Context, cr *model.CryostatInstance) error {
+	if meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeReconcilePaused)) == nil {
+		return nil
+	}
+	removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeReconcilePaused)
+	err := r.Status().Update(ctx, cr.Object)
+	if err != nil {
+		return err
+	}
+	// Only report resuming once the condition is removed, so it is not reported again if this reconcile fails
+	r.Log.Info("Resuming reconciliation", "Request.Namespace", cr.InstallNamespace, "Request.Name", cr.Name)
+	r.EventRecorder.Event(cr.Object, corev1.EventTypeNormal, eventReconcileResumedType,
+		"Reconciliation has resumed. Cryostat resources will be restored to their desired state.")
+	return nil
+}
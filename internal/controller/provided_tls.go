@@ -0,0 +1,396 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controller/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controller/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const eventProvidedCertificateInvalidType = reasonProvidedCertInvalid
+
+// Annotation on the keystore secret with a hash of the inputs used to create the keystore
+const annotationKeystoreSourceHash = "io.cryostat/keystore-source-hash"
+
+var errProvidedCertificateInvalid = errors.New("provided TLS certificate is invalid")
+
+func (r *Reconciler) setupProvidedTLS(ctx context.Context, cr *model.CryostatInstance) (*resources.TLSConfig, error) {
+	certs := common.ProvidedTLSCertificates(cr)
+
+	// The CA certificate of the core secret is trusted for all other certificates
+	coreSecret, err := r.getProvidedTLSSecret(ctx, cr, certs.CoreSecretName)
+	if err != nil {
+		return nil, err
+	}
+	caBytes := coreSecret.Data[constants.CAKey]
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caBytes) {
+		return nil, fmt.Errorf("%w: secret \"%s\" does not contain a PEM-encoded CA certificate in %s",
+			errProvidedCertificateInvalid, coreSecret.Name, constants.CAKey)
+	}
+
+	coreCert, err := verifyProvidedCertificate(coreSecret, roots, resources.NewCryostatCert(cr, "").Spec.DNSNames)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the certificates of the other components against the services they are used for
+	others := []struct {
+		secretName string
+		dnsNames   []string
+	}{
+		{certs.DatabaseSecretName, resources.NewDatabaseCert(cr).Spec.DNSNames},
+		{certs.StorageSecretName, resources.NewStorageCert(cr).Spec.DNSNames},
+		{certs.ReportsSecretName, resources.NewReportsCert(cr).Spec.DNSNames},
+		{certs.AgentProxySecretName, resources.NewAgentProxyCert(cr).Spec.DNSNames},
+	}
+	for _, other := range others {
+		secret, err := r.getProvidedTLSSecret(ctx, cr, other.secretName)
+		if err != nil {
+			return nil, err
+		}
+		_, err = verifyProvidedCertificate(secret, roots, other.dnsNames)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create secret to hold keystore password
+	keystorePassSecret := newKeystoreSecret(cr)
+	err = r.createOrUpdateKeystoreSecret(ctx, keystorePassSecret, cr.Object)
+	if err != nil {
+		return nil, err
+	}
+	err = r.Get(ctx, types.NamespacedName{Name: keystorePassSecret.Name, Namespace: keystorePassSecret.Namespace},
+		keystorePassSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a copy of the core secret with a PKCS12 keystore for Cryostat
+	keystoreSecret := newProvidedKeystoreSecret(cr)
+	err = r.createOrUpdateProvidedKeystoreSecret(ctx, cr, keystoreSecret, coreSecret, coreCert,
+		string(keystorePassSecret.Data[constants.KeystorePassSecretKey]))
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &resources.TLSConfig{
+		CryostatSecret:     keystoreSecret.Name,
+		DatabaseSecret:     certs.DatabaseSecretName,
+		StorageSecret:      certs.StorageSecretName,
+		ReportsSecret:      certs.ReportsSecretName,
+		AgentProxySecret:   certs.AgentProxySecretName,
+		KeystorePassSecret: keystorePassSecret.Name,
+		CACert:             caBytes,
+	}
+
+	agentSecret, err := r.getProvidedTLSSecret(ctx, cr, certs.AgentSecretName)
+	if err != nil {
+		return nil, err
+	}
+	caSecretName := resources.NewCryostatCACert(r.gvk, cr).Spec.SecretName
+	for _, ns := range cr.TargetNamespaces {
+		// Set up agent TLS in each target namespace, continuing with other namespaces on failure
+		err := r.reconcileProvidedAgentTLS(ctx, cr, agentSecret, roots, caSecretName, caBytes, ns)
+		if err != nil {
+			r.recordTargetNamespaceError(cr, ns, err)
+		}
+		targetNamespaceStatus(cr, ns).AgentTLSReady = err == nil
+	}
+
+	// Clean up resources from target namespaces that are no longer requested
+	for _, ns := range toDelete(cr) {
+		err := r.cleanUpProvidedAgentTLS(ctx, cr, caSecretName, ns)
+		if err != nil {
+			r.recordTargetNamespaceError(cr, ns, err)
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+func (r *Reconciler) reconcileProvidedAgentTLS(ctx context.Context, cr *model.CryostatInstance, agentSecret *corev1.Secret,
+	roots *x509.CertPool, caSecretName string, caBytes []byte, namespace string) error {
+	// The agent certificate must be valid for the callback service in this namespace
+	_, err := verifyProvidedCertificate(agentSecret, roots, resources.NewAgentCert(cr, namespace, r.gvk).Spec.DNSNames)
+	if err != nil {
+		return err
+	}
+
+	// Copy Cryostat CA secret in each target namespace
+	if namespace != cr.InstallNamespace {
+		namespaceSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      caSecretName,
+				Namespace: namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+		err := r.createOrUpdateCertSecret(ctx, namespaceSecret, caBytes,
+			common.LabelsForTargetNamespaceObject(cr))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Copy the agent certificate to the secret name expected by the agent webhook
+	var owner metav1.Object
+	if namespace == cr.InstallNamespace {
+		owner = cr.Object
+	}
+	targetSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.AgentCertificateName(r.gvk, cr, namespace),
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeTLS,
+	}
+	return r.createOrUpdateSecret(ctx, targetSecret, owner, func() error {
+		common.MergeLabelsAndAnnotations(&targetSecret.ObjectMeta,
+			common.LabelsForTargetNamespaceObject(cr), map[string]string{})
+		targetSecret.Data = agentSecret.Data
+		return nil
+	})
+}
+
+func (r *Reconciler) cleanUpProvidedAgentTLS(ctx context.Context, cr *model.CryostatInstance, caSecretName string,
+	namespace string) error {
+	// Delete any Cryostat CA secret copies in removed namespaces
+	if namespace != cr.InstallNamespace {
+		namespaceSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      caSecretName,
+				Namespace: namespace,
+			},
+		}
+		err := r.deleteSecret(ctx, namespaceSecret)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Delete the agent certificate copy
+	namespaceAgentSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.AgentCertificateName(r.gvk, cr, namespace),
+			Namespace: namespace,
+		},
+	}
+	return r.deleteSecret(ctx, namespaceAgentSecret)
+}
+
+func (r *Reconciler) getProvidedTLSSecret(ctx context.Context, cr *model.CryostatInstance, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: cr.InstallNamespace}, secret)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: secret \"%s\" not found", errProvidedCertificateInvalid, name)
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+// verifyProvidedCertificate checks that a user-provided TLS secret contains a key pair whose certificate
+// is issued by one of the roots, and is valid for each of the DNS names
+func verifyProvidedCertificate(secret *corev1.Secret, roots *x509.CertPool, dnsNames []string) (*tls.Certificate, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: secret \"%s\" %s", errProvidedCertificateInvalid, secret.Name, fmt.Sprintf(format, args...))
+	}
+	if secret.Type != corev1.SecretTypeTLS {
+		return nil, invalid("must be of type %s", corev1.SecretTypeTLS)
+	}
+	if len(secret.Data[constants.CAKey]) == 0 {
+		return nil, invalid("does not contain %s", constants.CAKey)
+	}
+	keyPair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, invalid("does not contain a valid key pair: %s", err.Error())
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, der := range keyPair.Certificate[1:] {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, invalid("contains an invalid certificate chain: %s", err.Error())
+		}
+		intermediates.AddCert(cert)
+	}
+	_, err = keyPair.Leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, invalid("is not trusted by the CA certificate of the core secret: %s", err.Error())
+	}
+
+	for _, name := range dnsNames {
+		if !certificateHasDNSName(keyPair.Leaf, name) {
+			return nil, invalid("is missing the DNS name %s", name)
+		}
+	}
+	return &keyPair, nil
+}
+
+func certificateHasDNSName(cert *x509.Certificate, name string) bool {
+	// Wildcard names must appear in the certificate as-is
+	if strings.HasPrefix(name, "*.") {
+		for _, dnsName := range cert.DNSNames {
+			if strings.EqualFold(dnsName, name) {
+				return true
+			}
+		}
+		return false
+	}
+	return cert.VerifyHostname(name) == nil
+}
+
+func newProvidedKeystoreSecret(cr *model.CryostatInstance) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-provided-tls",
+			Namespace: cr.InstallNamespace,
+		},
+		Type: corev1.SecretTypeTLS,
+	}
+}
+
+func (r *Reconciler) createOrUpdateProvidedKeystoreSecret(ctx context.Context, cr *model.CryostatInstance, secret *corev1.Secret,
+	coreSecret *corev1.Secret, coreCert *tls.Certificate, password string) error {
+	sourceHash := keystoreSourceHash(coreSecret, password)
+	return r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
+		// Encoding the keystore is not deterministic, so only do so when its inputs change
+		if secret.Annotations[annotationKeystoreSourceHash] == sourceHash && len(secret.Data[constants.KeyStoreFile]) > 0 {
+			return nil
+		}
+		keystore, err := encodeKeystore(coreCert, coreSecret.Data[constants.CAKey], password)
+		if err != nil {
+			return err
+		}
+		common.MergeLabelsAndAnnotations(&secret.ObjectMeta, map[string]string{},
+			map[string]string{annotationKeystoreSourceHash: sourceHash})
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       coreSecret.Data[corev1.TLSCertKey],
+			corev1.TLSPrivateKeyKey: coreSecret.Data[corev1.TLSPrivateKeyKey],
+			constants.CAKey:         coreSecret.Data[constants.CAKey],
+			constants.KeyStoreFile:  keystore,
+		}
+		return nil
+	})
+}
+
+func keystoreSourceHash(coreSecret *corev1.Secret, password string) string {
+	hash := sha256.New()
+	for _, data := range [][]byte{coreSecret.Data[corev1.TLSCertKey], coreSecret.Data[corev1.TLSPrivateKeyKey],
+		coreSecret.Data[constants.CAKey], []byte(password)} {
+		// Prefix each input with its length, so that the boundaries between them are unambiguous
+		hash.Write([]byte(fmt.Sprintf("%d:", len(data))))
+		hash.Write(data)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// encodeKeystore creates a PKCS12 keystore with the same profile used by cert-manager
+func encodeKeystore(cert *tls.Certificate, caBytes []byte, password string) ([]byte, error) {
+	chain := []*x509.Certificate{}
+	for _, der := range cert.Certificate[1:] {
+		parsed, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, parsed)
+	}
+	cas, err := parseCertificates(caBytes)
+	if err != nil {
+		return nil, err
+	}
+	return pkcs12.Modern2023.Encode(cert.PrivateKey, cert.Leaf, append(chain, cas...), password)
+}
+
+func parseCertificates(pemBytes []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
+
+// watchProvidedTLSSecrets reconciles each Cryostat that references a Secret for its
+// provided TLS certificates when that Secret changes
+func (r *Reconciler) watchProvidedTLSSecrets(c common.ControllerBuilder) common.ControllerBuilder {
+	return c.Watches(&corev1.Secret{}, c.EnqueueRequestsFromMapFunc(r.mapFromProvidedTLSSecret()),
+		c.WithPredicates(predicate.ResourceVersionChangedPredicate{}))
+}
+
+func (r *Reconciler) mapFromProvidedTLSSecret() func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		crs := &operatorv1beta2.CryostatList{}
+		err := r.List(ctx, crs, client.InNamespace(obj.GetNamespace()))
+		if err != nil {
+			r.Log.Error(err, "Failed to list Cryostats", "namespace", obj.GetNamespace())
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for _, cr := range crs.Items {
+			if cr.Spec.TLS == nil || cr.Spec.TLS.Certificates == nil {
+				continue
+			}
+			certs := cr.Spec.TLS.Certificates
+			names := []string{certs.CoreSecretName, certs.DatabaseSecretName, certs.StorageSecretName,
+				certs.ReportsSecretName, certs.AgentProxySecretName, certs.AgentSecretName}
+			if slices.Contains(names, obj.GetName()) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name},
+				})
+			}
+		}
+		return requests
+	}
+}
@@ -26,7 +26,6 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
@@ -83,6 +82,14 @@ func (r *Reconciler) reconcileDatabasePVC(ctx context.Context, cr *model.Cryosta
 			cfg = (*operatorv1beta2.StorageConfiguration)(&cr.Spec.StorageOptions.LegacyStorageConfiguration)
 		}
 	}
+	if !resources.DeployManagedDatabase(cr) {
+		// If using an external database, do nothing.
+		// Don't delete the PVC to prevent accidental data loss
+		// depending on the reclaim policy. The user may be transitioning
+		// from the managed database to an external database, but the
+		// pre-existing database PVC may still contain data the user wants to retain.
+		return nil
+	}
 	return r.reconcilePVC(ctx, cr, cfg, *resource.NewQuantity(DefaultDatabasePVCSize, resource.BinarySI), &name)
 }
 
@@ -109,35 +116,45 @@ func (r *Reconciler) reconcileStoragePVC(ctx context.Context, cr *model.Cryostat
 
 func (r *Reconciler) createOrUpdatePVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim,
 	owner metav1.Object, config *operatorv1beta2.PersistentVolumeClaimConfig) error {
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, pvc, func() error {
-		// Merge labels and annotations to prevent overriding any set by Kubernetes
-		common.MergeLabelsAndAnnotations(&pvc.ObjectMeta, config.Labels, config.Annotations)
-
-		// Set the Cryostat CR as controller
-		if err := controllerutil.SetControllerReference(owner, pvc, r.Scheme); err != nil {
-			return err
-		}
+	// Set labels and annotations from CR
+	common.MergeLabelsAndAnnotations(&pvc.ObjectMeta, config.Labels, config.Annotations)
+
+	// Only apply the PVC spec fields set in the CR, so that fields set by Kubernetes controllers
+	// (e.g. VolumeName, StorageClassName) remain owned by them. Most fields are immutable after
+	// creation, so the existing values of those are kept when applying. Resource requests can be
+	// expanded, and in rare cases shrunken. Let the modification proceed, and if not admitted,
+	// let the user know with a warning Event.
+	pvc.Spec = *config.Spec.DeepCopy()
+	return r.applyObject(ctx, pvc, owner)
+}
 
-		// Several PVC spec fields are modified by Kubernetes controllers (e.g. VolumeName, StorageClassName).
-		// Resetting those modifications to the values from our CR results in an infinite loop of
-		// modifications between our controller and the Kubernetes controllers.
-		// To avoid this, only set the PVC spec fields (except resources) during creation.
-		// In most cases, updates to these fields are invalid anyways.
-		if pvc.CreationTimestamp.IsZero() {
-			pvc.Spec = *config.Spec
-		} else {
-			// Resource requests can be expanded, and in rare cases shrunken. Let the modification proceed,
-			// and if not admitted, let the user know with a warning Event.
-			requestedStorage := config.Spec.Resources.Requests.Storage()
-			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *requestedStorage
-		}
-		return nil
-	})
-	if err != nil {
-		return err
+// keepImmutablePVCFields keeps the existing values of the immutable fields set in the
+// desired PVC, or owned by the operator
+func keepImmutablePVCFields(desired *corev1.PersistentVolumeClaim, existing *corev1.PersistentVolumeClaim) {
+	keep := func(field string, set bool) bool {
+		return set || isFieldManagedByOperator(existing, "spec", field)
+	}
+	if keep("accessModes", desired.Spec.AccessModes != nil) {
+		desired.Spec.AccessModes = existing.Spec.AccessModes
+	}
+	if keep("selector", desired.Spec.Selector != nil) {
+		desired.Spec.Selector = existing.Spec.Selector
+	}
+	if keep("volumeName", desired.Spec.VolumeName != "") {
+		desired.Spec.VolumeName = existing.Spec.VolumeName
+	}
+	if keep("storageClassName", desired.Spec.StorageClassName != nil) {
+		desired.Spec.StorageClassName = existing.Spec.StorageClassName
+	}
+	if keep("volumeMode", desired.Spec.VolumeMode != nil) {
+		desired.Spec.VolumeMode = existing.Spec.VolumeMode
+	}
+	if keep("dataSource", desired.Spec.DataSource != nil) {
+		desired.Spec.DataSource = existing.Spec.DataSource
+	}
+	if keep("dataSourceRef", desired.Spec.DataSourceRef != nil) {
+		desired.Spec.DataSourceRef = existing.Spec.DataSourceRef
 	}
-	r.Log.Info(fmt.Sprintf("Persistent Volume Claim %s", op), "name", pvc.Name, "namespace", pvc.Namespace)
-	return nil
 }
 
 func configurePVC(name string, cfg *operatorv1beta2.StorageConfiguration, defaultSize resource.Quantity) *operatorv1beta2.PersistentVolumeClaimConfig {
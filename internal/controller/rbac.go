@@ -17,19 +17,16 @@ package controller
 import (
 	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 
 	"github.com/cryostatio/cryostat-operator/internal/controller/common"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
-	"github.com/google/go-cmp/cmp"
 	oauthv1 "github.com/openshift/api/oauth/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *Reconciler) reconcileRBAC(ctx context.Context, cr *model.CryostatInstance) error {
@@ -57,6 +54,10 @@ func (r *Reconciler) finalizeRBAC(ctx context.Context, cr *model.CryostatInstanc
 	if err != nil {
 		return err
 	}
+	err = r.deleteClusterRoleBinding(ctx, r.newAllNamespacesClusterRoleBinding(cr))
+	if err != nil {
+		return err
+	}
 	return r.finalizeRoleBindings(ctx, cr)
 }
 
@@ -120,6 +121,18 @@ func (r *Reconciler) newRoleBinding(cr *model.CryostatInstance, namespace string
 }
 
 func (r *Reconciler) reconcileRoleBinding(ctx context.Context, cr *model.CryostatInstance) error {
+	// Access to all namespaces is granted by a ClusterRoleBinding instead
+	if cr.Spec.AllNamespaces {
+		err := r.cleanUpRoleBindings(ctx, cr)
+		if err != nil {
+			return err
+		}
+		for _, ns := range cr.TargetNamespaces {
+			targetNamespaceStatus(cr, ns).RBACReady = true
+		}
+		return nil
+	}
+
 	sa := newServiceAccount(cr)
 	subjects := []rbacv1.Subject{
 		{
@@ -135,27 +148,49 @@ func (r *Reconciler) reconcileRoleBinding(ctx context.Context, cr *model.Cryosta
 		roleRef := &rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
-			Name:     "cryostat-operator-cryostat-namespaced",
+			Name:     namespacedClusterRoleName,
 		}
 
 		err := r.createOrUpdateRoleBinding(ctx, binding, cr.Object, subjects, roleRef,
 			common.LabelsForTargetNamespaceObject(cr))
 		if err != nil {
-			return err
+			// Continue with other namespaces
+			r.recordTargetNamespaceError(cr, ns, err)
 		}
+		targetNamespaceStatus(cr, ns).RBACReady = err == nil
 	}
 	// Delete any RoleBindings in target namespaces that are no longer requested
 	for _, ns := range toDelete(cr) {
 		binding := r.newRoleBinding(cr, ns)
 		err := r.deleteRoleBinding(ctx, binding)
 		if err != nil {
-			return err
+			r.recordTargetNamespaceError(cr, ns, err)
 		}
 	}
 
 	return nil
 }
 
+func (r *Reconciler) cleanUpRoleBindings(ctx context.Context, cr *model.CryostatInstance) error {
+	// Look up bindings by label, rather than checking every namespace in the cluster
+	bindings := &rbacv1.RoleBindingList{}
+	err := r.List(ctx, bindings, client.MatchingLabels(common.LabelsForTargetNamespaceObject(cr)))
+	if err != nil {
+		return err
+	}
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		if binding.Name != r.newRoleBinding(cr, binding.Namespace).Name {
+			continue
+		}
+		err := r.deleteRoleBinding(ctx, binding)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *Reconciler) finalizeRoleBindings(ctx context.Context, cr *model.CryostatInstance) error {
 	for _, ns := range cr.TargetNamespaces {
 		binding := r.newRoleBinding(cr, ns)
@@ -175,11 +210,43 @@ func (r *Reconciler) newClusterRoleBinding(cr *model.CryostatInstance) *rbacv1.C
 	}
 }
 
-const clusterRoleName = "cryostat-operator-cryostat"
+func (r *Reconciler) newAllNamespacesClusterRoleBinding(cr *model.CryostatInstance) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: common.ClusterUniqueNameWithPrefix(r.gvk, "all-namespaces", cr.Name, cr.InstallNamespace),
+		},
+	}
+}
+
+const (
+	clusterRoleName           = "cryostat-operator-cryostat"
+	namespacedClusterRoleName = "cryostat-operator-cryostat-namespaced"
+)
 
 func (r *Reconciler) reconcileClusterRoleBinding(ctx context.Context, cr *model.CryostatInstance) error {
-	binding := r.newClusterRoleBinding(cr)
+	err := r.reconcileServiceAccountClusterRoleBinding(ctx, cr, r.newClusterRoleBinding(cr), clusterRoleName)
+	if err != nil {
+		return err
+	}
 
+	// In all namespaces mode, bind the namespaced ClusterRole cluster-wide
+	// in place of the RoleBindings in each target namespace
+	binding := r.newAllNamespacesClusterRoleBinding(cr)
+	if cr.Spec.AllNamespaces {
+		err = r.reconcileServiceAccountClusterRoleBinding(ctx, cr, binding, namespacedClusterRoleName)
+		if err != nil {
+			return err
+		}
+		for _, ns := range cr.TargetNamespaces {
+			targetNamespaceStatus(cr, ns).RBACReady = true
+		}
+		return nil
+	}
+	return r.deleteClusterRoleBinding(ctx, binding)
+}
+
+func (r *Reconciler) reconcileServiceAccountClusterRoleBinding(ctx context.Context, cr *model.CryostatInstance,
+	binding *rbacv1.ClusterRoleBinding, roleName string) error {
 	sa := newServiceAccount(cr)
 	subjects := []rbacv1.Subject{
 		{
@@ -192,39 +259,21 @@ func (r *Reconciler) reconcileClusterRoleBinding(ctx context.Context, cr *model.
 	roleRef := &rbacv1.RoleRef{
 		APIGroup: "rbac.authorization.k8s.io",
 		Kind:     "ClusterRole",
-		Name:     clusterRoleName,
+		Name:     roleName,
 	}
 
-	return r.createOrUpdateClusterRoleBinding(ctx, binding, cr.Object, subjects, roleRef)
+	return r.createOrUpdateClusterRoleBinding(ctx, binding, cr.Object, subjects, roleRef,
+		common.LabelsForTargetNamespaceObject(cr))
 }
 
 func (r *Reconciler) createOrUpdateServiceAccount(ctx context.Context, sa *corev1.ServiceAccount,
 	owner metav1.Object, labels map[string]string, annotations map[string]string) error {
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, sa, func() error {
-		// TODO just replace the labels and annotations we manage, once we allow the user to configure
-		// ServiceAccount annotations/labels in the CR, we can simply overwrite them all
+	// Only the labels and annotations we manage are applied, others are left as is
+	common.MergeLabelsAndAnnotations(&sa.ObjectMeta, labels, annotations)
 
-		// Update labels and annotations managed by the operator
-		for key, val := range labels {
-			metav1.SetMetaDataLabel(&sa.ObjectMeta, key, val)
-		}
-		for key, val := range annotations {
-			metav1.SetMetaDataAnnotation(&sa.ObjectMeta, key, val)
-		}
-
-		// Set the Cryostat CR as controller
-		if err := controllerutil.SetControllerReference(owner, sa, r.Scheme); err != nil {
-			return err
-		}
-		// AutomountServiceAccountToken specified in Pod, which takes precedence
-		// Secrets, ImagePullSecrets are modified by Kubernetes/OpenShift
-		return nil
-	})
-	if err != nil {
-		return err
-	}
-	r.Log.Info(fmt.Sprintf("Service Account %s", op), "name", sa.Name, "namespace", sa.Namespace)
-	return nil
+	// AutomountServiceAccountToken specified in Pod, which takes precedence
+	// Secrets, ImagePullSecrets are modified by Kubernetes/OpenShift
+	return r.applyObject(ctx, sa, owner)
 }
 
 func (r *Reconciler) cleanUpRole(ctx context.Context, cr *model.CryostatInstance, role *rbacv1.Role) error {
@@ -246,44 +295,25 @@ func (r *Reconciler) createOrUpdateRoleBinding(ctx context.Context, binding *rba
 	owner metav1.Object, subjects []rbacv1.Subject, roleRef *rbacv1.RoleRef,
 	labels map[string]string) error {
 	bindingCopy := binding.DeepCopy()
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, binding, func() error {
-		common.MergeLabelsAndAnnotations(&binding.ObjectMeta, labels, map[string]string{})
-		// Update the list of Subjects
-		binding.Subjects = subjects
-		// Update the Role reference
-		roleRef, err := getRoleRef(binding, &binding.RoleRef, roleRef)
-		if err != nil {
-			return err
-		}
-		binding.RoleRef = *roleRef
-		return nil
-	})
+	common.MergeLabelsAndAnnotations(&binding.ObjectMeta, labels, map[string]string{})
+	// Update the list of Subjects
+	binding.Subjects = subjects
+	// Update the Role reference
+	binding.RoleRef = *roleRef
+
+	err := r.applyObject(ctx, binding, nil)
 	if err != nil {
-		if err == errRoleRefModified {
+		// The RoleRef field is immutable. In order to update this field, we need to
+		// delete and re-create the role binding.
+		// See: https://kubernetes.io/docs/reference/access-authn-authz/rbac/#clusterrolebinding-example
+		if isImmutableFieldChanged(err, "roleRef") {
 			return r.recreateRoleBinding(ctx, bindingCopy, owner, subjects, roleRef, labels)
 		}
 		return err
 	}
-	r.Log.Info(fmt.Sprintf("Role Binding %s", op), "name", binding.Name, "namespace", binding.Namespace)
 	return nil
 }
 
-var errRoleRefModified error = errors.New("role binding roleRef has been modified")
-
-func getRoleRef(binding metav1.Object, oldRef *rbacv1.RoleRef, newRef *rbacv1.RoleRef) (*rbacv1.RoleRef, error) {
-	// The RoleRef field is immutable. In order to update this field, we need to
-	// delete and re-create the role binding.
-	// See: https://kubernetes.io/docs/reference/access-authn-authz/rbac/#clusterrolebinding-example
-	creationTimestamp := binding.GetCreationTimestamp()
-	if creationTimestamp.IsZero() {
-		return newRef, nil
-	} else if !cmp.Equal(oldRef, newRef) {
-		// Return error so role binding can be recreated
-		return nil, errRoleRefModified
-	}
-	return oldRef, nil
-}
-
 func (r *Reconciler) recreateRoleBinding(ctx context.Context, binding *rbacv1.RoleBinding, owner metav1.Object,
 	subjects []rbacv1.Subject, roleRef *rbacv1.RoleRef, labels map[string]string) error {
 	// Delete and recreate role binding
@@ -309,39 +339,35 @@ func (r *Reconciler) deleteRoleBinding(ctx context.Context, binding *rbacv1.Role
 }
 
 func (r *Reconciler) createOrUpdateClusterRoleBinding(ctx context.Context, binding *rbacv1.ClusterRoleBinding,
-	owner metav1.Object, subjects []rbacv1.Subject, roleRef *rbacv1.RoleRef) error {
+	owner metav1.Object, subjects []rbacv1.Subject, roleRef *rbacv1.RoleRef, labels map[string]string) error {
 	bindingCopy := binding.DeepCopy()
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, binding, func() error {
-		// Update the list of Subjects
-		binding.Subjects = subjects
-		// Update the Role reference
-		roleRef, err := getRoleRef(binding, &binding.RoleRef, roleRef)
-		if err != nil {
-			return err
-		}
-		binding.RoleRef = *roleRef
-
-		// ClusterRoleBinding can't be owned by namespaced CR, clean up using finalizer
-		return nil
-	})
+	// Labels identify the CR, since the binding has no owner reference to watch
+	common.MergeLabelsAndAnnotations(&binding.ObjectMeta, labels, map[string]string{})
+	// Update the list of Subjects
+	binding.Subjects = subjects
+	// Update the Role reference
+	binding.RoleRef = *roleRef
+
+	// ClusterRoleBinding can't be owned by namespaced CR, clean up using finalizer
+	err := r.applyObject(ctx, binding, nil)
 	if err != nil {
-		if err == errRoleRefModified {
-			return r.recreateClusterRoleBinding(ctx, bindingCopy, owner, subjects, roleRef)
+		// The RoleRef field is immutable, so the binding must be recreated if it changed
+		if isImmutableFieldChanged(err, "roleRef") {
+			return r.recreateClusterRoleBinding(ctx, bindingCopy, owner, subjects, roleRef, labels)
 		}
 		return err
 	}
-	r.Log.Info(fmt.Sprintf("Cluster Role Binding %s", op), "name", binding.Name)
 	return nil
 }
 
 func (r *Reconciler) recreateClusterRoleBinding(ctx context.Context, binding *rbacv1.ClusterRoleBinding, owner metav1.Object,
-	subjects []rbacv1.Subject, roleRef *rbacv1.RoleRef) error {
+	subjects []rbacv1.Subject, roleRef *rbacv1.RoleRef, labels map[string]string) error {
 	// Delete and recreate role binding
 	err := r.deleteClusterRoleBinding(ctx, binding)
 	if err != nil {
 		return err
 	}
-	return r.createOrUpdateClusterRoleBinding(ctx, binding, owner, subjects, roleRef)
+	return r.createOrUpdateClusterRoleBinding(ctx, binding, owner, subjects, roleRef, labels)
 }
 
 func (r *Reconciler) deleteClusterRoleBinding(ctx context.Context, clusterBinding *rbacv1.ClusterRoleBinding) error {
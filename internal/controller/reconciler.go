@@ -18,9 +18,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
@@ -30,10 +32,10 @@ import (
 	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
 	"github.com/go-logr/logr"
-	"github.com/google/go-cmp/cmp"
 	openshiftv1 "github.com/openshift/api/route/v1"
 	securityv1 "github.com/openshift/api/security/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -48,6 +50,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -67,6 +70,8 @@ type ReconcilerConfig struct {
 	NewControllerBuilder   func(ctrl.Manager) common.ControllerBuilder
 	common.ReconcilerTLS
 	common.OSUtils
+	// Used to check connectivity to an external database
+	Dialer common.Dialer
 }
 
 // CommonReconciler is an interface for behaviour of the Cryostat reconciler
@@ -83,6 +88,11 @@ type Reconciler struct {
 	gvk          *schema.GroupVersionKind
 }
 
+// PendingRequeueAfter is how long to wait before reconciling a Cryostat again while its resources
+// are not yet ready. Longer requeues are only scheduled for renewing certificates and rotating
+// credentials, and do not indicate that the Cryostat is still being set up.
+const PendingRequeueAfter = 5 * time.Second
+
 // Name used for Finalizer that handles Cryostat deletion
 const cryostatFinalizer = "operator.cryostat.io/cryostat.finalizer"
 
@@ -113,6 +123,9 @@ const databaseImageTagEnv = "RELATED_IMAGE_DATABASE"
 // Environment variable to override the agent proxy image
 const agentProxyImageTagEnv = "RELATED_IMAGE_AGENT_PROXY"
 
+// Environment variable to override the agent init container image
+const agentInitImageTagEnv = "RELATED_IMAGE_AGENT_INIT"
+
 // Regular expression for the start of a GID range in the OpenShift
 // supplemental groups SCC annotation
 var supGroupRegexp = regexp.MustCompile(`^\d+`)
@@ -126,6 +139,7 @@ const (
 	reasonAllCertsReady          = "AllCertificatesReady"
 	reasonCertManagerUnavailable = "CertManagerUnavailable"
 	reasonCertManagerDisabled    = "CertManagerDisabled"
+	reasonProvidedCertInvalid    = "ProvidedCertificateInvalid"
 )
 
 // Map Cryostat conditions to deployment conditions
@@ -160,6 +174,9 @@ func newReconciler(config *ReconcilerConfig, objType client.Object, isNamespaced
 	if config.OSUtils == nil {
 		config.OSUtils = &common.DefaultOSUtils{}
 	}
+	if config.Dialer == nil {
+		config.Dialer = &net.Dialer{}
+	}
 	return &Reconciler{
 		ReconcilerConfig: config,
 		objectType:       objType,
@@ -174,6 +191,9 @@ func (r *Reconciler) reconcileCryostat(ctx context.Context, cr *model.CryostatIn
 	// Check if this Cryostat is being deleted
 	if cr.Object.GetDeletionTimestamp() != nil {
 		if controllerutil.ContainsFinalizer(cr.Object, cryostatFinalizer) {
+			// Clean up every namespace targeted when last reconciled, including
+			// those that matched the target namespace selector at the time
+			addStatusTargetNamespaces(cr)
 			err := r.finalizeCryostat(ctx, cr)
 			if err != nil {
 				return reconcile.Result{}, err
@@ -191,118 +211,294 @@ func (r *Reconciler) reconcileCryostat(ctx context.Context, cr *model.CryostatIn
 		}
 	}
 
-	// Create lock config map or fail if owned by another CR
-	err := r.reconcileLockConfigMap(ctx, cr)
+	// Add any namespaces matching the target namespace selector
+	err := r.resolveTargetNamespaces(ctx, cr)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	err = r.reconcileSecrets(ctx, cr)
+	// Skip all changes to Cryostat resources while paused, but keep the status up to date
+	if isReconcilePaused(cr) {
+		return r.reconcilePaused(ctx, cr)
+	}
+	err = r.resumeIfPaused(ctx, cr)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	// Reconcile RBAC resources for Cryostat
-	err = r.reconcileRBAC(ctx, cr)
+	// Create lock config map or fail if owned by another CR
+	err = r.reconcileLockConfigMap(ctx, cr)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
+	// Track the readiness of each target namespace, so failures in one
+	// namespace do not block reconciling the others
+	initTargetNamespaceStatuses(cr)
+
+	// Reconcile each component independently, so that a failure in one component
+	// does not prevent reconciling those that do not depend on it
+	secretsErr := r.reconcileComponent(ctx, cr, operatorv1beta2.ConditionTypeSecretsReconcileFailed, func() error {
+		return r.reconcileSecrets(ctx, cr)
+	})
+
+	// Reconcile RBAC resources for Cryostat
+	rbacErr := r.reconcileComponent(ctx, cr, operatorv1beta2.ConditionTypeRBACReconcileFailed, func() error {
+		return r.reconcileRBAC(ctx, cr)
+	})
+
 	// Set up TLS using cert-manager, if available
-	tlsConfig, err := r.configureTLS(ctx, cr)
-	if err != nil {
-		if err == common.ErrCertNotReady {
-			// Not an error condition, just retry
-			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	var tlsConfig *resources.TLSConfig
+	var tlsRequeue, expiryRequeue time.Duration
+	tlsErr := r.reconcileComponent(ctx, cr, operatorv1beta2.ConditionTypeTLSReconcileFailed, func() error {
+		var err error
+		tlsConfig, tlsRequeue, err = r.configureTLS(ctx, cr)
+		// Publish when each certificate expires, and warn about those expiring soon. This is
+		// done even if TLS could not be set up, which may be caused by an expired certificate.
+		var statusErr error
+		expiryRequeue, statusErr = r.reconcileCertificateStatus(ctx, cr)
+		if err != nil {
+			return err
 		}
-		reqLogger.Error(err, "Failed to set up TLS for Cryostat")
-		return reconcile.Result{}, err
+		return statusErr
+	})
+
+	imageTags := r.getImageTags(cr)
+	cr.Status.Images = r.getImageStatus(cr, imageTags)
+	serviceSpecs := &resources.ServiceSpecs{
+		InsightsURL: r.InsightsProxy,
 	}
 
-	err = r.reconcileOAuth2ProxyConfig(ctx, cr, tlsConfig)
-	if err != nil {
-		return reconcile.Result{}, err
+	databaseErr := r.reconcileComponent(ctx, cr, operatorv1beta2.ConditionTypeDatabaseReconcileFailed, func() error {
+		fsGroup, err := r.getFSGroup(ctx, cr.InstallNamespace)
+		if err != nil {
+			return err
+		}
+		return r.reconcileDatabase(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs, *fsGroup)
+	}, secretsErr, tlsErr)
+
+	storageErr := r.reconcileComponent(ctx, cr, operatorv1beta2.ConditionTypeStorageReconcileFailed, func() error {
+		fsGroup, err := r.getFSGroup(ctx, cr.InstallNamespace)
+		if err != nil {
+			return err
+		}
+		return r.reconcileStorage(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs, *fsGroup)
+	}, secretsErr, tlsErr)
+
+	// Credentials are changed once the database and object storage are deployed
+	var credentialsRequeue time.Duration
+	credentialsErr := r.reconcileComponent(ctx, cr, operatorv1beta2.ConditionTypeCredentialsReconcileFailed, func() error {
+		var err error
+		credentialsRequeue, err = r.reconcileCredentials(ctx, cr, tlsConfig, imageTags, serviceSpecs)
+		return err
+	}, secretsErr, tlsErr, databaseErr, storageErr)
+
+	databaseBackupErr := r.reconcileComponent(ctx, cr, operatorv1beta2.ConditionTypeDatabaseBackupReconcileFailed, func() error {
+		return r.reconcileDatabaseBackup(ctx, cr, tlsConfig, imageTags, serviceSpecs)
+	}, secretsErr, tlsErr, databaseErr, storageErr)
+
+	reportsErr := r.reconcileComponent(ctx, cr, operatorv1beta2.ConditionTypeReportsReconcileFailed, func() error {
+		return r.reconcileReports(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs)
+	}, tlsErr)
+
+	// The main deployment uses the URLs of the storage and reports services, but
+	// does not depend on those components being reconciled
+	var deployment *appsv1.Deployment
+	coreErr := r.reconcileComponent(ctx, cr, operatorv1beta2.ConditionTypeCoreReconcileFailed, func() error {
+		var err error
+		deployment, err = r.reconcileCore(ctx, cr, tlsConfig, imageTags, serviceSpecs)
+		return err
+	}, secretsErr, rbacErr, tlsErr, databaseErr)
+	if serviceSpecs.CoreURL != nil {
+		cr.Status.ApplicationURL = serviceSpecs.CoreURL.String()
+	}
+
+	// OpenShift-specific
+	openShiftErr := r.reconcileComponent(ctx, cr, operatorv1beta2.ConditionTypeOpenShiftReconcileFailed, func() error {
+		return r.reconcileOpenShift(ctx, cr)
+	}, coreErr)
+
+	componentErrs := []error{secretsErr, rbacErr, tlsErr, databaseErr, storageErr, credentialsErr, databaseBackupErr, reportsErr,
+		coreErr, openShiftErr}
+	failedComponents := []error{}
+	pending := false
+	for _, componentErr := range componentErrs {
+		if componentErr == nil {
+			continue
+		}
+		if isNotReady(componentErr) || componentErr == errDependencyNotReady {
+			pending = true
+		} else if componentErr != errDependencyFailed {
+			failedComponents = append(failedComponents, componentErr)
+		}
+	}
+
+	// Update CR Status
+	r.countInjectedAgentPods(ctx, cr)
+	*cr.TargetNamespaceStatus = statusTargetNamespaces(cr)
+
+	// The current generation of the CR has been fully applied, unless a component or target namespace failed
+	failed := failedTargetNamespaces(cr)
+	if len(failed) == 0 && len(failedComponents) == 0 && !pending {
+		cr.Status.ObservedGeneration = cr.Object.GetGeneration()
+	}
+
+	if deployment != nil {
+		// Check deployment status and update conditions
+		err = r.updateConditionsFromDeployment(ctx, cr, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace},
+			mainDeploymentConditions)
+	} else {
+		setAggregateConditions(cr)
+		err = r.Status().Update(ctx, cr.Object)
 	}
-	err = r.reconcileAgentProxyConfig(ctx, cr, tlsConfig)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	serviceSpecs := &resources.ServiceSpecs{
-		InsightsURL: r.InsightsProxy,
+	// Retry any components or target namespaces that failed, with backoff
+	if len(failedComponents) == 1 {
+		return reconcile.Result{}, failedComponents[0]
+	} else if len(failedComponents) > 1 {
+		return reconcile.Result{}, errors.Join(failedComponents...)
+	}
+	if len(failed) > 0 {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile target namespaces: %s", strings.Join(failed, ", "))
+	}
+	// Retry any components or target namespaces that are not yet ready
+	if pending || !allAgentTLSReady(cr) || isDatabaseUnreachable(cr) || isDatabaseUpgrading(cr) {
+		return reconcile.Result{RequeueAfter: PendingRequeueAfter}, nil
+	}
+	// Check on any credential changes in progress, or rotate the credentials when they are next due.
+	// Certificates issued by the built-in CA are likewise renewed when they are next due, and
+	// certificates are reported once they enter the expiry warning window or expire.
+	if requeue := earliestRequeue(credentialsRequeue, tlsRequeue, expiryRequeue); requeue > 0 {
+		return reconcile.Result{RequeueAfter: requeue}, nil
+	}
+
+	reqLogger.Info("Successfully reconciled Cryostat")
+	return reconcile.Result{}, nil
+}
+
+func (r *Reconciler) reconcileCore(ctx context.Context, cr *model.CryostatInstance, tlsConfig *resources.TLSConfig,
+	imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs) (*appsv1.Deployment, error) {
+	err := r.reconcileOAuth2ProxyConfig(ctx, cr, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	err = r.reconcileAgentProxyConfig(ctx, cr, tlsConfig)
+	if err != nil {
+		return nil, err
 	}
 	err = r.reconcileCoreService(ctx, cr, tlsConfig, serviceSpecs)
 	if err != nil {
-		return requeueIfIngressNotReady(err)
+		return nil, err
 	}
 	err = r.reconcileCoreNetworkPolicy(ctx, cr)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
 	err = r.reconcileAgentGatewayService(ctx, cr)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
 	err = r.reconcileAgentCallbackServices(ctx, cr)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
 
-	imageTags := r.getImageTags()
-	fsGroup, err := r.getFSGroup(ctx, cr.InstallNamespace)
+	// Set these URLs here, in case the storage and reports components failed
+	serviceSpecs.ReportsURL = reportsURL(cr, tlsConfig)
+	serviceSpecs.StorageURL, err = storageURL(cr, tlsConfig)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
 
-	err = r.reconcileDatabase(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs, *fsGroup)
+	fsGroup, err := r.getFSGroup(ctx, cr.InstallNamespace)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
-
-	err = r.reconcileStorage(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs, *fsGroup)
+	deployment, err := resources.NewDeploymentForCR(cr, serviceSpecs, imageTags, tlsConfig, *fsGroup, r.IsOpenShift)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
-
-	err = r.reconcileReports(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs)
-	if err != nil {
-		return reconcile.Result{}, err
+	// Stop Cryostat while its database is being restored or upgraded
+	if isRestoreInProgress(cr) || isDatabaseUpgrading(cr) {
+		replicas := int32(0)
+		deployment.Spec.Replicas = &replicas
 	}
-
-	deployment, err := resources.NewDeploymentForCR(cr, serviceSpecs, imageTags, tlsConfig, *fsGroup, r.IsOpenShift)
+	// Keep Cryostat running with its current credentials until object storage is using rotated credentials
+	held, err := r.isCoreRolloutHeld(ctx, cr)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
+	}
+	if held {
+		found := &appsv1.Deployment{}
+		err = r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
+		if err == nil {
+			r.Log.Info("Waiting for object storage to use rotated credentials", "name", deployment.Name,
+				"namespace", deployment.Namespace)
+			return found, nil
+		} else if !kerrors.IsNotFound(err) {
+			return nil, err
+		}
 	}
 	err = r.createOrUpdateDeployment(ctx, deployment, cr.Object)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
+	return deployment, nil
+}
 
-	// Update CR Status
-	if serviceSpecs.CoreURL != nil {
-		cr.Status.ApplicationURL = serviceSpecs.CoreURL.String()
-	}
-	*cr.TargetNamespaceStatus = cr.TargetNamespaces
-	err = r.Status().Update(ctx, cr.Object)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
+const reasonReconcileFailed = "ReconcileFailed"
 
-	// OpenShift-specific
-	err = r.reconcileOpenShift(ctx, cr)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
+// errDependencyNotReady is returned when a component is skipped because
+// a component it depends on is not yet ready
+var errDependencyNotReady = errors.New("dependency not yet ready")
 
-	// Check deployment status and update conditions
-	err = r.updateConditionsFromDeployment(ctx, cr, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace},
-		mainDeploymentConditions)
-	if err != nil {
-		return reconcile.Result{}, err
+// errDependencyFailed is returned when a component is skipped because
+// a component it depends on failed to reconcile
+var errDependencyFailed = errors.New("dependency failed to reconcile")
+
+// reconcileComponent runs reconcileFunc, unless one of the dependency errors is non-nil.
+// A failure is reported using the component's ReconcileFailed condition and a Warning event,
+// which are cleared once the component reconciles successfully.
+func (r *Reconciler) reconcileComponent(ctx context.Context, cr *model.CryostatInstance,
+	condType operatorv1beta2.CryostatConditionType, reconcileFunc func() error, dependencies ...error) error {
+	for _, dependency := range dependencies {
+		if dependency == nil {
+			continue
+		}
+		if isNotReady(dependency) || dependency == errDependencyNotReady {
+			return errDependencyNotReady
+		}
+		// Leave the component's condition as is, it will be retried along with its dependency
+		return errDependencyFailed
+	}
+
+	err := reconcileFunc()
+	if err != nil && !isNotReady(err) {
+		r.Log.Error(err, "Failed to reconcile component", "name", cr.Name, "namespace", cr.InstallNamespace,
+			"condition", condType)
+		r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, string(condType), err.Error())
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:               string(condType),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: cr.Object.GetGeneration(),
+			Reason:             reasonReconcileFailed,
+			Message:            err.Error(),
+		})
+		return err
+	}
+	if err == nil {
+		removeConditionIfPresent(cr, condType)
 	}
+	return err
+}
 
-	reqLogger.Info("Successfully reconciled Cryostat")
-	return reconcile.Result{}, nil
+// isNotReady returns whether the error indicates that a resource is still
+// being prepared, rather than a failure
+func isNotReady(err error) bool {
+	return err == common.ErrCertNotReady || err == ErrIngressNotReady
 }
 
 func (r *Reconciler) setupWithManager(c common.ControllerBuilder, impl reconcile.Reconciler) error {
@@ -310,7 +506,8 @@ func (r *Reconciler) setupWithManager(c common.ControllerBuilder, impl reconcile
 
 	// Watch for changes to secondary resources and requeue the owner Cryostat
 	objTypes := []client.Object{&appsv1.Deployment{}, &corev1.Service{}, &corev1.ConfigMap{}, &corev1.Secret{},
-		&corev1.PersistentVolumeClaim{}, &corev1.ServiceAccount{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}, &netv1.Ingress{}}
+		&corev1.PersistentVolumeClaim{}, &corev1.ServiceAccount{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}, &netv1.Ingress{},
+		&netv1.NetworkPolicy{}, &batchv1.CronJob{}, &batchv1.Job{}}
 	if r.IsOpenShift {
 		objTypes = append(objTypes, &openshiftv1.Route{})
 	}
@@ -323,12 +520,32 @@ func (r *Reconciler) setupWithManager(c common.ControllerBuilder, impl reconcile
 		c = c.Owns(objType)
 	}
 
-	// Watch objects that we create in target namespaces
-	c, err := r.watchTargetNamespaces(c, &rbacv1.RoleBinding{}, &corev1.Secret{}, &corev1.Service{})
+	// Watch objects that we create in target namespaces, along with cluster-scoped
+	// objects that cannot have an owner reference to the CR
+	c, err := r.watchTargetNamespaces(c, &rbacv1.RoleBinding{}, &corev1.Secret{}, &corev1.Service{},
+		&rbacv1.ClusterRoleBinding{})
+	if err != nil {
+		return err
+	}
+
+	// Watch namespaces to update the targets of CRs using a namespace selector
+	c = r.watchNamespaces(c)
+
+	// Watch pods labelled for agent injection to update the targets of CRs in all namespaces mode
+	c, err = r.watchAgentPods(c)
 	if err != nil {
 		return err
 	}
 
+	// Watch secrets containing user-provided TLS certificates
+	c = r.watchProvidedTLSSecrets(c)
+
+	// Watch the cluster's TLS security profile, and certificates issued by the service CA
+	if r.IsOpenShift {
+		c = r.watchAPIServer(c)
+		c = r.watchServingCertSecrets(c)
+	}
+
 	return c.Complete(impl)
 }
 
@@ -351,8 +568,11 @@ func (r *Reconciler) finalizeCryostat(ctx context.Context, cr *model.CryostatIns
 		return err
 	}
 
+	// Stop exporting the expiry of this CR's certificates
+	deleteCertificateExpiryMetrics(cr)
+
 	// Finalizer for certificates and associated secrets
-	if r.IsCertManagerEnabled(cr) {
+	if r.IsTLSEnabled(cr) {
 		err = r.finalizeTLS(ctx, cr)
 		if err != nil {
 			return err
@@ -366,17 +586,30 @@ func (r *Reconciler) finalizeCryostat(ctx context.Context, cr *model.CryostatIns
 	return nil
 }
 
-func (r *Reconciler) configureTLS(ctx context.Context, cr *model.CryostatInstance) (*resources.TLSConfig, error) {
+func (r *Reconciler) configureTLS(ctx context.Context, cr *model.CryostatInstance) (*resources.TLSConfig, time.Duration, error) {
 	var tlsConfig *resources.TLSConfig
+	var requeue time.Duration
 	var err error
-	if r.IsCertManagerEnabled(cr) {
-		tlsConfig, err = r.setupTLS(ctx, cr)
+	if r.IsTLSEnabled(cr) {
+		if r.IsCertManagerEnabled(cr) {
+			tlsConfig, requeue, err = r.setupTLS(ctx, cr)
+		} else if common.IsBuiltInCAEnabled(cr) {
+			tlsConfig, requeue, err = r.setupBuiltInTLS(ctx, cr)
+		} else if common.IsServiceCAEnabled(cr) {
+			tlsConfig, requeue, err = r.setupServiceCATLS(ctx, cr)
+		} else {
+			tlsConfig, err = r.setupProvidedTLS(ctx, cr)
+			if err == nil {
+				// The CA of provided certificates is managed by the user
+				err = r.deleteCATrustBundle(ctx, cr)
+			}
+		}
 		if err != nil {
 			if err == common.ErrCertNotReady {
 				condErr := r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
 					reasonWaitingForCert, "Waiting for certificates to become ready.")
 				if condErr != nil {
-					return nil, err
+					return nil, 0, err
 				}
 
 			}
@@ -384,25 +617,47 @@ func (r *Reconciler) configureTLS(ctx context.Context, cr *model.CryostatInstanc
 				condErr := r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
 					reasonCertManagerUnavailable, eventCertManagerUnavailableMsg)
 				if condErr != nil {
-					return nil, condErr
+					return nil, 0, condErr
 				}
 			}
-			return nil, err
+			if errors.Is(err, errProvidedCertificateInvalid) {
+				r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, eventProvidedCertificateInvalidType, err.Error())
+				condErr := r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
+					reasonProvidedCertInvalid, err.Error())
+				if condErr != nil {
+					return nil, 0, condErr
+				}
+			}
+			return nil, 0, err
+		}
+
+		// Servers using these certificates follow the TLS security profile
+		tlsConfig.SecurityProfile, err = common.TLSSecurityProfile(ctx, r.Client, cr, r.IsOpenShift, r.FIPSEnabled)
+		if err != nil {
+			return nil, 0, err
 		}
 
 		err = r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
 			reasonAllCertsReady, "All certificates for Cryostat components are ready.")
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 	} else {
+		// No agent certificates are needed
+		for _, ns := range cr.TargetNamespaces {
+			targetNamespaceStatus(cr, ns).AgentTLSReady = true
+		}
+		err = r.deleteCATrustBundle(ctx, cr)
+		if err != nil {
+			return nil, 0, err
+		}
 		err = r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
 			reasonCertManagerDisabled, "TLS setup has been disabled.")
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 	}
-	return tlsConfig, err
+	return tlsConfig, requeue, err
 }
 
 func (r *Reconciler) reconcileReports(ctx context.Context, reqLogger logr.Logger, cr *model.CryostatInstance,
@@ -422,7 +677,10 @@ func (r *Reconciler) reconcileReports(ctx context.Context, reqLogger logr.Logger
 	if err != nil {
 		return err
 	}
-	deployment := resources.NewDeploymentForReports(cr, imageTags, serviceSpecs, tls, r.IsOpenShift)
+	deployment, err := resources.NewDeploymentForReports(cr, imageTags, serviceSpecs, tls, r.IsOpenShift)
+	if err != nil {
+		return err
+	}
 	if desired == 0 {
 		if err := r.Delete(ctx, deployment); err != nil && !kerrors.IsNotFound(err) {
 			return err
@@ -469,7 +727,41 @@ func (r *Reconciler) reconcileDatabase(ctx context.Context, reqLogger logr.Logge
 	if err != nil {
 		return err
 	}
-	deployment := resources.NewDeploymentForDatabase(cr, imageTags, tls, r.IsOpenShift, fsGroup)
+	deployment, err := resources.NewDeploymentForDatabase(cr, imageTags, tls, r.IsOpenShift, fsGroup)
+	if err != nil {
+		return err
+	}
+	deployManagedDatabase := resources.DeployManagedDatabase(cr)
+	if !deployManagedDatabase {
+		if err := r.Delete(ctx, deployment); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+
+		removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeDatabaseDeploymentAvailable,
+			operatorv1beta2.ConditionTypeDatabaseDeploymentProgressing,
+			operatorv1beta2.ConditionTypeDatabaseDeploymentReplicaFailure)
+		r.checkDatabaseReachable(ctx, cr)
+		return r.Status().Update(ctx, cr.Object)
+	}
+	removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeDatabaseReachable)
+
+	// Keep using the previous image, and stop the database while its data is upgraded
+	image, stop, err := r.reconcileDatabaseUpgrade(ctx, cr, imageTags, fsGroup)
+	if err != nil {
+		return err
+	}
+	if image != imageTags.DatabaseImageTag {
+		previousImageTags := *imageTags
+		previousImageTags.DatabaseImageTag = image
+		deployment, err = resources.NewDeploymentForDatabase(cr, &previousImageTags, tls, r.IsOpenShift, fsGroup)
+		if err != nil {
+			return err
+		}
+	}
+	if stop {
+		replicas := int32(0)
+		deployment.Spec.Replicas = &replicas
+	}
 
 	err = r.createOrUpdateDeployment(ctx, deployment, cr.Object)
 	if err != nil {
@@ -503,10 +795,13 @@ func (r *Reconciler) reconcileStorage(ctx context.Context, reqLogger logr.Logger
 		return err
 	}
 
-	deployment := resources.NewDeploymentForStorage(cr, imageTags, tls, r.IsOpenShift, fsGroup)
+	deployment, err := resources.NewDeploymentForStorage(cr, imageTags, tls, r.IsOpenShift, fsGroup)
+	if err != nil {
+		return err
+	}
 	deployManagedStorage := resources.DeployManagedStorage(cr)
 	if !deployManagedStorage {
-		serviceSpecs.StorageURL, err = url.Parse(*cr.Spec.ObjectStorageOptions.Provider.URL)
+		serviceSpecs.StorageURL, err = storageURL(cr, tls)
 		if err != nil {
 			return err
 		}
@@ -538,17 +833,54 @@ func (r *Reconciler) reconcileStorage(ctx context.Context, reqLogger logr.Logger
 	return nil
 }
 
-func (r *Reconciler) getImageTags() *resources.ImageTags {
+func (r *Reconciler) getImageTags(cr *model.CryostatInstance) *resources.ImageTags {
+	images := cr.Spec.Images
+	if images == nil {
+		images = &operatorv1beta2.ImageConfigList{}
+	}
 	return &resources.ImageTags{
-		OAuth2ProxyImageTag:         r.GetEnvOrDefault(oauth2ProxyImageTagEnv, constants.DefaultOAuth2ProxyImageTag),
-		OpenShiftOAuthProxyImageTag: r.GetEnvOrDefault(openshiftOauthProxyImageTagEnv, constants.DefaultOpenShiftOAuthProxyImageTag),
-		CoreImageTag:                r.GetEnvOrDefault(coreImageTagEnv, constants.DefaultCoreImageTag),
-		DatasourceImageTag:          r.GetEnvOrDefault(datasourceImageTagEnv, constants.DefaultDatasourceImageTag),
-		GrafanaImageTag:             r.GetEnvOrDefault(grafanaImageTagEnv, constants.DefaultGrafanaImageTag),
-		ReportsImageTag:             r.GetEnvOrDefault(reportsImageTagEnv, constants.DefaultReportsImageTag),
-		StorageImageTag:             r.GetEnvOrDefault(storageImageTagEnv, constants.DefaultStorageImageTag),
-		DatabaseImageTag:            r.GetEnvOrDefault(databaseImageTagEnv, constants.DefaultDatabaseImageTag),
-		AgentProxyImageTag:          r.GetEnvOrDefault(agentProxyImageTagEnv, constants.DefaultAgentProxyImageTag),
+		OAuth2ProxyImageTag: common.ResolveImage(images.AuthProxy,
+			r.GetEnvOrDefault(oauth2ProxyImageTagEnv, constants.DefaultOAuth2ProxyImageTag)),
+		OpenShiftOAuthProxyImageTag: common.ResolveImage(images.AuthProxy,
+			r.GetEnvOrDefault(openshiftOauthProxyImageTagEnv, constants.DefaultOpenShiftOAuthProxyImageTag)),
+		CoreImageTag: common.ResolveImage(images.Core,
+			r.GetEnvOrDefault(coreImageTagEnv, constants.DefaultCoreImageTag)),
+		DatasourceImageTag: common.ResolveImage(images.DataSource,
+			r.GetEnvOrDefault(datasourceImageTagEnv, constants.DefaultDatasourceImageTag)),
+		GrafanaImageTag: common.ResolveImage(images.Grafana,
+			r.GetEnvOrDefault(grafanaImageTagEnv, constants.DefaultGrafanaImageTag)),
+		ReportsImageTag: common.ResolveImage(images.Reports,
+			r.GetEnvOrDefault(reportsImageTagEnv, constants.DefaultReportsImageTag)),
+		StorageImageTag: common.ResolveImage(images.Storage,
+			r.GetEnvOrDefault(storageImageTagEnv, constants.DefaultStorageImageTag)),
+		DatabaseImageTag: common.ResolveImage(images.Database,
+			r.GetEnvOrDefault(databaseImageTagEnv, constants.DefaultDatabaseImageTag)),
+		AgentProxyImageTag: common.ResolveImage(images.AgentProxy,
+			r.GetEnvOrDefault(agentProxyImageTagEnv, constants.DefaultAgentProxyImageTag)),
+	}
+}
+
+// getImageStatus reports the images used by each component
+func (r *Reconciler) getImageStatus(cr *model.CryostatInstance, imageTags *resources.ImageTags) *operatorv1beta2.ImageStatus {
+	authProxyImage := imageTags.OAuth2ProxyImageTag
+	if r.IsOpenShift {
+		authProxyImage = imageTags.OpenShiftOAuthProxyImageTag
+	}
+	var agentInitConfig *operatorv1beta2.ImageConfig
+	if cr.Spec.Images != nil {
+		agentInitConfig = cr.Spec.Images.AgentInit
+	}
+	return &operatorv1beta2.ImageStatus{
+		Core:       imageTags.CoreImageTag,
+		DataSource: imageTags.DatasourceImageTag,
+		Grafana:    imageTags.GrafanaImageTag,
+		AuthProxy:  authProxyImage,
+		AgentProxy: imageTags.AgentProxyImageTag,
+		Reports:    imageTags.ReportsImageTag,
+		Database:   imageTags.DatabaseImageTag,
+		Storage:    imageTags.StorageImageTag,
+		AgentInit: common.ResolveImage(agentInitConfig,
+			r.GetEnvOrDefault(agentInitImageTagEnv, constants.DefaultAgentInitImageTag)),
 	}
 }
 
@@ -591,11 +923,13 @@ func (r *Reconciler) updateCondition(ctx context.Context, cr *model.CryostatInst
 	condType operatorv1beta2.CryostatConditionType, status metav1.ConditionStatus, reason string, message string) error { // nolint:unparam
 	reqLogger := r.Log.WithValues("Request.Namespace", cr.InstallNamespace, "Request.Name", cr.Name)
 	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
-		Type:    string(condType),
-		Status:  status,
-		Reason:  reason,
-		Message: message,
+		Type:               string(condType),
+		Status:             status,
+		ObservedGeneration: cr.Object.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
 	})
+	setAggregateConditions(cr)
 	err := r.Status().Update(ctx, cr.Object)
 	if err != nil {
 		reqLogger.Error(err, "failed to update condition", "type", condType)
@@ -614,20 +948,8 @@ func (r *Reconciler) updateConditionsFromDeployment(ctx context.Context, cr *mod
 		return err
 	}
 
-	// Associate deployment conditions with Cryostat conditions
-	for condType, deployCondType := range mapping {
-		condition := findDeployCondition(deploy.Status.Conditions, deployCondType)
-		if condition == nil {
-			removeConditionIfPresent(cr, condType)
-		} else {
-			meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
-				Type:    string(condType),
-				Status:  metav1.ConditionStatus(condition.Status),
-				Reason:  condition.Reason,
-				Message: condition.Message,
-			})
-		}
-	}
+	setConditionsFromDeployment(cr, deploy, mapping)
+	setAggregateConditions(cr)
 	err = r.Status().Update(ctx, cr.Object)
 	if err != nil {
 		reqLogger.Error(err, "failed to update conditions for deployment", "deployment", deploy.Name)
@@ -635,7 +957,33 @@ func (r *Reconciler) updateConditionsFromDeployment(ctx context.Context, cr *mod
 	return err
 }
 
-var errSelectorModified error = errors.New("deployment selector has been modified")
+// setConditionsFromDeployment associates deployment conditions with Cryostat conditions
+func setConditionsFromDeployment(cr *model.CryostatInstance, deploy *appsv1.Deployment, mapping deploymentConditionTypeMap) {
+	for condType, deployCondType := range mapping {
+		condition := findDeployCondition(deploy.Status.Conditions, deployCondType)
+		if condition == nil {
+			removeConditionIfPresent(cr, condType)
+			continue
+		}
+		reason := condition.Reason
+		message := condition.Message
+		if deployCondType == appsv1.DeploymentProgressing && reason == reasonNewReplicaSetAvailable &&
+			!isDeploymentRolledOut(deploy) {
+			// The condition still describes the previous roll out, until the deployment
+			// controller has observed the latest changes and replaced all pods
+			reason = reasonRolloutInProgress
+			message = fmt.Sprintf("Deployment %s is rolling out: %d of %d replicas are updated and available.",
+				deploy.Name, min(deploy.Status.UpdatedReplicas, deploy.Status.AvailableReplicas), desiredReplicas(deploy))
+		}
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:               string(condType),
+			Status:             metav1.ConditionStatus(condition.Status),
+			ObservedGeneration: cr.Object.GetGeneration(),
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+}
 
 func (r *Reconciler) createOrUpdateDeployment(ctx context.Context, deploy *appsv1.Deployment, owner metav1.Object) error {
 	// Annotate the deployment with hashes for any referenced secrets/config maps
@@ -645,39 +993,14 @@ func (r *Reconciler) createOrUpdateDeployment(ctx context.Context, deploy *appsv
 	}
 	// Make a copy of the new desired deployment
 	deployCopy := deploy.DeepCopy()
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, deploy, func() error {
-		// Merge any required labels and annotations
-		common.MergeLabelsAndAnnotations(&deploy.ObjectMeta, deployCopy.Labels, deployCopy.Annotations)
-		// Set the Cryostat CR as controller
-		if err := controllerutil.SetControllerReference(owner, deploy, r.Scheme); err != nil {
-			return err
-		}
-		// Immutable, only updated when the deployment is created
-		if deploy.CreationTimestamp.IsZero() {
-			deploy.Spec.Selector = deployCopy.Spec.Selector
-		} else if !cmp.Equal(deploy.Spec.Selector, deployCopy.Spec.Selector) {
-			// Return error so deployment can be recreated
-			return errSelectorModified
-		}
-		// Set the replica count and update strategy
-		deploy.Spec.Replicas = deployCopy.Spec.Replicas
-		deploy.Spec.Strategy = deployCopy.Spec.Strategy
-
-		// Update pod template spec to propagate any changes from Cryostat CR
-		deploy.Spec.Template.Spec = deployCopy.Spec.Template.Spec
-
-		// Update pod template metadata
-		common.MergeLabelsAndAnnotations(&deploy.Spec.Template.ObjectMeta, deployCopy.Spec.Template.Labels,
-			deployCopy.Spec.Template.Annotations)
-		return nil
-	})
+	err = r.applyObject(ctx, deploy, owner)
 	if err != nil {
-		if err == errSelectorModified {
+		// The selector is immutable, so the deployment must be recreated if it changed
+		if isImmutableFieldChanged(err, "spec.selector") {
 			return r.recreateDeployment(ctx, deployCopy, owner)
 		}
 		return err
 	}
-	r.Log.Info(fmt.Sprintf("Deployment %s", op), "name", deploy.Name, "namespace", deploy.Namespace)
 	return nil
 }
 
@@ -719,11 +1042,11 @@ func (r *Reconciler) watchTargetNamespaces(c common.ControllerBuilder,
 func (r *Reconciler) targetNamespacePredicate() (predicate.Predicate, error) {
 	// Use a label selector that matches the existence of the
 	// target namespace labels
+	return labelsExistPredicate(constants.TargetNamespaceCRNameLabel, constants.TargetNamespaceCRNamespaceLabel)
+}
+
+func labelsExistPredicate(labels ...string) (predicate.Predicate, error) {
 	selector := metav1.LabelSelector{}
-	labels := []string{
-		constants.TargetNamespaceCRNameLabel,
-		constants.TargetNamespaceCRNamespaceLabel,
-	}
 	for _, label := range labels {
 		selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
 			Key:      label,
@@ -758,11 +1081,46 @@ func (r *Reconciler) mapFromTargetNamespace() func(ctx context.Context, obj clie
 	}
 }
 
-func requeueIfIngressNotReady(err error) (reconcile.Result, error) {
-	if err == ErrIngressNotReady {
-		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+func (r *Reconciler) watchAgentPods(c common.ControllerBuilder) (common.ControllerBuilder, error) {
+	// Only watch the metadata of pods with the labels requesting agent injection.
+	// Agents are injected when pods are created, so updates are ignored.
+	pred, err := labelsExistPredicate(constants.AgentLabelCryostatName, constants.AgentLabelCryostatNamespace)
+	if err != nil {
+		return nil, err
+	}
+	pod := &metav1.PartialObjectMetadata{}
+	pod.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+	return c.Watches(pod, c.EnqueueRequestsFromMapFunc(r.mapFromAgentPod()),
+		c.WithPredicates(predicate.And(pred, predicate.Funcs{
+			UpdateFunc: func(event.UpdateEvent) bool { return false },
+		}))), nil
+}
+
+func (r *Reconciler) mapFromAgentPod() func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		labels := obj.GetLabels()
+		cr := &operatorv1beta2.Cryostat{}
+		err := r.Get(ctx, types.NamespacedName{
+			Name:      labels[constants.AgentLabelCryostatName],
+			Namespace: labels[constants.AgentLabelCryostatNamespace],
+		}, cr)
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				r.Log.Error(err, "Failed to get Cryostat for agent pod", "name", obj.GetName(),
+					"namespace", obj.GetNamespace())
+			}
+			return nil
+		}
+		// Otherwise, agents may only be injected into pods in the CR's target namespaces
+		if !cr.Spec.AllNamespaces {
+			return nil
+		}
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name},
+			},
+		}
 	}
-	return reconcile.Result{}, err
 }
 
 func removeConditionIfPresent(cr *model.CryostatInstance, condType ...operatorv1beta2.CryostatConditionType) {
@@ -782,3 +1140,14 @@ func findDeployCondition(conditions []appsv1.DeploymentCondition, condType appsv
 	}
 	return nil
 }
+
+// earliestRequeue returns the shortest of the positive durations, or zero if there are none
+func earliestRequeue(durations ...time.Duration) time.Duration {
+	var earliest time.Duration
+	for _, d := range durations {
+		if d > 0 && (earliest == 0 || d < earliest) {
+			earliest = d
+		}
+	}
+	return earliest
+}
@@ -15,13 +15,23 @@
 package controller_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	certMeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	gomegatypes "github.com/onsi/gomega/types"
@@ -29,10 +39,12 @@ import (
 	consolev1 "github.com/openshift/api/console/v1"
 	openshiftv1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	netv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -46,9 +58,11 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -57,6 +71,7 @@ import (
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
 	"github.com/cryostatio/cryostat-operator/internal/test"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 type controllerTest struct {
@@ -66,6 +81,7 @@ type controllerTest struct {
 type cryostatTestInput struct {
 	reconciler controller.CommonReconciler
 	objs       []ctrlclient.Object
+	legacyObjs []ctrlclient.Object
 	test.TestReconcilerConfig
 	*test.TestResources
 }
@@ -100,8 +116,15 @@ func (c *controllerTest) commonJustBeforeEach(t *cryostatTestInput) {
 	// TODO When using envtest instead of fake client, this is probably no longer needed
 	err := test.SetCreationTimestampAndUUID(t.objs...)
 	Expect(err).ToNot(HaveOccurred())
-	t.Client = fake.NewClientBuilder().WithScheme(s).WithObjects(t.objs...).
-		WithStatusSubresource(&operatorv1beta2.Cryostat{}, &certv1.Certificate{}, &openshiftv1.Route{}).Build()
+	// The API server sets the generation of newly created custom resources
+	for _, obj := range t.objs {
+		if cr, ok := obj.(*operatorv1beta2.Cryostat); ok && cr.Generation == 0 {
+			cr.Generation = 1
+		}
+	}
+	t.Client = test.NewClientWithServerSideApply(test.NewClientWithTimestamp(fake.NewClientBuilder().WithScheme(s).WithObjects(t.objs...).
+		WithStatusSubresource(&operatorv1beta2.Cryostat{}, &certv1.Certificate{}, &openshiftv1.Route{}, &batchv1.Job{}).Build()))
+	test.CreateLegacyObjects(context.Background(), t.Client, t.legacyObjs...)
 	t.reconciler, err = c.constructorFunc(t.newReconcilerConfig(s, t.Client))
 	Expect(err).ToNot(HaveOccurred())
 }
@@ -125,7 +148,7 @@ func (t *cryostatTestInput) newReconcilerConfig(scheme *runtime.Scheme, client c
 		insightsURL = parsed
 	}
 	return &controller.ReconcilerConfig{
-		Client:                 test.NewClientWithTimestamp(test.NewTestClient(client, t.TestResources)),
+		Client:                 test.NewTestClient(client, t.TestResources),
 		Scheme:                 scheme,
 		IsOpenShift:            t.OpenShift,
 		EventRecorder:          record.NewFakeRecorder(1024),
@@ -136,6 +159,7 @@ func (t *cryostatTestInput) newReconcilerConfig(scheme *runtime.Scheme, client c
 		IsCertManagerInstalled: !t.CertManagerMissing,
 		NewControllerBuilder:   test.NewControllerBuilder(&t.TestReconcilerConfig),
 		OSUtils:                test.NewTestOSUtils(&t.TestReconcilerConfig),
+		Dialer:                 test.NewTestDialer(&t.TestReconcilerConfig),
 	}
 }
 
@@ -197,6 +221,15 @@ func expectSuccessful(t **cryostatTestInput) {
 		(*t).checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
 			"AllCertificatesReady")
 	})
+	It("should set observedGeneration in CR Status and conditions", func() {
+		(*t).expectObservedGeneration()
+	})
+	It("should set Ready condition to false", func() {
+		(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse,
+			"ComponentsNotReady")
+		(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeReconciling)
+		(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeStalled)
+	})
 	Context("deployment is progressing", func() {
 		JustBeforeEach(func() {
 			(*t).makeDeploymentProgress((*t).Name)
@@ -208,6 +241,13 @@ func expectSuccessful(t **cryostatTestInput) {
 				"TestProgressing")
 			(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeMainDeploymentReplicaFailure)
 		})
+		It("should set aggregate conditions", func() {
+			(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse,
+				"ComponentsNotReady")
+			(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReconciling, metav1.ConditionTrue,
+				"ComponentsUpdating")
+			(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeStalled)
+		})
 		Context("then becomes available", func() {
 			JustBeforeEach(func() {
 				(*t).makeDeploymentAvailable((*t).Name)
@@ -219,6 +259,58 @@ func expectSuccessful(t **cryostatTestInput) {
 					"TestProgressing")
 				(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeMainDeploymentReplicaFailure)
 			})
+			It("should set aggregate conditions", func() {
+				(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse,
+					"ComponentsUpdating")
+				(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReconciling, metav1.ConditionTrue,
+					"ComponentsUpdating")
+				(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeStalled)
+			})
+		})
+		Context("then completes its roll out", func() {
+			JustBeforeEach(func() {
+				(*t).makeDeploymentComplete((*t).Name)
+			})
+			It("should set aggregate conditions", func() {
+				(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionTrue,
+					"AllComponentsReady")
+				(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeReconciling)
+				(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeStalled)
+			})
+			Context("then the spec is changed", func() {
+				JustBeforeEach(func() {
+					// Change the pod template of the main deployment
+					cr := (*t).getCryostatInstance()
+					cr.Spec.OperandMetadata = &operatorv1beta2.OperandMetadata{
+						PodMetadata: &operatorv1beta2.ResourceMetadata{
+							Annotations: map[string]string{"changed": "spec"},
+						},
+					}
+					(*t).updateCryostatInstance(cr)
+					(*t).reconcileCryostatFully()
+				})
+				It("should set observedGeneration in CR Status and conditions", func() {
+					(*t).expectObservedGeneration()
+				})
+				It("should wait for the deployment to roll out", func() {
+					(*t).checkConditionPresent(operatorv1beta2.ConditionTypeMainDeploymentProgressing, metav1.ConditionTrue,
+						"RolloutInProgress")
+					(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse,
+						"ComponentsUpdating")
+					(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReconciling, metav1.ConditionTrue,
+						"ComponentsUpdating")
+				})
+				Context("then completes its roll out", func() {
+					JustBeforeEach(func() {
+						(*t).makeDeploymentComplete((*t).Name)
+					})
+					It("should set aggregate conditions", func() {
+						(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionTrue,
+							"AllComponentsReady")
+						(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeReconciling)
+					})
+				})
+			})
 		})
 		Context("then fails to roll out", func() {
 			JustBeforeEach(func() {
@@ -232,6 +324,13 @@ func expectSuccessful(t **cryostatTestInput) {
 				(*t).checkConditionPresent(operatorv1beta2.ConditionTypeMainDeploymentReplicaFailure, metav1.ConditionTrue,
 					"TestReplicaFailure")
 			})
+			It("should set aggregate conditions", func() {
+				(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse,
+					"ComponentFailed")
+				(*t).checkConditionPresent(operatorv1beta2.ConditionTypeStalled, metav1.ConditionTrue,
+					"ComponentFailed")
+				(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeReconciling)
+			})
 		})
 	})
 }
@@ -325,7 +424,8 @@ func (c *controllerTest) commonTests() {
 			BeforeEach(func() {
 				cr = t.NewCryostat()
 				oldDeploy = t.OtherDeployment()
-				t.objs = append(t.objs, cr.Object, oldDeploy)
+				t.objs = append(t.objs, cr.Object)
+				t.legacyObjs = append(t.legacyObjs, oldDeploy)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
@@ -359,6 +459,7 @@ func (c *controllerTest) commonTests() {
 				BeforeEach(func() {
 					selector := metav1.AddLabelToSelector(&metav1.LabelSelector{}, "other", "label")
 					oldDeploy.Spec.Selector = selector
+					oldDeploy.Spec.Template.Labels = selector.MatchLabels
 				})
 				It("should delete and recreate the deployment", func() {
 					t.expectMainDeployment()
@@ -366,6 +467,91 @@ func (c *controllerTest) commonTests() {
 					t.expectStorageDeployment()
 				})
 			})
+			Context("with fields owned by another field manager", func() {
+				JustBeforeEach(func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					deploy.Spec.Template.Spec.Containers[0].Image = "other/image:latest"
+					err = t.Client.Update(context.Background(), deploy, ctrlclient.FieldOwner("other-manager"))
+					Expect(err).ToNot(HaveOccurred())
+
+					t.reconcileCryostatFully()
+				})
+				It("should emit an ApplyConflict event", func() {
+					recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+					Eventually(recorder.Events).Should(Receive(SatisfyAll(
+						ContainSubstring("Warning ApplyConflict Deployment"),
+						ContainSubstring(`.spec.template.spec.containers[name="cryostat"].image changed by "other-manager"`),
+					)))
+				})
+				It("should take back ownership of the changed fields", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deploy.ManagedFields).ToNot(ContainElement(HaveField("Manager", "other-manager")))
+				})
+				It("should revert the changed fields", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					t.checkMainPodTemplate(deploy, cr)
+				})
+			})
+			Context("when scaled by another field manager", func() {
+				JustBeforeEach(func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					deploy.Spec.Replicas = &[]int32{3}[0]
+					err = t.Client.Update(context.Background(), deploy, ctrlclient.FieldOwner("other-manager"))
+					Expect(err).ToNot(HaveOccurred())
+
+					t.reconcileCryostatFully()
+				})
+				It("should not change the replicas", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deploy.Spec.Replicas).To(Equal(&[]int32{3}[0]))
+				})
+				Context("when the operator stops Cryostat", func() {
+					JustBeforeEach(func() {
+						cr := t.getCryostatInstance()
+						cr.Object.SetAnnotations(map[string]string{
+							"operator.cryostat.io/restore-in-progress": "restore",
+						})
+						t.updateCryostatInstance(cr)
+						t.reconcileCryostatFully()
+					})
+					It("should scale down the deployment", func() {
+						deploy := &appsv1.Deployment{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(deploy.Spec.Replicas).To(Equal(&[]int32{0}[0]))
+					})
+				})
+			})
+			Context("with labels added by another field manager", func() {
+				JustBeforeEach(func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					metav1.SetMetaDataLabel(&deploy.ObjectMeta, "added", "by-user")
+					metav1.SetMetaDataLabel(&deploy.Spec.Template.ObjectMeta, "added", "by-user")
+					err = t.Client.Update(context.Background(), deploy, ctrlclient.FieldOwner("other-manager"))
+					Expect(err).ToNot(HaveOccurred())
+
+					t.reconcileCryostatFully()
+				})
+				It("should keep the labels", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deploy.Labels).To(HaveKeyWithValue("added", "by-user"))
+					Expect(deploy.Spec.Template.Labels).To(HaveKeyWithValue("added", "by-user"))
+				})
+			})
 		})
 		Context("with an existing Service Account", func() {
 			var cr *model.CryostatInstance
@@ -373,9 +559,11 @@ func (c *controllerTest) commonTests() {
 			BeforeEach(func() {
 				cr = t.NewCryostat()
 				oldSA = t.OtherServiceAccount()
-				t.objs = append(t.objs, cr.Object, oldSA)
+				t.objs = append(t.objs, cr.Object)
+				t.legacyObjs = append(t.legacyObjs, withoutServiceAccountSecrets(oldSA))
 			})
 			JustBeforeEach(func() {
+				t.addServiceAccountSecrets(oldSA)
 				t.reconcileCryostatFully()
 			})
 			It("should update the Service Account", func() {
@@ -384,7 +572,7 @@ func (c *controllerTest) commonTests() {
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(sa.Annotations).To(Equal(map[string]string{
-					"hello": "world",
+					"hello": "annotation",
 					"serviceaccounts.openshift.io/oauth-redirectreference.route": fmt.Sprintf(`{"metadata":{"creationTimestamp":null},"reference":{"group":"","kind":"Route","name":"%s"}}`, t.Name),
 				}))
 
@@ -444,7 +632,8 @@ func (c *controllerTest) commonTests() {
 			BeforeEach(func() {
 				cr = t.NewCryostat()
 				oldBinding = t.OtherRoleBinding(t.Namespace)
-				t.objs = append(t.objs, cr.Object, oldBinding)
+				t.objs = append(t.objs, cr.Object)
+				t.legacyObjs = append(t.legacyObjs, oldBinding)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
@@ -479,7 +668,8 @@ func (c *controllerTest) commonTests() {
 			BeforeEach(func() {
 				cr = t.NewCryostat()
 				oldBinding = t.OtherClusterRoleBinding()
-				t.objs = append(t.objs, cr.Object, oldBinding)
+				t.objs = append(t.objs, cr.Object)
+				t.legacyObjs = append(t.legacyObjs, oldBinding)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
@@ -492,8 +682,9 @@ func (c *controllerTest) commonTests() {
 				}, binding)
 				Expect(err).ToNot(HaveOccurred())
 
-				// Labels and annotations are unaffected
-				Expect(binding.Labels).To(Equal(oldBinding.Labels))
+				// Labels are merged with existing ones
+				metav1.SetMetaDataLabel(&expected.ObjectMeta, "test", "label")
+				Expect(binding.Labels).To(Equal(expected.Labels))
 				Expect(binding.Annotations).To(Equal(oldBinding.Annotations))
 
 				// Subjects and RoleRef should be fully replaced
@@ -509,6 +700,53 @@ func (c *controllerTest) commonTests() {
 				})
 			})
 		})
+		Context("when a Network Policy is modified", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				expected := t.NewCryostatIngressNetworkPolicy()
+				policy := &netv1.NetworkPolicy{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, policy)
+				Expect(err).ToNot(HaveOccurred())
+				// Allow all ingress to every pod in the namespace
+				policy.Spec.PodSelector = metav1.LabelSelector{}
+				policy.Spec.Ingress = []netv1.NetworkPolicyIngressRule{{}}
+				err = t.Client.Update(context.Background(), policy, ctrlclient.FieldOwner("kubectl-edit"))
+				Expect(err).ToNot(HaveOccurred())
+				t.reconcileCryostatFully()
+			})
+			It("should restore the Network Policy", func() {
+				t.checkNetworkPolicy(t.NewCryostatIngressNetworkPolicy())
+			})
+		})
+		Context("when the Cluster Role Binding is modified", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				binding := &rbacv1.ClusterRoleBinding{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewClusterRoleBinding().Name}, binding)
+				Expect(err).ToNot(HaveOccurred())
+				binding.Labels = nil
+				binding.Subjects = t.OtherClusterRoleBinding().Subjects
+				err = t.Client.Update(context.Background(), binding)
+				Expect(err).ToNot(HaveOccurred())
+				t.reconcileCryostatFully()
+			})
+			It("should restore the Cluster Role Binding", func() {
+				expected := t.NewClusterRoleBinding()
+				binding := &rbacv1.ClusterRoleBinding{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name}, binding)
+				Expect(err).ToNot(HaveOccurred())
+				// The labels identify the Cryostat CR when the binding is watched
+				Expect(binding.Labels).To(Equal(expected.Labels))
+				Expect(binding.Subjects).To(Equal(expected.Subjects))
+				Expect(binding.RoleRef).To(Equal(expected.RoleRef))
+			})
+		})
 		Context("with an existing Database Secret", func() {
 			var cr *model.CryostatInstance
 			var oldSecret *corev1.Secret
@@ -561,7 +799,8 @@ func (c *controllerTest) commonTests() {
 			BeforeEach(func() {
 				cr = t.NewCryostat()
 				oldCoreRoute = t.OtherCoreRoute()
-				t.objs = append(t.objs, cr.Object, oldCoreRoute)
+				t.objs = append(t.objs, cr.Object)
+				t.legacyObjs = append(t.legacyObjs, oldCoreRoute)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
@@ -598,7 +837,7 @@ func (c *controllerTest) commonTests() {
 			})
 			It("should not create cryostat networkpolicy", func() {
 				t.expectNoNetworkPolicy(t.NewCryostatIngressNetworkPolicy().Name)
-				t.expectNoNetworkPolicy(t.NewCryostatEgressNetworkPolicy().Name)
+				t.expectNoNetworkPolicy(t.NewCryostatEgressNetworkPolicy("").Name)
 			})
 			It("should not create database networkpolicy", func() {
 				t.expectNoNetworkPolicy(t.NewDatabaseIngressNetworkPolicy().Name)
@@ -678,7 +917,7 @@ func (c *controllerTest) commonTests() {
 				t.reconcileCryostatFully()
 			})
 			It("should create cryostat networkpolicy", func() {
-				t.checkNetworkPolicy(t.NewCryostatEgressNetworkPolicy())
+				t.checkNetworkPolicy(t.NewCryostatEgressNetworkPolicy("127.0.0.1"))
 			})
 		})
 		Context("with report generator service", func() {
@@ -880,8 +1119,8 @@ func (c *controllerTest) commonTests() {
 			var cr *model.CryostatInstance
 			BeforeEach(func() {
 				cr = t.NewCryostatWithSecrets()
-				t.objs = append(t.objs, cr.Object, t.NewTestCertSecret("testCert1"),
-					t.NewTestCertSecret("testCert2"))
+				t.objs = append(t.objs, cr.Object, t.NewTestCertSecret("test-cert1"),
+					t.NewTestCertSecret("test-cert2"))
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
@@ -902,8 +1141,8 @@ func (c *controllerTest) commonTests() {
 		})
 		Context("Adding a certificate to the TrustedCertSecrets list", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostat().Object, t.NewTestCertSecret("testCert1"),
-					t.NewTestCertSecret("testCert2"))
+				t.objs = append(t.objs, t.NewCryostat().Object, t.NewTestCertSecret("test-cert1"),
+					t.NewTestCertSecret("test-cert2"))
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
@@ -935,8 +1174,8 @@ func (c *controllerTest) commonTests() {
 			var cr *model.CryostatInstance
 			BeforeEach(func() {
 				cr = t.NewCryostatWithTrustedCertConfigMaps()
-				t.objs = append(t.objs, cr.Object, t.NewTestCertConfigMap("testCertCM1"),
-					t.NewTestCertConfigMap("testCertCM2"))
+				t.objs = append(t.objs, cr.Object, t.NewTestCertConfigMap("test-cert-cm1"),
+					t.NewTestCertConfigMap("test-cert-cm2"))
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
@@ -957,8 +1196,8 @@ func (c *controllerTest) commonTests() {
 		})
 		Context("Adding a config map certificate to the TrustedCertSecrets list", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostat().Object, t.NewTestCertConfigMap("testCertCM1"),
-					t.NewTestCertConfigMap("testCertCM2"))
+				t.objs = append(t.objs, t.NewCryostat().Object, t.NewTestCertConfigMap("test-cert-cm1"),
+					t.NewTestCertConfigMap("test-cert-cm2"))
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
@@ -1144,266 +1383,994 @@ func (c *controllerTest) commonTests() {
 				})
 			})
 		})
-		Context("with S3 storage bucket names configuration", func() {
+		Context("with an external database", func() {
 			BeforeEach(func() {
-				secretName := "external-s3-creds"
-				t.StorageSecret = t.NewExternalStorageSecret(secretName)
-				t.objs = append(t.objs, t.NewCryostatWithCustomizedStorageBucketNames().Object, t.StorageSecret)
+				t.objs = append(t.objs, t.NewCryostatWithExternalDatabase().Object, t.NewExternalDatabaseSecret(),
+					t.NewExternalDatabaseCASecret())
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
 			})
-			It("Should update the core deployment", func() {
+			It("should not deploy the managed database", func() {
+				t.expectNoManagedDatabase()
+			})
+			It("should configure the core container", func() {
 				t.checkCoreHasEnvironmentVariables([]corev1.EnvVar{
 					{
-						Name:  "STORAGE_BUCKETS_ARCHIVES_NAME",
-						Value: "a",
+						Name: "QUARKUS_DATASOURCE_PASSWORD",
+						ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: "external-db-creds",
+								},
+								Key: "password",
+							},
+						},
 					},
 					{
-						Name:  "CRYOSTAT_SERVICES_REPORTS_STORAGE_CACHE_NAME",
-						Value: "b",
+						Name:  "QUARKUS_DATASOURCE_JDBC_URL",
+						Value: "jdbc:postgresql://postgres.example.com:6543/cryostat-db?sslmode=verify-full&sslrootcert=/var/run/secrets/operator.cryostat.io/external-database-ca/ca.crt",
 					},
 					{
-						Name:  "STORAGE_BUCKETS_EVENT_TEMPLATES_NAME",
-						Value: "c",
+						Name:  "QUARKUS_DATASOURCE_USERNAME",
+						Value: "cryostat-user",
 					},
-					{
-						Name:  "STORAGE_BUCKETS_PROBE_TEMPLATES_NAME",
-						Value: "d",
+				})
+			})
+			It("should mount the database CA certificate", func() {
+				deployment := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deployment)
+				Expect(err).ToNot(HaveOccurred())
+				readOnlyMode := int32(0440)
+				Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "external-database-ca",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "external-db-ca",
+							Items: []corev1.KeyToPath{
+								{
+									Key:  corev1.TLSCertKey,
+									Path: "ca.crt",
+									Mode: &readOnlyMode,
+								},
+							},
+						},
 					},
+				}))
+				Expect(deployment.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+					Name:      "external-database-ca",
+					MountPath: "/var/run/secrets/operator.cryostat.io/external-database-ca",
+					ReadOnly:  true,
+				}))
+			})
+			It("should set DatabaseReachable condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseReachable, metav1.ConditionTrue,
+					"DatabaseConnected")
+			})
+		})
+		Context("with an unreachable external database", func() {
+			BeforeEach(func() {
+				t.DatabaseUnreachable = true
+				t.objs = append(t.objs, t.NewCryostatWithExternalDatabaseDefaults().Object)
+			})
+			JustBeforeEach(func() {
+				// Never fully reconciled, since the database is polled until reachable
+				Eventually(func() *metav1.Condition {
+					result, err := t.reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Requeue || result.RequeueAfter > 0).To(BeTrue())
+					return meta.FindStatusCondition(t.getCryostatInstance().Status.Conditions,
+						string(operatorv1beta2.ConditionTypeDatabaseReachable))
+				}).WithTimeout(time.Minute).WithPolling(time.Millisecond).ShouldNot(BeNil())
+			})
+			It("should set DatabaseReachable condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseReachable, metav1.ConditionFalse,
+					"DatabaseUnreachable")
+			})
+			It("should set Ready condition to false", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse,
+					"ComponentsNotReady")
+			})
+		})
+		Context("with an external database using defaults", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithExternalDatabaseDefaults().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should configure the core container", func() {
+				secretOptional := false
+				t.checkCoreHasEnvironmentVariables([]corev1.EnvVar{
 					{
-						Name:  "STORAGE_BUCKETS_HEAP_DUMPS_NAME",
-						Value: "e",
+						Name: "QUARKUS_DATASOURCE_PASSWORD",
+						ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: t.Name + "-db",
+								},
+								Key:      "CONNECTION_KEY",
+								Optional: &secretOptional,
+							},
+						},
 					},
 					{
-						Name:  "STORAGE_BUCKETS_THREAD_DUMPS_NAME",
-						Value: "f",
+						Name:  "QUARKUS_DATASOURCE_JDBC_URL",
+						Value: "jdbc:postgresql://postgres.example.com:5432/cryostat",
 					},
 					{
-						Name:  "STORAGE_BUCKETS_METADATA_NAME",
-						Value: "z",
+						Name:  "QUARKUS_DATASOURCE_USERNAME",
+						Value: "cryostat",
 					},
 				})
 			})
 		})
-		Context("with custom PVC spec overriding all defaults", func() {
+		Context("switching from the managed database to an external database", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostatWithPVCSpec().Object)
+				t.objs = append(t.objs, t.NewCryostat().Object)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
+				t.makeDeploymentAvailable(t.Name + "-database")
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseDeploymentAvailable, metav1.ConditionTrue,
+					"TestAvailable")
+
+				cr := t.getCryostatInstance()
+				cr.Spec.DatabaseOptions = t.NewCryostatWithExternalDatabaseDefaults().Spec.DatabaseOptions
+				t.updateCryostatInstance(cr)
+				t.reconcileCryostatFully()
 			})
-			It("should create the PVC with requested spec", func() {
-				t.expectPVC(t.NewCustomDatabasePVC())
-				t.expectPVC(t.NewCustomStoragePVC())
-			})
-		})
-		Context("with custom PVC spec overriding some defaults", func() {
-			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostatWithPVCSpecSomeDefault().Object)
+			It("should remove the managed database", func() {
+				t.expectNoManagedDatabase()
 			})
-			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
+			It("should keep the database PVC", func() {
+				t.expectPVC(t.NewDatabasePVC())
 			})
-			It("should create the PVC with requested spec", func() {
-				t.expectPVC(t.NewCustomDatabasePVCSomeDefault())
-				t.expectPVC(t.NewCustomStoragePVCSomeDefault())
+			It("should remove the database deployment conditions", func() {
+				t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseDeploymentAvailable)
+				t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseDeploymentProgressing)
+				t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseDeploymentReplicaFailure)
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseReachable, metav1.ConditionTrue,
+					"DatabaseConnected")
 			})
 		})
-		Context("with custom PVC config with no spec", func() {
+		Context("with database backups", func() {
+			var cr *model.CryostatInstance
+
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostatWithPVCLabelsOnly().Object)
+				cr = t.NewCryostatWithDatabaseBackup()
+				t.objs = append(t.objs, cr.Object)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
 			})
-			It("should create the PVC with requested label", func() {
-				t.expectPVC(t.NewDefaultDatabasePVCWithLabel())
-				t.expectPVC(t.NewDefaultStoragePVCWithLabel())
-			})
-		})
-		Context("with a legacy PVC spec", func() {
-			Context("with custom PVC spec overriding all defaults", func() {
-				BeforeEach(func() {
-					t.objs = append(t.objs, t.NewCryostatWithPVCSpecLegacy().Object)
-				})
-				JustBeforeEach(func() {
-					t.reconcileCryostatFully()
-				})
-				It("should create the PVC with requested spec", func() {
-					t.expectPVC(t.NewCustomDatabasePVCLegacy())
-					t.expectPVC(t.NewCustomStoragePVCLegacy())
-				})
+			It("should create the backup cron job", func() {
+				t.expectDatabaseBackupCronJob(false)
 			})
-			Context("with custom PVC spec overriding some defaults", func() {
+			Context("with a restore in progress", func() {
 				BeforeEach(func() {
-					t.objs = append(t.objs, t.NewCryostatWithPVCSpecSomeDefaultLegacy().Object)
+					cr.Object.SetAnnotations(map[string]string{
+						"operator.cryostat.io/restore-in-progress": "restore",
+					})
 				})
-				JustBeforeEach(func() {
-					t.reconcileCryostatFully()
+				It("should suspend the backup cron job", func() {
+					t.expectDatabaseBackupCronJob(true)
 				})
-				It("should create the PVC with requested spec", func() {
-					t.expectPVC(t.NewCustomDatabasePVCSomeDefaultLegacy())
-					t.expectPVC(t.NewCustomStoragePVCSomeDefaultLegacy())
+				It("should scale down the core deployment", func() {
+					deployment := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deployment)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(*deployment.Spec.Replicas).To(Equal(int32(0)))
 				})
 			})
-			Context("with custom PVC config with no spec", func() {
-				BeforeEach(func() {
-					t.objs = append(t.objs, t.NewCryostatWithPVCLabelsOnlyLegacy().Object)
-				})
+			Context("when backups are disabled", func() {
 				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.DatabaseOptions.Backup = nil
+					t.updateCryostatInstance(cr)
 					t.reconcileCryostatFully()
 				})
-				It("should create the PVC with requested label", func() {
-					t.expectPVC(t.NewDefaultDatabasePVCWithLabelLegacy())
-					t.expectPVC(t.NewDefaultStoragePVCWithLabelLegacy())
+				It("should delete the backup cron job", func() {
+					t.expectNoDatabaseBackupCronJob()
 				})
 			})
 		})
-		Context("with both custom PVC specs overriding all defaults", func() {
+		Context("with database backups to external S3", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostatWithPVCSpecBoth().Object)
+				secretName := "external-s3-creds"
+				t.StorageSecret = t.NewExternalStorageSecret(secretName)
+				t.objs = append(t.objs, t.NewCryostatWithDatabaseBackupExternalS3(secretName).Object, t.StorageSecret)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
 			})
-			It("should create the PVC with requested spec", func() {
-				t.expectPVC(t.NewCustomDatabasePVC())
-				t.expectPVC(t.NewCustomStoragePVC())
+			It("should upload backups to the external bucket", func() {
+				cronJob := &batchv1.CronJob{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-backup", Namespace: t.Namespace}, cronJob)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cronJob.Spec.Schedule).To(Equal("@daily"))
+
+				env := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env
+				Expect(env).To(ContainElements(
+					corev1.EnvVar{
+						Name:  "S3_BUCKET_URL",
+						Value: "https://example.com:1234/my-backups",
+					},
+					corev1.EnvVar{
+						Name:  "S3_REGION",
+						Value: "region-east-1",
+					},
+					corev1.EnvVar{
+						Name:  "S3_TLS_TRUST_ALL",
+						Value: "true",
+					},
+					corev1.EnvVar{
+						Name:  "BACKUP_RETENTION",
+						Value: "7",
+					},
+				))
+				Expect(env).ToNot(ContainElement(HaveField("Name", "CURL_CA_BUNDLE")))
 			})
 		})
-		Context("with an existing DB PVC", func() {
-			var oldPVC *corev1.PersistentVolumeClaim
+		Context("with database backups and an external database", func() {
 			BeforeEach(func() {
-				oldPVC = t.NewDefaultPVC()
-				t.objs = append(t.objs, t.NewCryostatWithPVCSpec().Object, oldPVC)
+				cr := t.NewCryostatWithExternalDatabaseDefaults()
+				cr.Spec.DatabaseOptions.Backup = t.NewCryostatWithDatabaseBackup().Spec.DatabaseOptions.Backup
+				t.objs = append(t.objs, cr.Object)
 			})
-			Context("that successfully updates", func() {
-				BeforeEach(func() {
-					// Add some labels and annotations to test merging
-					metav1.SetMetaDataLabel(&oldPVC.ObjectMeta, "my", "other-label")
-					metav1.SetMetaDataLabel(&oldPVC.ObjectMeta, "another", "label")
-					metav1.SetMetaDataAnnotation(&oldPVC.ObjectMeta, "my/custom", "other-annotation")
-					metav1.SetMetaDataAnnotation(&oldPVC.ObjectMeta, "another/custom", "annotation")
-				})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should not create the backup cron job", func() {
+				t.expectNoDatabaseBackupCronJob()
+			})
+		})
+		Context("with a managed database", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should record the database version", func() {
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.DatabaseVersion).To(Equal("16"))
+				t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseUpgrading)
+				t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseUpgradeFailed)
+			})
+			Context("when the database image moves to a new major version", func() {
+				var previousImage string
+
 				JustBeforeEach(func() {
-					t.reconcileCryostatFully()
+					previousImage = t.getDatabaseImage()
+					cr := t.getCryostatInstance()
+					cr.Spec.Images = &operatorv1beta2.ImageConfigList{
+						Database: &operatorv1beta2.ImageConfig{
+							Image: "my/database-image:17",
+						},
+					}
+					cr.Spec.DatabaseOptions = &operatorv1beta2.DatabaseOptions{
+						MajorVersion: &[]string{"17"}[0],
+					}
+					t.updateCryostatInstance(cr)
+
+					// Stops the database, then starts the upgrade
+					for i := 0; i < 2; i++ {
+						result, err := t.reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.RequeueAfter).ToNot(BeZero())
+					}
 				})
-				It("should update metadata and resource requests", func() {
-					expected := t.NewDefaultPVC()
-					metav1.SetMetaDataLabel(&expected.ObjectMeta, "my", "database")
-					metav1.SetMetaDataLabel(&expected.ObjectMeta, "another", "label")
-					metav1.SetMetaDataLabel(&expected.ObjectMeta, "app", t.Name)
-					metav1.SetMetaDataAnnotation(&expected.ObjectMeta, "my/custom", "database")
-					metav1.SetMetaDataAnnotation(&expected.ObjectMeta, "another/custom", "annotation")
-					expected.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("5Gi")
-					t.expectPVC(expected)
+				It("should set the DatabaseUpgrading condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseUpgrading, metav1.ConditionTrue,
+						"DatabaseUpgradeStarted")
+					t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseUpgradeFailed)
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse,
+						"ComponentsNotReady")
+				})
+				It("should stop the database and core deployments", func() {
+					t.expectDeploymentReplicas(t.Name+"-database", 0)
+					t.expectDeploymentReplicas(t.Name, 0)
+					Expect(t.getDatabaseImage()).To(Equal(previousImage))
+				})
+				It("should create the upgrade job", func() {
+					t.expectDatabaseUpgradeJob(previousImage, "my/database-image:17")
+				})
+				It("should not update the database version", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.DatabaseVersion).To(Equal("16"))
+				})
+				Context("when the upgrade succeeds", func() {
+					JustBeforeEach(func() {
+						t.setDatabaseUpgradeJobCondition(batchv1.JobComplete)
+						t.reconcileCryostatFully()
+					})
+					It("should update the database version", func() {
+						cr := t.getCryostatInstance()
+						Expect(cr.Status.DatabaseVersion).To(Equal("17"))
+						t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseUpgrading, metav1.ConditionFalse,
+							"DatabaseUpgraded")
+						t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseUpgradeFailed)
+					})
+					It("should start the new database image", func() {
+						t.expectDeploymentReplicas(t.Name+"-database", 1)
+						t.expectDeploymentReplicas(t.Name, 1)
+						Expect(t.getDatabaseImage()).To(Equal("my/database-image:17"))
+					})
+					It("should delete the upgrade job", func() {
+						job := &batchv1.Job{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-upgrade-17", Namespace: t.Namespace}, job)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+					It("should report how to remove the previous data", func() {
+						cr := t.getCryostatInstance()
+						condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeDatabaseUpgrading))
+						Expect(condition).ToNot(BeNil())
+						Expect(condition.Message).To(ContainSubstring("/var/lib/pgsql/data-16"))
+						Expect(condition.Message).To(ContainSubstring("rm -rf /var/lib/pgsql/data-16"))
+						recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+						Eventually(recorder.Events).Should(Receive(And(ContainSubstring("Normal DatabaseUpgraded"),
+							ContainSubstring("rm -rf /var/lib/pgsql/data-16"))))
+					})
+				})
+				Context("when the upgrade fails", func() {
+					JustBeforeEach(func() {
+						t.setDatabaseUpgradeJobCondition(batchv1.JobFailed)
+						t.reconcileCryostatFully()
+					})
+					It("should set the DatabaseUpgradeFailed condition", func() {
+						t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseUpgradeFailed, metav1.ConditionTrue,
+							"DatabaseUpgradeJobFailed")
+						t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseUpgrading, metav1.ConditionFalse,
+							"DatabaseUpgradeJobFailed")
+						t.checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse,
+							"ComponentFailed")
+					})
+					It("should keep the previous database version running", func() {
+						t.expectDeploymentReplicas(t.Name+"-database", 1)
+						t.expectDeploymentReplicas(t.Name, 1)
+						Expect(t.getDatabaseImage()).To(Equal(previousImage))
+						cr := t.getCryostatInstance()
+						Expect(cr.Status.DatabaseVersion).To(Equal("16"))
+					})
+					It("should emit a warning event", func() {
+						recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+						Eventually(recorder.Events).Should(Receive(ContainSubstring("Warning DatabaseUpgradeFailed")))
+					})
+					Context("when the upgrade job is deleted", func() {
+						JustBeforeEach(func() {
+							job := &batchv1.Job{}
+							err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-upgrade-17", Namespace: t.Namespace}, job)
+							Expect(err).ToNot(HaveOccurred())
+							err = t.Client.Delete(context.Background(), job, ctrlclient.PropagationPolicy(metav1.DeletePropagationBackground))
+							Expect(err).ToNot(HaveOccurred())
+							_, err = t.reconcile()
+							Expect(err).ToNot(HaveOccurred())
+						})
+						It("should retry the upgrade", func() {
+							t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseUpgrading, metav1.ConditionTrue,
+								"DatabaseUpgradeStarted")
+							t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseUpgradeFailed)
+							t.expectDeploymentReplicas(t.Name+"-database", 0)
+						})
+					})
 				})
 			})
-			Context("that fails to update", func() {
+			Context("when the database image changes without a recorded version", func() {
+				var previousImage string
+
 				JustBeforeEach(func() {
-					// Replace client with one that fails to update the PVC
-					invalidErr := kerrors.NewInvalid(schema.ParseGroupKind("PersistentVolumeClaim"), oldPVC.Name, field.ErrorList{
-						field.Forbidden(field.NewPath("spec"), "test error"),
+					previousImage = t.getDatabaseImage()
+					cr := t.getCryostatInstance()
+					cr.Status.DatabaseVersion = ""
+					err := t.Client.Status().Update(context.Background(), cr.Object)
+					Expect(err).ToNot(HaveOccurred())
+
+					cr = t.getCryostatInstance()
+					cr.Spec.Images = &operatorv1beta2.ImageConfigList{
+						Database: &operatorv1beta2.ImageConfig{
+							Image: "my/database-image:16.1",
+						},
+					}
+					t.updateCryostatInstance(cr)
+					_, err = t.reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				})
+				It("should check the version of the existing data", func() {
+					t.expectDatabaseVersionJob(previousImage)
+				})
+				It("should keep the database running", func() {
+					t.expectDeploymentReplicas(t.Name+"-database", 1)
+					t.expectDeploymentReplicas(t.Name, 1)
+					Expect(t.getDatabaseImage()).To(Equal(previousImage))
+					t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseUpgrading)
+				})
+				Context("when the existing data uses the same version", func() {
+					JustBeforeEach(func() {
+						t.completeDatabaseVersionJob("16\n")
+						t.reconcileCryostatFully()
 					})
-					origClient := t.reconciler.GetConfig().Client
-					t.reconciler.GetConfig().Client = test.NewClientWithUpdateError(origClient, invalidErr, func(obj ctrlclient.Object) bool {
-						return test.FailOnMatch(obj, oldPVC, t.Client.Scheme())
+					It("should record the database version", func() {
+						cr := t.getCryostatInstance()
+						Expect(cr.Status.DatabaseVersion).To(Equal("16"))
+						t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseUpgrading)
 					})
-
-					// Expect an Invalid status error after reconciling
-					Eventually(func() bool {
-						_, err := t.reconcile()
-						if err != nil {
-							return kerrors.IsInvalid(err)
+					It("should update the database image without stopping it", func() {
+						t.expectDeploymentReplicas(t.Name+"-database", 1)
+						t.expectDeploymentReplicas(t.Name, 1)
+						Expect(t.getDatabaseImage()).To(Equal("my/database-image:16.1"))
+					})
+					It("should delete the version job", func() {
+						t.expectNoJob(t.Name + "-database-version")
+						t.expectNoJob(t.Name + "-database-upgrade-16")
+					})
+				})
+				Context("when the existing data uses a previous version", func() {
+					JustBeforeEach(func() {
+						t.completeDatabaseVersionJob("15\n")
+						// Stops the database, then starts the upgrade
+						for i := 0; i < 2; i++ {
+							result, err := t.reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							Expect(result.RequeueAfter).ToNot(BeZero())
 						}
-						return false
-					}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(BeTrue())
+					})
+					It("should upgrade the database from the version of the data", func() {
+						cr := t.getCryostatInstance()
+						Expect(cr.Status.DatabaseVersion).To(Equal("15"))
+						t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseUpgrading, metav1.ConditionTrue,
+							"DatabaseUpgradeStarted")
+						t.expectDeploymentReplicas(t.Name+"-database", 0)
+						Expect(t.getDatabaseImage()).To(Equal(previousImage))
+						t.expectNoJob(t.Name + "-database-version")
+						job := &batchv1.Job{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-upgrade-16", Namespace: t.Namespace}, job)
+						Expect(err).ToNot(HaveOccurred())
+					})
 				})
-				It("should emit a PersistentVolumeClaimInvalid event", func() {
-					recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
-					var eventMsg string
-					Expect(recorder.Events).To(Receive(&eventMsg))
-					Expect(eventMsg).To(ContainSubstring("PersistentVolumeClaimInvalid"))
+				Context("when the version of the existing data cannot be read", func() {
+					JustBeforeEach(func() {
+						t.setJobCondition(t.Name+"-database-version", batchv1.JobFailed)
+						_, err := t.reconcile()
+						Expect(err).ToNot(HaveOccurred())
+					})
+					It("should upgrade the database from an unknown version", func() {
+						cr := t.getCryostatInstance()
+						Expect(cr.Status.DatabaseVersion).To(BeEmpty())
+						condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeDatabaseUpgrading))
+						Expect(condition).ToNot(BeNil())
+						Expect(condition.Reason).To(Equal("DatabaseUpgradeStarted"))
+						Expect(condition.Message).To(ContainSubstring("PostgreSQL unknown to 16"))
+						t.expectDeploymentReplicas(t.Name+"-database", 0)
+					})
 				})
 			})
-		})
-		Context("with custom EmptyDir config", func() {
-			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostatWithDefaultEmptyDir().Object)
-			})
-			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
-			})
-			It("should create the EmptyDir with default specs", func() {
-				t.expectDatabaseEmptyDir(t.NewDefaultEmptyDir())
-				t.expectStorageEmptyDir(t.NewDefaultEmptyDir())
+			Context("when the database uses an EmptyDir", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.StorageOptions = t.NewCryostatWithDefaultEmptyDir().Spec.StorageOptions
+					cr.Spec.DatabaseOptions = &operatorv1beta2.DatabaseOptions{
+						MajorVersion: &[]string{"17"}[0],
+					}
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatFully()
+				})
+				It("should not upgrade the database", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.DatabaseVersion).To(Equal("17"))
+					t.checkConditionAbsent(operatorv1beta2.ConditionTypeDatabaseUpgrading)
+					t.expectDeploymentReplicas(t.Name+"-database", 1)
+				})
 			})
 		})
-		Context("with custom EmptyDir config with requested spec", func() {
+		Context("with credential rotation", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostatWithEmptyDirSpec().Object)
+				t.GeneratedPasswords = append(t.GeneratedPasswords, "new_auth_cookie_secret", "new_connection_key",
+					"new_object_storage")
+				t.objs = append(t.objs, t.NewCryostatWithCredentialRotation().Object)
 			})
 			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
+				t.reconcileCryostatUntilRequeueScheduled()
 			})
-			It("should create the EmptyDir with requested specs", func() {
-				t.expectDatabaseEmptyDir(t.NewCustomDatabaseEmptyDir())
-				t.expectStorageEmptyDir(t.NewCustomStorageEmptyDir())
+			It("should record the last rotation time", func() {
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.LastRotationTime).ToNot(BeNil())
+				t.expectNoCredentialRotationSecret()
+			})
+			Context("when rotation is due", func() {
+				var lastRotation metav1.Time
+				var coreAnnotations map[string]string
+
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					lastRotation = metav1.NewTime(time.Now().Add(-2200 * time.Hour).Truncate(time.Second))
+					cr.Status.LastRotationTime = &lastRotation
+					err := t.Client.Status().Update(context.Background(), cr.Object)
+					Expect(err).ToNot(HaveOccurred())
+					coreAnnotations = t.getDeploymentTemplateAnnotations(t.Name)
+
+					// Generates the new credentials, then starts changing the database password
+					for i := 0; i < 2; i++ {
+						result, err := t.reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+					}
+				})
+				It("should create the password job", func() {
+					t.expectDatabasePasswordJob(t.Name+"-db", t.Name+"-credential-rotation")
+				})
+				It("should not change the credentials yet", func() {
+					t.expectSecretKey(t.Name+"-db", "CONNECTION_KEY", "connection_key")
+					t.expectSecretKey(t.Name+"-storage", "SECRET_KEY", "object_storage")
+					t.expectSecretKey(t.Name+"-oauth2-cookie", "OAUTH2_PROXY_COOKIE_SECRET", "auth_cookie_secret")
+				})
+				Context("when the password job succeeds", func() {
+					JustBeforeEach(func() {
+						t.setJobCondition(t.Name+"-database-password", batchv1.JobComplete)
+						result, err := t.reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+					})
+					It("should update the secrets", func() {
+						t.expectSecretKey(t.Name+"-db", "CONNECTION_KEY", "new_connection_key")
+						t.expectSecretKey(t.Name+"-db", "ENCRYPTION_KEY", "encryption_key")
+						t.expectSecretKey(t.Name+"-storage", "SECRET_KEY", "new_object_storage")
+						t.expectSecretKey(t.Name+"-storage", "ACCESS_KEY", "cryostat")
+						t.expectSecretKey(t.Name+"-oauth2-cookie", "OAUTH2_PROXY_COOKIE_SECRET", "new_auth_cookie_secret")
+					})
+					It("should delete the password job", func() {
+						job := &batchv1.Job{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-password", Namespace: t.Namespace}, job)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+					It("should not restart Cryostat before object storage", func() {
+						result, err := t.reconcile()
+						Expect(err).ToNot(HaveOccurred())
+						Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+						Expect(t.getDeploymentTemplateAnnotations(t.Name)).To(Equal(coreAnnotations))
+						cr := t.getCryostatInstance()
+						Expect(cr.Status.LastRotationTime).To(Equal(&lastRotation))
+					})
+					Context("when object storage has rolled out", func() {
+						JustBeforeEach(func() {
+							_, err := t.reconcile()
+							Expect(err).ToNot(HaveOccurred())
+							t.setDeploymentRolledOut(t.Name + "-storage")
+							t.reconcileCryostatUntilRequeueScheduled()
+						})
+						It("should restart Cryostat", func() {
+							Expect(t.getDeploymentTemplateAnnotations(t.Name)).ToNot(Equal(coreAnnotations))
+						})
+						It("should complete the rotation", func() {
+							cr := t.getCryostatInstance()
+							Expect(cr.Status.LastRotationTime.After(lastRotation.Time)).To(BeTrue())
+							t.expectNoCredentialRotationSecret()
+						})
+						It("should emit an event", func() {
+							recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+							Eventually(recorder.Events).Should(Receive(ContainSubstring("Normal CredentialsRotated")))
+						})
+					})
+				})
+				Context("when the password job fails", func() {
+					JustBeforeEach(func() {
+						t.setJobCondition(t.Name+"-database-password", batchv1.JobFailed)
+						_, err := t.reconcile()
+						Expect(err).To(HaveOccurred())
+					})
+					It("should set the CredentialsReconcileFailed condition", func() {
+						t.checkConditionPresent(operatorv1beta2.ConditionTypeCredentialsReconcileFailed, metav1.ConditionTrue,
+							"ReconcileFailed")
+					})
+					It("should keep the current credentials", func() {
+						t.expectSecretKey(t.Name+"-db", "CONNECTION_KEY", "connection_key")
+						t.expectSecretKey(t.Name+"-storage", "SECRET_KEY", "object_storage")
+						Expect(t.getDeploymentTemplateAnnotations(t.Name)).To(Equal(coreAnnotations))
+					})
+				})
 			})
 		})
-		Context("with legacy custom EmptyDir config with requested spec", func() {
+		Context("with credential rotation and an external database", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostatWithEmptyDirSpecLegacy().Object)
+				t.GeneratedPasswords = append(t.GeneratedPasswords, "new_auth_cookie_secret", "new_object_storage")
+				cr := t.NewCryostatWithExternalDatabase()
+				cr.Spec.CredentialRotation = t.NewCryostatWithCredentialRotation().Spec.CredentialRotation
+				t.objs = append(t.objs, cr.Object, t.NewExternalDatabaseSecret(), t.NewExternalDatabaseCASecret())
 			})
 			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
+				t.reconcileCryostatUntilRequeueScheduled()
+				cr := t.getCryostatInstance()
+				cr.Status.LastRotationTime = &metav1.Time{Time: time.Now().Add(-2200 * time.Hour)}
+				err := t.Client.Status().Update(context.Background(), cr.Object)
+				Expect(err).ToNot(HaveOccurred())
+				for i := 0; i < 2; i++ {
+					_, err = t.reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				}
 			})
-			It("should create the EmptyDir with requested specs", func() {
-				t.expectDatabaseEmptyDir(t.NewCustomEmptyDirLegacy())
-				t.expectStorageEmptyDir(t.NewCustomEmptyDirLegacy())
+			It("should only rotate the generated credentials it can apply", func() {
+				job := &batchv1.Job{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-password", Namespace: t.Namespace}, job)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				t.expectSecretKey(t.Name+"-db", "CONNECTION_KEY", "connection_key")
+				t.expectSecretKey(t.Name+"-storage", "SECRET_KEY", "new_object_storage")
+				t.expectSecretKey(t.Name+"-oauth2-cookie", "OAUTH2_PROXY_COOKIE_SECRET", "new_auth_cookie_secret")
 			})
 		})
-		Context("with both custom EmptyDir configs with requested spec", func() {
+		Context("when switching to a provided database secret", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostatWithEmptyDirSpecBoth().Object)
+				t.objs = append(t.objs, t.NewCryostat().Object)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
+
+				cr := t.getCryostatInstance()
+				cr.Spec.DatabaseOptions = t.NewCryostatWithDatabaseSecretProvided().Spec.DatabaseOptions
+				t.updateCryostatInstance(cr)
 			})
-			It("should create the EmptyDir with requested specs", func() {
-				t.expectDatabaseEmptyDir(t.NewCustomDatabaseEmptyDir())
-				t.expectStorageEmptyDir(t.NewCustomStorageEmptyDir())
+			Context("with the same encryption key", func() {
+				BeforeEach(func() {
+					secret := t.NewCustomDatabaseSecret()
+					secret.Data["ENCRYPTION_KEY"] = []byte("encryption_key")
+					t.objs = append(t.objs, secret)
+				})
+				JustBeforeEach(func() {
+					result, err := t.reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+				})
+				It("should create the password job", func() {
+					t.expectDatabasePasswordJob(t.Name+"-db", t.NewCustomDatabaseSecret().Name)
+				})
+				It("should keep using the current secret", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.DatabaseSecret).To(Equal(t.Name + "-db"))
+				})
+				Context("when the password job succeeds", func() {
+					JustBeforeEach(func() {
+						t.setJobCondition(t.Name+"-database-password", batchv1.JobComplete)
+						t.reconcileCryostatFully()
+					})
+					It("should use the provided secret", func() {
+						cr := t.getCryostatInstance()
+						Expect(cr.Status.DatabaseSecret).To(Equal(t.NewCustomDatabaseSecret().Name))
+						t.checkConditionAbsent(operatorv1beta2.ConditionTypeSecretsReconcileFailed)
+						t.checkConditionAbsent(operatorv1beta2.ConditionTypeCredentialsReconcileFailed)
+					})
+					It("should emit an event", func() {
+						recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+						Eventually(recorder.Events).Should(Receive(ContainSubstring("Normal DatabaseSecretChanged")))
+					})
+				})
+			})
+			Context("with a different encryption key", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCustomDatabaseSecret())
+				})
+				JustBeforeEach(func() {
+					_, err := t.reconcile()
+					Expect(err).To(HaveOccurred())
+				})
+				It("should set the SecretsReconcileFailed condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeSecretsReconcileFailed, metav1.ConditionTrue,
+						"ReconcileFailed")
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.DatabaseSecret).To(Equal(t.Name + "-db"))
+				})
+				It("should emit a warning event", func() {
+					recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+					Eventually(recorder.Events).Should(Receive(ContainSubstring("Warning DatabaseSecretMismatched")))
+				})
 			})
 		})
-		Context("with overridden image tags", func() {
-			var mainDeploy, databaseDeploy, storageDeploy, reportsDeploy *appsv1.Deployment
+		Context("with S3 storage bucket names configuration", func() {
 			BeforeEach(func() {
-				t.ReportReplicas = 1
-				t.objs = append(t.objs, t.NewCryostatWithReportsSvc().Object)
+				secretName := "external-s3-creds"
+				t.StorageSecret = t.NewExternalStorageSecret(secretName)
+				t.objs = append(t.objs, t.NewCryostatWithCustomizedStorageBucketNames().Object, t.StorageSecret)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
-				mainDeploy = &appsv1.Deployment{}
-				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, mainDeploy)
-				Expect(err).ToNot(HaveOccurred())
-				databaseDeploy = &appsv1.Deployment{}
-				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, databaseDeploy)
-				Expect(err).ToNot(HaveOccurred())
-				storageDeploy = &appsv1.Deployment{}
-				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-storage", Namespace: t.Namespace}, storageDeploy)
-				Expect(err).ToNot(HaveOccurred())
-				reportsDeploy = &appsv1.Deployment{}
-				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-reports", Namespace: t.Namespace}, reportsDeploy)
-				Expect(err).ToNot(HaveOccurred())
 			})
-			Context("for development", func() {
-				BeforeEach(func() {
-					coreImg := "my/core-image:1.0.0-SNAPSHOT"
-					datasourceImg := "my/datasource-image:1.0.0-BETA25"
-					grafanaImg := "my/grafana-image:1.0.0-dev"
-					reportsImg := "my/reports-image:1.0.0-SNAPSHOT"
-					storageImg := "my/storage-image:1.0.0-dev"
+			It("Should update the core deployment", func() {
+				t.checkCoreHasEnvironmentVariables([]corev1.EnvVar{
+					{
+						Name:  "STORAGE_BUCKETS_ARCHIVES_NAME",
+						Value: "a",
+					},
+					{
+						Name:  "CRYOSTAT_SERVICES_REPORTS_STORAGE_CACHE_NAME",
+						Value: "b",
+					},
+					{
+						Name:  "STORAGE_BUCKETS_EVENT_TEMPLATES_NAME",
+						Value: "c",
+					},
+					{
+						Name:  "STORAGE_BUCKETS_PROBE_TEMPLATES_NAME",
+						Value: "d",
+					},
+					{
+						Name:  "STORAGE_BUCKETS_HEAP_DUMPS_NAME",
+						Value: "e",
+					},
+					{
+						Name:  "STORAGE_BUCKETS_THREAD_DUMPS_NAME",
+						Value: "f",
+					},
+					{
+						Name:  "STORAGE_BUCKETS_METADATA_NAME",
+						Value: "z",
+					},
+				})
+			})
+		})
+		Context("with custom PVC spec overriding all defaults", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithPVCSpec().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the PVC with requested spec", func() {
+				t.expectPVC(t.NewCustomDatabasePVC())
+				t.expectPVC(t.NewCustomStoragePVC())
+			})
+		})
+		Context("with custom PVC spec overriding some defaults", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithPVCSpecSomeDefault().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the PVC with requested spec", func() {
+				t.expectPVC(t.NewCustomDatabasePVCSomeDefault())
+				t.expectPVC(t.NewCustomStoragePVCSomeDefault())
+			})
+		})
+		Context("with custom PVC config with no spec", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithPVCLabelsOnly().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the PVC with requested label", func() {
+				t.expectPVC(t.NewDefaultDatabasePVCWithLabel())
+				t.expectPVC(t.NewDefaultStoragePVCWithLabel())
+			})
+		})
+		Context("with a legacy PVC spec", func() {
+			Context("with custom PVC spec overriding all defaults", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithPVCSpecLegacy().Object)
+				})
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+				It("should create the PVC with requested spec", func() {
+					t.expectPVC(t.NewCustomDatabasePVCLegacy())
+					t.expectPVC(t.NewCustomStoragePVCLegacy())
+				})
+			})
+			Context("with custom PVC spec overriding some defaults", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithPVCSpecSomeDefaultLegacy().Object)
+				})
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+				It("should create the PVC with requested spec", func() {
+					t.expectPVC(t.NewCustomDatabasePVCSomeDefaultLegacy())
+					t.expectPVC(t.NewCustomStoragePVCSomeDefaultLegacy())
+				})
+			})
+			Context("with custom PVC config with no spec", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithPVCLabelsOnlyLegacy().Object)
+				})
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+				It("should create the PVC with requested label", func() {
+					t.expectPVC(t.NewDefaultDatabasePVCWithLabelLegacy())
+					t.expectPVC(t.NewDefaultStoragePVCWithLabelLegacy())
+				})
+			})
+		})
+		Context("with both custom PVC specs overriding all defaults", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithPVCSpecBoth().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the PVC with requested spec", func() {
+				t.expectPVC(t.NewCustomDatabasePVC())
+				t.expectPVC(t.NewCustomStoragePVC())
+			})
+		})
+		Context("when the PVC is bound", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+
+				// Simulate the binding of the claim by Kubernetes
+				pvc := &corev1.PersistentVolumeClaim{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewDefaultPVC().Name, Namespace: t.Namespace}, pvc)
+				Expect(err).ToNot(HaveOccurred())
+				pvc.Spec.VolumeName = "pv-1234"
+				err = t.Client.Update(context.Background(), pvc, ctrlclient.FieldOwner("kube-controller-manager"))
+				Expect(err).ToNot(HaveOccurred())
+
+				t.reconcileCryostatFully()
+			})
+			It("should keep the volume name", func() {
+				pvc := &corev1.PersistentVolumeClaim{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewDefaultPVC().Name, Namespace: t.Namespace}, pvc)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pvc.Spec.VolumeName).To(Equal("pv-1234"))
+			})
+			It("should only own the fields set in the CR", func() {
+				pvc := &corev1.PersistentVolumeClaim{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewDefaultPVC().Name, Namespace: t.Namespace}, pvc)
+				Expect(err).ToNot(HaveOccurred())
+
+				entry := findManagedFields(pvc, "cryostat-operator")
+				Expect(entry).ToNot(BeNil())
+				fields := string(entry.FieldsV1.Raw)
+				Expect(fields).To(ContainSubstring(`"f:resources"`))
+				Expect(fields).ToNot(ContainSubstring(`"f:volumeName"`))
+				Expect(fields).ToNot(ContainSubstring(`"f:storageClassName"`))
+				Expect(fields).ToNot(ContainSubstring(`"f:volumeMode"`))
+			})
+		})
+		Context("with an existing DB PVC", func() {
+			var oldPVC *corev1.PersistentVolumeClaim
+			BeforeEach(func() {
+				oldPVC = t.NewDefaultPVC()
+				t.objs = append(t.objs, t.NewCryostatWithPVCSpec().Object)
+				t.legacyObjs = append(t.legacyObjs, oldPVC)
+			})
+			Context("that successfully updates", func() {
+				BeforeEach(func() {
+					// Storage requests can only be changed once the claim is bound,
+					// and only if its storage class allows expansion
+					oldPVC.Status.Phase = corev1.ClaimBound
+					oldPVC.Spec.StorageClassName = &[]string{"expandable"}[0]
+					t.objs = append(t.objs, &storagev1.StorageClass{
+						ObjectMeta:           metav1.ObjectMeta{Name: "expandable"},
+						Provisioner:          "example.com/provisioner",
+						AllowVolumeExpansion: &[]bool{true}[0],
+					})
+					// Add some labels and annotations to test merging
+					metav1.SetMetaDataLabel(&oldPVC.ObjectMeta, "my", "other-label")
+					metav1.SetMetaDataLabel(&oldPVC.ObjectMeta, "another", "label")
+					metav1.SetMetaDataAnnotation(&oldPVC.ObjectMeta, "my/custom", "other-annotation")
+					metav1.SetMetaDataAnnotation(&oldPVC.ObjectMeta, "another/custom", "annotation")
+				})
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+				It("should update metadata and resource requests", func() {
+					expected := t.NewDefaultPVC()
+					metav1.SetMetaDataLabel(&expected.ObjectMeta, "my", "database")
+					metav1.SetMetaDataLabel(&expected.ObjectMeta, "another", "label")
+					metav1.SetMetaDataLabel(&expected.ObjectMeta, "app", t.Name)
+					metav1.SetMetaDataAnnotation(&expected.ObjectMeta, "my/custom", "database")
+					metav1.SetMetaDataAnnotation(&expected.ObjectMeta, "another/custom", "annotation")
+					expected.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("5Gi")
+					expected.Spec.StorageClassName = oldPVC.Spec.StorageClassName
+					t.expectPVC(expected)
+				})
+			})
+			Context("that fails to update", func() {
+				JustBeforeEach(func() {
+					// Replace client with one that fails to update the PVC
+					invalidErr := kerrors.NewInvalid(schema.ParseGroupKind("PersistentVolumeClaim"), oldPVC.Name, field.ErrorList{
+						field.Forbidden(field.NewPath("spec"), "test error"),
+					})
+					origClient := t.reconciler.GetConfig().Client
+					t.reconciler.GetConfig().Client = test.NewClientWithUpdateError(origClient, invalidErr, func(obj ctrlclient.Object) bool {
+						return test.FailOnMatch(obj, oldPVC, t.Client.Scheme())
+					})
+
+					// Expect an Invalid status error after reconciling
+					Eventually(func() bool {
+						_, err := t.reconcile()
+						if err != nil {
+							return kerrors.IsInvalid(err)
+						}
+						return false
+					}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(BeTrue())
+				})
+				It("should emit a PersistentVolumeClaimInvalid event", func() {
+					recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+					var eventMsg string
+					Expect(recorder.Events).To(Receive(&eventMsg))
+					Expect(eventMsg).To(ContainSubstring("PersistentVolumeClaimInvalid"))
+				})
+			})
+		})
+		Context("with custom EmptyDir config", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithDefaultEmptyDir().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the EmptyDir with default specs", func() {
+				t.expectDatabaseEmptyDir(t.NewDefaultEmptyDir())
+				t.expectStorageEmptyDir(t.NewDefaultEmptyDir())
+			})
+		})
+		Context("with custom EmptyDir config with requested spec", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithEmptyDirSpec().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the EmptyDir with requested specs", func() {
+				t.expectDatabaseEmptyDir(t.NewCustomDatabaseEmptyDir())
+				t.expectStorageEmptyDir(t.NewCustomStorageEmptyDir())
+			})
+		})
+		Context("with legacy custom EmptyDir config with requested spec", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithEmptyDirSpecLegacy().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the EmptyDir with requested specs", func() {
+				t.expectDatabaseEmptyDir(t.NewCustomEmptyDirLegacy())
+				t.expectStorageEmptyDir(t.NewCustomEmptyDirLegacy())
+			})
+		})
+		Context("with both custom EmptyDir configs with requested spec", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithEmptyDirSpecBoth().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the EmptyDir with requested specs", func() {
+				t.expectDatabaseEmptyDir(t.NewCustomDatabaseEmptyDir())
+				t.expectStorageEmptyDir(t.NewCustomStorageEmptyDir())
+			})
+		})
+		Context("with overridden image tags", func() {
+			var mainDeploy, databaseDeploy, storageDeploy, reportsDeploy *appsv1.Deployment
+			BeforeEach(func() {
+				t.ReportReplicas = 1
+				t.objs = append(t.objs, t.NewCryostatWithReportsSvc().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				mainDeploy = &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, mainDeploy)
+				Expect(err).ToNot(HaveOccurred())
+				databaseDeploy = &appsv1.Deployment{}
+				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, databaseDeploy)
+				Expect(err).ToNot(HaveOccurred())
+				storageDeploy = &appsv1.Deployment{}
+				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-storage", Namespace: t.Namespace}, storageDeploy)
+				Expect(err).ToNot(HaveOccurred())
+				reportsDeploy = &appsv1.Deployment{}
+				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-reports", Namespace: t.Namespace}, reportsDeploy)
+				Expect(err).ToNot(HaveOccurred())
+			})
+			Context("for development", func() {
+				BeforeEach(func() {
+					coreImg := "my/core-image:1.0.0-SNAPSHOT"
+					datasourceImg := "my/datasource-image:1.0.0-BETA25"
+					grafanaImg := "my/grafana-image:1.0.0-dev"
+					reportsImg := "my/reports-image:1.0.0-SNAPSHOT"
+					storageImg := "my/storage-image:1.0.0-dev"
 					databaseImg := "my/database-image:1.0.0-dev"
 					oauth2ProxyImg := "my/auth-proxy:1.0.0-dev"
 					openshiftAuthProxyImg := "my/openshift-auth-proxy:1.0.0-dev"
@@ -1418,508 +2385,1463 @@ func (c *controllerTest) commonTests() {
 					t.EnvOpenShiftOAuthProxyImageTag = &openshiftAuthProxyImg
 					t.EnvAgentProxyImageTag = &agentProxyImg
 				})
-				It("should create deployment with the expected tags", func() {
+				It("should create deployment with the expected tags", func() {
+					t.expectMainDeployment()
+					t.expectDatabaseDeployment()
+					t.expectStorageDeployment()
+					t.checkReportsDeployment()
+				})
+				It("should set ImagePullPolicy to Always", func() {
+					containers := mainDeploy.Spec.Template.Spec.Containers
+					Expect(containers).To(HaveLen(5))
+					for _, container := range containers {
+						Expect(container.ImagePullPolicy).To(Equal(corev1.PullAlways))
+					}
+					databaseContainers := databaseDeploy.Spec.Template.Spec.Containers
+					Expect(databaseContainers).To(HaveLen(1))
+					Expect(databaseContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+					storageContainers := storageDeploy.Spec.Template.Spec.Containers
+					Expect(storageContainers).To(HaveLen(1))
+					Expect(storageContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+					reportContainers := reportsDeploy.Spec.Template.Spec.Containers
+					Expect(reportContainers).To(HaveLen(1))
+					Expect(reportContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+				})
+			})
+			Context("for release", func() {
+				BeforeEach(func() {
+					coreImg := "my/core-image:1.0.0"
+					datasourceImg := "my/datasource-image:1.0.0"
+					grafanaImg := "my/grafana-image:1.0.0"
+					reportsImg := "my/reports-image:1.0.0"
+					storageImg := "my/storage-image:1.0.0"
+					databaseImg := "my/database-image:1.0.0"
+					oauth2ProxyImg := "my/authproxy-image:1.0.0"
+					openshiftAuthProxyImg := "my/openshift-authproxy-image:1.0.0"
+					agentProxyImg := "my/agent-proxy:1.0.0"
+					t.EnvCoreImageTag = &coreImg
+					t.EnvDatasourceImageTag = &datasourceImg
+					t.EnvGrafanaImageTag = &grafanaImg
+					t.EnvReportsImageTag = &reportsImg
+					t.EnvDatabaseImageTag = &databaseImg
+					t.EnvStorageImageTag = &storageImg
+					t.EnvOAuth2ProxyImageTag = &oauth2ProxyImg
+					t.EnvOpenShiftOAuthProxyImageTag = &openshiftAuthProxyImg
+					t.EnvAgentProxyImageTag = &agentProxyImg
+				})
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+				It("should create deployment with the expected tags", func() {
+					t.expectMainDeployment()
+					t.checkReportsDeployment()
+				})
+				It("should set ImagePullPolicy to IfNotPresent", func() {
+					containers := mainDeploy.Spec.Template.Spec.Containers
+					Expect(containers).To(HaveLen(5))
+					for _, container := range containers {
+						fmt.Println(container.Image)
+						Expect(container.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+					}
+					databaseContainers := databaseDeploy.Spec.Template.Spec.Containers
+					Expect(databaseContainers).To(HaveLen(1))
+					Expect(databaseContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+					storageContainers := storageDeploy.Spec.Template.Spec.Containers
+					Expect(storageContainers).To(HaveLen(1))
+					Expect(storageContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+					reportContainers := reportsDeploy.Spec.Template.Spec.Containers
+					Expect(reportContainers).To(HaveLen(1))
+					Expect(reportContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+				})
+			})
+			Context("by digest", func() {
+				BeforeEach(func() {
+					coreImg := "my/core-image@sha256:99b57e9b8880bc5d4d799b508603628c37c3e6a0d4bdd0988e9dc3ad8e04c495"
+					datasourceImg := "my/datasource-image@sha256:59ded87392077c2371b26e021aade0409855b597383fa78e549eefafab8fc90c"
+					grafanaImg := "my/grafana-image@sha256:e5bc16c2c5b69cd6fd8fdf1381d0a8b6cc9e01d92b9e1bb0a61ed89196563c72"
+					reportsImg := "my/reports-image@sha256:8a23ca5e8c8a343789b8c14558a44a49d35ecd130c18e62edf0d1ad9ce88d37d"
+					storageImg := "my/storage-image@sha256:8b23ca5e8c8a343789b8c14558a44a49d35ecd130c18e62edf0d1ad9ce88d37d"
+					databaseImg := "my/database-image@sha256:8c23ca5e8c8a343789b8c14558a44a49d35ecd130c18e62edf0d1ad9ce88d37d"
+					oauth2ProxyImage := "my/authproxy-image@sha256:8c23ca5e8c8a343789b8c14558a44a49d35ecd130c18e62edf0d1ad9ce88d37d"
+					openshiftAuthProxyImage := "my/openshift-authproxy-image@sha256:8c23ca5e8c8a343789b8c14558a44a49d35ecd130c18e62edf0d1ad9ce88d37d"
+					agentProxyImg := "my/agent-proxy@sha256:2da2edd513ce134e1b99ea61e84b794c2dece7bd24b9949cc267a1c29020f26a"
+					t.EnvCoreImageTag = &coreImg
+					t.EnvDatasourceImageTag = &datasourceImg
+					t.EnvGrafanaImageTag = &grafanaImg
+					t.EnvReportsImageTag = &reportsImg
+					t.EnvDatabaseImageTag = &databaseImg
+					t.EnvStorageImageTag = &storageImg
+					t.EnvOAuth2ProxyImageTag = &oauth2ProxyImage
+					t.EnvOpenShiftOAuthProxyImageTag = &openshiftAuthProxyImage
+					t.EnvAgentProxyImageTag = &agentProxyImg
+				})
+				It("should create deployment with the expected tags", func() {
+					t.expectMainDeployment()
+					t.checkReportsDeployment()
+				})
+				It("should set ImagePullPolicy to IfNotPresent", func() {
+					containers := mainDeploy.Spec.Template.Spec.Containers
+					Expect(containers).To(HaveLen(5))
+					for _, container := range containers {
+						Expect(container.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+					}
+					databaseContainers := databaseDeploy.Spec.Template.Spec.Containers
+					Expect(databaseContainers).To(HaveLen(1))
+					Expect(databaseContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+					storageContainers := storageDeploy.Spec.Template.Spec.Containers
+					Expect(storageContainers).To(HaveLen(1))
+					Expect(storageContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+					reportContainers := reportsDeploy.Spec.Template.Spec.Containers
+					Expect(reportContainers).To(HaveLen(1))
+					Expect(reportContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+				})
+			})
+			Context("with latest", func() {
+				BeforeEach(func() {
+					coreImg := "my/core-image:latest"
+					datasourceImg := "my/datasource-image:latest"
+					grafanaImg := "my/grafana-image:latest"
+					reportsImg := "my/reports-image:latest"
+					oauth2ProxyImg := "my/authproxy-image:latest"
+					openshiftAuthProxyImg := "my/openshift-authproxy-image:latest"
+					dbImg := "my/db-image:latest"
+					storageImg := "my/storage-image:latest"
+					agentProxyImg := "my/agent-proxy:latest"
+					t.EnvCoreImageTag = &coreImg
+					t.EnvDatasourceImageTag = &datasourceImg
+					t.EnvGrafanaImageTag = &grafanaImg
+					t.EnvReportsImageTag = &reportsImg
+					t.EnvOAuth2ProxyImageTag = &oauth2ProxyImg
+					t.EnvOpenShiftOAuthProxyImageTag = &openshiftAuthProxyImg
+					t.EnvDatabaseImageTag = &dbImg
+					t.EnvStorageImageTag = &storageImg
+					t.EnvAgentProxyImageTag = &agentProxyImg
+				})
+				It("should create deployment with the expected tags", func() {
+					t.expectMainDeployment()
+					t.checkReportsDeployment()
+				})
+				It("should set ImagePullPolicy to Always", func() {
+					containers := mainDeploy.Spec.Template.Spec.Containers
+					Expect(containers).To(HaveLen(5))
+					for _, container := range containers {
+						Expect(container.ImagePullPolicy).To(Equal(corev1.PullAlways), "Container %s", container.Image)
+					}
+					databaseContainers := databaseDeploy.Spec.Template.Spec.Containers
+					Expect(databaseContainers).To(HaveLen(1))
+					Expect(databaseContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+					storageContainers := storageDeploy.Spec.Template.Spec.Containers
+					Expect(storageContainers).To(HaveLen(1))
+					Expect(storageContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+					reportContainers := reportsDeploy.Spec.Template.Spec.Containers
+					Expect(reportContainers).To(HaveLen(1))
+					Expect(reportContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+				})
+			})
+		})
+		Context("with image overrides in the CR", func() {
+			var mainDeploy, databaseDeploy, storageDeploy *appsv1.Deployment
+			BeforeEach(func() {
+				databaseImg := "my/database-image:1.0.0"
+				t.EnvDatabaseImageTag = &databaseImg
+				t.objs = append(t.objs, t.NewCryostatWithImageOverrides().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				mainDeploy = &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, mainDeploy)
+				Expect(err).ToNot(HaveOccurred())
+				databaseDeploy = &appsv1.Deployment{}
+				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, databaseDeploy)
+				Expect(err).ToNot(HaveOccurred())
+				storageDeploy = &appsv1.Deployment{}
+				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-storage", Namespace: t.Namespace}, storageDeploy)
+				Expect(err).ToNot(HaveOccurred())
+			})
+			It("should use the overridden image for the core container", func() {
+				coreContainer := mainDeploy.Spec.Template.Spec.Containers[0]
+				Expect(coreContainer.Image).To(Equal("mirror.example.com/cryostat/cryostat:canary"))
+				Expect(coreContainer.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+			})
+			It("should pin the database image by digest", func() {
+				databaseContainer := databaseDeploy.Spec.Template.Spec.Containers[0]
+				Expect(databaseContainer.Image).To(Equal("my/database-image@" + test.ImageDigest))
+				Expect(databaseContainer.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+			})
+			It("should add image pull secrets to each pod", func() {
+				for _, deploy := range []*appsv1.Deployment{mainDeploy, databaseDeploy, storageDeploy} {
+					Expect(deploy.Spec.Template.Spec.ImagePullSecrets).To(Equal(t.NewImagePullSecrets()), "Deployment %s", deploy.Name)
+				}
+			})
+			It("should report the resolved images in the status", func() {
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.Images).ToNot(BeNil())
+				Expect(cr.Status.Images.Core).To(Equal("mirror.example.com/cryostat/cryostat:canary"))
+				Expect(cr.Status.Images.Database).To(Equal("my/database-image@" + test.ImageDigest))
+				Expect(cr.Status.Images.Storage).To(Equal(storageDeploy.Spec.Template.Spec.Containers[0].Image))
+				Expect(mainDeploy.Spec.Template.Spec.Containers).To(ContainElement(SatisfyAll(
+					HaveField("Name", t.Name+"-auth-proxy"),
+					HaveField("Image", cr.Status.Images.AuthProxy),
+				)))
+				Expect(cr.Status.Images.AgentInit).To(Equal("mirror.example.com/cryostat/cryostat-agent-init@" + test.ImageDigest))
+			})
+		})
+		Context("when deleted", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			Context("ClusterRoleBinding exists", func() {
+				JustBeforeEach(func() {
+					t.reconcileDeletedCryostat()
+				})
+				It("should delete the ClusterRoleBinding", func() {
+					t.checkClusterRoleBindingDeleted()
+				})
+				It("should delete Cryostat", func() {
+					t.expectNoCryostat()
+				})
+			})
+			Context("ClusterRoleBinding does not exist", func() {
+				JustBeforeEach(func() {
+					err := t.Client.Delete(context.Background(), t.NewClusterRoleBinding())
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileDeletedCryostat()
+				})
+				It("should delete Cryostat", func() {
+					t.expectNoCryostat()
+				})
+			})
+			Context("RoleBinding exists", func() {
+				JustBeforeEach(func() {
+					t.reconcileDeletedCryostat()
+				})
+				It("should delete the RoleBinding", func() {
+					t.checkRoleBindingsDeleted()
+				})
+				It("should delete Cryostat", func() {
+					t.expectNoCryostat()
+				})
+			})
+			Context("RoleBinding does not exist", func() {
+				JustBeforeEach(func() {
+					err := t.Client.Delete(context.Background(), t.NewRoleBinding(t.Namespace))
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileDeletedCryostat()
+				})
+				It("should delete Cryostat", func() {
+					t.expectNoCryostat()
+				})
+			})
+		})
+		Context("on OpenShift", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create ConsoleLink", func() {
+				t.expectConsoleLink()
+			})
+			Context("with an existing ConsoleLink", func() {
+				var oldLink *consolev1.ConsoleLink
+				BeforeEach(func() {
+					oldLink = t.OtherConsoleLink()
+					t.objs = append(t.objs, oldLink)
+				})
+				It("should update the ConsoleLink", func() {
+					link := &consolev1.ConsoleLink{}
+					expectedLink := t.NewConsoleLink()
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expectedLink.Name}, link)
+					Expect(err).ToNot(HaveOccurred())
+					// Existing labels and annotations should remain
+					Expect(link.Labels).To(Equal(oldLink.Labels))
+					Expect(link.Annotations).To(Equal(oldLink.Annotations))
+
+					// Check managed spec fields
+					Expect(link.Spec.Link).To(Equal(expectedLink.Spec.Link))
+					Expect(link.Spec.Location).To(Equal(expectedLink.Spec.Location))
+					Expect(link.Spec.NamespaceDashboard).To(Equal(expectedLink.Spec.NamespaceDashboard))
+				})
+			})
+			Context("with an existing application url in APIServer AdditionalCORSAllowedOrigins", func() {
+				BeforeEach(func() {
+					t.objs = []ctrlclient.Object{
+						t.NewApiServerWithApplicationURL(),
+						t.NewNamespace(),
+					}
+				})
+				It("should remove the application url", func() {
+					apiServer := &configv1.APIServer{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cluster"}, apiServer)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).ToNot(ContainElement(fmt.Sprintf("https://%s\\.example\\.com", t.Name)))
+					Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).To(ContainElement("https://an-existing-user-specified\\.allowed\\.origin\\.com"))
+				})
+			})
+			It("should add the finalizer", func() {
+				t.expectCryostatFinalizerPresent()
+			})
+			Context("with restricted SCC", func() {
+				BeforeEach(func() {
+					t.objs = []ctrlclient.Object{
+						t.NewCryostat().Object, t.NewNamespaceWithSCCSupGroups(), t.NewApiServer(),
+					}
+				})
+				It("should set fsGroup to value derived from namespace", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					sc := deploy.Spec.Template.Spec.SecurityContext
+					Expect(sc).ToNot(BeNil())
+					Expect(sc.FSGroup).ToNot(BeNil())
+					Expect(*sc.FSGroup).To(Equal(int64(1000130000)))
+				})
+			})
+			Context("when deleted", func() {
+				Context("ConsoleLink exists", func() {
+					JustBeforeEach(func() {
+						t.reconcileDeletedCryostat()
+					})
+					It("should delete the ConsoleLink", func() {
+						link := &consolev1.ConsoleLink{}
+						expectedLink := t.NewConsoleLink()
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: expectedLink.Name}, link)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+					It("should remove the application url from APIServer AdditionalCORSAllowedOrigins", func() {
+						apiServer := &configv1.APIServer{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cluster"}, apiServer)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).ToNot(ContainElement(fmt.Sprintf("https://%s\\.example\\.com", t.Name)))
+						Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).To(ContainElement("https://an-existing-user-specified\\.allowed\\.origin\\.com"))
+					})
+					It("should delete Cryostat", func() {
+						t.expectNoCryostat()
+					})
+				})
+				Context("ConsoleLink does not exist", func() {
+					JustBeforeEach(func() {
+						err := t.Client.Delete(context.Background(), t.NewConsoleLink())
+						Expect(err).ToNot(HaveOccurred())
+						t.reconcileDeletedCryostat()
+					})
+					It("should delete Cryostat", func() {
+						t.expectNoCryostat()
+					})
+				})
+			})
+			Context("with Insights enabled", func() {
+				BeforeEach(func() {
+					t.InsightsURL = "http://insights-proxy.foo.svc.cluster.local"
+				})
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+				It("should create deployment", func() {
 					t.expectMainDeployment()
-					t.expectDatabaseDeployment()
-					t.expectStorageDeployment()
-					t.checkReportsDeployment()
 				})
-				It("should set ImagePullPolicy to Always", func() {
-					containers := mainDeploy.Spec.Template.Spec.Containers
-					Expect(containers).To(HaveLen(5))
-					for _, container := range containers {
-						Expect(container.ImagePullPolicy).To(Equal(corev1.PullAlways))
-					}
-					databaseContainers := databaseDeploy.Spec.Template.Spec.Containers
-					Expect(databaseContainers).To(HaveLen(1))
-					Expect(databaseContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
-					storageContainers := storageDeploy.Spec.Template.Spec.Containers
-					Expect(storageContainers).To(HaveLen(1))
-					Expect(storageContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
-					reportContainers := reportsDeploy.Spec.Template.Spec.Containers
-					Expect(reportContainers).To(HaveLen(1))
-					Expect(reportContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+			})
+		})
+		Context("with cert-manager disabled in CR", func() {
+			BeforeEach(func() {
+				t.TLS = false
+				t.objs = append(t.objs, t.NewCryostatCertManagerDisabled().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create deployment and set owner", func() {
+				t.expectMainDeployment()
+			})
+			It("should not create certificates", func() {
+				certs := &certv1.CertificateList{}
+				err := t.Client.List(context.Background(), certs, &ctrlclient.ListOptions{
+					Namespace: t.Namespace,
 				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(certs.Items).To(BeEmpty())
 			})
-			Context("for release", func() {
+			It("should create routes with edge TLS termination", func() {
+				t.expectRoutes()
+			})
+			It("should create the agent proxy config map", func() {
+				t.expectAgentProxyConfigMap()
+			})
+		})
+		Context("with cert-manager not configured in CR", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatCertManagerUndefined().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create deployment and set owner", func() {
+				t.expectMainDeployment()
+			})
+			It("should create certificates", func() {
+				t.expectCertificates()
+			})
+			It("should create routes with re-encrypt TLS termination", func() {
+				t.expectRoutes()
+			})
+			It("should set TLSSetupComplete condition", func() {
+				t.reconcileCryostatFully()
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+					"AllCertificatesReady")
+			})
+			It("should create the agent proxy config map", func() {
+				t.expectAgentProxyConfigMap()
+			})
+		})
+		Context("with DISABLE_SERVICE_TLS=true", func() {
+			BeforeEach(func() {
+				disableTLS := true
+				t.EnvDisableTLS = &disableTLS
+				t.TLS = false
+				t.objs = append(t.objs, t.NewCryostatCertManagerUndefined().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create deployment and set owner", func() {
+				t.expectMainDeployment()
+			})
+			It("should not create certificates", func() {
+				certs := &certv1.CertificateList{}
+				err := t.Client.List(context.Background(), certs, &ctrlclient.ListOptions{
+					Namespace: t.Namespace,
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(certs.Items).To(BeEmpty())
+			})
+			It("should create routes with edge TLS termination", func() {
+				t.expectRoutes()
+			})
+			It("should set TLSSetupComplete Condition", func() {
+				t.reconcileCryostatFully()
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+					"CertManagerDisabled")
+			})
+			It("should create the agent proxy config map", func() {
+				t.expectAgentProxyConfigMap()
+			})
+		})
+		Context("Disable cert-manager after being enabled", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+
+				cryostat := t.getCryostatInstance()
+
+				t.TLS = false
+				certManager := false
+				cryostat.Spec.EnableCertManager = &certManager
+				t.updateCryostatInstance(cryostat)
+
+				t.reconcileCryostatFully()
+			})
+			It("should update the deployment", func() {
+				t.expectMainDeployment()
+			})
+			It("should create routes with edge TLS termination", func() {
+				t.expectRoutes()
+			})
+			It("should set TLSSetupComplete Condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+					"CertManagerDisabled")
+			})
+			It("should create the agent proxy config map", func() {
+				t.expectAgentProxyConfigMap()
+			})
+		})
+		Context("Enable cert-manager after being disabled", func() {
+			BeforeEach(func() {
+				t.TLS = false
+				t.objs = append(t.objs, t.NewCryostatCertManagerDisabled().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+
+				cryostat := t.getCryostatInstance()
+
+				t.TLS = true
+				certManager := true
+				cryostat.Spec.EnableCertManager = &certManager
+				t.updateCryostatInstance(cryostat)
+
+				t.reconcileCryostatFully()
+			})
+			It("should update the deployment", func() {
+				t.expectMainDeployment()
+			})
+			It("should create certificates", func() {
+				t.expectCertificates()
+			})
+			It("should create routes with re-encrypt TLS termination", func() {
+				t.expectRoutes()
+			})
+			It("should set TLSSetupComplete condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+					"AllCertificatesReady")
+			})
+			It("should create the agent proxy config map", func() {
+				t.expectAgentProxyConfigMap()
+			})
+		})
+		Context("cert-manager missing", func() {
+			JustBeforeEach(func() {
+				// Replace with an empty RESTMapper
+				t.reconciler.GetConfig().RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+			})
+			Context("and enabled", func() {
 				BeforeEach(func() {
-					coreImg := "my/core-image:1.0.0"
-					datasourceImg := "my/datasource-image:1.0.0"
-					grafanaImg := "my/grafana-image:1.0.0"
-					reportsImg := "my/reports-image:1.0.0"
-					storageImg := "my/storage-image:1.0.0"
-					databaseImg := "my/database-image:1.0.0"
-					oauth2ProxyImg := "my/authproxy-image:1.0.0"
-					openshiftAuthProxyImg := "my/openshift-authproxy-image:1.0.0"
-					agentProxyImg := "my/agent-proxy:1.0.0"
-					t.EnvCoreImageTag = &coreImg
-					t.EnvDatasourceImageTag = &datasourceImg
-					t.EnvGrafanaImageTag = &grafanaImg
-					t.EnvReportsImageTag = &reportsImg
-					t.EnvDatabaseImageTag = &databaseImg
-					t.EnvStorageImageTag = &storageImg
-					t.EnvOAuth2ProxyImageTag = &oauth2ProxyImg
-					t.EnvOpenShiftOAuthProxyImageTag = &openshiftAuthProxyImg
-					t.EnvAgentProxyImageTag = &agentProxyImg
+					t.objs = append(t.objs, t.NewCryostat().Object)
+				})
+				JustBeforeEach(func() {
+					_, err := t.reconcile()
+					Expect(err).To(HaveOccurred())
+				})
+				It("should emit a CertManagerUnavailable Event", func() {
+					recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+					var eventMsg string
+					Expect(recorder.Events).To(Receive(&eventMsg))
+					Expect(eventMsg).To(ContainSubstring("CertManagerUnavailable"))
+				})
+				It("should set TLSSetupComplete Condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
+						"CertManagerUnavailable")
+				})
+				It("should set aggregate conditions", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse,
+						"ComponentFailed")
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeStalled, metav1.ConditionTrue,
+						"ComponentFailed")
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeReconciling, metav1.ConditionTrue,
+						"NewGeneration")
+				})
+				It("should not set observedGeneration in CR Status", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.ObservedGeneration).ToNot(Equal(cr.Object.GetGeneration()))
+				})
+			})
+			Context("and disabled", func() {
+				BeforeEach(func() {
+					t.TLS = false
+					t.objs = append(t.objs, t.NewCryostatCertManagerDisabled().Object)
+				})
+				JustBeforeEach(func() {
+					_, err := t.reconcile()
+					Expect(err).ToNot(HaveOccurred())
+				})
+				It("should not emit a CertManagerUnavailable Event", func() {
+					recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+					Expect(recorder.Events).ToNot(Receive())
+				})
+				It("should set TLSSetupComplete Condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+						"CertManagerDisabled")
+				})
+			})
+		})
+		Context("with an external issuer", func() {
+			var otherNS string
+
+			BeforeEach(func() {
+				otherNS = "other-target"
+				t.TargetNamespaces = []string{t.Namespace, otherNS}
+				t.objs = append(t.objs, t.NewOtherNamespace(otherNS), t.NewExternalIssuer())
+			})
+			Context("that is a ClusterIssuer", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithIssuerRef("ClusterIssuer").Object)
 				})
 				JustBeforeEach(func() {
 					t.reconcileCryostatFully()
 				})
-				It("should create deployment with the expected tags", func() {
-					t.expectMainDeployment()
-					t.checkReportsDeployment()
+				It("should issue certificates from the issuer", func() {
+					t.expectCertificateIssuer(certMeta.ObjectReference{Name: test.ExternalIssuerName, Kind: "ClusterIssuer"})
+				})
+				It("should not create a CA", func() {
+					t.expectNoCryostatCA()
+				})
+				It("should copy the issuer's CA to each target namespace", func() {
+					secret := &corev1.Secret{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewCACert().Spec.SecretName, Namespace: otherNS}, secret)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(secret.Data).To(HaveKeyWithValue(corev1.TLSCertKey, t.NewExternalIssuerCABytes()))
 				})
-				It("should set ImagePullPolicy to IfNotPresent", func() {
-					containers := mainDeploy.Spec.Template.Spec.Containers
-					Expect(containers).To(HaveLen(5))
-					for _, container := range containers {
-						fmt.Println(container.Image)
-						Expect(container.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
-					}
-					databaseContainers := databaseDeploy.Spec.Template.Spec.Containers
-					Expect(databaseContainers).To(HaveLen(1))
-					Expect(databaseContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
-					storageContainers := storageDeploy.Spec.Template.Spec.Containers
-					Expect(storageContainers).To(HaveLen(1))
-					Expect(storageContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
-					reportContainers := reportsDeploy.Spec.Template.Spec.Containers
-					Expect(reportContainers).To(HaveLen(1))
-					Expect(reportContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+				It("should set TLSSetupComplete condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+						"AllCertificatesReady")
+				})
+				It("should only accept agent certificates issued for this Cryostat", func() {
+					Expect(t.getAgentProxyNginxConf()).To(ContainSubstring(
+						fmt.Sprintf(`if ($ssl_client_s_dn !~ "(^|,)OU=%s(,|$)")`, t.GetAgentServiceName())))
 				})
 			})
-			Context("by digest", func() {
+			Context("that is of an external type", func() {
 				BeforeEach(func() {
-					coreImg := "my/core-image@sha256:99b57e9b8880bc5d4d799b508603628c37c3e6a0d4bdd0988e9dc3ad8e04c495"
-					datasourceImg := "my/datasource-image@sha256:59ded87392077c2371b26e021aade0409855b597383fa78e549eefafab8fc90c"
-					grafanaImg := "my/grafana-image@sha256:e5bc16c2c5b69cd6fd8fdf1381d0a8b6cc9e01d92b9e1bb0a61ed89196563c72"
-					reportsImg := "my/reports-image@sha256:8a23ca5e8c8a343789b8c14558a44a49d35ecd130c18e62edf0d1ad9ce88d37d"
-					storageImg := "my/storage-image@sha256:8b23ca5e8c8a343789b8c14558a44a49d35ecd130c18e62edf0d1ad9ce88d37d"
-					databaseImg := "my/database-image@sha256:8c23ca5e8c8a343789b8c14558a44a49d35ecd130c18e62edf0d1ad9ce88d37d"
-					oauth2ProxyImage := "my/authproxy-image@sha256:8c23ca5e8c8a343789b8c14558a44a49d35ecd130c18e62edf0d1ad9ce88d37d"
-					openshiftAuthProxyImage := "my/openshift-authproxy-image@sha256:8c23ca5e8c8a343789b8c14558a44a49d35ecd130c18e62edf0d1ad9ce88d37d"
-					agentProxyImg := "my/agent-proxy@sha256:2da2edd513ce134e1b99ea61e84b794c2dece7bd24b9949cc267a1c29020f26a"
-					t.EnvCoreImageTag = &coreImg
-					t.EnvDatasourceImageTag = &datasourceImg
-					t.EnvGrafanaImageTag = &grafanaImg
-					t.EnvReportsImageTag = &reportsImg
-					t.EnvDatabaseImageTag = &databaseImg
-					t.EnvStorageImageTag = &storageImg
-					t.EnvOAuth2ProxyImageTag = &oauth2ProxyImage
-					t.EnvOpenShiftOAuthProxyImageTag = &openshiftAuthProxyImage
-					t.EnvAgentProxyImageTag = &agentProxyImg
+					cr := t.NewCryostatWithIssuerRef("AWSPCAClusterIssuer")
+					cr.Spec.TLS.IssuerRef.Group = "awspca.cert-manager.io"
+					t.objs = append(t.objs, cr.Object)
 				})
-				It("should create deployment with the expected tags", func() {
-					t.expectMainDeployment()
-					t.checkReportsDeployment()
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
 				})
-				It("should set ImagePullPolicy to IfNotPresent", func() {
-					containers := mainDeploy.Spec.Template.Spec.Containers
-					Expect(containers).To(HaveLen(5))
-					for _, container := range containers {
-						Expect(container.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
-					}
-					databaseContainers := databaseDeploy.Spec.Template.Spec.Containers
-					Expect(databaseContainers).To(HaveLen(1))
-					Expect(databaseContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
-					storageContainers := storageDeploy.Spec.Template.Spec.Containers
-					Expect(storageContainers).To(HaveLen(1))
-					Expect(storageContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
-					reportContainers := reportsDeploy.Spec.Template.Spec.Containers
-					Expect(reportContainers).To(HaveLen(1))
-					Expect(reportContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+				It("should issue certificates from the issuer", func() {
+					t.expectCertificateIssuer(certMeta.ObjectReference{Name: test.ExternalIssuerName, Kind: "AWSPCAClusterIssuer",
+						Group: "awspca.cert-manager.io"})
 				})
 			})
-			Context("with latest", func() {
+			Context("that replaces the generated CA", func() {
 				BeforeEach(func() {
-					coreImg := "my/core-image:latest"
-					datasourceImg := "my/datasource-image:latest"
-					grafanaImg := "my/grafana-image:latest"
-					reportsImg := "my/reports-image:latest"
-					oauth2ProxyImg := "my/authproxy-image:latest"
-					openshiftAuthProxyImg := "my/openshift-authproxy-image:latest"
-					dbImg := "my/db-image:latest"
-					storageImg := "my/storage-image:latest"
-					agentProxyImg := "my/agent-proxy:latest"
-					t.EnvCoreImageTag = &coreImg
-					t.EnvDatasourceImageTag = &datasourceImg
-					t.EnvGrafanaImageTag = &grafanaImg
-					t.EnvReportsImageTag = &reportsImg
-					t.EnvOAuth2ProxyImageTag = &oauth2ProxyImg
-					t.EnvOpenShiftOAuthProxyImageTag = &openshiftAuthProxyImg
-					t.EnvDatabaseImageTag = &dbImg
-					t.EnvStorageImageTag = &storageImg
-					t.EnvAgentProxyImageTag = &agentProxyImg
+					t.objs = append(t.objs, t.NewCryostat().Object)
 				})
-				It("should create deployment with the expected tags", func() {
-					t.expectMainDeployment()
-					t.checkReportsDeployment()
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+					cr := t.getCryostatInstance()
+					cr.Spec.TLS = t.NewCryostatWithIssuerRef("Issuer").Spec.TLS
+					t.updateCryostatInstance(cr)
+					// The generated CA remains trusted while agents move to the issuer's CA
+					t.reconcileCryostatUntilRequeueAfter(time.Minute)
 				})
-				It("should set ImagePullPolicy to Always", func() {
-					containers := mainDeploy.Spec.Template.Spec.Containers
-					Expect(containers).To(HaveLen(5))
-					for _, container := range containers {
-						Expect(container.ImagePullPolicy).To(Equal(corev1.PullAlways), "Container %s", container.Image)
-					}
-					databaseContainers := databaseDeploy.Spec.Template.Spec.Containers
-					Expect(databaseContainers).To(HaveLen(1))
-					Expect(databaseContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
-					storageContainers := storageDeploy.Spec.Template.Spec.Containers
-					Expect(storageContainers).To(HaveLen(1))
-					Expect(storageContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
-					reportContainers := reportsDeploy.Spec.Template.Spec.Containers
-					Expect(reportContainers).To(HaveLen(1))
-					Expect(reportContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+				It("should reissue certificates from the issuer", func() {
+					t.expectCertificateIssuer(certMeta.ObjectReference{Name: test.ExternalIssuerName, Kind: "Issuer"})
+				})
+				It("should delete the generated CA", func() {
+					t.expectNoCryostatCA()
+				})
+				It("should leave the external issuer untouched", func() {
+					expected := t.NewExternalIssuer()
+					issuer := &certv1.Issuer{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, issuer)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(issuer.Spec).To(Equal(expected.Spec))
+					Expect(issuer.OwnerReferences).To(BeEmpty())
 				})
 			})
 		})
-		Context("when deleted", func() {
+		Context("when the CA issuer's CA changes", func() {
+			var externalCert *certv1.Certificate
+			var reportsCertUID types.UID
+
 			BeforeEach(func() {
 				t.objs = append(t.objs, t.NewCryostat().Object)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
+				cr := t.getCryostatInstance()
+
+				// Add a certificate from an external issuer owned by the CR
+				externalCert = t.NewReportsCert()
+				externalCert.Name = "external"
+				externalCert.Spec.SecretName = "external-tls"
+				externalCert.Spec.IssuerRef = certMeta.ObjectReference{Name: test.ExternalIssuerName, Kind: "ClusterIssuer"}
+				err := controllerutil.SetControllerReference(cr.Object, externalCert, test.NewTestScheme())
+				Expect(err).ToNot(HaveOccurred())
+				err = t.Client.Create(context.Background(), externalCert)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Simulate an issuer created for a previous CA by an earlier version of the operator
+				issuer := &certv1.Issuer{}
+				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-ca", Namespace: t.Namespace}, issuer)
+				Expect(err).ToNot(HaveOccurred())
+				issuer.Spec.CA.SecretName = "previous-ca"
+				err = t.Client.Update(context.Background(), issuer, ctrlclient.FieldOwner(test.LegacyFieldManager))
+				Expect(err).ToNot(HaveOccurred())
+
+				reportsCertUID = t.getCertificate(t.NewReportsCert()).UID
+				t.reconcileCryostatFully()
 			})
-			Context("ClusterRoleBinding exists", func() {
+			It("should reissue certificates from the CA issuer", func() {
+				Expect(t.getCertificate(t.NewReportsCert()).UID).ToNot(Equal(reportsCertUID))
+				t.expectCertificates()
+			})
+			It("should leave certificates from external issuers untouched", func() {
+				Expect(t.getCertificate(externalCert).Spec).To(Equal(externalCert.Spec))
+			})
+		})
+		Context("with private key and lifetime options", func() {
+			var expectedKey *certv1.CertificatePrivateKey
+
+			BeforeEach(func() {
+				cr := t.NewCryostatWithPrivateKey()
+				cr.Spec.TLS.Lifetimes = t.NewCertificateLifetimes()
+				t.objs = append(t.objs, cr.Object)
+				expectedKey = &certv1.CertificatePrivateKey{
+					Algorithm:      certv1.ECDSAKeyAlgorithm,
+					Size:           384,
+					RotationPolicy: certv1.RotationPolicyAlways,
+				}
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should configure the CA certificate", func() {
+				cert := t.getCertificate(t.NewCACert())
+				Expect(cert.Spec.PrivateKey).To(Equal(expectedKey))
+				Expect(cert.Spec.Duration).To(Equal(&metav1.Duration{Duration: 8760 * time.Hour}))
+				Expect(cert.Spec.RenewBefore).To(BeNil())
+			})
+			It("should configure server certificates", func() {
+				for _, expected := range []*certv1.Certificate{t.NewCryostatCert(), t.NewReportsCert(), t.NewAgentProxyCert(),
+					t.NewDatabaseCert(), t.NewStorageCert()} {
+					cert := t.getCertificate(expected)
+					Expect(cert.Spec.PrivateKey).To(Equal(expectedKey))
+					Expect(cert.Spec.Duration).To(Equal(&metav1.Duration{Duration: 720 * time.Hour}))
+					Expect(cert.Spec.RenewBefore).To(Equal(&metav1.Duration{Duration: 240 * time.Hour}))
+				}
+			})
+			It("should configure agent certificates", func() {
+				for _, ns := range t.TargetNamespaces {
+					cert := t.getCertificate(t.NewAgentCert(ns))
+					Expect(cert.Spec.PrivateKey).To(Equal(expectedKey))
+					Expect(cert.Spec.Duration).To(Equal(&metav1.Duration{Duration: 360 * time.Hour}))
+					Expect(cert.Spec.RenewBefore).To(BeNil())
+				}
+			})
+		})
+		Context("with provided TLS certificates", func() {
+			var ca *test.TestCA
+			var otherNS string
+
+			BeforeEach(func() {
+				otherNS = "other-target"
+				t.TargetNamespaces = []string{t.Namespace, otherNS}
+				ca = test.NewTestCA()
+				t.objs = append(t.objs, t.NewOtherNamespace(otherNS), t.NewCryostatWithProvidedCertificates().Object)
+				for _, secret := range t.NewProvidedTLSSecrets(ca) {
+					t.objs = append(t.objs, secret)
+				}
+			})
+			JustBeforeEach(func() {
+				// Provided certificates do not require cert-manager
+				t.reconciler.GetConfig().RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+			})
+			Context("that are valid", func() {
 				JustBeforeEach(func() {
-					t.reconcileDeletedCryostat()
+					t.reconcileCryostatFully()
 				})
-				It("should delete the ClusterRoleBinding", func() {
-					t.checkClusterRoleBindingDeleted()
+				It("should set TLSSetupComplete condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+						"AllCertificatesReady")
 				})
-				It("should delete Cryostat", func() {
-					t.expectNoCryostat()
+				It("should not create certificates", func() {
+					certs := &certv1.CertificateList{}
+					err := t.Client.List(context.Background(), certs, &ctrlclient.ListOptions{
+						Namespace: t.Namespace,
+					})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(certs.Items).To(BeEmpty())
+				})
+				It("should create a keystore from the core certificate", func() {
+					secret := t.getProvidedKeystoreSecret()
+					Expect(secret.Data).To(HaveKeyWithValue(corev1.TLSCertKey, t.getSecret("core-tls").Data[corev1.TLSCertKey]))
+
+					password := t.getSecret(t.Name + "-keystore").Data["KEYSTORE_PASS"]
+					_, cert, caCerts, err := pkcs12.DecodeChain(secret.Data["keystore.p12"], string(password))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(cert.DNSNames).To(ConsistOf(t.Name, t.Name+"."+t.Namespace+".svc",
+						t.Name+"."+t.Namespace+".svc.cluster.local"))
+					Expect(caCerts).To(ConsistOf(ca.Cert))
+				})
+				It("should not recreate the keystore on later reconciles", func() {
+					keystore := t.getProvidedKeystoreSecret().Data["keystore.p12"]
+					t.reconcileCryostatFully()
+					Expect(t.getProvidedKeystoreSecret().Data["keystore.p12"]).To(Equal(keystore))
+				})
+				It("should configure deployments with the provided secrets", func() {
+					mainVolumes := t.getDeploymentVolumeSecrets(t.Name)
+					Expect(mainVolumes).To(HaveKeyWithValue("keystore", t.Name+"-provided-tls"))
+					Expect(mainVolumes).To(HaveKeyWithValue("agent-proxy-tls-secret", "agent-proxy-tls"))
+					Expect(t.getDeploymentVolumeSecrets(t.Name + "-database")).To(ContainElement("database-tls"))
+					Expect(t.getDeploymentVolumeSecrets(t.Name + "-storage")).To(ContainElement("storage-tls"))
+				})
+				It("should copy the agent certificate to each target namespace", func() {
+					for _, ns := range t.TargetNamespaces {
+						secret := &corev1.Secret{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.GetClusterUniqueNameForAgent(ns), Namespace: ns}, secret)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(secret.Data).To(Equal(t.getSecret("agent-tls").Data))
+					}
+					caSecret := &corev1.Secret{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewCACert().Spec.SecretName, Namespace: otherNS}, caSecret)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caSecret.Data).To(HaveKeyWithValue(corev1.TLSCertKey, ca.CertPEM))
+				})
+				It("should mark agent TLS ready in each target namespace", func() {
+					cr := t.getCryostatInstance()
+					for _, ns := range t.TargetNamespaces {
+						Expect(cr.Status.TargetNamespaceStatuses).To(ContainElement(And(
+							HaveField("Namespace", ns), HaveField("AgentTLSReady", true))))
+					}
+				})
+				It("should report the provided certificates in CR status", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.Certificates).To(ContainElement(And(HaveField("Name", "core-tls"),
+						HaveField("Namespace", t.Namespace), HaveField("Issuer", ca.Cert.Subject.String()))))
+					Expect(cr.Status.Certificates).ToNot(ContainElement(HaveField("Name", t.Name+"-provided-tls")))
+				})
+				It("should emit CertificateExpiring Events", func() {
+					// The provided certificates expire within a day
+					Expect(t.receiveEvents()).To(ContainElement(And(ContainSubstring("CertificateExpiring"),
+						ContainSubstring(t.Namespace+"/core-tls"))))
+				})
+				It("should schedule a reconcile for when the certificates expire", func() {
+					result, err := t.reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically("~", 24*time.Hour, time.Minute))
 				})
 			})
-			Context("ClusterRoleBinding does not exist", func() {
+			Context("with a certificate missing a DNS name", func() {
 				JustBeforeEach(func() {
-					err := t.Client.Delete(context.Background(), t.NewClusterRoleBinding())
+					t.updateProvidedTLSSecret(t.NewProvidedTLSSecret("database-tls", ca, t.Name+"-database"))
+					_, err := t.reconcile()
+					Expect(err).To(HaveOccurred())
+				})
+				It("should set TLSSetupComplete condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
+						"ProvidedCertificateInvalid")
+				})
+				It("should emit a ProvidedCertificateInvalid Event", func() {
+					recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+					var eventMsg string
+					Expect(recorder.Events).To(Receive(&eventMsg))
+					Expect(eventMsg).To(ContainSubstring("ProvidedCertificateInvalid"))
+					Expect(eventMsg).To(ContainSubstring("database-tls"))
+					Expect(eventMsg).To(ContainSubstring(t.Name + "-database." + t.Namespace + ".svc"))
+				})
+				It("should not create deployments", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+			Context("with a certificate issued by another CA", func() {
+				JustBeforeEach(func() {
+					t.updateProvidedTLSSecret(t.NewProvidedTLSSecrets(test.NewTestCA())[2])
+					_, err := t.reconcile()
+					Expect(err).To(HaveOccurred())
+				})
+				It("should set TLSSetupComplete condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
+						"ProvidedCertificateInvalid")
+					cr := t.getCryostatInstance()
+					condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeTLSSetupComplete))
+					Expect(condition.Message).To(ContainSubstring("storage-tls"))
+				})
+			})
+			Context("with a missing secret", func() {
+				JustBeforeEach(func() {
+					err := t.Client.Delete(context.Background(), t.NewProvidedTLSSecrets(ca)[3])
 					Expect(err).ToNot(HaveOccurred())
-					t.reconcileDeletedCryostat()
+					_, err = t.reconcile()
+					Expect(err).To(HaveOccurred())
 				})
-				It("should delete Cryostat", func() {
-					t.expectNoCryostat()
+				It("should set TLSSetupComplete condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
+						"ProvidedCertificateInvalid")
 				})
 			})
-			Context("RoleBinding exists", func() {
+			Context("with an agent certificate missing a target namespace", func() {
 				JustBeforeEach(func() {
-					t.reconcileDeletedCryostat()
+					t.updateProvidedTLSSecret(t.NewProvidedTLSSecret("agent-tls", ca,
+						fmt.Sprintf("*.%s.%s.svc", t.GetAgentServiceName(), t.Namespace)))
+					_, err := t.reconcile()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(otherNS))
 				})
-				It("should delete the RoleBinding", func() {
-					t.checkRoleBindingsDeleted()
+				It("should report the namespace", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.TargetNamespaceStatuses).To(ContainElement(And(
+						HaveField("Namespace", t.Namespace), HaveField("AgentTLSReady", true))))
+					Expect(cr.Status.TargetNamespaceStatuses).To(ContainElement(And(
+						HaveField("Namespace", otherNS), HaveField("AgentTLSReady", false),
+						HaveField("LastError", ContainSubstring("agent-tls")))))
+				})
+				It("should not copy the agent certificate to the namespace", func() {
+					secret := &corev1.Secret{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.GetClusterUniqueNameForAgent(otherNS), Namespace: otherNS}, secret)
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+		Context("with the built-in CA", func() {
+			var otherNS string
+			var result reconcile.Result
+			var cr *model.CryostatInstance
+
+			BeforeEach(func() {
+				otherNS = "other-target"
+				t.TargetNamespaces = []string{t.Namespace, otherNS}
+				cr = t.NewCryostatWithBuiltInCA()
+				t.objs = append(t.objs, t.NewOtherNamespace(otherNS), cr.Object)
+			})
+			JustBeforeEach(func() {
+				// The built-in CA does not require cert-manager
+				t.reconciler.GetConfig().RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+				t.reconcileCryostatUntilRequeueScheduled()
+				var err error
+				result, err = t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+			})
+			It("should set TLSSetupComplete condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+					"AllCertificatesReady")
+			})
+			It("should create a self-signed CA", func() {
+				ca := t.getBuiltInCA()
+				Expect(ca.IsCA).To(BeTrue())
+				Expect(ca.Subject.CommonName).To(Equal(t.NewCACert().Spec.CommonName))
+				Expect(ca.CheckSignatureFrom(ca)).To(Succeed())
+			})
+			It("should issue certificates in place of cert-manager", func() {
+				ca := t.getBuiltInCA()
+				certs := []*certv1.Certificate{t.NewCryostatCert(), t.NewReportsCert(), t.NewDatabaseCert(),
+					t.NewStorageCert(), t.NewAgentProxyCert()}
+				for _, ns := range t.TargetNamespaces {
+					certs = append(certs, t.NewAgentCert(ns))
+				}
+				for _, cert := range certs {
+					secret := t.getSecret(cert.Spec.SecretName)
+					Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+					Expect(metav1.IsControlledBy(secret, t.getCryostatInstance().Object)).To(BeTrue())
+					Expect(secret.Data).To(HaveKeyWithValue("ca.crt", t.getSecret(t.NewCACert().Spec.SecretName).Data[corev1.TLSCertKey]))
+
+					leaf := t.parseSecretCertificate(secret)
+					Expect(leaf.CheckSignatureFrom(ca)).To(Succeed())
+					Expect(leaf.Subject.CommonName).To(Equal(cert.Spec.CommonName))
+					Expect(leaf.DNSNames).To(Equal(cert.Spec.DNSNames))
+				}
+			})
+			It("should create a keystore for the core certificate", func() {
+				secret := t.getSecret(t.Name + "-tls")
+				password := t.getSecret(t.Name + "-keystore").Data["KEYSTORE_PASS"]
+				_, cert, caCerts, err := pkcs12.DecodeChain(secret.Data["keystore.p12"], string(password))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cert.Raw).To(Equal(t.parseSecretCertificate(secret).Raw))
+				Expect(caCerts).To(ConsistOf(t.getBuiltInCA()))
+			})
+			It("should not change the keystore password on later reconciles", func() {
+				secret := t.getSecret(t.Name + "-keystore")
+				t.reconcileCryostatUntilRequeueScheduled()
+				Expect(t.getSecret(t.Name + "-keystore").Data).To(Equal(secret.Data))
+			})
+			It("should not reissue certificates on later reconciles", func() {
+				secret := t.getSecret(t.Name + "-tls")
+				t.reconcileCryostatUntilRequeueScheduled()
+				Expect(t.getSecret(t.Name + "-tls").Data).To(Equal(secret.Data))
+			})
+			It("should schedule certificate renewal", func() {
+				// Certificates are valid for 90 days, and renewed after 60 days
+				Expect(result.RequeueAfter).To(BeNumerically("~", 60*24*time.Hour, time.Hour))
+			})
+			It("should configure deployments with the issued certificates", func() {
+				mainVolumes := t.getDeploymentVolumeSecrets(t.Name)
+				Expect(mainVolumes).To(HaveKeyWithValue("keystore", t.Name+"-tls"))
+				Expect(mainVolumes).To(HaveKeyWithValue("agent-proxy-tls-secret", t.Name+"-agent-tls"))
+				Expect(t.getDeploymentVolumeSecrets(t.Name + "-database")).To(ContainElement(t.Name + "-database-tls"))
+				Expect(t.getDeploymentVolumeSecrets(t.Name + "-storage")).To(ContainElement(t.Name + "-storage-tls"))
+			})
+			It("should copy the agent certificate and CA to each target namespace", func() {
+				secret := &corev1.Secret{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.GetClusterUniqueNameForAgent(otherNS), Namespace: otherNS}, secret)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(secret.Data).To(Equal(t.getSecret(t.GetClusterUniqueNameForAgent(otherNS)).Data))
+
+				caSecret := &corev1.Secret{}
+				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewCACert().Spec.SecretName, Namespace: otherNS}, caSecret)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caSecret.Data).To(HaveKeyWithValue(corev1.TLSCertKey, t.getSecret(t.NewCACert().Spec.SecretName).Data[corev1.TLSCertKey]))
+				Expect(caSecret.Data).ToNot(HaveKey(corev1.TLSPrivateKeyKey))
+			})
+			It("should mark agent TLS ready in each target namespace", func() {
+				cr := t.getCryostatInstance()
+				for _, ns := range t.TargetNamespaces {
+					Expect(cr.Status.TargetNamespaceStatuses).To(ContainElement(And(
+						HaveField("Namespace", ns), HaveField("AgentTLSReady", true))))
+				}
+			})
+			It("should report when each certificate expires in CR status", func() {
+				cr := t.getCryostatInstance()
+				expected := []types.NamespacedName{}
+				for _, name := range []string{t.NewCACert().Spec.SecretName, t.Name + "-tls", t.Name + "-database-tls",
+					t.Name + "-storage-tls", t.Name + "-reports-tls", t.Name + "-agent-tls"} {
+					expected = append(expected, types.NamespacedName{Name: name, Namespace: t.Namespace})
+				}
+				for _, ns := range t.TargetNamespaces {
+					expected = append(expected, types.NamespacedName{Name: t.GetClusterUniqueNameForAgent(ns), Namespace: ns})
+				}
+				Expect(cr.Status.Certificates).To(HaveLen(len(expected)))
+				for i, status := range cr.Status.Certificates {
+					Expect(types.NamespacedName{Name: status.Name, Namespace: status.Namespace}).To(Equal(expected[i]))
+					Expect(time.Until(status.NotAfter.Time)).To(BeNumerically("~", 90*24*time.Hour, time.Hour))
+					Expect(status.Issuer).To(Equal(t.getBuiltInCA().Subject.String()))
+				}
+			})
+			It("should export when each certificate expires as a metric", func() {
+				leaf := t.parseSecretCertificate(t.getSecret(t.Name + "-tls"))
+				Expect(t.getCertificateExpiryMetric(t.Name+"-tls", t.Namespace)).To(Equal(float64(leaf.NotAfter.Unix())))
+			})
+			It("should not emit certificate expiry Events", func() {
+				Expect(t.receiveEvents()).ToNot(ContainElement(ContainSubstring("CertificateExpir")))
+			})
+			It("should set CertificatesExpiring condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeCertificatesExpiring, metav1.ConditionFalse,
+					"CertificatesValid")
+			})
+			Context("with an expiry warning window longer than the time until renewal", func() {
+				BeforeEach(func() {
+					cr.Spec.TLS.ExpiryWarningWindow = &metav1.Duration{Duration: 40 * 24 * time.Hour}
+				})
+				It("should schedule a reconcile for when the certificates enter the warning window", func() {
+					// Certificates are valid for 90 days
+					Expect(result.RequeueAfter).To(BeNumerically("~", 50*24*time.Hour, time.Hour))
+				})
+			})
+			Context("with an expiry warning window longer than the certificate lifetimes", func() {
+				BeforeEach(func() {
+					cr.Spec.TLS.ExpiryWarningWindow = &metav1.Duration{Duration: 100 * 24 * time.Hour}
+				})
+				It("should emit CertificateExpiring Events", func() {
+					Expect(t.receiveEvents()).To(ContainElement(And(ContainSubstring("CertificateExpiring"),
+						ContainSubstring(otherNS+"/"+t.GetClusterUniqueNameForAgent(otherNS)))))
+				})
+				It("should set CertificatesExpiring condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeCertificatesExpiring, metav1.ConditionTrue,
+						"CertificateExpiring")
+					condition := meta.FindStatusCondition(t.getCryostatInstance().Status.Conditions,
+						string(operatorv1beta2.ConditionTypeCertificatesExpiring))
+					Expect(condition.Message).To(ContainSubstring(otherNS + "/" + t.GetClusterUniqueNameForAgent(otherNS)))
+				})
+				It("should not emit the Events again on later reconciles", func() {
+					t.receiveEvents()
+					t.reconcileCryostatUntilRequeueScheduled()
+					Expect(t.receiveEvents()).ToNot(ContainElement(ContainSubstring("CertificateExpiring")))
+				})
+			})
+			Context("when a target namespace is removed", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.TargetNamespaces = []string{t.Namespace}
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatUntilRequeueScheduled()
 				})
-				It("should delete Cryostat", func() {
-					t.expectNoCryostat()
+				It("should stop exporting the expiry of its agent certificate", func() {
+					Expect(t.getCertificateExpiryMetric(t.GetClusterUniqueNameForAgent(otherNS), otherNS)).To(BeZero())
+					Expect(t.getCertificateExpiryMetric(t.GetClusterUniqueNameForAgent(t.Namespace), t.Namespace)).ToNot(BeZero())
+					Expect(t.getCertificateExpiryMetric(t.Name+"-tls", t.Namespace)).ToNot(BeZero())
 				})
 			})
-			Context("RoleBinding does not exist", func() {
+			Context("when the Cryostat is deleted", func() {
 				JustBeforeEach(func() {
-					err := t.Client.Delete(context.Background(), t.NewRoleBinding(t.Namespace))
-					Expect(err).ToNot(HaveOccurred())
 					t.reconcileDeletedCryostat()
 				})
-				It("should delete Cryostat", func() {
-					t.expectNoCryostat()
+				It("should stop exporting certificate expiry metrics", func() {
+					Expect(t.getCertificateExpiryMetric(t.Name+"-tls", t.Namespace)).To(BeZero())
 				})
 			})
-		})
-		Context("on OpenShift", func() {
-			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostat().Object)
-			})
-			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
-			})
-			It("should create ConsoleLink", func() {
-				t.expectConsoleLink()
-			})
-			Context("with an existing ConsoleLink", func() {
-				var oldLink *consolev1.ConsoleLink
-				BeforeEach(func() {
-					oldLink = t.OtherConsoleLink()
-					t.objs = append(t.objs, oldLink)
+			Context("with a certificate issued by another CA", func() {
+				JustBeforeEach(func() {
+					t.updateProvidedTLSSecret(t.NewProvidedTLSSecret(t.Name+"-reports-tls", test.NewTestCA(),
+						t.NewReportsCert().Spec.DNSNames...))
+					t.reconcileCryostatUntilRequeueScheduled()
 				})
-				It("should update the ConsoleLink", func() {
-					link := &consolev1.ConsoleLink{}
-					expectedLink := t.NewConsoleLink()
-					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expectedLink.Name}, link)
-					Expect(err).ToNot(HaveOccurred())
-					// Existing labels and annotations should remain
-					Expect(link.Labels).To(Equal(oldLink.Labels))
-					Expect(link.Annotations).To(Equal(oldLink.Annotations))
-
-					// Check managed spec fields
-					Expect(link.Spec.Link).To(Equal(expectedLink.Spec.Link))
-					Expect(link.Spec.Location).To(Equal(expectedLink.Spec.Location))
-					Expect(link.Spec.NamespaceDashboard).To(Equal(expectedLink.Spec.NamespaceDashboard))
+				It("should reissue the certificate", func() {
+					leaf := t.parseSecretCertificate(t.getSecret(t.Name + "-reports-tls"))
+					Expect(leaf.CheckSignatureFrom(t.getBuiltInCA())).To(Succeed())
 				})
 			})
-			Context("with an existing application url in APIServer AdditionalCORSAllowedOrigins", func() {
-				BeforeEach(func() {
-					t.objs = []ctrlclient.Object{
-						t.NewApiServerWithApplicationURL(),
-						t.NewNamespace(),
+			Context("when the CA is deleted", func() {
+				var oldCA *x509.Certificate
+				var oldCAPEM []byte
+				var oldAnnotations map[string]string
+
+				JustBeforeEach(func() {
+					oldCA = t.getBuiltInCA()
+					oldCAPEM = t.getSecret(t.NewCACert().Spec.SecretName).Data[corev1.TLSCertKey]
+					oldAnnotations = t.getDeploymentTemplateAnnotations(t.Name)
+					err := t.Client.Delete(context.Background(), t.getSecret(t.NewCACert().Spec.SecretName))
+					Expect(err).ToNot(HaveOccurred())
+					t.receiveEvents()
+					// The CA rotation is checked more often than certificates are renewed
+					t.reconcileCryostatUntilRequeueAfter(time.Minute)
+				})
+				It("should reissue all certificates from a new CA", func() {
+					ca := t.getBuiltInCA()
+					Expect(ca.Equal(oldCA)).To(BeFalse())
+					for _, name := range []string{t.Name + "-tls", t.Name + "-reports-tls", t.Name + "-database-tls",
+						t.Name + "-storage-tls", t.Name + "-agent-tls", t.GetClusterUniqueNameForAgent(otherNS)} {
+						leaf := t.parseSecretCertificate(t.getSecret(name))
+						Expect(leaf.CheckSignatureFrom(ca)).To(Succeed())
 					}
 				})
-				It("should remove the application url", func() {
-					apiServer := &configv1.APIServer{}
-					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cluster"}, apiServer)
+				It("should trust both CAs in each target namespace", func() {
+					newCAPEM := t.getSecret(t.NewCACert().Spec.SecretName).Data[corev1.TLSCertKey]
+					secret := &corev1.Secret{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.GetClusterUniqueNameForAgent(otherNS), Namespace: otherNS}, secret)
 					Expect(err).ToNot(HaveOccurred())
-					Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).ToNot(ContainElement(fmt.Sprintf("https://%s\\.example\\.com", t.Name)))
-					Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).To(ContainElement("https://an-existing-user-specified\\.allowed\\.origin\\.com"))
-				})
-			})
-			It("should add the finalizer", func() {
-				t.expectCryostatFinalizerPresent()
-			})
-			Context("with restricted SCC", func() {
-				BeforeEach(func() {
-					t.objs = []ctrlclient.Object{
-						t.NewCryostat().Object, t.NewNamespaceWithSCCSupGroups(), t.NewApiServer(),
-					}
+					Expect(string(secret.Data["ca.crt"])).To(And(HavePrefix(string(bytes.TrimSpace(newCAPEM))),
+						ContainSubstring(string(oldCAPEM))))
+
+					caSecret := &corev1.Secret{}
+					err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewCACert().Spec.SecretName, Namespace: otherNS}, caSecret)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caSecret.Data[corev1.TLSCertKey]).To(Equal(secret.Data["ca.crt"]))
 				})
-				It("should set fsGroup to value derived from namespace", func() {
-					deploy := &appsv1.Deployment{}
-					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+				It("should trust both CAs in the agent proxy", func() {
+					cm := &corev1.ConfigMap{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-agent-proxy", Namespace: t.Namespace}, cm)
 					Expect(err).ToNot(HaveOccurred())
-					sc := deploy.Spec.Template.Spec.SecurityContext
-					Expect(sc).ToNot(BeNil())
-					Expect(sc.FSGroup).ToNot(BeNil())
-					Expect(*sc.FSGroup).To(Equal(int64(1000130000)))
+					Expect(cm.Data["ca-bundle.crt"]).To(ContainSubstring(string(oldCAPEM)))
+					Expect(cm.Data["nginx.conf"]).To(ContainSubstring("ssl_client_certificate /etc/nginx-cryostat/ca-bundle.crt;"))
+				})
+				It("should trust both CAs in Cryostat", func() {
+					newCAPEM := t.getSecret(t.NewCACert().Spec.SecretName).Data[corev1.TLSCertKey]
+					Expect(t.getSecret(t.Name + "-tls").Data["ca.crt"]).To(Equal(newCAPEM))
+					Expect(t.getSecret(t.Name + "-ca-bundle").Data["previous-ca.crt"]).To(Equal(oldCAPEM))
+
+					sources := t.getCoreTruststoreSources()
+					Expect(sources).To(ContainElement(HaveField("Secret", And(
+						HaveField("Name", t.Name+"-tls"),
+						HaveField("Items", ConsistOf(HaveField("Key", "ca.crt"))),
+					))))
+					Expect(sources).To(ContainElement(HaveField("Secret", And(
+						HaveField("Name", t.Name+"-ca-bundle"),
+						HaveField("Items", ConsistOf(HaveField("Key", "previous-ca.crt"))),
+					))))
+				})
+				It("should restart the Cryostat pod", func() {
+					annotations := t.getDeploymentTemplateAnnotations(t.Name)
+					Expect(annotations["io.cryostat/config-map-hash"]).ToNot(Equal(oldAnnotations["io.cryostat/config-map-hash"]))
+				})
+				It("should report the CA rotation in CR status", func() {
+					rotation := t.getCryostatInstance().Status.CARotation
+					Expect(rotation).ToNot(BeNil())
+					Expect(time.Since(rotation.StartTime.Time)).To(BeNumerically("<", time.Minute))
+					Expect(rotation.OverlapEndTime.Sub(rotation.StartTime.Time)).To(Equal(7 * 24 * time.Hour))
+					Expect(rotation.PendingWorkloads).To(BeEmpty())
+				})
+				It("should emit a CARotationStarted Event", func() {
+					Expect(t.receiveEvents()).To(ContainElement(ContainSubstring("CARotationStarted")))
+				})
+				It("should check the CA rotation again later", func() {
+					result, err := t.reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
 				})
-			})
-			Context("when deleted", func() {
-				Context("ConsoleLink exists", func() {
-					JustBeforeEach(func() {
-						t.reconcileDeletedCryostat()
-					})
-					It("should delete the ConsoleLink", func() {
-						link := &consolev1.ConsoleLink{}
-						expectedLink := t.NewConsoleLink()
-						err := t.Client.Get(context.Background(), types.NamespacedName{Name: expectedLink.Name}, link)
-						Expect(kerrors.IsNotFound(err)).To(BeTrue())
-					})
-					It("should remove the application url from APIServer AdditionalCORSAllowedOrigins", func() {
-						apiServer := &configv1.APIServer{}
-						err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cluster"}, apiServer)
-						Expect(err).ToNot(HaveOccurred())
-						Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).ToNot(ContainElement(fmt.Sprintf("https://%s\\.example\\.com", t.Name)))
-						Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).To(ContainElement("https://an-existing-user-specified\\.allowed\\.origin\\.com"))
+				Context("with agents injected before the rotation", func() {
+					BeforeEach(func() {
+						pod := t.NewAgentPod(otherNS)
+						pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+						pod.Labels["pod-template-hash"] = "5d8f6b9c7"
+						pod.OwnerReferences = []metav1.OwnerReference{
+							{
+								APIVersion: "apps/v1",
+								Kind:       "ReplicaSet",
+								Name:       "app-5d8f6b9c7",
+								UID:        "1234",
+								Controller: &[]bool{true}[0],
+							},
+						}
+						t.objs = append(t.objs, pod)
 					})
-					It("should delete Cryostat", func() {
-						t.expectNoCryostat()
+					It("should report the workloads still using the previous CA", func() {
+						rotation := t.getCryostatInstance().Status.CARotation
+						Expect(rotation).ToNot(BeNil())
+						Expect(rotation.PendingWorkloads).To(ConsistOf(operatorv1beta2.CARotationWorkload{
+							Namespace: otherNS,
+							Kind:      "Deployment",
+							Name:      "app",
+							Pods:      1,
+						}))
 					})
 				})
-				Context("ConsoleLink does not exist", func() {
+				Context("after the overlap period", func() {
 					JustBeforeEach(func() {
-						err := t.Client.Delete(context.Background(), t.NewConsoleLink())
+						secret := t.getSecret(t.Name + "-ca-bundle")
+						secret.Annotations["io.cryostat/ca-rotation-start"] = time.Now().Add(-8 * 24 * time.Hour).UTC().Format(time.RFC3339)
+						err := t.Client.Update(context.Background(), secret)
 						Expect(err).ToNot(HaveOccurred())
-						t.reconcileDeletedCryostat()
+						t.receiveEvents()
+						t.reconcileCryostatUntilRequeueScheduled()
 					})
-					It("should delete Cryostat", func() {
-						t.expectNoCryostat()
+					It("should stop trusting the previous CA", func() {
+						newCAPEM := t.getSecret(t.NewCACert().Spec.SecretName).Data[corev1.TLSCertKey]
+						secret := &corev1.Secret{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.GetClusterUniqueNameForAgent(otherNS), Namespace: otherNS}, secret)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(secret.Data["ca.crt"]).To(Equal(newCAPEM))
+
+						cm := &corev1.ConfigMap{}
+						err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-agent-proxy", Namespace: t.Namespace}, cm)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(cm.Data).ToNot(HaveKey("ca-bundle.crt"))
+
+						Expect(t.getCoreTruststoreSources()).ToNot(ContainElement(HaveField("Secret",
+							HaveField("Name", t.Name+"-ca-bundle"))))
+					})
+					It("should remove the CA rotation from CR status", func() {
+						Expect(t.getCryostatInstance().Status.CARotation).To(BeNil())
+					})
+					It("should emit a CARotationCompleted Event", func() {
+						Expect(t.receiveEvents()).To(ContainElement(ContainSubstring("CARotationCompleted")))
 					})
-				})
-			})
-			Context("with Insights enabled", func() {
-				BeforeEach(func() {
-					t.InsightsURL = "http://insights-proxy.foo.svc.cluster.local"
-				})
-				JustBeforeEach(func() {
-					t.reconcileCryostatFully()
-				})
-				It("should create deployment", func() {
-					t.expectMainDeployment()
 				})
 			})
 		})
-		Context("with cert-manager disabled in CR", func() {
+		Context("with the built-in CA and private key and lifetime options", func() {
+			var result reconcile.Result
+			var cr *model.CryostatInstance
+
 			BeforeEach(func() {
-				t.TLS = false
-				t.objs = append(t.objs, t.NewCryostatCertManagerDisabled().Object)
+				cr = t.NewCryostatWithPrivateKey()
+				cr.Spec.TLS.Provider = t.NewCryostatWithBuiltInCA().Spec.TLS.Provider
+				cr.Spec.TLS.Lifetimes = t.NewCertificateLifetimes()
+				// Only warn about certificates that were not renewed when due
+				cr.Spec.TLS.ExpiryWarningWindow = &metav1.Duration{Duration: 24 * time.Hour}
+				t.objs = append(t.objs, cr.Object)
 			})
 			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
-			})
-			It("should create deployment and set owner", func() {
-				t.expectMainDeployment()
-			})
-			It("should not create certificates", func() {
-				certs := &certv1.CertificateList{}
-				err := t.Client.List(context.Background(), certs, &ctrlclient.ListOptions{
-					Namespace: t.Namespace,
-				})
+				t.reconciler.GetConfig().RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+				t.reconcileCryostatUntilRequeueScheduled()
+				var err error
+				result, err = t.reconcile()
 				Expect(err).ToNot(HaveOccurred())
-				Expect(certs.Items).To(BeEmpty())
-			})
-			It("should create routes with edge TLS termination", func() {
-				t.expectRoutes()
-			})
-			It("should create the agent proxy config map", func() {
-				t.expectAgentProxyConfigMap()
-			})
-		})
-		Context("with cert-manager not configured in CR", func() {
-			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostatCertManagerUndefined().Object)
-			})
-			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
 			})
-			It("should create deployment and set owner", func() {
-				t.expectMainDeployment()
+			It("should issue certificates with the requested keys", func() {
+				for _, name := range []string{t.NewCACert().Spec.SecretName, t.Name + "-tls", t.Name + "-reports-tls",
+					t.GetClusterUniqueNameForAgent(t.Namespace)} {
+					cert := t.parseSecretCertificate(t.getSecret(name))
+					key, ok := cert.PublicKey.(*ecdsa.PublicKey)
+					Expect(ok).To(BeTrue())
+					Expect(key.Curve).To(Equal(elliptic.P384()))
+				}
 			})
-			It("should create certificates", func() {
-				t.expectCertificates()
+			It("should issue certificates with the requested lifetimes", func() {
+				lifetime := func(name string) time.Duration {
+					cert := t.parseSecretCertificate(t.getSecret(name))
+					return cert.NotAfter.Sub(cert.NotBefore)
+				}
+				Expect(lifetime(t.NewCACert().Spec.SecretName)).To(Equal(8760 * time.Hour))
+				Expect(lifetime(t.Name + "-tls")).To(Equal(720 * time.Hour))
+				Expect(lifetime(t.GetClusterUniqueNameForAgent(t.Namespace))).To(Equal(360 * time.Hour))
 			})
-			It("should create routes with re-encrypt TLS termination", func() {
-				t.expectRoutes()
+			It("should schedule renewal of the earliest certificate", func() {
+				// The agent certificate is renewed after two thirds of 15 days
+				Expect(result.RequeueAfter).To(BeNumerically("~", 240*time.Hour, time.Hour))
 			})
-			It("should set TLSSetupComplete condition", func() {
-				t.reconcileCryostatFully()
-				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
-					"AllCertificatesReady")
+			Context("when the key algorithm changes", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.TLS.PrivateKey = nil
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatUntilRequeueAfter(time.Minute)
+				})
+				It("should reissue certificates", func() {
+					ca := t.getBuiltInCA()
+					Expect(ca.PublicKey).To(BeAssignableToTypeOf(&rsa.PublicKey{}))
+					cert := t.parseSecretCertificate(t.getSecret(t.Name + "-tls"))
+					Expect(cert.PublicKey).To(BeAssignableToTypeOf(&rsa.PublicKey{}))
+					Expect(cert.CheckSignatureFrom(ca)).To(Succeed())
+				})
 			})
-			It("should create the agent proxy config map", func() {
-				t.expectAgentProxyConfigMap()
+			Context("with a rotation policy of Never", func() {
+				var oldCert *x509.Certificate
+
+				BeforeEach(func() {
+					cr.Spec.TLS.PrivateKey.RotationPolicy = "Never"
+				})
+				JustBeforeEach(func() {
+					oldCert = t.parseSecretCertificate(t.getSecret(t.Name + "-tls"))
+					err := t.Client.Delete(context.Background(), t.getSecret(t.NewCACert().Spec.SecretName))
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostatUntilRequeueAfter(time.Minute)
+				})
+				It("should reuse private keys when reissuing certificates", func() {
+					cert := t.parseSecretCertificate(t.getSecret(t.Name + "-tls"))
+					Expect(cert.CheckSignatureFrom(t.getBuiltInCA())).To(Succeed())
+					Expect(cert.SerialNumber).ToNot(Equal(oldCert.SerialNumber))
+					Expect(cert.PublicKey).To(Equal(oldCert.PublicKey))
+				})
 			})
 		})
-		Context("with DISABLE_SERVICE_TLS=true", func() {
+		Context("with roles", func() {
 			BeforeEach(func() {
-				disableTLS := true
-				t.EnvDisableTLS = &disableTLS
-				t.TLS = false
-				t.objs = append(t.objs, t.NewCryostatCertManagerUndefined().Object)
-			})
-			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
-			})
-			It("should create deployment and set owner", func() {
-				t.expectMainDeployment()
-			})
-			It("should not create certificates", func() {
-				certs := &certv1.CertificateList{}
-				err := t.Client.List(context.Background(), certs, &ctrlclient.ListOptions{
-					Namespace: t.Namespace,
-				})
-				Expect(err).ToNot(HaveOccurred())
-				Expect(certs.Items).To(BeEmpty())
+				t.objs = append(t.objs, t.NewCryostatWithRoles().Object)
 			})
-			It("should create routes with edge TLS termination", func() {
-				t.expectRoutes()
+			It("should fail to configure the authorization server", func() {
+				Eventually(func() error {
+					_, err := t.reconcile()
+					return err
+				}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(MatchError(ContainSubstring("not available on OpenShift")))
 			})
-			It("should set TLSSetupComplete Condition", func() {
-				t.reconcileCryostatFully()
-				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
-					"CertManagerDisabled")
+		})
+		Context("with an OpenID Connect provider", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithOIDC().Object, t.NewOIDCClientSecret(), t.NewOIDCCAConfigMap())
 			})
-			It("should create the agent proxy config map", func() {
-				t.expectAgentProxyConfigMap()
+			It("should fail to configure the auth proxy", func() {
+				Eventually(func() error {
+					_, err := t.reconcile()
+					return err
+				}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(MatchError(ContainSubstring("not available on OpenShift")))
 			})
 		})
-		Context("Disable cert-manager after being enabled", func() {
+		Context("with the service CA", func() {
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostat().Object)
+				t.ServiceCA = test.NewTestCA()
+				t.objs = append(t.objs, t.NewCryostatWithServiceCA().Object)
 			})
 			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
-
-				cryostat := t.getCryostatInstance()
-
-				t.TLS = false
-				certManager := false
-				cryostat.Spec.EnableCertManager = &certManager
-				t.updateCryostatInstance(cryostat)
-
-				t.reconcileCryostatFully()
+				// The service CA does not require cert-manager
+				t.reconciler.GetConfig().RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+				t.reconcileCryostatUntilRequeueScheduled()
 			})
-			It("should update the deployment", func() {
-				t.expectMainDeployment()
+			It("should set TLSSetupComplete condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
+					"AllCertificatesReady")
 			})
-			It("should create routes with edge TLS termination", func() {
-				t.expectRoutes()
+			It("should keep the injected service CA bundle on later reconciles", func() {
+				t.reconcileCryostatUntilRequeueScheduled()
+				cm := &corev1.ConfigMap{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-service-ca", Namespace: t.Namespace}, cm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cm.Annotations).To(HaveKeyWithValue("service.beta.openshift.io/inject-cabundle", "true"))
+				Expect(cm.Data).To(HaveKeyWithValue("service-ca.crt", string(t.ServiceCA.CertPEM)))
 			})
-			It("should set TLSSetupComplete Condition", func() {
-				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
-					"CertManagerDisabled")
+			It("should request serving certificates for services", func() {
+				for _, name := range []string{t.Name, t.Name + "-database", t.Name + "-storage"} {
+					svc := &corev1.Service{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, svc)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(svc.Annotations).To(HaveKeyWithValue("service.beta.openshift.io/serving-cert-secret-name",
+						name+"-serving-cert"))
+				}
 			})
-			It("should create the agent proxy config map", func() {
-				t.expectAgentProxyConfigMap()
+			It("should use the serving certificates for components", func() {
+				for _, name := range []string{t.Name, t.Name + "-database", t.Name + "-storage"} {
+					secret := t.getSecret(name + "-tls")
+					Expect(metav1.IsControlledBy(secret, t.getCryostatInstance().Object)).To(BeTrue())
+					Expect(secret.Data[corev1.TLSCertKey]).To(Equal(t.getSecret(name + "-serving-cert").Data[corev1.TLSCertKey]))
+					Expect(secret.Data[corev1.TLSPrivateKeyKey]).To(Equal(t.getSecret(name + "-serving-cert").Data[corev1.TLSPrivateKeyKey]))
+				}
 			})
-		})
-		Context("Enable cert-manager after being disabled", func() {
-			BeforeEach(func() {
-				t.TLS = false
-				t.objs = append(t.objs, t.NewCryostatCertManagerDisabled().Object)
+			It("should trust the service CA for components other than Cryostat", func() {
+				for _, name := range []string{t.Name + "-database", t.Name + "-storage"} {
+					Expect(t.getSecret(name + "-tls").Data).To(HaveKeyWithValue("ca.crt", t.ServiceCA.CertPEM))
+				}
+				Expect(t.getSecret(t.Name + "-tls").Data).To(HaveKeyWithValue("ca.crt",
+					t.getSecret(t.NewCACert().Spec.SecretName).Data[corev1.TLSCertKey]))
 			})
-			JustBeforeEach(func() {
-				t.reconcileCryostatFully()
-
-				cryostat := t.getCryostatInstance()
-
-				t.TLS = true
-				certManager := true
-				cryostat.Spec.EnableCertManager = &certManager
-				t.updateCryostatInstance(cryostat)
-
-				t.reconcileCryostatFully()
+			It("should create a keystore for the core certificate", func() {
+				secret := t.getSecret(t.Name + "-tls")
+				password := t.getSecret(t.Name + "-keystore").Data["KEYSTORE_PASS"]
+				_, cert, _, err := pkcs12.DecodeChain(secret.Data["keystore.p12"], string(password))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cert.CheckSignatureFrom(t.ServiceCA.Cert)).To(Succeed())
 			})
-			It("should update the deployment", func() {
-				t.expectMainDeployment()
+			It("should not recreate the keystore on later reconciles", func() {
+				secret := t.getSecret(t.Name + "-tls")
+				t.reconcileCryostatUntilRequeueScheduled()
+				Expect(t.getSecret(t.Name + "-tls").Data).To(Equal(secret.Data))
 			})
-			It("should create certificates", func() {
-				t.expectCertificates()
+			It("should issue agent certificates using the built-in CA", func() {
+				ca := t.getBuiltInCA()
+				for _, name := range []string{t.Name + "-agent-tls", t.GetClusterUniqueNameForAgent(t.Namespace)} {
+					Expect(t.parseSecretCertificate(t.getSecret(name)).CheckSignatureFrom(ca)).To(Succeed())
+				}
 			})
-			It("should create routes with re-encrypt TLS termination", func() {
-				t.expectRoutes()
+			It("should issue the reports certificate using the built-in CA without a reports service", func() {
+				cert := t.parseSecretCertificate(t.getSecret(t.Name + "-reports-tls"))
+				Expect(cert.CheckSignatureFrom(t.getBuiltInCA())).To(Succeed())
 			})
-			It("should set TLSSetupComplete condition", func() {
-				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
-					"AllCertificatesReady")
+			It("should add the service CA to Cryostat's truststore", func() {
+				deployment := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deployment)
+				Expect(err).ToNot(HaveOccurred())
+				var sources []corev1.VolumeProjection
+				for _, volume := range deployment.Spec.Template.Spec.Volumes {
+					if volume.Name == "cert-secrets" {
+						sources = volume.Projected.Sources
+					}
+				}
+				Expect(sources).To(ContainElement(HaveField("ConfigMap.LocalObjectReference.Name", t.Name+"-service-ca")))
 			})
-			It("should create the agent proxy config map", func() {
-				t.expectAgentProxyConfigMap()
+			It("should trust the service CA for the route", func() {
+				route := &openshiftv1.Route{}
+				expected := t.NewCoreRoute()
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, route)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(route.Spec.TLS.DestinationCACertificate).To(Equal(string(t.ServiceCA.CertPEM)))
 			})
 		})
-		Context("cert-manager missing", func() {
+		Context("with TLS security profiles", func() {
+			var fips bool
+
+			BeforeEach(func() {
+				fips = false
+			})
 			JustBeforeEach(func() {
-				// Replace with an empty RESTMapper
-				t.reconciler.GetConfig().RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+				t.reconciler.GetConfig().FIPSEnabled = fips
+				t.reconcileCryostatFully()
 			})
-			Context("and enabled", func() {
+			Context("with a profile in the CR", func() {
 				BeforeEach(func() {
-					t.objs = append(t.objs, t.NewCryostat().Object)
-				})
-				JustBeforeEach(func() {
-					_, err := t.reconcile()
-					Expect(err).To(HaveOccurred())
+					t.objs = []ctrlclient.Object{
+						t.NewApiServerWithTLSSecurityProfile(configv1.TLSProfileOldType),
+						t.NewNamespace(),
+						t.NewCryostatWithTLSSecurityProfile(configv1.TLSProfileModernType).Object,
+					}
 				})
-				It("should emit a CertManagerUnavailable Event", func() {
-					recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
-					var eventMsg string
-					Expect(recorder.Events).To(Receive(&eventMsg))
-					Expect(eventMsg).To(ContainSubstring("CertManagerUnavailable"))
+				It("should only allow TLS 1.3 in the agent proxy", func() {
+					nginxConf := t.getAgentProxyNginxConf()
+					Expect(nginxConf).To(ContainSubstring("ssl_protocols TLSv1.3;"))
+					Expect(nginxConf).ToNot(ContainSubstring("ssl_ciphers"))
+					Expect(nginxConf).To(ContainSubstring("ssl_conf_command Ciphersuites TLS_AES_128_GCM_SHA256:TLS_AES_256_GCM_SHA384:TLS_CHACHA20_POLY1305_SHA256;"))
 				})
-				It("should set TLSSetupComplete Condition", func() {
-					t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
-						"CertManagerUnavailable")
+				It("should only allow TLS 1.3 in the OAuth proxy", func() {
+					container := t.getAuthProxyContainer()
+					Expect(container.Args).To(ContainElement("--tls-min-version=VersionTLS13"))
+					Expect(container.Args).ToNot(ContainElement(HavePrefix("--tls-cipher-suite=")))
 				})
 			})
-			Context("and disabled", func() {
+			Context("with a cluster-wide profile", func() {
 				BeforeEach(func() {
-					t.TLS = false
-					t.objs = append(t.objs, t.NewCryostatCertManagerDisabled().Object)
+					t.objs = []ctrlclient.Object{
+						t.NewApiServerWithTLSSecurityProfile(configv1.TLSProfileOldType),
+						t.NewNamespace(),
+						t.NewCryostat().Object,
+					}
 				})
-				JustBeforeEach(func() {
-					_, err := t.reconcile()
-					Expect(err).ToNot(HaveOccurred())
+				It("should use the cluster-wide profile in the agent proxy", func() {
+					nginxConf := t.getAgentProxyNginxConf()
+					Expect(nginxConf).To(ContainSubstring("ssl_protocols TLSv1 TLSv1.1 TLSv1.2 TLSv1.3;"))
+					Expect(nginxConf).To(ContainSubstring(":AES128-SHA:"))
+					Expect(nginxConf).To(ContainSubstring(":DES-CBC3-SHA;"))
 				})
-				It("should not emit a CertManagerUnavailable Event", func() {
-					recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
-					Expect(recorder.Events).ToNot(Receive())
+				It("should use the cluster-wide profile in the OAuth proxy", func() {
+					container := t.getAuthProxyContainer()
+					Expect(container.Args).To(ContainElement("--tls-min-version=VersionTLS10"))
+					Expect(container.Args).To(ContainElement("--tls-cipher-suite=TLS_RSA_WITH_AES_128_CBC_SHA"))
+					Expect(container.Args).To(ContainElement("--tls-cipher-suite=TLS_RSA_WITH_3DES_EDE_CBC_SHA"))
 				})
-				It("should set TLSSetupComplete Condition", func() {
-					t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
-						"CertManagerDisabled")
+				Context("in FIPS mode", func() {
+					BeforeEach(func() {
+						fips = true
+					})
+					It("should only allow FIPS-approved ciphers in the agent proxy", func() {
+						nginxConf := t.getAgentProxyNginxConf()
+						Expect(nginxConf).To(ContainSubstring("ssl_protocols TLSv1.2 TLSv1.3;"))
+						Expect(nginxConf).To(ContainSubstring("ssl_ciphers ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384:DHE-RSA-AES128-GCM-SHA256:DHE-RSA-AES256-GCM-SHA384:ECDHE-ECDSA-AES128-SHA256:ECDHE-RSA-AES128-SHA256:ECDHE-ECDSA-AES256-SHA384:ECDHE-RSA-AES256-SHA384;"))
+						Expect(nginxConf).To(ContainSubstring("ssl_conf_command Ciphersuites TLS_AES_128_GCM_SHA256:TLS_AES_256_GCM_SHA384;"))
+					})
 				})
 			})
 		})
@@ -2233,6 +4155,51 @@ func (c *controllerTest) commonTests() {
 				t.expectMainDeployment()
 			})
 		})
+		Context("with pod template overrides", func() {
+			var deployment *appsv1.Deployment
+
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithPodTemplateOverrides().Object, t.NewCustomCAConfigMap())
+				deployment = &appsv1.Deployment{}
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			Context("in the core pod", func() {
+				JustBeforeEach(func() {
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deployment)
+					Expect(err).ToNot(HaveOccurred())
+				})
+				It("should merge overrides into the core container", func() {
+					coreContainer := deployment.Spec.Template.Spec.Containers[0]
+					Expect(coreContainer.Name).To(Equal(t.Name))
+					Expect(coreContainer.Env).To(ContainElement(corev1.EnvVar{Name: "JAVA_OPTS_APPEND", Value: "-XX:+UseG1GC"}))
+					Expect(coreContainer.Ports).To(ConsistOf(t.NewCorePorts()))
+					Expect(coreContainer.VolumeMounts).To(ConsistOf(t.NewCoreVolumeMounts()))
+				})
+				It("should add sidecar containers", func() {
+					Expect(deployment.Spec.Template.Spec.Containers).To(ContainElement(t.NewLogShipperContainer()))
+				})
+				It("should add volumes", func() {
+					Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(t.NewCustomCAVolume()))
+					Expect(deployment.Spec.Template.Spec.Containers).To(ContainElement(SatisfyAll(
+						HaveField("Name", t.Name+"-grafana"),
+						HaveField("VolumeMounts", ContainElement(corev1.VolumeMount{
+							Name: "custom-ca", MountPath: "/etc/custom-ca", ReadOnly: true,
+						})),
+					)))
+				})
+			})
+			It("should add init containers to the database pod", func() {
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, deployment)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deployment.Spec.Template.Spec.InitContainers).To(ConsistOf(HaveField("Name", "init-db")))
+				Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+			})
+			It("should not modify the storage pod", func() {
+				t.expectStorageDeployment()
+			})
+		})
 		Context("with Agent options", func() {
 			Context("with hostname verification disabled", func() {
 				BeforeEach(func() {
@@ -2267,9 +4234,8 @@ func (c *controllerTest) commonTests() {
 			})
 
 			JustBeforeEach(func() {
-				c.commonJustBeforeEach(otherInput)
-
-				// Controllers need to share client to have shared view of objects
+				// Controllers need to share client to have shared view of objects,
+				// which have already been created for both inputs
 				otherInput.Client = t.Client
 				config := otherInput.newReconcilerConfig(otherInput.Client.Scheme(), otherInput.Client)
 				reconciler, err := c.constructorFunc(config)
@@ -2280,10 +4246,6 @@ func (c *controllerTest) commonTests() {
 				otherInput.reconcileCryostatFully()
 			})
 
-			JustAfterEach(func() {
-				c.commonJustAfterEach(otherInput)
-			})
-
 			Context("installing into its target namespace", func() {
 				BeforeEach(func() {
 					// Set up the CRs so the Cryostat's namespace conflicts with the target
@@ -2346,7 +4308,8 @@ func (c *controllerTest) commonTests() {
 		Context("with a modified CA certificate", func() {
 			var oldCerts []*certv1.Certificate
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostat().Object, t.OtherCAIssuer())
+				t.objs = append(t.objs, t.NewCryostat().Object)
+				t.legacyObjs = append(t.legacyObjs, t.OtherCAIssuer())
 				oldCerts = []*certv1.Certificate{
 					t.NewCryostatCert(),
 					t.NewReportsCert(),
@@ -2355,7 +4318,7 @@ func (c *controllerTest) commonTests() {
 				// the annotation is gone.
 				for i, cert := range oldCerts {
 					metav1.SetMetaDataAnnotation(&oldCerts[i].ObjectMeta, "bad", "cert")
-					t.objs = append(t.objs, cert)
+					t.legacyObjs = append(t.legacyObjs, cert)
 				}
 			})
 			JustBeforeEach(func() {
@@ -2376,7 +4339,8 @@ func (c *controllerTest) commonTests() {
 		Context("with modified certificates", func() {
 			var oldCerts []*certv1.Certificate
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostat().Object, t.OtherCAIssuer())
+				t.objs = append(t.objs, t.NewCryostat().Object)
+				t.legacyObjs = append(t.legacyObjs, t.OtherCAIssuer())
 				oldCerts = []*certv1.Certificate{
 					t.OtherCACert(),
 					t.OtherAgentProxyCert(),
@@ -2387,7 +4351,7 @@ func (c *controllerTest) commonTests() {
 				// the annotation is gone.
 				for i, cert := range oldCerts {
 					metav1.SetMetaDataAnnotation(&oldCerts[i].ObjectMeta, "bad", "cert")
-					t.objs = append(t.objs, cert)
+					t.legacyObjs = append(t.legacyObjs, cert)
 				}
 			})
 			JustBeforeEach(func() {
@@ -2413,9 +4377,10 @@ func (c *controllerTest) commonTests() {
 					t.NewReportsCert(),
 					t.NewAgentProxyCert(),
 				}
-				t.objs = append(t.objs, t.NewCryostat().Object, t.OtherCAIssuer())
+				t.objs = append(t.objs, t.NewCryostat().Object)
+				t.legacyObjs = append(t.legacyObjs, t.OtherCAIssuer())
 				for _, cert := range oldCerts {
-					t.objs = append(t.objs, cert)
+					t.legacyObjs = append(t.legacyObjs, cert)
 				}
 			})
 			JustBeforeEach(func() {
@@ -2459,89 +4424,399 @@ func (c *controllerTest) commonTests() {
 				t.reconcileCryostatFully()
 			})
 
-			It("should create legacy certificate", func() {
-				expected := t.NewCryostatCert()
-				expected.Spec.Keystores.PKCS12.Profile = ""
-
-				actual := &certv1.Certificate{}
-				err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, actual)
+			It("should create legacy certificate", func() {
+				expected := t.NewCryostatCert()
+				expected.Spec.Keystores.PKCS12.Profile = ""
+
+				actual := &certv1.Certificate{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, actual)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(actual.Spec).To(Equal(expected.Spec))
+			})
+		})
+
+		Context("with an expired certificate that is not ready", func() {
+			var secret *corev1.Secret
+
+			BeforeEach(func() {
+				// The issuer failed to renew the certificate
+				cert := t.NewReportsCert()
+				cert.Status.Conditions = []certv1.CertificateCondition{
+					{
+						Type:   certv1.CertificateConditionReady,
+						Status: certMeta.ConditionFalse,
+						Reason: "Failed",
+					},
+				}
+				secret = t.NewExpiredTLSSecret(cert.Spec.SecretName, test.NewTestCA(), cert.Spec.DNSNames...)
+				t.objs = append(t.objs, t.NewCryostat().Object, cert, secret)
+			})
+
+			JustBeforeEach(func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).ToNot(BeZero())
+			})
+
+			It("should wait for the certificate", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionFalse,
+					"WaitingForCertificate")
+			})
+			It("should report when the certificate expired in CR status", func() {
+				leaf := t.parseSecretCertificate(secret)
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.Certificates).To(ContainElement(SatisfyAll(
+					HaveField("Name", secret.Name),
+					HaveField("Namespace", secret.Namespace),
+					HaveField("NotAfter.Time", BeTemporally("==", leaf.NotAfter)),
+				)))
+			})
+			It("should export when the certificate expired as a metric", func() {
+				leaf := t.parseSecretCertificate(secret)
+				Expect(t.getCertificateExpiryMetric(secret.Name, secret.Namespace)).To(Equal(float64(leaf.NotAfter.Unix())))
+			})
+			It("should emit a CertificateExpired Event", func() {
+				Expect(t.receiveEvents()).To(ContainElement(And(ContainSubstring("Warning CertificateExpired"),
+					ContainSubstring(secret.Namespace+"/"+secret.Name))))
+			})
+			It("should set CertificatesExpiring condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeCertificatesExpiring, metav1.ConditionTrue,
+					"CertificateExpired")
+			})
+		})
+
+		Context("with an already owned certificate secret", func() {
+			var secret *corev1.Secret
+			var owner ctrlclient.Object
+
+			JustBeforeEach(func() {
+				err := controllerutil.SetControllerReference(owner, secret, test.NewTestScheme())
+				Expect(err).ToNot(HaveOccurred())
+				err = t.Client.Update(context.Background(), secret)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Context("owned by Cryostat CR", func() {
+				BeforeEach(func() {
+					cr := t.NewCryostat().Object
+					cert := t.NewCryostatCert()
+					secret = t.NewCertSecret(cert)
+					owner = cr
+					t.objs = append(t.objs, cr, cert, secret)
+				})
+
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+
+				It("should replace the owner reference", func() {
+					t.expectCertificates()
+				})
+			})
+
+			Context("owned by Certificate", func() {
+				BeforeEach(func() {
+					cert := t.NewCryostatCert()
+					secret = t.NewCertSecret(cert)
+					owner = cert
+					t.objs = append(t.objs, t.NewCryostat().Object, cert, secret)
+				})
+
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+
+				It("should do nothing", func() {
+					t.expectCertificates()
+				})
+			})
+
+			Context("owned by a different Certificate", func() {
+				BeforeEach(func() {
+					cert := t.NewCryostatCert()
+					otherCert := t.NewReportsCert()
+					secret = t.NewCertSecret(cert)
+					owner = otherCert
+					t.objs = append(t.objs, t.NewCryostat().Object, cert, otherCert, secret)
+				})
+
+				It("should fail", func() {
+					Eventually(func() error {
+						result, err := t.reconcile()
+						if err != nil {
+							return err
+						}
+						// Fail if it reconciles successfully
+						Expect(result).ToNot(Equal(reconcile.Result{}))
+						return nil
+					}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(BeAssignableToTypeOf(&controllerutil.AlreadyOwnedError{}))
+				})
+			})
+		})
+
+		Context("when a component fails to reconcile", func() {
+			var reconcileErr error
+			var origClient ctrlclient.Client
+
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+
+				// Remove some resources so that they must be recreated
+				for _, obj := range []ctrlclient.Object{
+					t.NewStorageService(),
+					&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: t.Name, Namespace: t.Namespace}},
+					&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: t.Name + "-database", Namespace: t.Namespace}},
+				} {
+					err := t.Client.Delete(context.Background(), obj)
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				// Fail to recreate the storage service
+				svc := t.NewStorageService()
+				forbiddenErr := kerrors.NewForbidden(schema.GroupResource{Resource: "services"}, svc.Name,
+					errors.New("exceeded quota"))
+				origClient = t.reconciler.GetConfig().Client
+				t.reconciler.GetConfig().Client = test.NewClientWithCreateError(origClient, forbiddenErr, func(obj ctrlclient.Object) bool {
+					return test.FailOnMatch(obj, svc, t.Client.Scheme())
+				})
+
+				_, reconcileErr = t.reconcile()
+			})
+
+			It("should return an error", func() {
+				Expect(reconcileErr).To(HaveOccurred())
+				Expect(reconcileErr.Error()).To(ContainSubstring("exceeded quota"))
+			})
+
+			It("should set the StorageReconcileFailed condition", func() {
+				cr := t.getCryostatInstance()
+				condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeStorageReconcileFailed))
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("ReconcileFailed"))
+				Expect(condition.Message).To(ContainSubstring("exceeded quota"))
+			})
+
+			It("should emit a StorageReconcileFailed event", func() {
+				recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+				Expect(recorder.Events).To(Receive(ContainSubstring("Warning StorageReconcileFailed")))
+			})
+
+			It("should set the Ready condition to false", func() {
+				cr := t.getCryostatInstance()
+				condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeReady))
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.Reason).To(Equal("ReconcileFailed"))
+				Expect(condition.Message).To(ContainSubstring("StorageReconcileFailed"))
+				Expect(meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeStalled))).To(BeNil())
+			})
+
+			It("should continue reconciling independent components", func() {
+				t.expectDatabaseDeployment()
+			})
+
+			It("should continue reconciling the core component", func() {
+				t.expectMainDeployment()
+				cr := t.getCryostatInstance()
+				Expect(meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeCoreReconcileFailed))).To(BeNil())
+			})
+
+			It("should not report dependent components as failed", func() {
+				cr := t.getCryostatInstance()
+				Expect(meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeCredentialsReconcileFailed))).To(BeNil())
+				Expect(meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeDatabaseBackupReconcileFailed))).To(BeNil())
+			})
+
+			Context("when the component recovers", func() {
+				JustBeforeEach(func() {
+					t.reconciler.GetConfig().Client = origClient
+					t.reconcileCryostatFully()
+				})
+
+				It("should remove the StorageReconcileFailed condition", func() {
+					cr := t.getCryostatInstance()
+					Expect(meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeStorageReconcileFailed))).To(BeNil())
+				})
+
+				It("should reconcile dependent components", func() {
+					t.expectMainDeployment()
+				})
+			})
+		})
+
+		Context("when reconciliation is paused", func() {
+			var result reconcile.Result
+			var coreImage string
+
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+
+				// Manually modify the main deployment
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				coreImage = deploy.Spec.Template.Spec.Containers[0].Image
+				deploy.Spec.Template.Spec.Containers[0].Image = "example.com/patched:latest"
+				deploy.Spec.Template.Spec.Containers = append(deploy.Spec.Template.Spec.Containers, corev1.Container{
+					Name:  "debug",
+					Image: "example.com/debug:latest",
+				})
+				err = t.Client.Update(context.Background(), deploy, ctrlclient.FieldOwner("kubectl-edit"))
+				Expect(err).ToNot(HaveOccurred())
+				deploy.Status.Conditions = []appsv1.DeploymentCondition{
+					{
+						Type:    appsv1.DeploymentAvailable,
+						Status:  corev1.ConditionTrue,
+						Reason:  "MinimumReplicasAvailable",
+						Message: "Deployment has minimum availability.",
+					},
+				}
+				err = t.Client.Status().Update(context.Background(), deploy)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Pause reconciliation
+				cr := t.getCryostatInstance()
+				cr.Object.SetAnnotations(map[string]string{
+					"operator.cryostat.io/reconcile-paused": "true",
+				})
+				t.updateCryostatInstance(cr)
+
+				result, err = t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(Equal(reconcile.Result{}))
+			})
+
+			It("should not revert the modified deployment", func() {
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
 				Expect(err).ToNot(HaveOccurred())
+				Expect(deploy.Spec.Template.Spec.Containers).To(ContainElement(HaveField("Name", "debug")))
+				Expect(deploy.Spec.Template.Spec.Containers[0].Image).To(Equal("example.com/patched:latest"))
+			})
 
-				Expect(actual.Spec).To(Equal(expected.Spec))
+			It("should set the ReconcilePaused condition", func() {
+				cr := t.getCryostatInstance()
+				condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeReconcilePaused))
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("PausedByAnnotation"))
 			})
-		})
 
-		Context("with an already owned certificate secret", func() {
-			var secret *corev1.Secret
-			var owner ctrlclient.Object
+			It("should continue to update deployment conditions", func() {
+				cr := t.getCryostatInstance()
+				condition := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeMainDeploymentAvailable))
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("MinimumReplicasAvailable"))
+			})
 
-			JustBeforeEach(func() {
-				err := controllerutil.SetControllerReference(owner, secret, test.NewTestScheme())
-				Expect(err).ToNot(HaveOccurred())
-				err = t.Client.Update(context.Background(), secret)
-				Expect(err).ToNot(HaveOccurred())
+			It("should emit a ReconcilePaused event", func() {
+				recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("Normal ReconcilePaused")))
 			})
 
-			Context("owned by Cryostat CR", func() {
-				BeforeEach(func() {
-					cr := t.NewCryostat().Object
-					cert := t.NewCryostatCert()
-					secret = t.NewCertSecret(cert)
-					owner = cr
-					t.objs = append(t.objs, cr, cert, secret)
+			Context("when deleted", func() {
+				JustBeforeEach(func() {
+					t.reconcileDeletedCryostat()
 				})
 
+				It("should delete Cryostat", func() {
+					t.expectNoCryostat()
+				})
+			})
+
+			Context("when resumed", func() {
 				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					delete(cr.Object.GetAnnotations(), "operator.cryostat.io/reconcile-paused")
+					t.updateCryostatInstance(cr)
 					t.reconcileCryostatFully()
 				})
 
-				It("should replace the owner reference", func() {
-					t.expectCertificates()
+				It("should remove the ReconcilePaused condition", func() {
+					cr := t.getCryostatInstance()
+					Expect(meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeReconcilePaused))).To(BeNil())
 				})
-			})
 
-			Context("owned by Certificate", func() {
-				BeforeEach(func() {
-					cert := t.NewCryostatCert()
-					secret = t.NewCertSecret(cert)
-					owner = cert
-					t.objs = append(t.objs, t.NewCryostat().Object, cert, secret)
+				It("should emit a ReconcileResumed event", func() {
+					recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+					Eventually(recorder.Events).Should(Receive(ContainSubstring("Normal ReconcileResumed")))
 				})
 
-				JustBeforeEach(func() {
-					t.reconcileCryostatFully()
+				It("should restore the fields set by the operator", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deploy.Spec.Template.Spec.Containers[0].Image).To(Equal(coreImage))
 				})
 
-				It("should do nothing", func() {
-					t.expectCertificates()
+				It("should keep the container added by another field manager", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deploy.Spec.Template.Spec.Containers).To(ContainElement(HaveField("Name", "debug")))
 				})
 			})
 
-			Context("owned by a different Certificate", func() {
-				BeforeEach(func() {
-					cert := t.NewCryostatCert()
-					otherCert := t.NewReportsCert()
-					secret = t.NewCertSecret(cert)
-					owner = otherCert
-					t.objs = append(t.objs, t.NewCryostat().Object, cert, otherCert, secret)
+			Context("when resumed and the status cannot be updated", func() {
+				var origClient ctrlclient.Client
+
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					delete(cr.Object.GetAnnotations(), "operator.cryostat.io/reconcile-paused")
+					t.updateCryostatInstance(cr)
+					t.receiveEvents()
+
+					origClient = t.reconciler.GetConfig().Client
+					statusErr := kerrors.NewServiceUnavailable("test error")
+					t.reconciler.GetConfig().Client = test.NewClientWithStatusUpdateError(origClient, statusErr,
+						func(obj ctrlclient.Object) bool {
+							return test.FailOnMatch(obj, cr.Object, t.Client.Scheme())
+						})
+					_, err := t.reconcile()
+					Expect(err).To(HaveOccurred())
 				})
 
-				It("should fail", func() {
-					Eventually(func() error {
-						result, err := t.reconcile()
-						if err != nil {
-							return err
-						}
-						// Fail if it reconciles successfully
-						Expect(result).ToNot(Equal(reconcile.Result{}))
-						return nil
-					}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(BeAssignableToTypeOf(&controllerutil.AlreadyOwnedError{}))
+				It("should not emit a ReconcileResumed event", func() {
+					Expect(t.receiveEvents()).ToNot(ContainElement(ContainSubstring("ReconcileResumed")))
+				})
+
+				It("should keep the ReconcilePaused condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeReconcilePaused, metav1.ConditionTrue,
+						"PausedByAnnotation")
+				})
+
+				Context("once the status can be updated", func() {
+					JustBeforeEach(func() {
+						t.reconciler.GetConfig().Client = origClient
+						t.reconcileCryostatFully()
+					})
+
+					It("should emit a single ReconcileResumed event", func() {
+						var resumed []string
+						Expect(t.receiveEvents()).To(ContainElement(ContainSubstring("Normal ReconcileResumed"), &resumed))
+						Expect(resumed).To(HaveLen(1))
+					})
 				})
 			})
 		})
 
 		Context("reconciling a multi-namespace request", func() {
 			targetNamespaces := []string{"multi-test-one", "multi-test-two"}
+			untargetedNamespace := "multi-test-untargeted"
 
 			BeforeEach(func() {
 				// Create Namespaces
@@ -2576,6 +4851,10 @@ func (c *controllerTest) commonTests() {
 					t.expectTargetNamespaces()
 				})
 
+				It("should update the target namespace statuses", func() {
+					t.expectTargetNamespaceStatuses(nil)
+				})
+
 				Context("when deleted", func() {
 					Context("RoleBindings exist", func() {
 						JustBeforeEach(func() {
@@ -2638,6 +4917,69 @@ func (c *controllerTest) commonTests() {
 				})
 			})
 
+			Context("with agent pods in a target namespace", func() {
+				BeforeEach(func() {
+					t.TargetNamespaces = targetNamespaces
+					t.objs = append(t.objs, t.NewCryostat().Object, t.NewAgentPod(targetNamespaces[0]))
+				})
+
+				It("should update the target namespace statuses", func() {
+					t.expectTargetNamespaceStatuses(map[string]int32{targetNamespaces[0]: 1})
+				})
+			})
+
+			Context("when a target namespace fails", func() {
+				var reconcileErr error
+
+				BeforeEach(func() {
+					t.TargetNamespaces = targetNamespaces
+					t.objs = append(t.objs, t.NewCryostat().Object)
+				})
+
+				JustBeforeEach(func() {
+					// Fail to recreate the RoleBinding in the second namespace
+					binding := t.NewRoleBinding(targetNamespaces[1])
+					err := t.Client.Delete(context.Background(), binding)
+					Expect(err).ToNot(HaveOccurred())
+					forbiddenErr := kerrors.NewForbidden(schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "rolebindings"},
+						binding.Name, errors.New("namespace is terminating"))
+					origClient := t.reconciler.GetConfig().Client
+					t.reconciler.GetConfig().Client = test.NewClientWithCreateError(origClient, forbiddenErr, func(obj ctrlclient.Object) bool {
+						return test.FailOnMatch(obj, binding, t.Client.Scheme())
+					})
+
+					_, reconcileErr = t.reconcile()
+				})
+
+				It("should return an error", func() {
+					Expect(reconcileErr).To(HaveOccurred())
+					Expect(reconcileErr.Error()).To(ContainSubstring(targetNamespaces[1]))
+				})
+
+				It("should report the failure in the namespace status", func() {
+					cr := t.getCryostatInstance()
+					status := findTargetNamespaceStatus(cr.Status.TargetNamespaceStatuses, targetNamespaces[1])
+					Expect(status).ToNot(BeNil())
+					Expect(status.RBACReady).To(BeFalse())
+					Expect(status.LastError).To(ContainSubstring("namespace is terminating"))
+					// Other resources in the namespace are still reconciled
+					Expect(status.AgentTLSReady).To(BeTrue())
+					Expect(status.CallbackServiceReady).To(BeTrue())
+				})
+
+				It("should not affect other namespaces", func() {
+					cr := t.getCryostatInstance()
+					status := findTargetNamespaceStatus(cr.Status.TargetNamespaceStatuses, targetNamespaces[0])
+					Expect(status).ToNot(BeNil())
+					Expect(status.RBACReady).To(BeTrue())
+					Expect(status.LastError).To(BeEmpty())
+				})
+
+				It("should continue reconciling the main deployment", func() {
+					t.expectMainDeployment()
+				})
+			})
+
 			Context("with removed target namespaces", func() {
 				BeforeEach(func() {
 					t.TargetNamespaces = targetNamespaces
@@ -2651,60 +4993,305 @@ func (c *controllerTest) commonTests() {
 					cr.Spec.TargetNamespaces = t.TargetNamespaces
 					t.updateCryostatInstance(cr)
 
-					// Reconcile again
-					t.reconcileCryostatFully()
+					// Reconcile again
+					t.reconcileCryostatFully()
+				})
+				It("should create the expected main deployment", func() {
+					t.expectMainDeployment()
+				})
+				It("leave RBAC for the first namespace", func() {
+					t.expectRBAC()
+				})
+				It("should remove RBAC from the second namespace", func() {
+					binding := t.NewRoleBinding(targetNamespaces[1])
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}, binding)
+					Expect(err).To(HaveOccurred())
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("leave certificate secrets for the first namespace", func() {
+					t.expectCertificates()
+				})
+				It("should remove CA cert secret from the second namespace", func() {
+					secret := t.NewCACertSecret(targetNamespaces[1])
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret)
+					Expect(err).To(HaveOccurred())
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("should remove agent certificate for the second namespace", func() {
+					cert := t.NewAgentCert(targetNamespaces[1])
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: cert.Name, Namespace: cert.Namespace}, cert)
+					Expect(err).To(HaveOccurred())
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("should remove agent cert secret for the second namespace", func() {
+					secret := t.NewAgentCertSecret(targetNamespaces[1])
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret)
+					Expect(err).To(HaveOccurred())
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("should remove agent cert secret copy from the second namespace", func() {
+					secret := t.NewAgentCertSecretCopy(targetNamespaces[1])
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret)
+					Expect(err).To(HaveOccurred())
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("should update the target namespaces in Status", func() {
+					t.expectTargetNamespaces()
+				})
+			})
+
+			Context("with no target namespaces", func() {
+				BeforeEach(func() {
+					t.TargetNamespaces = nil
+					t.objs = append(t.objs, t.NewCryostat().Object)
+				})
+				It("should update the target namespaces in Status", func() {
+					t.expectTargetNamespaces()
+				})
+			})
+
+			Context("with a target namespace selector", func() {
+				BeforeEach(func() {
+					// Select the second namespace by label, in addition to the listed first namespace
+					t.TargetNamespaces = targetNamespaces
+					for _, obj := range t.objs {
+						if obj.GetName() == targetNamespaces[1] {
+							obj.SetLabels(map[string]string{"cryostat": "enabled"})
+						}
+					}
+					cr := t.NewCryostat()
+					cr.Spec.TargetNamespaces = targetNamespaces[:1]
+					cr.Spec.TargetNamespaceSelector = &metav1.LabelSelector{
+						MatchLabels: map[string]string{"cryostat": "enabled"},
+					}
+					t.objs = append(t.objs, cr.Object)
+				})
+
+				It("should create the expected main deployment", func() {
+					t.expectMainDeployment()
+				})
+
+				It("should create certificate secrets in each namespace", func() {
+					t.expectCertificates()
+				})
+
+				It("should create RBAC in each namespace", func() {
+					t.expectRBAC()
+				})
+
+				It("should update the target namespaces in Status", func() {
+					t.expectTargetNamespaces()
+				})
+
+				Context("when deleted", func() {
+					JustBeforeEach(func() {
+						t.reconcileDeletedCryostat()
+					})
+
+					It("should delete the RoleBindings", func() {
+						t.checkRoleBindingsDeleted()
+					})
+
+					It("should delete CA cert secrets from each namespace", func() {
+						t.checkCASecretsDeleted()
+					})
+
+					It("should delete Cryostat", func() {
+						t.expectNoCryostat()
+					})
+				})
+
+				Context("when deleted after the namespace is no longer selected", func() {
+					JustBeforeEach(func() {
+						// Unlabel the namespace without reconciling, so it is only recorded in the status
+						ns := &corev1.Namespace{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: targetNamespaces[1]}, ns)
+						Expect(err).ToNot(HaveOccurred())
+						ns.Labels = nil
+						err = t.Client.Update(context.Background(), ns)
+						Expect(err).ToNot(HaveOccurred())
+
+						t.reconcileDeletedCryostat()
+					})
+
+					It("should delete the RoleBindings", func() {
+						t.checkRoleBindingsDeleted()
+					})
+
+					It("should delete Cryostat", func() {
+						t.expectNoCryostat()
+					})
+				})
+
+				Context("when the namespace is no longer selected", func() {
+					JustBeforeEach(func() {
+						ns := &corev1.Namespace{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: targetNamespaces[1]}, ns)
+						Expect(err).ToNot(HaveOccurred())
+						ns.Labels = nil
+						err = t.Client.Update(context.Background(), ns)
+						Expect(err).ToNot(HaveOccurred())
+
+						t.TargetNamespaces = targetNamespaces[:1]
+						t.reconcileCryostatFully()
+					})
+
+					It("should remove RBAC from the namespace", func() {
+						binding := t.NewRoleBinding(targetNamespaces[1])
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}, binding)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+
+					It("should remove the agent callback service from the namespace", func() {
+						svc := t.NewAgentCallbackService(targetNamespaces[1])
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, svc)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+
+					It("should update the target namespaces in Status", func() {
+						t.expectTargetNamespaces()
+					})
+				})
+			})
+
+			Context("with all namespaces enabled", func() {
+				BeforeEach(func() {
+					// Namespaces containing agent pods are targeted along with those in the spec
+					t.AllNamespaces = true
+					t.TargetNamespaces = targetNamespaces
+					cr := t.NewCryostat()
+					cr.Spec.TargetNamespaces = targetNamespaces[:1]
+					t.objs = append(t.objs, cr.Object, t.NewAgentPod(targetNamespaces[1]),
+						t.NewOtherNamespace(untargetedNamespace))
 				})
+
 				It("should create the expected main deployment", func() {
 					t.expectMainDeployment()
 				})
-				It("leave RBAC for the first namespace", func() {
-					t.expectRBAC()
-				})
-				It("should remove RBAC from the second namespace", func() {
-					binding := t.NewRoleBinding(targetNamespaces[1])
-					err := t.Client.Get(context.Background(), types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}, binding)
-					Expect(err).To(HaveOccurred())
-					Expect(kerrors.IsNotFound(err)).To(BeTrue())
-				})
-				It("leave certificate secrets for the first namespace", func() {
+
+				It("should create certificate secrets in each namespace", func() {
 					t.expectCertificates()
 				})
-				It("should remove CA cert secret from the second namespace", func() {
-					secret := t.NewCACertSecret(targetNamespaces[1])
-					err := t.Client.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret)
-					Expect(err).To(HaveOccurred())
-					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+
+				It("should bind the namespaced cluster role with a ClusterRoleBinding", func() {
+					expected := t.NewAllNamespacesClusterRoleBinding()
+					binding := &rbacv1.ClusterRoleBinding{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name}, binding)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(binding.GetLabels()).To(Equal(expected.GetLabels()))
+					Expect(binding.Subjects).To(Equal(expected.Subjects))
+					Expect(binding.RoleRef).To(Equal(expected.RoleRef))
 				})
-				It("should remove agent certificate for the second namespace", func() {
-					cert := t.NewAgentCert(targetNamespaces[1])
-					err := t.Client.Get(context.Background(), types.NamespacedName{Name: cert.Name, Namespace: cert.Namespace}, cert)
-					Expect(err).To(HaveOccurred())
-					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+
+				It("should not create RoleBindings in each namespace", func() {
+					for _, ns := range t.TargetNamespaces {
+						binding := t.NewRoleBinding(ns)
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}, binding)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					}
 				})
-				It("should remove agent cert secret for the second namespace", func() {
-					secret := t.NewAgentCertSecret(targetNamespaces[1])
-					err := t.Client.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret)
-					Expect(err).To(HaveOccurred())
-					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+
+				It("should update the target namespaces in Status", func() {
+					t.expectTargetNamespaces()
+					t.expectTargetNamespaceStatuses(map[string]int32{targetNamespaces[1]: 1})
 				})
-				It("should remove agent cert secret copy from the second namespace", func() {
-					secret := t.NewAgentCertSecretCopy(targetNamespaces[1])
+
+				It("should not create certificate secrets in namespaces without agent pods", func() {
+					secret := t.NewCACertSecret(untargetedNamespace)
 					err := t.Client.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret)
-					Expect(err).To(HaveOccurred())
 					Expect(kerrors.IsNotFound(err)).To(BeTrue())
 				})
-				It("should update the target namespaces in Status", func() {
-					t.expectTargetNamespaces()
+
+				Context("when an agent pod is created in another namespace", func() {
+					JustBeforeEach(func() {
+						t.TargetNamespaces = append(slices.Clone(targetNamespaces), untargetedNamespace)
+						err := t.Client.Create(context.Background(), t.NewAgentPod(untargetedNamespace))
+						Expect(err).ToNot(HaveOccurred())
+
+						t.reconcileCryostatFully()
+					})
+
+					It("should create certificate secrets in the namespace", func() {
+						t.expectCertificates()
+					})
+
+					It("should update the target namespaces in Status", func() {
+						t.expectTargetNamespaces()
+					})
+				})
+
+				Context("when the agent pods are deleted", func() {
+					JustBeforeEach(func() {
+						t.TargetNamespaces = targetNamespaces[:1]
+						err := t.Client.Delete(context.Background(), t.NewAgentPod(targetNamespaces[1]))
+						Expect(err).ToNot(HaveOccurred())
+
+						t.reconcileCryostatFully()
+					})
+
+					It("should delete certificate secrets in the namespace", func() {
+						secret := t.NewCACertSecret(targetNamespaces[1])
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+
+					It("should update the target namespaces in Status", func() {
+						t.expectTargetNamespaces()
+					})
+				})
+
+				Context("when all namespaces is disabled", func() {
+					JustBeforeEach(func() {
+						t.AllNamespaces = false
+						t.TargetNamespaces = targetNamespaces[:1]
+						cr := t.getCryostatInstance()
+						cr.Spec.AllNamespaces = false
+						t.updateCryostatInstance(cr)
+
+						t.reconcileCryostatFully()
+					})
+
+					It("should delete the ClusterRoleBinding for all namespaces", func() {
+						binding := t.NewAllNamespacesClusterRoleBinding()
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: binding.Name}, binding)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+
+					It("should create RBAC in each namespace", func() {
+						t.expectRBAC()
+					})
+
+					It("should update the target namespaces in Status", func() {
+						t.expectTargetNamespaces()
+					})
 				})
 			})
 
-			Context("with no target namespaces", func() {
+			Context("when enabling all namespaces", func() {
 				BeforeEach(func() {
-					t.TargetNamespaces = nil
+					t.TargetNamespaces = targetNamespaces
 					t.objs = append(t.objs, t.NewCryostat().Object)
 				})
-				It("should update the target namespaces in Status", func() {
-					t.expectTargetNamespaces()
+
+				JustBeforeEach(func() {
+					t.AllNamespaces = true
+					cr := t.getCryostatInstance()
+					cr.Spec.AllNamespaces = true
+					t.updateCryostatInstance(cr)
+
+					t.reconcileCryostatFully()
+				})
+
+				It("should remove RoleBindings from each namespace", func() {
+					for _, ns := range t.TargetNamespaces {
+						binding := t.NewRoleBinding(ns)
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: binding.Name, Namespace: binding.Namespace}, binding)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					}
+				})
+
+				It("should configure discovery for all namespaces", func() {
+					t.expectMainDeployment()
 				})
 			})
 
@@ -2849,6 +5436,35 @@ func (c *controllerTest) commonTests() {
 				t.expectRBAC()
 			})
 		})
+		Context("with the service CA", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithIngress()
+				cr.Spec.TLS = t.NewCryostatWithServiceCA().Spec.TLS
+				t.objs = append(t.objs, cr.Object)
+			})
+			It("should fail to set up TLS", func() {
+				_, err := t.reconcile()
+				Expect(err).To(MatchError(ContainSubstring("only available on OpenShift")))
+			})
+		})
+		Context("with a TLS security profile", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithIngress()
+				cr.Spec.TLS = t.NewCryostatWithTLSSecurityProfile(configv1.TLSProfileModernType).Spec.TLS
+				t.objs = append(t.objs, cr.Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should only allow TLS 1.3 in the authorization proxy", func() {
+				cm := &corev1.ConfigMap{}
+				expected := t.NewOAuth2ProxyConfigMap()
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, cm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"MinVersion": "TLS1.3"`))
+				Expect(cm.Data["alpha_config.json"]).ToNot(ContainSubstring(`"CipherSuites"`))
+			})
+		})
 		Context("with non-TLS ingress", func() {
 			BeforeEach(func() {
 				t.ExternalTLS = false
@@ -2888,7 +5504,8 @@ func (c *controllerTest) commonTests() {
 			BeforeEach(func() {
 				cr = t.NewCryostatWithIngress()
 				oldCoreIngress = t.OtherCoreIngress()
-				t.objs = append(t.objs, cr.Object, oldCoreIngress)
+				t.objs = append(t.objs, cr.Object)
+				t.legacyObjs = append(t.legacyObjs, oldCoreIngress)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
@@ -2959,12 +5576,126 @@ func (c *controllerTest) commonTests() {
 			})
 			Context("with existing config map", func() {
 				BeforeEach(func() {
-					t.objs = append(t.objs, t.NewCryostatWithIngress().Object, t.NewOAuth2ProxyConfigMapOld())
+					t.objs = append(t.objs, t.NewCryostatWithIngress().Object)
+					t.legacyObjs = append(t.legacyObjs, t.NewOAuth2ProxyConfigMapOld())
 				})
 				It("should create OAuth2 config map", func() {
 					t.expectOAuth2ConfigMap()
 				})
 			})
+			Context("with roles", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithRoles().Object)
+				})
+				It("should send requests for Cryostat and Grafana through the authorization server", func() {
+					cm := t.getOAuth2ConfigMap()
+					Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"id": "cryostat",` + "\n" +
+						`        "path": "/",` + "\n" + `        "uri": "http://localhost:8283"`))
+					Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"id": "grafana",` + "\n" +
+						`        "path": "/grafana/",` + "\n" + `        "uri": "http://localhost:8283"`))
+					Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"name": "X-Forwarded-Groups"`))
+					Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"claim": "groups"`))
+				})
+				It("should declare the authorization port", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					agentProxy := deploy.Spec.Template.Spec.Containers[4]
+					Expect(agentProxy.Name).To(Equal(t.Name + "-agent-proxy"))
+					Expect(agentProxy.Ports).To(ConsistOf(append(t.NewAgentProxyPorts(), corev1.ContainerPort{ContainerPort: 8283})))
+				})
+				It("should authorize requests using the roles", func() {
+					conf := t.getAgentProxyNginxConf()
+					Expect(conf).To(ContainSubstring("listen 127.0.0.1:8283;"))
+					Expect(conf).To(ContainSubstring("map $request_method $cryostat_role_0_method {\n\t\tdefault 0;\n\t\tGET 1;\n\t\tHEAD 1;\n\t}"))
+					Expect(conf).To(ContainSubstring(`"~^/api/v4/recordings(/.*)?$" 1;`))
+					Expect(conf).To(ContainSubstring(`"~^/api/v4/reports(/.*)?$" 1;`))
+					Expect(conf).To(ContainSubstring(`"~(^|,)\s*qa\.team\s*(,|$)" 1;`))
+					Expect(conf).To(ContainSubstring("map $uri $cryostat_role_1_path {\n\t\tdefault 1;\n\t}"))
+					Expect(conf).To(ContainSubstring(`"~(^|,)\s*cryostat-admins\s*(,|$)" 1;`))
+					Expect(conf).To(ContainSubstring(`map "$cryostat_role_0$cryostat_role_1" $cryostat_role_allowed {`))
+					Expect(conf).To(ContainSubstring("location / {\n\t\t\tif ($cryostat_role_allowed = 0) {\n\t\t\t\treturn 403;"))
+					Expect(conf).To(ContainSubstring("location /grafana/ {\n\t\t\tif ($cryostat_role_allowed = 0) {\n\t\t\t\treturn 403;"))
+					Expect(conf).To(ContainSubstring("proxy_pass http://127.0.0.1:3000/grafana/;"))
+				})
+				It("should forward requests for recordings with spaces in their names", func() {
+					conf := t.getAgentProxyNginxConf()
+					// A proxy_pass with a URI makes nginx encode the normalized URI again,
+					// so a request for "/api/v4/recordings/my%20recording.jfr" is forwarded as sent
+					Expect(conf).To(ContainSubstring("proxy_pass http://127.0.0.1:8181/;"))
+					Expect(conf).ToNot(ContainSubstring("$uri$is_args$args"))
+					Expect(conf).ToNot(ContainSubstring("return 400;"))
+					// The decoded path of the recording is still allowed by the viewer role
+					pattern := `^/api/v4/recordings(/.*)?$`
+					Expect(conf).To(ContainSubstring(`"~` + pattern + `" 1;`))
+					Expect(regexp.MustCompile(pattern).MatchString("/api/v4/recordings/my recording.jfr")).To(BeTrue())
+				})
+			})
+			Context("with an OpenID Connect provider", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithOIDC().Object, t.NewOIDCClientSecret(), t.NewOIDCCAConfigMap())
+				})
+				It("should configure the OIDC provider", func() {
+					cm := t.getOAuth2ConfigMap()
+					Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"provider": "oidc"`))
+					Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"issuerURL": "https://keycloak.example.com/realms/cryostat"`))
+					Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"clientSecretFile": "/var/run/secrets/operator.cryostat.io/oidc-client-secret/client-secret"`))
+					Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"scope": "openid email profile"`))
+					Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"cryostat-users"`))
+					Expect(cm.Data["alpha_config.json"]).To(ContainSubstring(`"/var/run/secrets/operator.cryostat.io/oidc-ca/ca.crt"`))
+					Expect(cm.Data["alpha_config.json"]).ToNot(ContainSubstring(`"google"`))
+				})
+				It("should list the allowed emails", func() {
+					cm := t.getOAuth2ConfigMap()
+					Expect(cm.Data).To(HaveKeyWithValue("authenticated_emails.txt", "alice@example.com\nbob@example.com\n"))
+				})
+				It("should configure the auth proxy for the provider", func() {
+					container := t.getAuthProxyContainer()
+					Expect(container.Env).To(ContainElements(
+						corev1.EnvVar{Name: "OAUTH2_PROXY_REDIRECT_URL", Value: "https://" + t.Name + ".example.com/oauth2/callback"},
+						corev1.EnvVar{Name: "OAUTH2_PROXY_AUTHENTICATED_EMAILS_FILE", Value: "/etc/oauth2_proxy/alpha_config/authenticated_emails.txt"},
+						corev1.EnvVar{Name: "OAUTH2_PROXY_SKIP_PROVIDER_BUTTON", Value: "true"},
+						corev1.EnvVar{Name: "OAUTH2_PROXY_SKIP_AUTH_ROUTES", Value: "^/health(/liveness)?$"},
+					))
+					Expect(container.Env).ToNot(ContainElement(HaveField("Name", "OAUTH2_PROXY_EMAIL_DOMAINS")))
+					Expect(container.VolumeMounts).To(ContainElements(
+						corev1.VolumeMount{Name: "oidc-client-secret", MountPath: "/var/run/secrets/operator.cryostat.io/oidc-client-secret", ReadOnly: true},
+						corev1.VolumeMount{Name: "oidc-ca", MountPath: "/var/run/secrets/operator.cryostat.io/oidc-ca", ReadOnly: true},
+					))
+				})
+				It("should mount the client secret and CA", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					volumes := deploy.Spec.Template.Spec.Volumes
+					Expect(volumes).To(ContainElement(HaveField("Name", "oidc-client-secret")))
+					for _, volume := range volumes {
+						switch volume.Name {
+						case "oidc-client-secret":
+							Expect(volume.Secret.SecretName).To(Equal("cryostat-oidc"))
+							Expect(volume.Secret.Items).To(ConsistOf(HaveField("Key", "client-secret")))
+						case "oidc-ca":
+							Expect(volume.ConfigMap.Name).To(Equal("keycloak-ca"))
+							Expect(volume.ConfigMap.Items).To(ConsistOf(HaveField("Path", "ca.crt")))
+						case t.Name + "-oauth2-proxy-cfg":
+							Expect(volume.ConfigMap.Items).To(ContainElement(HaveField("Key", "authenticated_emails.txt")))
+						}
+					}
+				})
+			})
+		})
+		Context("with an OpenID Connect provider without an ingress", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithOIDC()
+				cr.Spec.NetworkOptions = nil
+				t.objs = append(t.objs, cr.Object, t.NewOIDCClientSecret(), t.NewOIDCCAConfigMap())
+			})
+			It("should fail to configure the auth proxy", func() {
+				Eventually(func() error {
+					_, err := t.reconcile()
+					return err
+				}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(MatchError(ContainSubstring("requires an Ingress")))
+			})
 		})
 		Context("with report generator service", func() {
 			BeforeEach(func() {
@@ -3023,9 +5754,11 @@ func (c *controllerTest) commonTests() {
 			BeforeEach(func() {
 				cr = t.NewCryostat()
 				oldSA = t.OtherServiceAccount()
-				t.objs = append(t.objs, cr.Object, oldSA)
+				t.objs = append(t.objs, cr.Object)
+				t.legacyObjs = append(t.legacyObjs, withoutServiceAccountSecrets(oldSA))
 			})
 			It("should update the Service Account", func() {
+				t.addServiceAccountSecrets(oldSA)
 				t.reconcileCryostatFully()
 
 				sa := &corev1.ServiceAccount{}
@@ -3033,7 +5766,7 @@ func (c *controllerTest) commonTests() {
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(sa.Annotations).To(Equal(map[string]string{
-					"hello": "world",
+					"hello": "annotation",
 				}))
 
 				Expect(sa.Labels).To(Equal(map[string]string{
@@ -3091,7 +5824,8 @@ func (c *controllerTest) commonTests() {
 			BeforeEach(func() {
 				cr = t.NewCryostat()
 				oldBinding = t.OtherRoleBinding(t.Namespace)
-				t.objs = append(t.objs, cr.Object, oldBinding)
+				t.objs = append(t.objs, cr.Object)
+				t.legacyObjs = append(t.legacyObjs, oldBinding)
 			})
 			It("should update the Role Binding", func() {
 				t.reconcileCryostatFully()
@@ -3117,7 +5851,8 @@ func (c *controllerTest) commonTests() {
 			BeforeEach(func() {
 				cr = t.NewCryostat()
 				oldBinding = t.OtherClusterRoleBinding()
-				t.objs = append(t.objs, cr.Object, oldBinding)
+				t.objs = append(t.objs, cr.Object)
+				t.legacyObjs = append(t.legacyObjs, oldBinding)
 			})
 			It("should update the Cluster Role Binding", func() {
 				t.reconcileCryostatFully()
@@ -3129,8 +5864,9 @@ func (c *controllerTest) commonTests() {
 				}, binding)
 				Expect(err).ToNot(HaveOccurred())
 
-				// Labels and annotations are unaffected
-				Expect(binding.Labels).To(Equal(oldBinding.Labels))
+				// Labels are merged with existing ones
+				metav1.SetMetaDataLabel(&expected.ObjectMeta, "test", "label")
+				Expect(binding.Labels).To(Equal(expected.Labels))
 				Expect(binding.Annotations).To(Equal(oldBinding.Annotations))
 
 				// Subjects and RoleRef should be fully replaced
@@ -3197,6 +5933,9 @@ func (c *controllerTest) commonTests() {
 					&rbacv1.Role{},
 					&rbacv1.RoleBinding{},
 					&netv1.Ingress{},
+					&netv1.NetworkPolicy{},
+					&batchv1.CronJob{},
+					&batchv1.Job{},
 				}
 			})
 
@@ -3257,6 +5996,12 @@ func (c *controllerTest) commonTests() {
 					&rbacv1.RoleBinding{},
 					&corev1.Secret{},
 					&corev1.Service{},
+					&rbacv1.ClusterRoleBinding{},
+					&corev1.Namespace{},
+					agentPodMetadata(),
+					&corev1.Secret{},
+					&configv1.APIServer{},
+					&corev1.Secret{},
 				}
 			})
 
@@ -3344,57 +6089,260 @@ func (c *controllerTest) commonTests() {
 					handlerFunc = t.ControllerBuilder.MapFuncs[0]
 				})
 
-				Context("with both labels present", func() {
-					BeforeEach(func() {
-						obj = t.NewAgentCertSecretCopy("foo")
-					})
+				Context("with both labels present", func() {
+					BeforeEach(func() {
+						obj = t.NewAgentCertSecretCopy("foo")
+					})
+
+					It("should accept", func() {
+						result := handlerFunc(context.Background(), obj)
+						Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
+					})
+				})
+
+				Context("with name label missing", func() {
+					BeforeEach(func() {
+						obj = t.NewAgentCertSecretCopy("foo")
+						delete(obj.GetLabels(), "operator.cryostat.io/name")
+					})
+
+					It("should reject", func() {
+						result := handlerFunc(context.Background(), obj)
+						Expect(result).To(BeEmpty())
+					})
+				})
+
+				Context("with namespace label missing", func() {
+					BeforeEach(func() {
+						obj = t.NewAgentCertSecretCopy("foo")
+						delete(obj.GetLabels(), "operator.cryostat.io/namespace")
+					})
+
+					It("should reject", func() {
+						result := handlerFunc(context.Background(), obj)
+						Expect(result).To(BeEmpty())
+					})
+				})
+
+				Context("both labels missing", func() {
+					BeforeEach(func() {
+						obj = t.NewAgentCertSecret("foo")
+						delete(obj.GetLabels(), "operator.cryostat.io/name")
+					})
+
+					It("should reject", func() {
+						result := handlerFunc(context.Background(), obj)
+						Expect(result).To(BeEmpty())
+					})
+				})
+			})
+		})
+
+		Context("watches on namespaces", func() {
+			var handlerFunc handler.MapFunc
+			var pred predicate.Predicate
+			var ns *corev1.Namespace
+			var cr *model.CryostatInstance
+
+			BeforeEach(func() {
+				ns = t.NewOtherNamespace("selected")
+				cr = t.NewCryostat()
+				cr.Spec.TargetNamespaceSelector = &metav1.LabelSelector{
+					MatchLabels: map[string]string{"cryostat": "enabled"},
+				}
+				t.objs = append(t.objs, cr.Object)
+			})
+
+			JustBeforeEach(func() {
+				idx := slices.IndexFunc(t.ControllerBuilder.WatchesCalls, func(watch test.WatchesArgs) bool {
+					_, ok := watch.Object.(*corev1.Namespace)
+					return ok
+				})
+				Expect(idx).ToNot(Equal(-1))
+				handlerFunc = t.ControllerBuilder.MapFuncs[idx]
+				pred = t.ControllerBuilder.Predicates[idx]
+			})
+
+			It("should only accept updates that change labels", func() {
+				updated := ns.DeepCopy()
+				updated.Annotations = map[string]string{"test": "annotation"}
+				Expect(pred.Update(event.UpdateEvent{ObjectOld: ns, ObjectNew: updated})).To(BeFalse())
+				updated.Labels = map[string]string{"cryostat": "enabled"}
+				Expect(pred.Update(event.UpdateEvent{ObjectOld: ns, ObjectNew: updated})).To(BeTrue())
+			})
+
+			Context("with a matching namespace", func() {
+				BeforeEach(func() {
+					ns.Labels = map[string]string{"cryostat": "enabled"}
+				})
+
+				It("should enqueue the Cryostat", func() {
+					result := handlerFunc(context.Background(), ns)
+					Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
+				})
+			})
+
+			Context("with a non-matching namespace", func() {
+				It("should not enqueue the Cryostat", func() {
+					result := handlerFunc(context.Background(), ns)
+					Expect(result).To(BeEmpty())
+				})
+
+				Context("with all namespaces enabled", func() {
+					BeforeEach(func() {
+						cr.Spec.AllNamespaces = true
+					})
+
+					It("should not enqueue the Cryostat", func() {
+						result := handlerFunc(context.Background(), ns)
+						Expect(result).To(BeEmpty())
+					})
+				})
+			})
+		})
+
+		Context("watches on provided TLS secrets", func() {
+			var handlerFunc handler.MapFunc
+			var pred predicate.Predicate
+			var secret *corev1.Secret
+
+			BeforeEach(func() {
+				secret = &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "database-tls",
+						Namespace: t.Namespace,
+					},
+				}
+				t.objs = append(t.objs, t.NewCryostatWithProvidedCertificates().Object)
+			})
+
+			JustBeforeEach(func() {
+				idx := slices.IndexFunc(t.ControllerBuilder.Predicates, func(pred predicate.Predicate) bool {
+					_, ok := pred.(predicate.ResourceVersionChangedPredicate)
+					return ok
+				})
+				Expect(idx).ToNot(Equal(-1))
+				Expect(t.ControllerBuilder.WatchesCalls[idx].Object).To(BeAssignableToTypeOf(&corev1.Secret{}))
+				handlerFunc = t.ControllerBuilder.MapFuncs[idx]
+				pred = t.ControllerBuilder.Predicates[idx]
+			})
+
+			It("should only accept updates that change the resource version", func() {
+				updated := secret.DeepCopy()
+				Expect(pred.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: updated})).To(BeFalse())
+				updated.ResourceVersion = "2"
+				Expect(pred.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: updated})).To(BeTrue())
+			})
+
+			It("should enqueue the Cryostat referencing the secret", func() {
+				result := handlerFunc(context.Background(), secret)
+				Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
+			})
+
+			It("should not enqueue the Cryostat for other secrets", func() {
+				secret.Name = "other-tls"
+				result := handlerFunc(context.Background(), secret)
+				Expect(result).To(BeEmpty())
+			})
+
+			It("should not enqueue the Cryostat for secrets in other namespaces", func() {
+				secret.Namespace = "other"
+				result := handlerFunc(context.Background(), secret)
+				Expect(result).To(BeEmpty())
+			})
+		})
+
+		Context("watches on agent pods", func() {
+			var handlerFunc handler.MapFunc
+			var pred predicate.Predicate
+			var pod *corev1.Pod
+			var cr *model.CryostatInstance
+
+			BeforeEach(func() {
+				pod = t.NewAgentPod("agents")
+				cr = t.NewCryostat()
+				t.objs = append(t.objs, cr.Object)
+			})
 
-					It("should accept", func() {
-						result := handlerFunc(context.Background(), obj)
-						Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
-					})
+			JustBeforeEach(func() {
+				idx := slices.IndexFunc(t.ControllerBuilder.WatchesCalls, func(watch test.WatchesArgs) bool {
+					_, ok := watch.Object.(*metav1.PartialObjectMetadata)
+					return ok
 				})
+				Expect(idx).ToNot(Equal(-1))
+				handlerFunc = t.ControllerBuilder.MapFuncs[idx]
+				pred = t.ControllerBuilder.Predicates[idx]
+			})
 
-				Context("with name label missing", func() {
-					BeforeEach(func() {
-						obj = t.NewAgentCertSecretCopy("foo")
-						delete(obj.GetLabels(), "operator.cryostat.io/name")
-					})
+			It("should accept created and deleted pods with agent labels", func() {
+				Expect(pred.Create(event.CreateEvent{Object: pod})).To(BeTrue())
+				Expect(pred.Delete(event.DeleteEvent{Object: pod})).To(BeTrue())
+			})
 
-					It("should reject", func() {
-						result := handlerFunc(context.Background(), obj)
-						Expect(result).To(BeEmpty())
-					})
-				})
+			It("should reject updated pods", func() {
+				Expect(pred.Update(event.UpdateEvent{ObjectOld: pod, ObjectNew: pod.DeepCopy()})).To(BeFalse())
+			})
 
-				Context("with namespace label missing", func() {
-					BeforeEach(func() {
-						obj = t.NewAgentCertSecretCopy("foo")
-						delete(obj.GetLabels(), "operator.cryostat.io/namespace")
-					})
+			It("should reject pods without agent labels", func() {
+				delete(pod.Labels, "cryostat.io/name")
+				Expect(pred.Create(event.CreateEvent{Object: pod})).To(BeFalse())
+			})
 
-					It("should reject", func() {
-						result := handlerFunc(context.Background(), obj)
-						Expect(result).To(BeEmpty())
-					})
-				})
+			It("should not enqueue the Cryostat", func() {
+				result := handlerFunc(context.Background(), pod)
+				Expect(result).To(BeEmpty())
+			})
 
-				Context("both labels missing", func() {
-					BeforeEach(func() {
-						obj = t.NewAgentCertSecret("foo")
-						delete(obj.GetLabels(), "operator.cryostat.io/name")
-					})
+			Context("with all namespaces enabled", func() {
+				BeforeEach(func() {
+					cr.Spec.AllNamespaces = true
+				})
 
-					It("should reject", func() {
-						result := handlerFunc(context.Background(), obj)
-						Expect(result).To(BeEmpty())
-					})
+				It("should enqueue the Cryostat", func() {
+					result := handlerFunc(context.Background(), pod)
+					Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
 				})
 			})
 		})
 	})
 }
 
+func agentPodMetadata() *metav1.PartialObjectMetadata {
+	pod := &metav1.PartialObjectMetadata{}
+	pod.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+	return pod
+}
+
+func findManagedFields(obj metav1.Object, manager string) *metav1.ManagedFieldsEntry {
+	for i, entry := range obj.GetManagedFields() {
+		if entry.Manager == manager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return &obj.GetManagedFields()[i]
+		}
+	}
+	return nil
+}
+
+func withoutServiceAccountSecrets(sa *corev1.ServiceAccount) *corev1.ServiceAccount {
+	sa = sa.DeepCopy()
+	sa.ImagePullSecrets = nil
+	sa.Secrets = nil
+	sa.AutomountServiceAccountToken = nil
+	return sa
+}
+
+// addServiceAccountSecrets adds the secrets and token settings of the provided Service Account,
+// as OpenShift and users do, rather than the operator
+func (t *cryostatTestInput) addServiceAccountSecrets(from *corev1.ServiceAccount) {
+	sa := &corev1.ServiceAccount{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: from.Name, Namespace: from.Namespace}, sa)
+	Expect(err).ToNot(HaveOccurred())
+	sa.ImagePullSecrets = from.ImagePullSecrets
+	sa.Secrets = from.Secrets
+	sa.AutomountServiceAccountToken = from.AutomountServiceAccountToken
+	err = t.Client.Update(context.Background(), sa, ctrlclient.FieldOwner("openshift-controller-manager"))
+	Expect(err).ToNot(HaveOccurred())
+}
+
 func (t *cryostatTestInput) expectRoutes() {
 	t.checkRoute(t.NewCoreRoute())
 }
@@ -3420,6 +6368,15 @@ func (t *cryostatTestInput) checkConditionPresent(condType operatorv1beta2.Cryos
 	Expect(condition.Reason).To(Equal(reason))
 }
 
+func (t *cryostatTestInput) expectObservedGeneration() {
+	cr := t.getCryostatInstance()
+	generation := cr.Object.GetGeneration()
+	Expect(cr.Status.ObservedGeneration).To(Equal(generation))
+	for _, condition := range cr.Status.Conditions {
+		Expect(condition.ObservedGeneration).To(Equal(generation), "condition %s", condition.Type)
+	}
+}
+
 func (t *cryostatTestInput) checkConditionAbsent(condType operatorv1beta2.CryostatConditionType) {
 	cr := t.getCryostatInstance()
 
@@ -3428,11 +6385,14 @@ func (t *cryostatTestInput) checkConditionAbsent(condType operatorv1beta2.Cryost
 }
 
 func (t *cryostatTestInput) reconcileCryostatFully() {
-	Eventually(func() reconcile.Result {
+	// Longer requeues are only scheduled to renew certificates, rotate credentials
+	// or report expiring certificates
+	Eventually(func() time.Duration {
 		result, err := t.reconcile()
 		Expect(err).ToNot(HaveOccurred())
-		return result
-	}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(Equal(reconcile.Result{}))
+		return result.RequeueAfter
+	}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(Or(BeZero(),
+		BeNumerically(">", controller.PendingRequeueAfter)))
 }
 
 func (t *cryostatTestInput) reconcileDeletedCryostat() {
@@ -3679,6 +6639,26 @@ func (t *cryostatTestInput) expectAgentProxyConfigMap() {
 	Expect(cm.Immutable).To(Equal(expected.Immutable))
 }
 
+func (t *cryostatTestInput) getAuthProxyContainer() *corev1.Container {
+	deploy := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+	Expect(err).ToNot(HaveOccurred())
+	for i, container := range deploy.Spec.Template.Spec.Containers {
+		if container.Name == t.Name+"-auth-proxy" {
+			return &deploy.Spec.Template.Spec.Containers[i]
+		}
+	}
+	Fail("auth proxy container not found")
+	return nil
+}
+
+func (t *cryostatTestInput) getOAuth2ConfigMap() *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-oauth2-proxy-cfg", Namespace: t.Namespace}, cm)
+	Expect(err).ToNot(HaveOccurred())
+	return cm
+}
+
 func (t *cryostatTestInput) expectOAuth2ConfigMap() {
 	expected := t.NewOAuth2ProxyConfigMap()
 	cm := &corev1.ConfigMap{}
@@ -3930,6 +6910,37 @@ func (t *cryostatTestInput) makeDeploymentAvailable(deployName string) {
 	t.setDeploymentConditions(deployName, &statusTrue, &statusTrue, nil)
 }
 
+func (t *cryostatTestInput) makeDeploymentComplete(deployName string) {
+	deploy := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: deployName, Namespace: t.Namespace}, deploy)
+	Expect(err).ToNot(HaveOccurred())
+
+	deploy.Status.ObservedGeneration = deploy.Generation
+	deploy.Status.Replicas = *deploy.Spec.Replicas
+	deploy.Status.UpdatedReplicas = *deploy.Spec.Replicas
+	deploy.Status.ReadyReplicas = *deploy.Spec.Replicas
+	deploy.Status.AvailableReplicas = *deploy.Spec.Replicas
+	deploy.Status.Conditions = []appsv1.DeploymentCondition{
+		{
+			Type:    appsv1.DeploymentAvailable,
+			Status:  corev1.ConditionTrue,
+			Reason:  "MinimumReplicasAvailable",
+			Message: "Deployment has minimum availability.",
+		},
+		{
+			Type:    appsv1.DeploymentProgressing,
+			Status:  corev1.ConditionTrue,
+			Reason:  "NewReplicaSetAvailable",
+			Message: "Test made deployment complete its roll out.",
+		},
+	}
+	err = t.Client.Status().Update(context.Background(), deploy)
+	Expect(err).ToNot(HaveOccurred())
+
+	// Reconcile again
+	t.reconcileCryostatFully()
+}
+
 func (t *cryostatTestInput) makeDeploymentFail(deployName string) {
 	statusTrue := corev1.ConditionTrue
 	statusFalse := corev1.ConditionFalse
@@ -4548,6 +7559,20 @@ func (t *cryostatTestInput) checkCoreHasEnvironmentVariables(expectedEnvVars []c
 	Expect(coreContainer.Env).To(ContainElements(expectedEnvVars))
 }
 
+func (t *cryostatTestInput) expectNoManagedDatabase() {
+	deployment := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, deployment)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+
+	svc := &corev1.Service{}
+	err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, svc)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+
+	policy := &netv1.NetworkPolicy{}
+	err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-db-internal-ingress", Namespace: t.Namespace}, policy)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
 func (t *cryostatTestInput) checkCoreDoesNotHaveEnvironmentVariable(name string) {
 	deployment := &appsv1.Deployment{}
 	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deployment)
@@ -4559,6 +7584,434 @@ func (t *cryostatTestInput) checkCoreDoesNotHaveEnvironmentVariable(name string)
 	}
 }
 
+func (t *cryostatTestInput) expectDatabaseBackupCronJob(suspend bool) {
+	cronJob := &batchv1.CronJob{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-backup", Namespace: t.Namespace}, cronJob)
+	Expect(err).ToNot(HaveOccurred())
+
+	cr := t.getCryostatInstance()
+	t.checkMetadata(cronJob, &metav1.ObjectMeta{
+		Name:      t.Name + "-database-backup",
+		Namespace: t.Namespace,
+		Labels:    map[string]string{"app": t.Name, "component": "database-backup"},
+	})
+	Expect(cronJob.Spec.Schedule).To(Equal("0 2 * * *"))
+	Expect(cronJob.Spec.ConcurrencyPolicy).To(Equal(batchv1.ForbidConcurrent))
+	Expect(cronJob.Spec.Suspend).To(Equal(&suspend))
+
+	template := cronJob.Spec.JobTemplate.Spec.Template
+	Expect(template.Labels).To(Equal(map[string]string{"app": t.Name, "component": "database-backup"}))
+	Expect(template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+	Expect(template.Spec.Volumes).To(ConsistOf(t.NewDatabaseBackupVolumes()))
+	Expect(template.Spec.SecurityContext).To(Equal(t.NewDatabaseBackupPodSecurityContext()))
+	Expect(template.Spec.Containers).To(HaveLen(1))
+
+	container := template.Spec.Containers[0]
+	Expect(container.Name).To(Equal(t.Name + "-database-backup"))
+	Expect(container.Image).To(HavePrefix("quay.io/cryostat/cryostat-db:"))
+	Expect(container.Command).To(HaveLen(3))
+	Expect(container.Command[2]).To(ContainSubstring("pg_dump --format=custom"))
+	// Retention lists every page of backups
+	Expect(container.Command[2]).To(ContainSubstring(`grep -q '<IsTruncated>true</IsTruncated>'`))
+	Expect(container.Command[2]).To(ContainSubstring(`--data-urlencode "continuation-token=${token}"`))
+	Expect(container.Env).To(ConsistOf(t.NewDatabaseBackupEnvironmentVariables()))
+	Expect(container.VolumeMounts).To(ConsistOf(t.NewDatabaseBackupVolumeMounts()))
+	Expect(container.SecurityContext).To(Equal(t.NewDatabaseSecurityContext(cr)))
+}
+
+func (t *cryostatTestInput) expectNoDatabaseBackupCronJob() {
+	cronJob := &batchv1.CronJob{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-backup", Namespace: t.Namespace}, cronJob)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
+func (t *cryostatTestInput) getDatabaseImage() string {
+	deployment := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, deployment)
+	Expect(err).ToNot(HaveOccurred())
+	return deployment.Spec.Template.Spec.Containers[0].Image
+}
+
+func (t *cryostatTestInput) expectDeploymentReplicas(name string, replicas int32) {
+	deployment := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, deployment)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(deployment.Spec.Replicas).To(Equal(&replicas))
+}
+
+func (t *cryostatTestInput) expectDatabaseUpgradeJob(previousImage string, image string) {
+	job := &batchv1.Job{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-upgrade-17", Namespace: t.Namespace}, job)
+	Expect(err).ToNot(HaveOccurred())
+
+	cr := t.getCryostatInstance()
+	t.checkMetadata(job, &metav1.ObjectMeta{
+		Name:      t.Name + "-database-upgrade-17",
+		Namespace: t.Namespace,
+		Labels:    map[string]string{"app": t.Name, "component": "database-upgrade"},
+	})
+	Expect(job.Spec.BackoffLimit).To(Equal(&[]int32{0}[0]))
+
+	template := job.Spec.Template
+	Expect(template.Labels).To(Equal(map[string]string{"app": t.Name, "component": "database-upgrade"}))
+	Expect(template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+	Expect(template.Spec.Volumes).To(ConsistOf(t.NewDatabaseUpgradeVolumes()))
+	Expect(template.Spec.SecurityContext).To(Equal(t.NewPodSecurityContext(cr)))
+	Expect(template.Spec.InitContainers).To(HaveLen(1))
+	Expect(template.Spec.Containers).To(HaveLen(1))
+
+	dump := template.Spec.InitContainers[0]
+	Expect(dump.Name).To(Equal("database-dump"))
+	Expect(dump.Image).To(Equal(previousImage))
+	Expect(dump.Command).To(HaveLen(3))
+	Expect(dump.Command[2]).To(HavePrefix("set -euo pipefail\n"))
+	Expect(dump.Command[2]).To(ContainSubstring("pg_dumpall"))
+	Expect(dump.Env).To(ConsistOf(t.NewDatabaseEnvironmentVariables(false)))
+	Expect(dump.VolumeMounts).To(ConsistOf(t.NewDatabaseUpgradeVolumeMounts()))
+	Expect(dump.SecurityContext).To(Equal(t.NewDatabaseSecurityContext(cr)))
+
+	upgrade := template.Spec.Containers[0]
+	Expect(upgrade.Name).To(Equal("database-upgrade"))
+	Expect(upgrade.Image).To(Equal(image))
+	Expect(upgrade.Command).To(HaveLen(3))
+	script := upgrade.Command[2]
+	Expect(script).To(HavePrefix("set -euo pipefail\n"))
+	Expect(script).To(ContainSubstring("initdb"))
+	// Every restore must stop at the first error, and be rolled back entirely
+	restores := regexp.MustCompile(`psql [^\n]*\\\n[^\n]*--file=`).FindAllString(script, -1)
+	Expect(restores).To(HaveLen(2))
+	for _, restore := range restores {
+		Expect(restore).To(ContainSubstring("-v ON_ERROR_STOP=1"))
+		Expect(restore).To(ContainSubstring("--single-transaction"))
+	}
+	// The previous data directory must only be replaced after restoring succeeded
+	Expect(strings.Index(script, `mv "${PGDATA}"`)).To(BeNumerically(">", strings.LastIndex(script, "psql ")))
+	Expect(upgrade.Env).To(ConsistOf(t.NewDatabaseEnvironmentVariables(false)))
+	Expect(upgrade.VolumeMounts).To(ConsistOf(t.NewDatabaseUpgradeVolumeMounts()))
+	Expect(upgrade.SecurityContext).To(Equal(t.NewDatabaseSecurityContext(cr)))
+}
+
+func (t *cryostatTestInput) expectDatabaseVersionJob(previousImage string) {
+	job := &batchv1.Job{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-version", Namespace: t.Namespace}, job)
+	Expect(err).ToNot(HaveOccurred())
+
+	cr := t.getCryostatInstance()
+	t.checkMetadata(job, &metav1.ObjectMeta{
+		Name:      t.Name + "-database-version",
+		Namespace: t.Namespace,
+		Labels:    map[string]string{"app": t.Name, "component": "database-version"},
+	})
+	Expect(job.Spec.BackoffLimit).To(Equal(&[]int32{0}[0]))
+	Expect(job.Spec.ActiveDeadlineSeconds).ToNot(BeNil())
+
+	template := job.Spec.Template
+	Expect(template.Labels).To(Equal(map[string]string{"app": t.Name, "component": "database-version"}))
+	Expect(template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+	Expect(template.Spec.Volumes).To(ConsistOf(t.NewDatabaseUpgradeVolumes()[0]))
+	Expect(template.Spec.SecurityContext).To(Equal(t.NewPodSecurityContext(cr)))
+	// Shares the volume of the running database
+	Expect(template.Spec.Affinity).ToNot(BeNil())
+	Expect(template.Spec.Affinity.PodAffinity).ToNot(BeNil())
+	Expect(template.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(ConsistOf(corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": t.Name, "kind": "cryostat", "component": "database"},
+		},
+		TopologyKey: "kubernetes.io/hostname",
+	}))
+	Expect(template.Spec.Containers).To(HaveLen(1))
+
+	container := template.Spec.Containers[0]
+	Expect(container.Name).To(Equal("database-version"))
+	Expect(container.Image).To(Equal(previousImage))
+	Expect(container.Command).To(HaveLen(3))
+	Expect(container.Command[2]).To(ContainSubstring(`"${PGDATA}/PG_VERSION"`))
+	Expect(container.Env).To(ConsistOf(t.NewDatabaseEnvironmentVariables(false)))
+	Expect(container.VolumeMounts).To(ConsistOf(corev1.VolumeMount{
+		Name:      t.Name + "-database",
+		MountPath: "/var/lib/pgsql",
+		ReadOnly:  true,
+	}))
+	Expect(container.SecurityContext).To(Equal(t.NewDatabaseSecurityContext(cr)))
+}
+
+// completeDatabaseVersionJob simulates the version Job reporting the provided termination message
+func (t *cryostatTestInput) completeDatabaseVersionJob(message string) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      t.Name + "-database-version-abcde",
+			Namespace: t.Namespace,
+			Labels:    map[string]string{"app": t.Name, "component": "database-version"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "database-version",
+					Image: "database-version",
+				},
+			},
+		},
+	}
+	err := t.Client.Create(context.Background(), pod)
+	Expect(err).ToNot(HaveOccurred())
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name: "database-version",
+			State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{
+					ExitCode: 0,
+					Message:  message,
+				},
+			},
+		},
+	}
+	err = t.Client.Status().Update(context.Background(), pod)
+	Expect(err).ToNot(HaveOccurred())
+	t.setJobCondition(t.Name+"-database-version", batchv1.JobComplete)
+}
+
+func (t *cryostatTestInput) expectNoJob(name string) {
+	job := &batchv1.Job{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, job)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
+func (t *cryostatTestInput) setDatabaseUpgradeJobCondition(condType batchv1.JobConditionType) {
+	t.setJobCondition(t.Name+"-database-upgrade-17", condType)
+}
+
+func (t *cryostatTestInput) reconcileCryostatUntilRequeueScheduled() {
+	t.reconcileCryostatUntilRequeueAfter(time.Hour)
+}
+
+func (t *cryostatTestInput) reconcileCryostatUntilRequeueAfter(delay time.Duration) {
+	Eventually(func() time.Duration {
+		result, err := t.reconcile()
+		Expect(err).ToNot(HaveOccurred())
+		return result.RequeueAfter
+	}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(BeNumerically(">", delay))
+}
+
+func (t *cryostatTestInput) expectNoCredentialRotationSecret() {
+	secret := &corev1.Secret{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-credential-rotation", Namespace: t.Namespace}, secret)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
+func (t *cryostatTestInput) expectSecretKey(name string, key string, value string) {
+	secret := &corev1.Secret{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, secret)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(string(secret.Data[key])).To(Equal(value))
+}
+
+func (t *cryostatTestInput) expectDatabasePasswordJob(currentSecret string, newSecret string) {
+	job := &batchv1.Job{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-password", Namespace: t.Namespace}, job)
+	Expect(err).ToNot(HaveOccurred())
+
+	cr := t.getCryostatInstance()
+	t.checkMetadata(job, &metav1.ObjectMeta{
+		Name:      t.Name + "-database-password",
+		Namespace: t.Namespace,
+		Labels:    map[string]string{"app": t.Name, "component": "database-password"},
+	})
+	Expect(job.Spec.BackoffLimit).To(Equal(&[]int32{0}[0]))
+
+	template := job.Spec.Template
+	Expect(template.Labels).To(Equal(map[string]string{"app": t.Name, "component": "database-password"}))
+	Expect(template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+	// Uses the same pod security context as the database backup jobs
+	Expect(template.Spec.SecurityContext).To(Equal(t.NewDatabaseBackupPodSecurityContext()))
+	Expect(template.Spec.Containers).To(HaveLen(1))
+
+	container := template.Spec.Containers[0]
+	Expect(container.Image).To(HavePrefix("quay.io/cryostat/cryostat-db:"))
+	Expect(container.Command).To(HaveLen(3))
+	Expect(container.Command[2]).To(ContainSubstring("ALTER ROLE CURRENT_USER PASSWORD :'password'"))
+	Expect(container.Env).To(ContainElements(
+		corev1.EnvVar{Name: "PGUSER", Value: "cryostat"},
+		corev1.EnvVar{Name: "PGDATABASE", Value: "cryostat"},
+		HaveField("ValueFrom.SecretKeyRef", SatisfyAll(
+			HaveField("Name", currentSecret),
+			HaveField("Key", "CONNECTION_KEY"),
+		)),
+		HaveField("ValueFrom.SecretKeyRef", SatisfyAll(
+			HaveField("Name", newSecret),
+			HaveField("Key", "CONNECTION_KEY"),
+		)),
+	))
+	Expect(container.SecurityContext).To(Equal(t.NewDatabaseSecurityContext(cr)))
+}
+
+func (t *cryostatTestInput) setJobCondition(name string, condType batchv1.JobConditionType) {
+	job := &batchv1.Job{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, job)
+	Expect(err).ToNot(HaveOccurred())
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+		Type:   condType,
+		Status: corev1.ConditionTrue,
+	})
+	err = t.Client.Status().Update(context.Background(), job)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func (t *cryostatTestInput) getDeploymentTemplateAnnotations(name string) map[string]string {
+	deployment := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, deployment)
+	Expect(err).ToNot(HaveOccurred())
+	return deployment.Spec.Template.Annotations
+}
+
+func (t *cryostatTestInput) setDeploymentRolledOut(name string) {
+	deployment := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, deployment)
+	Expect(err).ToNot(HaveOccurred())
+	deployment.Status.ObservedGeneration = deployment.Generation
+	deployment.Status.Replicas = *deployment.Spec.Replicas
+	deployment.Status.UpdatedReplicas = *deployment.Spec.Replicas
+	deployment.Status.ReadyReplicas = *deployment.Spec.Replicas
+	deployment.Status.AvailableReplicas = *deployment.Spec.Replicas
+	err = t.Client.Status().Update(context.Background(), deployment)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func (t *cryostatTestInput) getSecret(name string) *corev1.Secret {
+	secret := &corev1.Secret{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, secret)
+	Expect(err).ToNot(HaveOccurred())
+	return secret
+}
+
+// getCoreTruststoreSources returns the sources of the certificates trusted by Cryostat
+func (t *cryostatTestInput) getCoreTruststoreSources() []corev1.VolumeProjection {
+	deploy := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+	Expect(err).ToNot(HaveOccurred())
+	idx := slices.IndexFunc(deploy.Spec.Template.Spec.Volumes, func(volume corev1.Volume) bool {
+		return volume.Name == "cert-secrets"
+	})
+	Expect(idx).ToNot(Equal(-1))
+	volume := deploy.Spec.Template.Spec.Volumes[idx]
+	Expect(volume.Projected).ToNot(BeNil())
+	return volume.Projected.Sources
+}
+
+func (t *cryostatTestInput) getAgentProxyNginxConf() string {
+	expected := t.NewAgentProxyConfigMap()
+	cm := &corev1.ConfigMap{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, cm)
+	Expect(err).ToNot(HaveOccurred())
+	return cm.Data["nginx.conf"]
+}
+
+func (t *cryostatTestInput) getCertificate(expected *certv1.Certificate) *certv1.Certificate {
+	cert := &certv1.Certificate{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, cert)
+	Expect(err).ToNot(HaveOccurred())
+	return cert
+}
+
+func (t *cryostatTestInput) expectCertificateIssuer(issuerRef certMeta.ObjectReference) {
+	certs := []*certv1.Certificate{t.NewCryostatCert(), t.NewReportsCert(), t.NewAgentProxyCert(), t.NewDatabaseCert(), t.NewStorageCert()}
+	for _, ns := range t.TargetNamespaces {
+		agentCert := t.NewAgentCert(ns)
+		agentCert.Spec.Subject = &certv1.X509Subject{OrganizationalUnits: []string{t.GetAgentServiceName()}}
+		certs = append(certs, agentCert)
+	}
+	for _, expected := range certs {
+		cert := t.getCertificate(expected)
+		Expect(cert.Spec.IssuerRef).To(Equal(issuerRef))
+		expected.Spec.IssuerRef = issuerRef
+		Expect(cert.Spec).To(Equal(expected.Spec))
+	}
+}
+
+func (t *cryostatTestInput) expectNoCryostatCA() {
+	cert := &certv1.Certificate{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewCACert().Name, Namespace: t.Namespace}, cert)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+	secret := &corev1.Secret{}
+	err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewCACert().Spec.SecretName, Namespace: t.Namespace}, secret)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+	for _, expected := range []*certv1.Issuer{t.NewSelfSignedIssuer(), t.NewCryostatCAIssuer()} {
+		issuer := &certv1.Issuer{}
+		err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, issuer)
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+	}
+}
+
+func (t *cryostatTestInput) getBuiltInCA() *x509.Certificate {
+	return t.parseSecretCertificate(t.getSecret(t.NewCACert().Spec.SecretName))
+}
+
+func (t *cryostatTestInput) receiveEvents() []string {
+	recorder := t.reconciler.GetConfig().EventRecorder.(*record.FakeRecorder)
+	events := []string{}
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func (t *cryostatTestInput) getCertificateExpiryMetric(secretName string, namespace string) float64 {
+	families, err := metrics.Registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+	for _, family := range families {
+		if family.GetName() != "cryostat_operator_certificate_expiry_seconds" {
+			continue
+		}
+		for _, metric := range family.Metric {
+			labels := map[string]string{}
+			for _, label := range metric.Label {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["cryostat"] == t.Name && labels["cryostat_namespace"] == t.Namespace &&
+				labels["secret"] == secretName && labels["namespace"] == namespace {
+				return metric.Gauge.GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func (t *cryostatTestInput) parseSecretCertificate(secret *corev1.Secret) *x509.Certificate {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	Expect(block).ToNot(BeNil())
+	cert, err := x509.ParseCertificate(block.Bytes)
+	Expect(err).ToNot(HaveOccurred())
+	return cert
+}
+
+func (t *cryostatTestInput) getProvidedKeystoreSecret() *corev1.Secret {
+	return t.getSecret(t.Name + "-provided-tls")
+}
+
+func (t *cryostatTestInput) updateProvidedTLSSecret(secret *corev1.Secret) {
+	existing := t.getSecret(secret.Name)
+	existing.Data = secret.Data
+	err := t.Client.Update(context.Background(), existing)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+// getDeploymentVolumeSecrets returns the secret names of the deployment's volumes, indexed by volume name
+func (t *cryostatTestInput) getDeploymentVolumeSecrets(name string) map[string]string {
+	deployment := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, deployment)
+	Expect(err).ToNot(HaveOccurred())
+	secrets := map[string]string{}
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Secret != nil {
+			secrets[volume.Name] = volume.Secret.SecretName
+		}
+	}
+	return secrets
+}
+
 func (t *cryostatTestInput) getCryostatInstance() *model.CryostatInstance {
 	cr, err := t.lookupCryostatInstance()
 	Expect(err).ToNot(HaveOccurred())
@@ -4607,6 +8060,32 @@ func (t *cryostatTestInput) expectTargetNamespaces() {
 	Expect(*cr.TargetNamespaceStatus).To(ConsistOf(t.TargetNamespaces))
 }
 
+func (t *cryostatTestInput) expectTargetNamespaceStatuses(agentPods map[string]int32) {
+	cr := t.getCryostatInstance()
+	Expect(cr.Status.TargetNamespaceStatuses).To(HaveLen(len(t.TargetNamespaces)))
+	for _, ns := range t.TargetNamespaces {
+		status := findTargetNamespaceStatus(cr.Status.TargetNamespaceStatuses, ns)
+		Expect(status).ToNot(BeNil())
+		Expect(*status).To(Equal(operatorv1beta2.TargetNamespaceStatus{
+			Namespace:            ns,
+			RBACReady:            true,
+			AgentTLSReady:        true,
+			CallbackServiceReady: true,
+			InjectedAgentPods:    agentPods[ns],
+		}))
+	}
+}
+
+func findTargetNamespaceStatus(statuses []operatorv1beta2.TargetNamespaceStatus, namespace string) *operatorv1beta2.TargetNamespaceStatus {
+	idx := slices.IndexFunc(statuses, func(status operatorv1beta2.TargetNamespaceStatus) bool {
+		return status.Namespace == namespace
+	})
+	if idx < 0 {
+		return nil
+	}
+	return &statuses[idx]
+}
+
 func (t *cryostatTestInput) expectPredicateToAccept(pred predicate.Predicate, obj ctrlclient.Object) {
 	t.expectPredicate(pred, obj, BeTrue())
 }
@@ -0,0 +1,357 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	common "github.com/cryostatio/cryostat-operator/internal/controller/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controller/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// CryostatRestoreReconciler reconciles a CryostatRestore object
+type CryostatRestoreReconciler struct {
+	*ReconcilerConfig
+}
+
+func NewCryostatRestoreReconciler(config *ReconcilerConfig) *CryostatRestoreReconciler {
+	return &CryostatRestoreReconciler{
+		ReconcilerConfig: config,
+	}
+}
+
+// Name used for Finalizer that releases the Cryostat CR when a CryostatRestore is deleted
+const cryostatRestoreFinalizer = "operator.cryostat.io/cryostatrestore.finalizer"
+
+// How often to check progress of a restore that is waiting on the main Cryostat deployment
+const restoreRequeueDelay = 5 * time.Second
+
+// How long to wait for the main Cryostat deployment to scale down before giving up on a restore
+const restoreCoreStopTimeout = 5 * time.Minute
+
+const (
+	reasonCryostatNotFound      = "CryostatNotFound"
+	reasonBackupNotConfigured   = "BackupNotConfigured"
+	reasonWaitingForRestore     = "WaitingForRestore"
+	reasonWaitingForCoreStop    = "WaitingForCoreToStop"
+	reasonCryostatPaused        = "CryostatPaused"
+	reasonCoreStopTimedOut      = "CoreStopTimedOut"
+	reasonCoreStopped           = "CoreStopped"
+	reasonRestoreRunning        = "RestoreRunning"
+	reasonBackupRestored        = "BackupRestored"
+	reasonRestoreFailed         = "RestoreFailed"
+	reasonWaitingForCoreRestart = "WaitingForCoreToRestart"
+	reasonRestoreSucceeded      = "RestoreSucceeded"
+)
+
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=cryostatrestores,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=cryostatrestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=cryostatrestores/finalizers,verbs=update
+
+// Reconcile processes a CryostatRestore CR, restoring the database of a Cryostat CR from a backup
+func (r *CryostatRestoreReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	reqLogger.Info("Reconciling CryostatRestore")
+
+	restore := &operatorv1beta2.CryostatRestore{}
+	err := r.Get(ctx, request.NamespacedName, restore)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			reqLogger.Info("CryostatRestore instance not found")
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Error reading CryostatRestore instance")
+		return reconcile.Result{}, err
+	}
+
+	cr := &operatorv1beta2.Cryostat{}
+	err = r.Get(ctx, types.NamespacedName{Name: restore.Spec.CryostatName, Namespace: restore.Namespace}, cr)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		cr = nil
+	}
+
+	// Allow the Cryostat CR to start again if this restore is deleted before it finishes
+	if restore.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(restore, cryostatRestoreFinalizer) {
+			if err := r.releaseCryostat(ctx, restore, cr); err != nil {
+				return reconcile.Result{}, err
+			}
+			if err := common.RemoveFinalizer(ctx, r.Client, restore, cryostatRestoreFinalizer); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// A restore is only ever attempted once
+	if meta.FindStatusCondition(restore.Status.Conditions, string(operatorv1beta2.ConditionTypeRestoreComplete)) != nil {
+		return reconcile.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(restore, cryostatRestoreFinalizer) {
+		if err := common.AddFinalizer(ctx, r.Client, restore, cryostatRestoreFinalizer); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if cr == nil {
+		return reconcile.Result{}, r.failRestore(ctx, restore, nil, reasonCryostatNotFound,
+			fmt.Sprintf("Cryostat %s does not exist in namespace %s.", restore.Spec.CryostatName, restore.Namespace))
+	}
+
+	if !meta.IsStatusConditionTrue(restore.Status.Conditions, string(operatorv1beta2.ConditionTypeRestoreDatabaseRestored)) {
+		result, err := r.restoreDatabase(ctx, restore, cr)
+		if err != nil || result.RequeueAfter > 0 ||
+			!meta.IsStatusConditionTrue(restore.Status.Conditions, string(operatorv1beta2.ConditionTypeRestoreDatabaseRestored)) {
+			return result, err
+		}
+	}
+
+	// Start Cryostat again, then wait for it to become available
+	if err := r.releaseCryostat(ctx, restore, cr); err != nil {
+		return reconcile.Result{}, err
+	}
+	deploy := &appsv1.Deployment{}
+	err = r.Get(ctx, types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, deploy)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	if err != nil || !isDeploymentRestarted(deploy) {
+		err = r.setRestoreCondition(ctx, restore, operatorv1beta2.ConditionTypeRestoreCoreStopped, metav1.ConditionTrue,
+			reasonWaitingForCoreRestart, "Waiting for the Cryostat deployment to become available.")
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: restoreRequeueDelay}, nil
+	}
+
+	meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeRestoreCoreStopped),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: restore.Generation,
+		Reason:             reasonRestoreSucceeded,
+		Message:            "The Cryostat deployment has been restarted.",
+	})
+	err = r.setRestoreCondition(ctx, restore, operatorv1beta2.ConditionTypeRestoreComplete, metav1.ConditionTrue,
+		reasonRestoreSucceeded, fmt.Sprintf("Restored backup %s into the database of Cryostat %s.",
+			restore.Spec.BackupName, cr.Name))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	r.EventRecorder.Event(restore, corev1.EventTypeNormal, reasonRestoreSucceeded,
+		fmt.Sprintf("Restored backup %s", restore.Spec.BackupName))
+	reqLogger.Info("Successfully restored database", "backup", restore.Spec.BackupName)
+	return reconcile.Result{}, nil
+}
+
+// restoreDatabase stops the main Cryostat deployment, then runs a Job that restores the backup
+// into its database. The DatabaseRestored condition reports the outcome of the Job.
+func (r *CryostatRestoreReconciler) restoreDatabase(ctx context.Context, restore *operatorv1beta2.CryostatRestore,
+	cr *operatorv1beta2.Cryostat) (ctrl.Result, error) {
+	// Only one restore may run at a time for each Cryostat CR
+	holder, found := cr.Annotations[constants.RestoreInProgressAnnotation]
+	if found && holder != restore.Name {
+		err := r.setRestoreCondition(ctx, restore, operatorv1beta2.ConditionTypeRestoreCoreStopped, metav1.ConditionFalse,
+			reasonWaitingForRestore, fmt.Sprintf("Waiting for CryostatRestore %s to finish.", holder))
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: restoreRequeueDelay}, nil
+	}
+	if !found {
+		patch := client.MergeFrom(cr.DeepCopy())
+		metav1.SetMetaDataAnnotation(&cr.ObjectMeta, constants.RestoreInProgressAnnotation, restore.Name)
+		if err := r.Patch(ctx, cr, patch); err != nil {
+			return reconcile.Result{}, err
+		}
+		// Time the wait for the deployment to scale down from now, rather than from waiting on another restore
+		meta.RemoveStatusCondition(&restore.Status.Conditions, string(operatorv1beta2.ConditionTypeRestoreCoreStopped))
+		r.Log.Info("Stopping Cryostat to restore its database", "name", cr.Name, "namespace", cr.Namespace)
+	}
+
+	// Wait for the Cryostat controller to scale down the main deployment
+	deploy := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, deploy)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	if err == nil && !isDeploymentStopped(deploy) {
+		// The Cryostat controller does not scale down the deployment of a paused Cryostat
+		paused := cr.Annotations[constants.ReconcilePausedAnnotation] == "true"
+		reason := reasonWaitingForCoreStop
+		message := "Waiting for the Cryostat deployment to scale down."
+		if paused {
+			reason = reasonCryostatPaused
+			message = fmt.Sprintf("Cryostat %s is paused, so its deployment cannot be scaled down. Remove the %s annotation from it to continue.",
+				cr.Name, constants.ReconcilePausedAnnotation)
+		}
+		condition := meta.FindStatusCondition(restore.Status.Conditions, string(operatorv1beta2.ConditionTypeRestoreCoreStopped))
+		if condition != nil && condition.Status == metav1.ConditionFalse &&
+			time.Since(condition.LastTransitionTime.Time) > restoreCoreStopTimeout {
+			message = fmt.Sprintf("The Cryostat deployment did not scale down within %s.", restoreCoreStopTimeout)
+			if paused {
+				message = fmt.Sprintf("The Cryostat deployment did not scale down within %s, since Cryostat %s is paused by the %s annotation.",
+					restoreCoreStopTimeout, cr.Name, constants.ReconcilePausedAnnotation)
+			}
+			return reconcile.Result{}, r.failRestore(ctx, restore, cr, reasonCoreStopTimedOut, message)
+		}
+		err = r.setRestoreCondition(ctx, restore, operatorv1beta2.ConditionTypeRestoreCoreStopped, metav1.ConditionFalse,
+			reason, message)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: restoreRequeueDelay}, nil
+	}
+	meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeRestoreCoreStopped),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: restore.Generation,
+		Reason:             reasonCoreStopped,
+		Message:            "The Cryostat deployment has been scaled down.",
+	})
+
+	// The restore job reuses the configuration of the backup job
+	job := &batchv1.Job{}
+	err = r.Get(ctx, types.NamespacedName{Name: restore.Name + "-restore", Namespace: restore.Namespace}, job)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		cronJob := &batchv1.CronJob{}
+		err = r.Get(ctx, types.NamespacedName{Name: resources.DatabaseBackupCronJobName(cr.Name), Namespace: cr.Namespace}, cronJob)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return reconcile.Result{}, r.failRestore(ctx, restore, cr, reasonBackupNotConfigured,
+					fmt.Sprintf("Cryostat %s does not have database backups configured.", cr.Name))
+			}
+			return reconcile.Result{}, err
+		}
+		job = resources.NewJobForDatabaseRestore(restore, cronJob)
+		if err := controllerutil.SetControllerReference(restore, job, r.Scheme); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := r.Create(ctx, job); err != nil {
+			return reconcile.Result{}, err
+		}
+		r.Log.Info("Job created", "name", job.Name, "namespace", job.Namespace)
+	}
+
+	if isJobConditionTrue(job, batchv1.JobFailed) {
+		return reconcile.Result{}, r.failRestore(ctx, restore, cr, reasonRestoreFailed,
+			fmt.Sprintf("Job %s failed to restore backup %s.", job.Name, restore.Spec.BackupName))
+	}
+	if isJobConditionTrue(job, batchv1.JobComplete) {
+		return reconcile.Result{}, r.setRestoreCondition(ctx, restore, operatorv1beta2.ConditionTypeRestoreDatabaseRestored,
+			metav1.ConditionTrue, reasonBackupRestored, fmt.Sprintf("Restored backup %s.", restore.Spec.BackupName))
+	}
+	// The Job is owned by the restore, so its completion triggers another reconcile
+	return reconcile.Result{}, r.setRestoreCondition(ctx, restore, operatorv1beta2.ConditionTypeRestoreDatabaseRestored,
+		metav1.ConditionFalse, reasonRestoreRunning, fmt.Sprintf("Job %s is restoring backup %s.", job.Name,
+			restore.Spec.BackupName))
+}
+
+// failRestore marks the restore as complete but unsuccessful, and allows Cryostat to start again
+func (r *CryostatRestoreReconciler) failRestore(ctx context.Context, restore *operatorv1beta2.CryostatRestore,
+	cr *operatorv1beta2.Cryostat, reason string, message string) error {
+	if err := r.releaseCryostat(ctx, restore, cr); err != nil {
+		return err
+	}
+	meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeRestoreDatabaseRestored),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: restore.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	r.EventRecorder.Event(restore, corev1.EventTypeWarning, reason, message)
+	return r.setRestoreCondition(ctx, restore, operatorv1beta2.ConditionTypeRestoreComplete, metav1.ConditionFalse,
+		reason, message)
+}
+
+// releaseCryostat removes the restore annotation from the Cryostat CR, if held by this restore
+func (r *CryostatRestoreReconciler) releaseCryostat(ctx context.Context, restore *operatorv1beta2.CryostatRestore,
+	cr *operatorv1beta2.Cryostat) error {
+	if cr == nil || cr.Annotations[constants.RestoreInProgressAnnotation] != restore.Name {
+		return nil
+	}
+	patch := client.MergeFrom(cr.DeepCopy())
+	delete(cr.Annotations, constants.RestoreInProgressAnnotation)
+	if err := r.Patch(ctx, cr, patch); err != nil {
+		return err
+	}
+	r.Log.Info("Starting Cryostat after restoring its database", "name", cr.Name, "namespace", cr.Namespace)
+	return nil
+}
+
+func (r *CryostatRestoreReconciler) setRestoreCondition(ctx context.Context, restore *operatorv1beta2.CryostatRestore,
+	condType operatorv1beta2.CryostatRestoreConditionType, status metav1.ConditionStatus, reason string, message string) error {
+	meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		Type:               string(condType),
+		Status:             status,
+		ObservedGeneration: restore.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	err := r.Status().Update(ctx, restore)
+	if err != nil {
+		r.Log.Error(err, "failed to update CryostatRestore status", "name", restore.Name, "namespace", restore.Namespace)
+	}
+	return err
+}
+
+func isDeploymentStopped(deploy *appsv1.Deployment) bool {
+	return deploy.Spec.Replicas != nil && *deploy.Spec.Replicas == 0 && deploy.Status.Replicas == 0
+}
+
+func isDeploymentRestarted(deploy *appsv1.Deployment) bool {
+	return deploy.Spec.Replicas != nil && *deploy.Spec.Replicas > 0 &&
+		deploy.Status.AvailableReplicas >= *deploy.Spec.Replicas
+}
+
+func isJobConditionTrue(job *batchv1.Job, condType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == condType && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CryostatRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return r.NewControllerBuilder(mgr).
+		For(&operatorv1beta2.CryostatRestore{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
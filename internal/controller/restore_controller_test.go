@@ -0,0 +1,414 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controller"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+)
+
+type restoreTestInput struct {
+	client     ctrlclient.Client
+	controller *controller.CryostatRestoreReconciler
+	objs       []ctrlclient.Object
+	restore    *operatorv1beta2.CryostatRestore
+	*test.TestResources
+	test.TestReconcilerConfig
+}
+
+var _ = Describe("CryostatRestoreController", func() {
+	var t *restoreTestInput
+
+	BeforeEach(func() {
+		t = &restoreTestInput{
+			TestResources: &test.TestResources{
+				Name:      "cryostat",
+				Namespace: "test",
+			},
+			TestReconcilerConfig: test.TestReconcilerConfig{
+				ControllerBuilder: &test.TestCtrlBuilder{},
+			},
+		}
+		t.restore = t.NewCryostatRestore()
+		t.objs = []ctrlclient.Object{t.restore}
+	})
+
+	JustBeforeEach(func() {
+		s := test.NewTestScheme()
+		err := test.SetCreationTimestampAndUUID(t.objs...)
+		Expect(err).ToNot(HaveOccurred())
+		t.client = fake.NewClientBuilder().WithScheme(s).WithObjects(t.objs...).
+			WithStatusSubresource(&operatorv1beta2.Cryostat{}, &operatorv1beta2.CryostatRestore{}, &appsv1.Deployment{},
+				&batchv1.Job{}).Build()
+		t.controller = controller.NewCryostatRestoreReconciler(&controller.ReconcilerConfig{
+			Client:               t.client,
+			Scheme:               s,
+			Log:                  zap.New(),
+			EventRecorder:        record.NewFakeRecorder(1024),
+			NewControllerBuilder: test.NewControllerBuilder(&t.TestReconcilerConfig),
+		})
+	})
+
+	Describe("reconciling a restore", func() {
+		Context("for a Cryostat that does not exist", func() {
+			JustBeforeEach(func() {
+				t.expectReconcile(reconcile.Result{})
+			})
+			It("should fail the restore", func() {
+				t.checkCondition(operatorv1beta2.ConditionTypeRestoreComplete, metav1.ConditionFalse, "CryostatNotFound")
+			})
+		})
+
+		Context("while the core deployment is running", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithDatabaseBackup().Object, t.newCoreDeployment(1, 1),
+					t.NewDatabaseBackupCronJob())
+			})
+			JustBeforeEach(func() {
+				t.expectReconcile(reconcile.Result{RequeueAfter: 5 * time.Second})
+			})
+			It("should stop the core deployment", func() {
+				t.expectRestoreAnnotation("restore")
+				t.checkCondition(operatorv1beta2.ConditionTypeRestoreCoreStopped, metav1.ConditionFalse, "WaitingForCoreToStop")
+			})
+			It("should not start the restore", func() {
+				t.expectNoJob()
+			})
+		})
+
+		Context("while the core deployment does not scale down", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithDatabaseBackup().Object, t.newCoreDeployment(1, 1),
+					t.NewDatabaseBackupCronJob())
+			})
+			JustBeforeEach(func() {
+				t.expectReconcile(reconcile.Result{RequeueAfter: 5 * time.Second})
+				t.setConditionTransitionTime(operatorv1beta2.ConditionTypeRestoreCoreStopped, time.Now().Add(-10*time.Minute))
+				t.expectReconcile(reconcile.Result{})
+			})
+			It("should fail the restore", func() {
+				t.expectRestoreAnnotation("")
+				t.expectNoJob()
+				t.checkCondition(operatorv1beta2.ConditionTypeRestoreComplete, metav1.ConditionFalse, "CoreStopTimedOut")
+			})
+			It("should emit a warning event", func() {
+				recorder := t.controller.EventRecorder.(*record.FakeRecorder)
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("Warning CoreStopTimedOut")))
+			})
+		})
+
+		Context("while the Cryostat is paused", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithDatabaseBackup()
+				cr.Object.SetAnnotations(map[string]string{
+					"operator.cryostat.io/reconcile-paused": "true",
+				})
+				t.objs = append(t.objs, cr.Object, t.newCoreDeployment(1, 1), t.NewDatabaseBackupCronJob())
+			})
+			JustBeforeEach(func() {
+				t.expectReconcile(reconcile.Result{RequeueAfter: 5 * time.Second})
+			})
+			It("should report that the Cryostat is paused", func() {
+				t.expectRestoreAnnotation("restore")
+				t.checkCondition(operatorv1beta2.ConditionTypeRestoreCoreStopped, metav1.ConditionFalse, "CryostatPaused")
+				condition := meta.FindStatusCondition(t.getRestore().Status.Conditions, string(operatorv1beta2.ConditionTypeRestoreCoreStopped))
+				Expect(condition.Message).To(ContainSubstring("operator.cryostat.io/reconcile-paused"))
+				t.expectNoJob()
+			})
+			Context("for too long", func() {
+				JustBeforeEach(func() {
+					t.setConditionTransitionTime(operatorv1beta2.ConditionTypeRestoreCoreStopped, time.Now().Add(-10*time.Minute))
+					t.expectReconcile(reconcile.Result{})
+				})
+				It("should fail the restore", func() {
+					t.expectRestoreAnnotation("")
+					t.expectNoJob()
+					t.checkCondition(operatorv1beta2.ConditionTypeRestoreComplete, metav1.ConditionFalse, "CoreStopTimedOut")
+					condition := meta.FindStatusCondition(t.getRestore().Status.Conditions, string(operatorv1beta2.ConditionTypeRestoreComplete))
+					Expect(condition.Message).To(ContainSubstring("paused"))
+				})
+			})
+		})
+
+		Context("after waiting for another restore", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithDatabaseBackup()
+				cr.Object.SetAnnotations(map[string]string{
+					"operator.cryostat.io/restore-in-progress": "other-restore",
+				})
+				t.objs = append(t.objs, cr.Object, t.newCoreDeployment(1, 1), t.NewDatabaseBackupCronJob())
+			})
+			JustBeforeEach(func() {
+				t.expectReconcile(reconcile.Result{RequeueAfter: 5 * time.Second})
+				t.setConditionTransitionTime(operatorv1beta2.ConditionTypeRestoreCoreStopped, time.Now().Add(-10*time.Minute))
+				cr := &operatorv1beta2.Cryostat{}
+				err := t.client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, cr)
+				Expect(err).ToNot(HaveOccurred())
+				cr.Annotations = nil
+				Expect(t.client.Update(context.Background(), cr)).To(Succeed())
+				t.expectReconcile(reconcile.Result{RequeueAfter: 5 * time.Second})
+			})
+			It("should wait for the core deployment to stop", func() {
+				t.expectRestoreAnnotation("restore")
+				t.checkCondition(operatorv1beta2.ConditionTypeRestoreCoreStopped, metav1.ConditionFalse, "WaitingForCoreToStop")
+				t.checkConditionAbsent(operatorv1beta2.ConditionTypeRestoreComplete)
+			})
+		})
+
+		Context("while another restore is in progress", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithDatabaseBackup()
+				cr.Object.SetAnnotations(map[string]string{
+					"operator.cryostat.io/restore-in-progress": "other-restore",
+				})
+				t.objs = append(t.objs, cr.Object, t.newCoreDeployment(0, 0), t.NewDatabaseBackupCronJob())
+			})
+			JustBeforeEach(func() {
+				t.expectReconcile(reconcile.Result{RequeueAfter: 5 * time.Second})
+			})
+			It("should wait for the other restore", func() {
+				t.expectRestoreAnnotation("other-restore")
+				t.checkCondition(operatorv1beta2.ConditionTypeRestoreCoreStopped, metav1.ConditionFalse, "WaitingForRestore")
+				t.expectNoJob()
+			})
+		})
+
+		Context("once the core deployment is stopped", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithDatabaseBackup().Object, t.newCoreDeployment(0, 0),
+					t.NewDatabaseBackupCronJob())
+			})
+			JustBeforeEach(func() {
+				t.expectReconcile(reconcile.Result{})
+			})
+			It("should create the restore job", func() {
+				job := t.getJob()
+				Expect(metav1.IsControlledBy(job, t.getRestore())).To(BeTrue())
+				Expect(job.Labels).To(Equal(map[string]string{"app": t.Name, "component": "database-backup"}))
+				Expect(*job.Spec.BackoffLimit).To(Equal(int32(0)))
+
+				container := job.Spec.Template.Spec.Containers[0]
+				Expect(container.Name).To(Equal("database-restore"))
+				Expect(container.Image).To(Equal("quay.io/cryostat/cryostat-db:latest"))
+				Expect(container.Command[2]).To(ContainSubstring("pg_restore --clean --if-exists"))
+				Expect(container.Env).To(ConsistOf(
+					corev1.EnvVar{
+						Name:  "PGHOST",
+						Value: "cryostat-database.test.svc.cluster.local",
+					},
+					corev1.EnvVar{
+						Name:  "BACKUP_NAME",
+						Value: "20260102T030405Z.dump",
+					},
+				))
+			})
+			It("should update the conditions", func() {
+				t.checkCondition(operatorv1beta2.ConditionTypeRestoreCoreStopped, metav1.ConditionTrue, "CoreStopped")
+				t.checkCondition(operatorv1beta2.ConditionTypeRestoreDatabaseRestored, metav1.ConditionFalse, "RestoreRunning")
+				t.checkConditionAbsent(operatorv1beta2.ConditionTypeRestoreComplete)
+			})
+
+			Context("when the restore job succeeds", func() {
+				JustBeforeEach(func() {
+					t.setJobCondition(batchv1.JobComplete)
+					t.expectReconcile(reconcile.Result{RequeueAfter: 5 * time.Second})
+				})
+				It("should restart the core deployment", func() {
+					t.expectRestoreAnnotation("")
+					t.checkCondition(operatorv1beta2.ConditionTypeRestoreDatabaseRestored, metav1.ConditionTrue, "BackupRestored")
+					t.checkCondition(operatorv1beta2.ConditionTypeRestoreCoreStopped, metav1.ConditionTrue, "WaitingForCoreToRestart")
+					t.checkConditionAbsent(operatorv1beta2.ConditionTypeRestoreComplete)
+				})
+
+				Context("and the core deployment is available", func() {
+					JustBeforeEach(func() {
+						deploy := t.newCoreDeployment(1, 1)
+						Expect(t.client.Update(context.Background(), deploy)).To(Succeed())
+						deploy.Status = t.newCoreDeployment(1, 1).Status
+						Expect(t.client.Status().Update(context.Background(), deploy)).To(Succeed())
+						t.expectReconcile(reconcile.Result{})
+					})
+					It("should complete the restore", func() {
+						t.checkCondition(operatorv1beta2.ConditionTypeRestoreCoreStopped, metav1.ConditionFalse, "RestoreSucceeded")
+						t.checkCondition(operatorv1beta2.ConditionTypeRestoreComplete, metav1.ConditionTrue, "RestoreSucceeded")
+					})
+					It("should not restore again", func() {
+						Expect(t.client.Delete(context.Background(), t.getJob())).To(Succeed())
+						t.expectReconcile(reconcile.Result{})
+						t.expectNoJob()
+					})
+				})
+			})
+
+			Context("when the restore job fails", func() {
+				JustBeforeEach(func() {
+					t.setJobCondition(batchv1.JobFailed)
+					t.expectReconcile(reconcile.Result{})
+				})
+				It("should fail the restore", func() {
+					t.expectRestoreAnnotation("")
+					t.checkCondition(operatorv1beta2.ConditionTypeRestoreDatabaseRestored, metav1.ConditionFalse, "RestoreFailed")
+					t.checkCondition(operatorv1beta2.ConditionTypeRestoreComplete, metav1.ConditionFalse, "RestoreFailed")
+				})
+			})
+
+			Context("when the restore is deleted", func() {
+				JustBeforeEach(func() {
+					Expect(t.client.Delete(context.Background(), t.getRestore())).To(Succeed())
+					t.expectReconcile(reconcile.Result{})
+				})
+				It("should restart the core deployment", func() {
+					t.expectRestoreAnnotation("")
+					restore := &operatorv1beta2.CryostatRestore{}
+					err := t.client.Get(context.Background(), types.NamespacedName{Name: t.restore.Name, Namespace: t.Namespace}, restore)
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+
+		Context("without database backups configured", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object, t.newCoreDeployment(0, 0))
+			})
+			JustBeforeEach(func() {
+				t.expectReconcile(reconcile.Result{})
+			})
+			It("should fail the restore", func() {
+				t.expectRestoreAnnotation("")
+				t.expectNoJob()
+				t.checkCondition(operatorv1beta2.ConditionTypeRestoreComplete, metav1.ConditionFalse, "BackupNotConfigured")
+			})
+		})
+	})
+
+	Describe("setting up with manager", func() {
+		JustBeforeEach(func() {
+			err := t.controller.SetupWithManager(nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+		It("should watch restores and their jobs", func() {
+			builder := t.ControllerBuilder
+			Expect(builder.ForCalls).To(HaveLen(1))
+			Expect(builder.ForCalls[0].Object).To(Equal(&operatorv1beta2.CryostatRestore{}))
+			Expect(builder.OwnsCalls).To(HaveLen(1))
+			Expect(builder.OwnsCalls[0].Object).To(Equal(&batchv1.Job{}))
+			Expect(builder.CompleteCalled).To(BeTrue())
+		})
+	})
+})
+
+func (t *restoreTestInput) newCoreDeployment(replicas int32, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      t.Name,
+			Namespace: t.Namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          available,
+			AvailableReplicas: available,
+		},
+	}
+}
+
+func (t *restoreTestInput) expectReconcile(expected reconcile.Result) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: t.restore.Name, Namespace: t.Namespace}}
+	result, err := t.controller.Reconcile(context.Background(), req)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(expected))
+}
+
+func (t *restoreTestInput) getRestore() *operatorv1beta2.CryostatRestore {
+	restore := &operatorv1beta2.CryostatRestore{}
+	err := t.client.Get(context.Background(), types.NamespacedName{Name: t.restore.Name, Namespace: t.Namespace}, restore)
+	Expect(err).ToNot(HaveOccurred())
+	return restore
+}
+
+func (t *restoreTestInput) getJob() *batchv1.Job {
+	job := &batchv1.Job{}
+	err := t.client.Get(context.Background(), types.NamespacedName{Name: t.restore.Name + "-restore", Namespace: t.Namespace}, job)
+	Expect(err).ToNot(HaveOccurred())
+	return job
+}
+
+func (t *restoreTestInput) expectNoJob() {
+	job := &batchv1.Job{}
+	err := t.client.Get(context.Background(), types.NamespacedName{Name: t.restore.Name + "-restore", Namespace: t.Namespace}, job)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
+func (t *restoreTestInput) setJobCondition(condType batchv1.JobConditionType) {
+	job := t.getJob()
+	job.Status.Conditions = []batchv1.JobCondition{
+		{
+			Type:   condType,
+			Status: corev1.ConditionTrue,
+		},
+	}
+	Expect(t.client.Status().Update(context.Background(), job)).To(Succeed())
+}
+
+func (t *restoreTestInput) expectRestoreAnnotation(expected string) {
+	cr := &operatorv1beta2.Cryostat{}
+	err := t.client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, cr)
+	Expect(err).ToNot(HaveOccurred())
+	if len(expected) == 0 {
+		Expect(cr.Annotations).ToNot(HaveKey("operator.cryostat.io/restore-in-progress"))
+	} else {
+		Expect(cr.Annotations).To(HaveKeyWithValue("operator.cryostat.io/restore-in-progress", expected))
+	}
+}
+
+func (t *restoreTestInput) checkCondition(condType operatorv1beta2.CryostatRestoreConditionType,
+	status metav1.ConditionStatus, reason string) {
+	condition := meta.FindStatusCondition(t.getRestore().Status.Conditions, string(condType))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+}
+
+func (t *restoreTestInput) setConditionTransitionTime(condType operatorv1beta2.CryostatRestoreConditionType, transitionTime time.Time) {
+	restore := t.getRestore()
+	condition := meta.FindStatusCondition(restore.Status.Conditions, string(condType))
+	Expect(condition).ToNot(BeNil())
+	condition.LastTransitionTime = metav1.NewTime(transitionTime)
+	Expect(t.client.Status().Update(context.Background(), restore)).To(Succeed())
+}
+
+func (t *restoreTestInput) checkConditionAbsent(condType operatorv1beta2.CryostatRestoreConditionType) {
+	Expect(meta.FindStatusCondition(t.getRestore().Status.Conditions, string(condType))).To(BeNil())
+}
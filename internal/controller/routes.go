@@ -17,7 +17,6 @@ package controller
 import (
 	"context"
 	goerrors "errors"
-	"fmt"
 	"net/url"
 
 	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
@@ -26,8 +25,9 @@ import (
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
 	routev1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func newCoreRoute(cr *model.CryostatInstance) *routev1.Route {
@@ -95,31 +95,35 @@ func (r *Reconciler) createOrUpdateRoute(ctx context.Context, route *routev1.Rou
 		}
 	}
 
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, route, func() error {
-		// Set labels and annotations from CR
-		common.MergeLabelsAndAnnotations(&route.ObjectMeta, config.Labels, config.Annotations)
+	// Set labels and annotations from CR
+	common.MergeLabelsAndAnnotations(&route.ObjectMeta, config.Labels, config.Annotations)
 
-		// Set the Cryostat CR as controller
-		if err := controllerutil.SetControllerReference(owner, route, r.Scheme); err != nil {
-			return err
+	// Update Route spec
+	route.Spec.To.Kind = "Service"
+	route.Spec.To.Name = svc.Name
+	route.Spec.Port = &routev1.RoutePort{TargetPort: exposePort.TargetPort}
+	route.Spec.TLS = routeTLS
+
+	// If a custom host has been provided, specify that in the route.
+	// Modifying the route's host after creation appears to have no effect,
+	// so keep the existing host
+	existing := &routev1.Route{}
+	err := r.Get(ctx, types.NamespacedName{Name: route.Name, Namespace: route.Namespace}, existing)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, err
 		}
-		// Update Route spec
-		route.Spec.To.Kind = "Service"
-		route.Spec.To.Name = svc.Name
-		route.Spec.Port = &routev1.RoutePort{TargetPort: exposePort.TargetPort}
-		route.Spec.TLS = routeTLS
-
-		// If a custom host has been provided, specify that in the route.
-		// Modifying the route's host after creation appears to have no effect
-		if route.CreationTimestamp.IsZero() && config.ExternalHost != nil {
+		if config.ExternalHost != nil {
 			route.Spec.Host = *config.ExternalHost
 		}
-		return nil
-	})
+	} else {
+		route.Spec.Host = existing.Spec.Host
+	}
+
+	err = r.applyObject(ctx, route, owner)
 	if err != nil {
 		return nil, err
 	}
-	r.Log.Info(fmt.Sprintf("Route %s", op), "name", route.Name, "namespace", route.Namespace)
 	return route, nil
 }
 
@@ -15,6 +15,7 @@
 package controller
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -24,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -52,7 +54,7 @@ func (r *Reconciler) reconcileAuthProxyCookieSecret(ctx context.Context, cr *mod
 
 		// secret is generated, so don't regenerate it when updating
 		if secret.CreationTimestamp.IsZero() {
-			secret.StringData["OAUTH2_PROXY_COOKIE_SECRET"] = r.GenPasswd(32)
+			secret.StringData[authProxyCookieSecretKey] = r.GenPasswd(32)
 		}
 		return nil
 	})
@@ -62,7 +64,7 @@ const (
 	// The suffix to be appended to the name of a Cryostat CR to name its database secret
 	databaseSecretNameSuffix          = "-db"
 	eventDatabaseSecretMismatchedType = "DatabaseSecretMismatched"
-	eventDatabaseMismatchMsg          = "\"databaseOptions.secretName\" field cannot be updated, please revert its value or re-create this Cryostat custom resource"
+	eventDatabaseMismatchMsg          = "\"databaseOptions.secretName\" field can only be changed to an existing secret with the same ENCRYPTION_KEY, please revert its value or re-create this Cryostat custom resource"
 )
 
 var errDatabaseSecretUpdated = errors.New("database secret cannot be updated, but another secret is specified")
@@ -74,28 +76,44 @@ func (r *Reconciler) reconcileDatabaseConnectionSecret(ctx context.Context, cr *
 			Namespace: cr.InstallNamespace,
 		},
 	}
-	secretName := secret.Name
 	secretProvided := cr.Spec.DatabaseOptions != nil && cr.Spec.DatabaseOptions.SecretName != nil
-	if secretProvided {
-		secretName = *cr.Spec.DatabaseOptions.SecretName
-	}
+	secretName := getDatabaseSecretName(cr)
 
-	// If the CR status contains the secret name, emit an Event in case the configured secret's name does not match.
+	// If the CR status contains the secret name, the configured secret can only be changed to one with the same
+	// encryption key. The database password is then changed to the new secret's connection key, after which the
+	// CR status is updated.
 	if len(cr.Status.DatabaseSecret) > 0 && cr.Status.DatabaseSecret != secretName {
-		r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, eventDatabaseSecretMismatchedType, eventDatabaseMismatchMsg)
-		return errDatabaseSecretUpdated
+		compatible, err := r.isDatabaseSecretCompatible(ctx, cr, secretName)
+		if err != nil {
+			return err
+		}
+		if !compatible {
+			r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, eventDatabaseSecretMismatchedType, eventDatabaseMismatchMsg)
+			return errDatabaseSecretUpdated
+		}
+		return nil
 	}
 
 	if !secretProvided {
-		err := r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
+		// Recover the new keys if the operator stopped while replacing this secret during a rotation
+		rotation, err := r.getCredentialRotationSecret(ctx, cr)
+		if err != nil {
+			return err
+		}
+		err = r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
 			if secret.StringData == nil {
 				secret.StringData = map[string]string{}
 			}
 
 			// Password is generated, so don't regenerate it when updating
 			if secret.CreationTimestamp.IsZero() {
-				secret.StringData[constants.DatabaseSecretConnectionKey] = r.GenPasswd(32)
-				secret.StringData[constants.DatabaseSecretEncryptionKey] = r.GenPasswd(32)
+				if rotation != nil && len(rotation.Data[constants.DatabaseSecretConnectionKey]) > 0 {
+					secret.StringData[constants.DatabaseSecretConnectionKey] = string(rotation.Data[constants.DatabaseSecretConnectionKey])
+					secret.StringData[constants.DatabaseSecretEncryptionKey] = string(rotation.Data[constants.DatabaseSecretEncryptionKey])
+				} else {
+					secret.StringData[constants.DatabaseSecretConnectionKey] = r.GenPasswd(32)
+					secret.StringData[constants.DatabaseSecretEncryptionKey] = r.GenPasswd(32)
+				}
 			}
 
 			secret.Immutable = &[]bool{true}[0]
@@ -111,6 +129,35 @@ func (r *Reconciler) reconcileDatabaseConnectionSecret(ctx context.Context, cr *
 	return r.Status().Update(ctx, cr.Object)
 }
 
+// isDatabaseSecretCompatible returns whether the database secret with the provided name exists,
+// and has the same encryption key as the database secret currently in use
+func (r *Reconciler) isDatabaseSecretCompatible(ctx context.Context, cr *model.CryostatInstance, secretName string) (bool, error) {
+	current := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: cr.Status.DatabaseSecret, Namespace: cr.InstallNamespace}, current)
+	if err != nil {
+		return false, err
+	}
+	secret := &corev1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: cr.InstallNamespace}, secret)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	key := secret.Data[constants.DatabaseSecretEncryptionKey]
+	return len(key) > 0 && len(secret.Data[constants.DatabaseSecretConnectionKey]) > 0 &&
+		bytes.Equal(key, current.Data[constants.DatabaseSecretEncryptionKey]), nil
+}
+
+// getDatabaseSecretName returns the name of the database secret configured for the CR
+func getDatabaseSecretName(cr *model.CryostatInstance) string {
+	if cr.Spec.DatabaseOptions != nil && cr.Spec.DatabaseOptions.SecretName != nil {
+		return *cr.Spec.DatabaseOptions.SecretName
+	}
+	return cr.Name + databaseSecretNameSuffix
+}
+
 // storageSecretNameSuffix is the suffix to be appended to the name of a
 // Cryostat CR to name its object storage secret
 const storageSecretNameSuffix = "-storage"
@@ -158,6 +205,7 @@ func (r *Reconciler) setDataIfNotPresent(secret *corev1.Secret, key string, valu
 	}
 }
 
+// Secrets are not applied, since generated values depend on the contents of the existing secret
 func (r *Reconciler) createOrUpdateSecret(ctx context.Context, secret *corev1.Secret, owner metav1.Object,
 	delegate controllerutil.MutateFn) error {
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
@@ -0,0 +1,246 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"time"
+
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controller/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controller/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controller/model"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// Annotation on a Service requesting a serving certificate from the OpenShift service CA,
+	// which is stored in the named secret
+	annotationServingCertSecretName = "service.beta.openshift.io/serving-cert-secret-name"
+	// Annotation on a secret created by the OpenShift service CA, naming the Service it was issued for
+	annotationServingCertOriginatingService = "service.beta.openshift.io/originating-service-name"
+	// Annotation on a ConfigMap requesting that the OpenShift service CA bundle is injected into it
+	annotationInjectCABundle = "service.beta.openshift.io/inject-cabundle"
+)
+
+var errServiceCAUnavailable = errors.New("the ServiceCA TLS provider is only available on OpenShift")
+
+// setupServiceCATLS uses certificates issued by the OpenShift service CA for Cryostat's services, while
+// the agent proxy and agents use certificates issued by the built-in CA
+func (r *Reconciler) setupServiceCATLS(ctx context.Context, cr *model.CryostatInstance) (*resources.TLSConfig, time.Duration, error) {
+	if !r.IsOpenShift {
+		return nil, 0, errServiceCAUnavailable
+	}
+
+	caConfigMap := newServiceCAConfigMap(cr)
+	serviceCA, err := r.reconcileServiceCAConfigMap(ctx, cr, caConfigMap)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The built-in CA issues the certificates used to authenticate agents
+	ca, err := r.reconcileBuiltInCA(ctx, cr)
+	if err != nil {
+		return nil, 0, err
+	}
+	renewal := ca.renewal
+
+	keystoreSecret, keystorePass, err := r.reconcileKeystorePassword(ctx, cr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cryostatCert := resources.NewCryostatCert(cr, keystoreSecret.Name)
+	reportsCert := resources.NewReportsCert(cr)
+	databaseCert := resources.NewDatabaseCert(cr)
+	storageCert := resources.NewStorageCert(cr)
+	agentProxyCert := resources.NewAgentProxyCert(cr)
+	tlsConfig := &resources.TLSConfig{
+		CryostatSecret:     cryostatCert.Spec.SecretName,
+		DatabaseSecret:     databaseCert.Spec.SecretName,
+		StorageSecret:      storageCert.Spec.SecretName,
+		ReportsSecret:      reportsCert.Spec.SecretName,
+		AgentProxySecret:   agentProxyCert.Spec.SecretName,
+		KeystorePassSecret: keystoreSecret.Name,
+		CACert:             serviceCA,
+		ServiceCAConfigMap: caConfigMap.Name,
+	}
+
+	// The service CA only issues certificates for existing services, so create them before
+	// waiting for their certificates
+	err = r.reconcileServingCertServices(ctx, cr, tlsConfig)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	servingCerts := []struct {
+		cert        *certv1.Certificate
+		serviceName string
+		deployed    bool
+		caPEM       []byte
+	}{
+		// Cryostat trusts the built-in CA for agents, and the service CA for the other components
+		{cryostatCert, cr.Name, true, ca.certPEM},
+		{reportsCert, cr.Name + "-reports", cr.Spec.ReportOptions != nil && cr.Spec.ReportOptions.Replicas > 0, serviceCA},
+		{databaseCert, cr.Name + "-database", resources.DeployManagedDatabase(cr), serviceCA},
+		{storageCert, cr.Name + "-storage", resources.DeployManagedStorage(cr), serviceCA},
+	}
+	for _, serving := range servingCerts {
+		if !serving.deployed {
+			// Without a service, the secret is still mounted by other components, so issue its
+			// certificate using the built-in CA instead
+			certRenewal, err := r.reconcileBuiltInCertificate(ctx, cr, ca, serving.cert, keystorePass)
+			if err != nil {
+				return nil, 0, err
+			}
+			renewal = earliestTime(renewal, certRenewal)
+			continue
+		}
+		err := r.reconcileServingCertSecret(ctx, cr, serving.cert, servingCertSecretName(serving.serviceName),
+			serving.caPEM, keystorePass)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// Agents authenticate the agent proxy using the built-in CA
+	certRenewal, err := r.reconcileBuiltInCertificate(ctx, cr, ca, agentProxyCert, keystorePass)
+	if err != nil {
+		return nil, 0, err
+	}
+	renewal = earliestTime(renewal, certRenewal)
+
+	return r.setupBuiltInAgentTLS(ctx, cr, ca, tlsConfig, renewal)
+}
+
+func newServiceCAConfigMap(cr *model.CryostatInstance) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-service-ca",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+}
+
+// reconcileServiceCAConfigMap creates a config map for the OpenShift service CA bundle to be injected
+// into, and returns the bundle once it is available
+func (r *Reconciler) reconcileServiceCAConfigMap(ctx context.Context, cr *model.CryostatInstance,
+	cm *corev1.ConfigMap) ([]byte, error) {
+	// The injected data is managed by OpenShift, so only apply the metadata
+	common.MergeLabelsAndAnnotations(&cm.ObjectMeta, map[string]string{},
+		map[string]string{annotationInjectCABundle: "true"})
+	err := r.applyObject(ctx, cm, cr.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	caBundle := cm.Data[operatorv1beta2.DefaultConfigMapCertificateKey]
+	if len(caBundle) == 0 {
+		r.Log.Info("Waiting for service CA bundle", "name", cm.Name, "namespace", cm.Namespace)
+		return nil, common.ErrCertNotReady
+	}
+	return []byte(caBundle), nil
+}
+
+// reconcileServingCertServices creates the services of deployed components, which request serving
+// certificates from the OpenShift service CA
+func (r *Reconciler) reconcileServingCertServices(ctx context.Context, cr *model.CryostatInstance,
+	tlsConfig *resources.TLSConfig) error {
+	// URLs are set in the specs when the components are reconciled later on
+	specs := &resources.ServiceSpecs{}
+	_, err := r.applyCoreService(ctx, cr, tlsConfig)
+	if err != nil {
+		return err
+	}
+	err = r.reconcileReportsService(ctx, cr, tlsConfig, specs)
+	if err != nil {
+		return err
+	}
+	err = r.reconcileDatabaseService(ctx, cr, specs)
+	if err != nil {
+		return err
+	}
+	return r.reconcileStorageService(ctx, cr, tlsConfig, specs)
+}
+
+// requestServingCertificate annotates the service to request a serving certificate from the
+// OpenShift service CA, if it issues the certificates for Cryostat's services
+func requestServingCertificate(cr *model.CryostatInstance, svc *corev1.Service) {
+	if common.IsServiceCAEnabled(cr) {
+		common.MergeLabelsAndAnnotations(&svc.ObjectMeta, map[string]string{},
+			map[string]string{annotationServingCertSecretName: servingCertSecretName(svc.Name)})
+	}
+}
+
+func servingCertSecretName(serviceName string) string {
+	return serviceName + "-serving-cert"
+}
+
+// reconcileServingCertSecret copies the serving certificate issued by the OpenShift service CA into
+// the secret used by the component, along with the CA that it trusts and any keystore it requires
+func (r *Reconciler) reconcileServingCertSecret(ctx context.Context, cr *model.CryostatInstance,
+	cert *certv1.Certificate, servingSecretName string, caPEM []byte, keystorePass string) error {
+	servingSecret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: servingSecretName, Namespace: cr.InstallNamespace}, servingSecret)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			r.Log.Info("Waiting for serving certificate", "name", servingSecretName, "namespace", cr.InstallNamespace)
+			return common.ErrCertNotReady
+		}
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cert.Spec.SecretName,
+			Namespace: cert.Namespace,
+		},
+	}
+	keystore := cert.Spec.Keystores != nil && cert.Spec.Keystores.PKCS12 != nil
+	return r.createOrUpdateBuiltInCertSecret(ctx, cr, secret, func() error {
+		data := map[string][]byte{
+			corev1.TLSCertKey:       servingSecret.Data[corev1.TLSCertKey],
+			corev1.TLSPrivateKeyKey: servingSecret.Data[corev1.TLSPrivateKeyKey],
+			constants.CAKey:         caPEM,
+		}
+		if keystore {
+			// Encoding the keystore is not deterministic, so only do so when its inputs change
+			sourceHash := keystoreSourceHash(&corev1.Secret{Data: data}, keystorePass)
+			if secret.Annotations[annotationKeystoreSourceHash] == sourceHash && len(secret.Data[constants.KeyStoreFile]) > 0 {
+				data[constants.KeyStoreFile] = secret.Data[constants.KeyStoreFile]
+			} else {
+				keyPair, err := tls.X509KeyPair(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey])
+				if err != nil {
+					return err
+				}
+				data[constants.KeyStoreFile], err = encodeKeystore(&keyPair, caPEM, keystorePass)
+				if err != nil {
+					return err
+				}
+				common.MergeLabelsAndAnnotations(&secret.ObjectMeta, map[string]string{},
+					map[string]string{annotationKeystoreSourceHash: sourceHash})
+			}
+		}
+		secret.Data = data
+		return nil
+	})
+}
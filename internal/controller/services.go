@@ -34,6 +34,20 @@ import (
 
 func (r *Reconciler) reconcileCoreService(ctx context.Context, cr *model.CryostatInstance,
 	tls *resources.TLSConfig, specs *resources.ServiceSpecs) error {
+	svc, err := r.applyCoreService(ctx, cr, tls)
+	if err != nil {
+		return err
+	}
+
+	if r.IsOpenShift {
+		return r.reconcileCoreRoute(ctx, svc, cr, tls, specs)
+	} else {
+		return r.reconcileCoreIngress(ctx, cr, specs)
+	}
+}
+
+func (r *Reconciler) applyCoreService(ctx context.Context, cr *model.CryostatInstance,
+	tls *resources.TLSConfig) (*corev1.Service, error) {
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -43,6 +57,7 @@ func (r *Reconciler) reconcileCoreService(ctx context.Context, cr *model.Cryosta
 	config := configureCoreService(cr)
 
 	err := r.createOrUpdateService(ctx, svc, cr.Object, &config.ServiceConfig, func() error {
+		requestServingCertificate(cr, svc)
 		svc.Spec.Selector = map[string]string{
 			"app":       cr.Name,
 			"component": "cryostat",
@@ -62,14 +77,9 @@ func (r *Reconciler) reconcileCoreService(ctx context.Context, cr *model.Cryosta
 		return nil
 	})
 	if err != nil {
-		return err
-	}
-
-	if r.IsOpenShift {
-		return r.reconcileCoreRoute(ctx, svc, cr, tls, specs)
-	} else {
-		return r.reconcileCoreIngress(ctx, cr, specs)
+		return nil, err
 	}
+	return svc, nil
 }
 
 func (r *Reconciler) reconcileReportsService(ctx context.Context, cr *model.CryostatInstance,
@@ -87,6 +97,7 @@ func (r *Reconciler) reconcileReportsService(ctx context.Context, cr *model.Cryo
 		return r.deleteService(ctx, svc)
 	}
 	err := r.createOrUpdateService(ctx, svc, cr.Object, &config.ServiceConfig, func() error {
+		requestServingCertificate(cr, svc)
 		svc.Spec.Selector = map[string]string{
 			"app":       cr.Name,
 			"component": "reports",
@@ -105,15 +116,24 @@ func (r *Reconciler) reconcileReportsService(ctx context.Context, cr *model.Cryo
 	}
 
 	// Set reports URL for deployment to use
+	specs.ReportsURL = reportsURL(cr, tls)
+	return nil
+}
+
+// reportsURL returns the URL of the reports service, or nil if reports are not deployed
+func reportsURL(cr *model.CryostatInstance, tls *resources.TLSConfig) *url.URL {
+	if cr.Spec.ReportOptions == nil || cr.Spec.ReportOptions.Replicas == 0 {
+		return nil
+	}
+	config := configureReportsService(cr)
 	scheme := constants.HttpsScheme
 	if tls == nil {
 		scheme = constants.HttpScheme
 	}
-	specs.ReportsURL = &url.URL{
+	return &url.URL{
 		Scheme: scheme,
-		Host:   fmt.Sprintf("%s:%d", svc.Name, *config.HTTPPort),
+		Host:   fmt.Sprintf("%s-reports:%d", cr.Name, *config.HTTPPort),
 	}
-	return nil
 }
 
 func newAgentService(cr *model.CryostatInstance) *corev1.Service {
@@ -155,8 +175,19 @@ func (r *Reconciler) reconcileDatabaseService(ctx context.Context, cr *model.Cry
 		},
 	}
 
+	if !resources.DeployManagedDatabase(cr) {
+		// Set database URL for deployment to use
+		specs.DatabaseURL = &url.URL{
+			Scheme: "jdbc:postgresql",
+			Host:   resources.ExternalDatabaseAddress(cr),
+			Path:   resources.ExternalDatabaseName(cr),
+		}
+		return r.deleteService(ctx, svc)
+	}
+
 	port := *config.DatabasePort
 	err := r.createOrUpdateService(ctx, svc, cr.Object, &config.ServiceConfig, func() error {
+		requestServingCertificate(cr, svc)
 		svc.Spec.Selector = map[string]string{
 			"app":       cr.Name,
 			"component": "database",
@@ -198,11 +229,8 @@ func (r *Reconciler) reconcileStorageService(ctx context.Context, cr *model.Cryo
 	if !deployManagedStorage {
 		return r.deleteService(ctx, svc)
 	}
-	scheme := constants.HttpScheme
-	if tls != nil {
-		scheme = constants.HttpsScheme
-	}
 	err := r.createOrUpdateService(ctx, svc, cr.Object, &config.ServiceConfig, func() error {
+		requestServingCertificate(cr, svc)
 		svc.Spec.Selector = map[string]string{
 			"app":       cr.Name,
 			"component": "storage",
@@ -221,15 +249,25 @@ func (r *Reconciler) reconcileStorageService(ctx context.Context, cr *model.Cryo
 	}
 
 	// Set storage URL for deployment to use
-	namedPort, err := GetNamedPort(constants.HttpPortName, svc)
-	if err != nil {
-		return err
+	specs.StorageURL, err = storageURL(cr, tls)
+	return err
+}
+
+// storageURL returns the URL of the managed storage service, or of the provider
+// if an external object storage is used
+func storageURL(cr *model.CryostatInstance, tls *resources.TLSConfig) (*url.URL, error) {
+	if !resources.DeployManagedStorage(cr) {
+		return url.Parse(*cr.Spec.ObjectStorageOptions.Provider.URL)
 	}
-	specs.StorageURL = &url.URL{
-		Scheme: scheme,
-		Host:   fmt.Sprintf("%s.%s.svc.cluster.local:%d", svc.Name, svc.Namespace, namedPort.Port),
+	config := configureStorageService(cr)
+	scheme := constants.HttpScheme
+	if tls != nil {
+		scheme = constants.HttpsScheme
 	}
-	return nil
+	return &url.URL{
+		Scheme: scheme,
+		Host:   fmt.Sprintf("%s-storage.%s.svc.cluster.local:%d", cr.Name, cr.InstallNamespace, *config.HTTPPort),
+	}, nil
 }
 
 func (r *Reconciler) newAgentCallbackService(cr *model.CryostatInstance, namespace string) *corev1.Service {
@@ -248,35 +286,34 @@ func (r *Reconciler) reconcileAgentCallbackServices(ctx context.Context, cr *mod
 	for _, ns := range cr.TargetNamespaces {
 		svc := r.newAgentCallbackService(cr, ns)
 
-		op, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
-			// Update labels and annotations
-			common.MergeLabelsAndAnnotations(&svc.ObjectMeta, config.Labels, config.Annotations)
-
-			// Select agent auto-configuration labels
-			svc.Spec.Selector = map[string]string{
-				constants.AgentLabelCryostatName:      cr.Name,
-				constants.AgentLabelCryostatNamespace: cr.InstallNamespace,
-			}
-			// No Ports. We contact the pods directly using their container ports.
-			svc.Spec.Ports = nil
-
-			// Headless service
-			svc.Spec.Type = corev1.ServiceTypeClusterIP
-			svc.Spec.ClusterIP = corev1.ClusterIPNone
-			return nil
-		})
+		// Update labels and annotations
+		common.MergeLabelsAndAnnotations(&svc.ObjectMeta, config.Labels, config.Annotations)
+
+		// Select agent auto-configuration labels
+		svc.Spec.Selector = map[string]string{
+			constants.AgentLabelCryostatName:      cr.Name,
+			constants.AgentLabelCryostatNamespace: cr.InstallNamespace,
+		}
+		// No Ports. We contact the pods directly using their container ports.
+
+		// Headless service
+		svc.Spec.Type = corev1.ServiceTypeClusterIP
+		svc.Spec.ClusterIP = corev1.ClusterIPNone
+
+		err := r.applyObject(ctx, svc, nil)
 		if err != nil {
-			return err
+			// Continue with other namespaces
+			r.recordTargetNamespaceError(cr, ns, err)
 		}
-		r.Log.Info(fmt.Sprintf("Service %s", op), "name", svc.Name, "namespace", svc.Namespace)
+		targetNamespaceStatus(cr, ns).CallbackServiceReady = err == nil
 	}
 
-	// Delete any RoleBindings in target namespaces that are no longer requested
+	// Delete any Services in target namespaces that are no longer requested
 	for _, ns := range toDelete(cr) {
 		svc := r.newAgentCallbackService(cr, ns)
 		err := r.deleteService(ctx, svc)
 		if err != nil {
-			return err
+			r.recordTargetNamespaceError(cr, ns, err)
 		}
 	}
 
@@ -443,25 +480,17 @@ func configureMetadata(config *operatorv1beta2.ResourceMetadata, appLabel string
 
 func (r *Reconciler) createOrUpdateService(ctx context.Context, svc *corev1.Service, owner metav1.Object,
 	config *operatorv1beta2.ServiceConfig, delegate controllerutil.MutateFn) error {
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
-		// Update labels and annotations
-		common.MergeLabelsAndAnnotations(&svc.ObjectMeta, config.Labels, config.Annotations)
+	// Update labels and annotations
+	common.MergeLabelsAndAnnotations(&svc.ObjectMeta, config.Labels, config.Annotations)
 
-		// Set the Cryostat CR as controller
-		if err := controllerutil.SetControllerReference(owner, svc, r.Scheme); err != nil {
-			return err
-		}
-
-		// Update the service type
-		svc.Spec.Type = *config.ServiceType
-		// Call the delegate for service-specific mutations
-		return delegate()
-	})
+	// Update the service type
+	svc.Spec.Type = *config.ServiceType
+	// Call the delegate for service-specific mutations
+	err := delegate()
 	if err != nil {
 		return err
 	}
-	r.Log.Info(fmt.Sprintf("Service %s", op), "name", svc.Name, "namespace", svc.Namespace)
-	return nil
+	return r.applyObject(ctx, svc, owner)
 }
 
 func (r *Reconciler) deleteService(ctx context.Context, svc *corev1.Service) error {
@@ -0,0 +1,408 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	certMeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/go-logr/logr"
+	configv1 "github.com/openshift/api/config/v1"
+	consolev1 "github.com/openshift/api/console/v1"
+	openshiftoperatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controller"
+	"github.com/cryostatio/cryostat-operator/internal/controller/common"
+	webhook "github.com/cryostatio/cryostat-operator/internal/webhook/v1beta2"
+)
+
+// Options describe the platform that a Cryostat CR is rendered for
+type Options struct {
+	// Render for OpenShift, using Routes and the OpenShift OAuth proxy
+	OpenShift bool
+	// Render as if cert-manager is installed in the cluster
+	CertManager bool
+	// Render as if the cluster is running in FIPS mode
+	FIPS bool
+	// Logger for the reconciler, discards all messages if unset
+	Log *logr.Logger
+}
+
+// Domain used for the hostnames of rendered Routes, in place of the cluster's router
+const routeDomain = "apps.example.com"
+
+// Fixed UID for the rendered CR, so that owner references are reproducible
+const crUID = types.UID("00000000-0000-0000-0000-000000000000")
+
+// Maximum number of reconciles before giving up on the CR converging
+const maxReconciles = 20
+
+// Placeholder value for generated passwords, which are never output
+const generatedPassword = "generated"
+
+// The kinds of objects managed by the operator, in the order they are output
+var renderedLists = []client.ObjectList{
+	&corev1.ServiceAccountList{},
+	&rbacv1.RoleBindingList{},
+	&rbacv1.ClusterRoleBindingList{},
+	&corev1.SecretList{},
+	&corev1.ConfigMapList{},
+	&certv1.IssuerList{},
+	&certv1.CertificateList{},
+	&corev1.PersistentVolumeClaimList{},
+	&corev1.ServiceList{},
+	&networkingv1.NetworkPolicyList{},
+	&networkingv1.IngressList{},
+	&routev1.RouteList{},
+	&appsv1.DeploymentList{},
+	&batchv1.CronJobList{},
+	&batchv1.JobList{},
+	&consolev1.ConsoleLinkList{},
+}
+
+// NewScheme returns a scheme containing all types used by the operator
+func NewScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(operatorv1beta1.AddToScheme(scheme))
+	utilruntime.Must(routev1.AddToScheme(scheme))
+	utilruntime.Must(certv1.AddToScheme(scheme))
+	utilruntime.Must(consolev1.AddToScheme(scheme))
+	utilruntime.Must(configv1.AddToScheme(scheme))
+	utilruntime.Must(openshiftoperatorv1.AddToScheme(scheme))
+	utilruntime.Must(operatorv1beta2.AddToScheme(scheme))
+	return scheme
+}
+
+// Render runs the Cryostat reconciler against a fake cluster containing only the provided CR,
+// and returns every object the reconciler creates. Objects are sorted by kind, namespace and name.
+// The values of Secrets are omitted, and server-populated fields such as status are removed.
+func Render(ctx context.Context, cr *operatorv1beta2.Cryostat, opts *Options) ([]*unstructured.Unstructured, error) {
+	scheme := NewScheme()
+	log := logr.Discard()
+	if opts.Log != nil {
+		log = *opts.Log
+	}
+
+	// Apply the same defaults as the webhook
+	cr = cr.DeepCopy()
+	if len(cr.Namespace) == 0 {
+		cr.Namespace = "default"
+	}
+	err := webhook.NewCryostatDefaulter(&log).Default(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	cr.UID = crUID
+
+	c := newRenderClient(scheme, initialObjects(cr, opts)...)
+	reconciler, err := controller.NewCryostatReconciler(&controller.ReconcilerConfig{
+		Client:                 c,
+		Log:                    log,
+		Scheme:                 scheme,
+		IsOpenShift:            opts.OpenShift,
+		IsCertManagerInstalled: opts.CertManager,
+		EventRecorder:          &record.FakeRecorder{}, // Discards events
+		RESTMapper:             newRESTMapper(opts.CertManager),
+		FIPSEnabled:            opts.FIPS,
+		ReconcilerTLS: common.NewReconcilerTLS(&common.ReconcilerTLSConfig{
+			Client: c,
+			OS:     &renderOSUtils{},
+		}),
+		OSUtils: &renderOSUtils{},
+		Dialer:  &renderDialer{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Reconcile until the reconciler no longer waits on external controllers
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	external := &externalControllers{Client: c, certSecrets: map[types.NamespacedName]bool{}}
+	converged := false
+	for i := 0; i < maxReconciles && !converged; i++ {
+		result, err := reconciler.Reconcile(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		// Longer requeues are scheduled for renewing certificates and rotating credentials,
+		// which have nothing left to do until then
+		converged = result.RequeueAfter == 0 || result.RequeueAfter > controller.PendingRequeueAfter
+		if !converged {
+			err = external.reconcile(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !converged {
+		return nil, fmt.Errorf("Cryostat %s/%s did not finish reconciling after %d attempts", cr.Namespace, cr.Name, maxReconciles)
+	}
+
+	return collectObjects(ctx, c, scheme, external.certSecrets)
+}
+
+func initialObjects(cr *operatorv1beta2.Cryostat, opts *Options) []client.Object {
+	objs := []client.Object{cr}
+	namespaces := map[string]bool{cr.Namespace: true}
+	for _, ns := range cr.Spec.TargetNamespaces {
+		namespaces[ns] = true
+	}
+	for ns := range namespaces {
+		objs = append(objs, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+	}
+	if opts.OpenShift {
+		// Cluster configuration modified by the operator on OpenShift
+		objs = append(objs, &configv1.APIServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}})
+	}
+	return objs
+}
+
+// newRenderClient returns a fake client that emulates server-side apply by replacing the
+// existing object, since only the operator modifies objects in the rendered cluster
+func newRenderClient(scheme *runtime.Scheme, objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&operatorv1beta2.Cryostat{}, &certv1.Certificate{}, &routev1.Route{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if patch.Type() != types.ApplyPatchType {
+					return c.Patch(ctx, obj, patch, opts...)
+				}
+				existing := obj.DeepCopyObject().(client.Object)
+				err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+				if kerrors.IsNotFound(err) {
+					return c.Create(ctx, obj)
+				} else if err != nil {
+					return err
+				}
+				obj.SetResourceVersion(existing.GetResourceVersion())
+				return c.Update(ctx, obj)
+			},
+		}).Build()
+}
+
+func newRESTMapper(certManager bool) meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{certv1.SchemeGroupVersion})
+	if certManager {
+		mapper.Add(certv1.SchemeGroupVersion.WithKind(certv1.IssuerKind), meta.RESTScopeNamespace)
+	}
+	return mapper
+}
+
+// externalControllers mocks the behaviour of controllers the operator waits on,
+// such as cert-manager and the OpenShift router
+type externalControllers struct {
+	client.Client
+	// Secrets created on behalf of cert-manager, which are not rendered
+	certSecrets map[types.NamespacedName]bool
+}
+
+func (e *externalControllers) reconcile(ctx context.Context) error {
+	certs := &certv1.CertificateList{}
+	err := e.List(ctx, certs)
+	if err != nil {
+		return err
+	}
+	for i := range certs.Items {
+		err = e.issueCertificate(ctx, &certs.Items[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	routes := &routev1.RouteList{}
+	err = e.List(ctx, routes)
+	if err != nil {
+		return err
+	}
+	for i := range routes.Items {
+		err = e.admitRoute(ctx, &routes.Items[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *externalControllers) issueCertificate(ctx context.Context, cert *certv1.Certificate) error {
+	if len(cert.Status.Conditions) > 0 {
+		return nil
+	}
+	// The contents of the secret are placeholders, only its existence matters
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cert.Spec.SecretName,
+			Namespace: cert.Namespace,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte(cert.Name + "-cert"),
+			corev1.TLSPrivateKeyKey: []byte(cert.Name + "-key"),
+			certMeta.TLSCAKey:       []byte(cert.Name + "-ca"),
+		},
+	}
+	err := e.Create(ctx, secret)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	e.certSecrets[client.ObjectKeyFromObject(secret)] = true
+
+	cert.Status.Conditions = append(cert.Status.Conditions, certv1.CertificateCondition{
+		Type:   certv1.CertificateConditionReady,
+		Status: certMeta.ConditionTrue,
+	})
+	return e.Status().Update(ctx, cert)
+}
+
+func (e *externalControllers) admitRoute(ctx context.Context, route *routev1.Route) error {
+	if len(route.Status.Ingress) > 0 {
+		return nil
+	}
+	host := route.Spec.Host
+	if len(host) == 0 {
+		host = fmt.Sprintf("%s-%s.%s", route.Name, route.Namespace, routeDomain)
+	}
+	route.Status.Ingress = append(route.Status.Ingress, routev1.RouteIngress{
+		Host: host,
+	})
+	return e.Status().Update(ctx, route)
+}
+
+func collectObjects(ctx context.Context, c client.Client, scheme *runtime.Scheme,
+	excluded map[types.NamespacedName]bool) ([]*unstructured.Unstructured, error) {
+	result := []*unstructured.Unstructured{}
+	for _, list := range renderedLists {
+		list = list.DeepCopyObject().(client.ObjectList)
+		err := c.List(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		objs := make([]client.Object, 0, len(items))
+		for _, item := range items {
+			objs = append(objs, item.(client.Object))
+		}
+		slices.SortFunc(objs, func(a, b client.Object) int {
+			return cmp.Or(cmp.Compare(a.GetNamespace(), b.GetNamespace()), cmp.Compare(a.GetName(), b.GetName()))
+		})
+		for _, obj := range objs {
+			if _, ok := obj.(*corev1.Secret); ok && excluded[client.ObjectKeyFromObject(obj)] {
+				continue
+			}
+			cleaned, err := cleanObject(obj, scheme)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, cleaned)
+		}
+	}
+	return result, nil
+}
+
+// cleanObject removes fields that are populated by the API server or external controllers,
+// and omits the values and annotations of Secrets
+func cleanObject(obj client.Object, scheme *runtime.Scheme) (*unstructured.Unstructured, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	cleaned := &unstructured.Unstructured{Object: content}
+	cleaned.SetGroupVersionKind(gvk)
+
+	unstructured.RemoveNestedField(cleaned.Object, "status")
+	for _, field := range []string{"resourceVersion", "managedFields", "creationTimestamp", "uid", "generation"} {
+		unstructured.RemoveNestedField(cleaned.Object, "metadata", field)
+	}
+	if _, ok := obj.(*appsv1.Deployment); ok {
+		unstructured.RemoveNestedField(cleaned.Object, "spec", "template", "metadata", "creationTimestamp")
+	}
+	if secret, ok := obj.(*corev1.Secret); ok {
+		// Values may be in either field, since the fake client does not migrate stringData
+		data := map[string]interface{}{}
+		for key := range secret.Data {
+			data[key] = ""
+		}
+		for key := range secret.StringData {
+			data[key] = ""
+		}
+		cleaned.Object["data"] = data
+		delete(cleaned.Object, "stringData")
+		// Annotations record when the secret's contents were issued, or a hash of them
+		annotations := cleaned.GetAnnotations()
+		for key := range annotations {
+			annotations[key] = ""
+		}
+		if len(annotations) > 0 {
+			cleaned.SetAnnotations(annotations)
+		}
+	}
+	return cleaned, nil
+}
+
+// renderOSUtils reads image overrides from the environment, but generates
+// placeholder passwords so that the rendered output is reproducible
+type renderOSUtils struct {
+	common.DefaultOSUtils
+}
+
+func (o *renderOSUtils) GenPasswd(length int) string {
+	return strings.Repeat(generatedPassword, length/len(generatedPassword)+1)[:length]
+}
+
+// renderDialer reports every external database as reachable, since the rendered
+// cluster does not have network access
+type renderDialer struct{}
+
+func (d *renderDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	client, server := net.Pipe()
+	server.Close()
+	return client, nil
+}
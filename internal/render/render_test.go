@@ -0,0 +1,191 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"context"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/render"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type renderTestInput struct {
+	cr   *operatorv1beta2.Cryostat
+	opts *render.Options
+	*test.TestResources
+}
+
+var _ = Describe("Render", func() {
+	var t *renderTestInput
+	var objs []*unstructured.Unstructured
+
+	BeforeEach(func() {
+		t = &renderTestInput{
+			opts: &render.Options{
+				CertManager: true,
+			},
+			TestResources: &test.TestResources{
+				Name:             "cryostat",
+				Namespace:        "test",
+				TargetNamespaces: []string{"test"},
+				TLS:              true,
+			},
+		}
+		t.cr = t.NewCryostat().Object.(*operatorv1beta2.Cryostat)
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		objs, err = render.Render(context.Background(), t.cr, t.opts)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should render the Cryostat deployments", func() {
+		Expect(objs).To(ContainElements(
+			haveKindAndName("Deployment", t.Name),
+			haveKindAndName("Deployment", t.Name+"-database"),
+			haveKindAndName("Deployment", t.Name+"-storage"),
+		))
+	})
+
+	It("should render the network policies", func() {
+		Expect(objs).To(ContainElement(haveKindAndName("NetworkPolicy", t.Name+"-internal-ingress")))
+	})
+
+	It("should render the certificates", func() {
+		Expect(objs).To(ContainElement(haveKindAndName("Certificate", t.Name)))
+	})
+
+	It("should not render the certificate secrets", func() {
+		Expect(objs).ToNot(ContainElement(haveKindAndName("Secret", t.Name+"-tls")))
+	})
+
+	It("should omit the values of secrets", func() {
+		for _, obj := range objs {
+			if obj.GetKind() == "Secret" {
+				data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).ToNot(BeEmpty())
+				for _, value := range data {
+					Expect(value).To(BeEmpty())
+				}
+				Expect(obj.Object).ToNot(HaveKey("stringData"))
+				for _, value := range obj.GetAnnotations() {
+					Expect(value).To(BeEmpty())
+				}
+			}
+		}
+	})
+
+	It("should not render status", func() {
+		for _, obj := range objs {
+			Expect(obj.Object).ToNot(HaveKey("status"))
+		}
+	})
+
+	It("should not render any routes", func() {
+		Expect(objs).ToNot(ContainElement(HaveField("Object", HaveKeyWithValue("kind", "Route"))))
+	})
+
+	It("should be reproducible", func() {
+		again, err := render.Render(context.Background(), t.cr, t.opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(again).To(Equal(objs))
+	})
+
+	Context("on OpenShift", func() {
+		BeforeEach(func() {
+			t.opts.OpenShift = true
+		})
+
+		It("should render the route", func() {
+			Expect(objs).To(ContainElement(haveKindAndName("Route", t.Name)))
+		})
+
+		It("should render the console link", func() {
+			Expect(objs).To(ContainElement(HaveField("Object", HaveKeyWithValue("kind", "ConsoleLink"))))
+		})
+	})
+
+	Context("with database backups", func() {
+		BeforeEach(func() {
+			t.cr = t.NewCryostatWithDatabaseBackup().Object.(*operatorv1beta2.Cryostat)
+		})
+
+		It("should render the backup cron job", func() {
+			Expect(objs).To(ContainElement(haveKindAndName("CronJob", t.NewDatabaseBackupCronJob().Name)))
+		})
+	})
+
+	Context("without cert-manager", func() {
+		BeforeEach(func() {
+			t.opts.CertManager = false
+			t.cr.Spec.EnableCertManager = &[]bool{false}[0]
+		})
+
+		It("should not render any certificates", func() {
+			Expect(objs).ToNot(ContainElement(HaveField("Object", HaveKeyWithValue("kind", "Certificate"))))
+			Expect(objs).ToNot(ContainElement(HaveField("Object", HaveKeyWithValue("kind", "Issuer"))))
+		})
+
+		It("should render the Cryostat deployment", func() {
+			Expect(objs).To(ContainElement(haveKindAndName("Deployment", t.Name)))
+		})
+	})
+
+	Context("with the built-in CA", func() {
+		BeforeEach(func() {
+			t.opts.CertManager = false
+			t.cr = t.NewCryostatWithBuiltInCA().Object.(*operatorv1beta2.Cryostat)
+		})
+
+		It("should not render any certificates", func() {
+			Expect(objs).ToNot(ContainElement(HaveField("Object", HaveKeyWithValue("kind", "Certificate"))))
+		})
+
+		It("should render the CA secret", func() {
+			Expect(objs).To(ContainElement(haveKindAndName("Secret", t.NewCACert().Spec.SecretName)))
+		})
+
+		It("should render the Cryostat deployment", func() {
+			Expect(objs).To(ContainElement(haveKindAndName("Deployment", t.Name)))
+		})
+	})
+
+	Context("with credential rotation", func() {
+		BeforeEach(func() {
+			t.cr = t.NewCryostatWithCredentialRotation().Object.(*operatorv1beta2.Cryostat)
+		})
+
+		It("should render the Cryostat deployment", func() {
+			Expect(objs).To(ContainElement(haveKindAndName("Deployment", t.Name)))
+		})
+
+		It("should not render a credential rotation", func() {
+			Expect(objs).ToNot(ContainElement(haveKindAndName("Secret", t.Name+"-credential-rotation")))
+		})
+	})
+})
+
+func haveKindAndName(kind string, name string) OmegaMatcher {
+	return SatisfyAll(
+		HaveField("Object", HaveKeyWithValue("kind", kind)),
+		WithTransform(func(obj *unstructured.Unstructured) string { return obj.GetName() }, Equal(name)),
+	)
+}
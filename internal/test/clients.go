@@ -16,6 +16,9 @@ package test
 
 import (
 	"context"
+	"encoding/json"
+	"maps"
+	"reflect"
 	"strings"
 	"time"
 
@@ -23,13 +26,23 @@ import (
 	certMeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	"github.com/onsi/gomega"
 	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/managedfields"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/applyconfigurations"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 type commonTestClient struct {
@@ -65,6 +78,7 @@ func (c *testClient) Get(ctx context.Context, key ctrlclient.ObjectKey, obj ctrl
 	// If this is a certificate or route, update the status after the first successful Get operation
 	c.makeCertificatesReady(ctx, obj)
 	c.updateRouteStatus(obj)
+	c.issueServiceCACertificates(ctx, obj)
 	return nil
 }
 
@@ -116,6 +130,30 @@ func (c *testClient) updateRouteStatus(obj runtime.Object) {
 	}
 }
 
+func (c *testClient) issueServiceCACertificates(ctx context.Context, obj runtime.Object) {
+	// If the test uses a service CA, mock the behaviour of OpenShift's service CA operator
+	// by injecting its CA bundle into config maps, and creating serving certificates for services
+	if c.ServiceCA == nil {
+		return
+	}
+	switch o := obj.(type) {
+	case *corev1.ConfigMap:
+		if o.Annotations["service.beta.openshift.io/inject-cabundle"] == "true" && len(o.Data["service-ca.crt"]) == 0 {
+			if o.Data == nil {
+				o.Data = map[string]string{}
+			}
+			o.Data["service-ca.crt"] = string(c.ServiceCA.CertPEM)
+			err := c.Client.Update(ctx, o, ctrlclient.FieldOwner(serviceCAFieldManager))
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		}
+	case *corev1.Service:
+		if name, ok := o.Annotations["service.beta.openshift.io/serving-cert-secret-name"]; ok {
+			err := c.Create(ctx, c.NewServingCertSecret(o, name))
+			gomega.Expect(ctrlclient.IgnoreAlreadyExists(err)).ToNot(gomega.HaveOccurred())
+		}
+	}
+}
+
 func (c *testClient) matchesCert(cert *certv1.Certificate) bool {
 	return c.matchesName(cert, c.NewCryostatCert(), c.NewCACert(), c.NewReportsCert(), c.NewAgentProxyCert(),
 		c.NewDatabaseCert(), c.NewStorageCert()) || c.matchesPrefix(cert, c.GetAgentCertPrefix())
@@ -167,6 +205,298 @@ func SetCreationTimestampAndUUID(objs ...ctrlclient.Object) error {
 	return nil
 }
 
+// LegacyFieldManager is the field manager recorded for changes made without specifying one.
+// Earlier versions of the operator used it for the objects they created or updated, before
+// objects were applied.
+const LegacyFieldManager = "manager"
+
+// Field manager for changes made by users
+const userFieldManager = "kubectl-edit"
+
+// Field manager for changes made by OpenShift's service CA operator
+const serviceCAFieldManager = "service-ca-operator"
+
+type applyClient struct {
+	*commonTestClient
+	builtinScheme *runtime.Scheme
+	builtinTypes  managedfields.TypeConverter
+	customTypes   managedfields.TypeConverter
+}
+
+// NewClientWithServerSideApply wraps a Client by emulating server-side apply, which is not
+// supported by the fake client. The fields owned by each field manager are recorded in the
+// managed fields of objects when they are created, updated or applied. Applying a change to
+// a field owned by another field manager results in a conflict, unless forced, as it would
+// with the API server. Changes to immutable fields of resources managed by the operator are
+// rejected.
+func NewClientWithServerSideApply(client ctrlclient.Client) ctrlclient.Client {
+	builtinScheme := runtime.NewScheme()
+	gomega.Expect(clientgoscheme.AddToScheme(builtinScheme)).To(gomega.Succeed())
+	return &applyClient{
+		commonTestClient: newCommonTestClient(client),
+		builtinScheme:    builtinScheme,
+		builtinTypes:     applyconfigurations.NewTypeConverter(builtinScheme),
+		// Without a schema, fields of custom resources are deduced from the objects
+		customTypes: managedfields.NewDeducedTypeConverter(),
+	}
+}
+
+func (c *applyClient) Create(ctx context.Context, obj ctrlclient.Object, opts ...ctrlclient.CreateOption) error {
+	createOpts := &ctrlclient.CreateOptions{}
+	createOpts.ApplyOptions(opts)
+	live, err := c.newObject(obj)
+	if err != nil {
+		return err
+	}
+	err = c.updateManagedFields(live, obj, createOpts.FieldManager)
+	if err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *applyClient) Update(ctx context.Context, obj ctrlclient.Object, opts ...ctrlclient.UpdateOption) error {
+	updateOpts := &ctrlclient.UpdateOptions{}
+	updateOpts.ApplyOptions(opts)
+	live, err := c.newObject(obj)
+	if err != nil {
+		return err
+	}
+	err = c.get(ctx, obj, live)
+	if err != nil {
+		return err
+	}
+	err = c.updateManagedFields(live, obj, updateOpts.FieldManager)
+	if err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *applyClient) Patch(ctx context.Context, obj ctrlclient.Object, patch ctrlclient.Patch,
+	opts ...ctrlclient.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	patchOpts := &ctrlclient.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	if patchOpts.FieldManager == "" {
+		return kerrors.NewBadRequest("PATCH requests of type apply must specify a field manager")
+	}
+
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	applied := &unstructured.Unstructured{}
+	err = json.Unmarshal(data, &applied.Object)
+	if err != nil {
+		return err
+	}
+
+	live, err := c.newObject(obj)
+	if err != nil {
+		return err
+	}
+	err = c.get(ctx, obj, live)
+	exists := err == nil
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	fieldManager, err := c.fieldManagerFor(live)
+	if err != nil {
+		return err
+	}
+	force := patchOpts.Force != nil && *patchOpts.Force
+	result, err := fieldManager.Apply(live, applied, patchOpts.FieldManager, force)
+	if err != nil {
+		return err
+	}
+	merged, err := c.newObject(obj)
+	if err != nil {
+		return err
+	}
+	err = c.Scheme().Convert(result, merged, nil)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		err = c.Client.Create(ctx, merged)
+	} else {
+		err = validateImmutableFields(live, merged)
+		if err != nil {
+			return err
+		}
+		// Metadata managed by the API server cannot be applied
+		merged.SetUID(live.GetUID())
+		merged.SetCreationTimestamp(live.GetCreationTimestamp())
+		merged.SetDeletionTimestamp(live.GetDeletionTimestamp())
+		merged.SetResourceVersion(live.GetResourceVersion())
+		merged.SetGeneration(live.GetGeneration())
+		var specChanged bool
+		specChanged, err = isSpecChanged(live, merged)
+		if err != nil {
+			return err
+		}
+		if specChanged {
+			merged.SetGeneration(live.GetGeneration() + 1)
+		}
+		err = c.Client.Update(ctx, merged)
+	}
+	if err != nil {
+		return err
+	}
+	// Return the object as stored by the API server
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(merged).Elem())
+	return nil
+}
+
+// newObject returns an empty object of the same type as obj, with its type information set
+func (c *applyClient) newObject(obj ctrlclient.Object) (ctrlclient.Object, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return nil, err
+	}
+	newObj, err := c.Scheme().New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	newObj.GetObjectKind().SetGroupVersionKind(gvk)
+	return newObj.(ctrlclient.Object), nil
+}
+
+// get retrieves the live version of obj, keeping the type information of live
+func (c *applyClient) get(ctx context.Context, obj ctrlclient.Object, live ctrlclient.Object) error {
+	gvk := live.GetObjectKind().GroupVersionKind()
+	err := c.Client.Get(ctx, ctrlclient.ObjectKeyFromObject(obj), live)
+	live.GetObjectKind().SetGroupVersionKind(gvk)
+	return err
+}
+
+// updateManagedFields records the fields changed from live to obj as owned by manager
+func (c *applyClient) updateManagedFields(live, obj ctrlclient.Object, manager string) error {
+	if manager == "" {
+		manager = LegacyFieldManager
+	}
+	fieldManager, err := c.fieldManagerFor(live)
+	if err != nil {
+		return err
+	}
+	gvk := live.GetObjectKind().GroupVersionKind()
+	updated := obj.DeepCopyObject().(ctrlclient.Object)
+	updated.GetObjectKind().SetGroupVersionKind(gvk)
+	result, err := fieldManager.Update(live, updated, manager)
+	if err != nil {
+		return err
+	}
+	resultMeta, err := meta.Accessor(result)
+	if err != nil {
+		return err
+	}
+	obj.SetManagedFields(resultMeta.GetManagedFields())
+	return nil
+}
+
+func (c *applyClient) fieldManagerFor(live ctrlclient.Object) (*managedfields.FieldManager, error) {
+	gvk := live.GetObjectKind().GroupVersionKind()
+	typeConverter := c.customTypes
+	if c.builtinScheme.Recognizes(gvk) {
+		typeConverter = c.builtinTypes
+	}
+	return managedfields.NewDefaultFieldManager(typeConverter, c.Scheme(), nopDefaulter{}, c.Scheme(),
+		gvk, gvk.GroupVersion(), "", nil)
+}
+
+// isSpecChanged returns whether the spec of updated differs from that of live, in which case
+// the API server increments the generation of the object
+func isSpecChanged(live, updated ctrlclient.Object) (bool, error) {
+	liveMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return false, err
+	}
+	updatedMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(updated)
+	if err != nil {
+		return false, err
+	}
+	return !equality.Semantic.DeepEqual(liveMap["spec"], updatedMap["spec"]), nil
+}
+
+// nopDefaulter leaves objects unchanged, since the fake client does not set default values
+type nopDefaulter struct{}
+
+func (nopDefaulter) Default(runtime.Object) {}
+
+func validateImmutableFields(existing, applied ctrlclient.Object) error {
+	var errs field.ErrorList
+	switch old := existing.(type) {
+	case *appsv1.Deployment:
+		if !equality.Semantic.DeepEqual(old.Spec.Selector, applied.(*appsv1.Deployment).Spec.Selector) {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "selector"), applied.(*appsv1.Deployment).Spec.Selector,
+				"field is immutable"))
+		}
+	case *corev1.ConfigMap:
+		cm := applied.(*corev1.ConfigMap)
+		if old.Immutable != nil && *old.Immutable &&
+			(!equality.Semantic.DeepEqual(old.Immutable, cm.Immutable) || !equality.Semantic.DeepEqual(old.Data, cm.Data)) {
+			errs = append(errs, field.Forbidden(field.NewPath("data"), "field is immutable when `immutable` is set"))
+		}
+	case *corev1.PersistentVolumeClaim:
+		spec := old.Spec.DeepCopy()
+		spec.Resources = applied.(*corev1.PersistentVolumeClaim).Spec.Resources
+		if !equality.Semantic.DeepEqual(*spec, applied.(*corev1.PersistentVolumeClaim).Spec) {
+			errs = append(errs, field.Forbidden(field.NewPath("spec"), "spec is immutable after creation except resources.requests"))
+		}
+	case *rbacv1.RoleBinding:
+		if !equality.Semantic.DeepEqual(old.RoleRef, applied.(*rbacv1.RoleBinding).RoleRef) {
+			errs = append(errs, field.Invalid(field.NewPath("roleRef"), applied.(*rbacv1.RoleBinding).RoleRef, "cannot change roleRef"))
+		}
+	case *rbacv1.ClusterRoleBinding:
+		if !equality.Semantic.DeepEqual(old.RoleRef, applied.(*rbacv1.ClusterRoleBinding).RoleRef) {
+			errs = append(errs, field.Invalid(field.NewPath("roleRef"), applied.(*rbacv1.ClusterRoleBinding).RoleRef, "cannot change roleRef"))
+		}
+	}
+	if len(errs) > 0 {
+		return kerrors.NewInvalid(existing.GetObjectKind().GroupVersionKind().GroupKind(), applied.GetName(), errs)
+	}
+	return nil
+}
+
+// CreateLegacyObjects creates the given objects, as if they were created by an earlier
+// version of the operator. Labels and annotations with custom values are then added
+// separately, as if by a user.
+func CreateLegacyObjects(ctx context.Context, client ctrlclient.Client, objs ...ctrlclient.Object) {
+	for _, obj := range objs {
+		labels := obj.GetLabels()
+		annotations := obj.GetAnnotations()
+		obj.SetLabels(withoutValue(labels, customLabelValue))
+		obj.SetAnnotations(withoutValue(annotations, customAnnotationValue))
+		err := SetCreationTimestampAndUUID(obj)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		err = client.Create(ctx, obj, ctrlclient.FieldOwner(LegacyFieldManager))
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+		if !maps.Equal(labels, obj.GetLabels()) || !maps.Equal(annotations, obj.GetAnnotations()) {
+			obj.SetLabels(labels)
+			obj.SetAnnotations(annotations)
+			err := client.Update(ctx, obj, ctrlclient.FieldOwner(userFieldManager))
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		}
+	}
+}
+
+func withoutValue(m map[string]string, value string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	result := maps.Clone(m)
+	maps.DeleteFunc(result, func(_ string, v string) bool {
+		return v == value
+	})
+	return result
+}
+
 type clientWithError struct {
 	*commonTestClient
 	err     *kerrors.StatusError
@@ -212,6 +542,21 @@ func (c *clientCreateError) Create(ctx context.Context, obj ctrlclient.Object, o
 	return c.Client.Create(ctx, obj, opts...)
 }
 
+func (c *clientCreateError) Patch(ctx context.Context, obj ctrlclient.Object, patch ctrlclient.Patch,
+	opts ...ctrlclient.PatchOption) error {
+	// Applying an object that does not yet exist creates it
+	if patch.Type() == types.ApplyPatchType && c.matchFn(obj) {
+		exists, err := c.exists(ctx, obj)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return c.err
+		}
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
 type clientUpdateError struct {
 	*clientWithError
 }
@@ -237,6 +582,68 @@ func (c *clientUpdateError) Update(ctx context.Context, obj ctrlclient.Object,
 	return c.Client.Update(ctx, obj, opts...)
 }
 
+func (c *clientUpdateError) Patch(ctx context.Context, obj ctrlclient.Object, patch ctrlclient.Patch,
+	opts ...ctrlclient.PatchOption) error {
+	// Applying an object that already exists updates it
+	if patch.Type() == types.ApplyPatchType && c.matchFn(obj) {
+		exists, err := c.exists(ctx, obj)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return c.err
+		}
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+type clientStatusUpdateError struct {
+	*clientWithError
+}
+
+// NewClientWithStatusUpdateError wraps a Client by returning an error when updating
+// the status of a specified object
+func NewClientWithStatusUpdateError(client ctrlclient.Client, err *kerrors.StatusError,
+	matchFn FailClientMatchFn) ctrlclient.Client {
+	return &clientStatusUpdateError{
+		clientWithError: &clientWithError{
+			commonTestClient: newCommonTestClient(client),
+			err:              err,
+			matchFn:          matchFn,
+		},
+	}
+}
+
+func (c *clientStatusUpdateError) Status() ctrlclient.SubResourceWriter {
+	return &statusWriterUpdateError{
+		SubResourceWriter: c.Client.Status(),
+		err:               c.err,
+		matchFn:           c.matchFn,
+	}
+}
+
+type statusWriterUpdateError struct {
+	ctrlclient.SubResourceWriter
+	err     *kerrors.StatusError
+	matchFn FailClientMatchFn
+}
+
+func (w *statusWriterUpdateError) Update(ctx context.Context, obj ctrlclient.Object,
+	opts ...ctrlclient.SubResourceUpdateOption) error {
+	if w.matchFn(obj) {
+		return w.err
+	}
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (c *clientWithError) exists(ctx context.Context, obj ctrlclient.Object) (bool, error) {
+	err := c.Client.Get(ctx, ctrlclient.ObjectKeyFromObject(obj), obj.DeepCopyObject().(ctrlclient.Object))
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 func matchesKind(obj, expected ctrlclient.Object, scheme *runtime.Scheme) (*bool, error) {
 	match := false
 	expectKinds, _, err := scheme.ObjectKinds(expected)
@@ -67,11 +67,11 @@ func (r *TestResources) NewCryostatWithSecretsV1Beta1() *operatorv1beta1.Cryosta
 	key := "test.crt"
 	cr.Spec.TrustedCertSecrets = []operatorv1beta1.CertificateSecret{
 		{
-			SecretName:     "testCert1",
+			SecretName:     "test-cert1",
 			CertificateKey: &key,
 		},
 		{
-			SecretName: "testCert2",
+			SecretName: "test-cert2",
 		},
 	}
 	return cr
@@ -81,11 +81,11 @@ func (r *TestResources) NewCryostatWithTemplatesV1Beta1() *operatorv1beta1.Cryos
 	cr := r.NewCryostatV1Beta1()
 	cr.Spec.EventTemplates = []operatorv1beta1.TemplateConfigMap{
 		{
-			ConfigMapName: "templateCM1",
+			ConfigMapName: "template-cm1",
 			Filename:      "template.jfc",
 		},
 		{
-			ConfigMapName: "templateCM2",
+			ConfigMapName: "template-cm2",
 			Filename:      "other-template.jfc",
 		},
 	}
@@ -15,6 +15,9 @@
 package test
 
 import (
+	"context"
+	"errors"
+	"net"
 	"strconv"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,6 +43,7 @@ type TestReconcilerConfig struct {
 	GeneratedPasswords             []string
 	ControllerBuilder              *TestCtrlBuilder
 	CertManagerMissing             bool
+	DatabaseUnreachable            bool
 }
 
 func NewTestReconcilerTLS(config *TestReconcilerConfig) common.ReconcilerTLS {
@@ -116,3 +120,22 @@ func (o *testOSUtils) GenPasswd(length int) string {
 	o.numPassGen++
 	return password
 }
+
+type testDialer struct {
+	unreachable bool
+}
+
+// NewTestDialer returns a Dialer that connects to any address, unless
+// the test configuration marks the database as unreachable
+func NewTestDialer(config *TestReconcilerConfig) common.Dialer {
+	return &testDialer{unreachable: config.DatabaseUnreachable}
+}
+
+func (d *testDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if d.unreachable {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("connection refused")}
+	}
+	client, server := net.Pipe()
+	server.Close()
+	return client, nil
+}
@@ -15,12 +15,20 @@
 package test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"hash/fnv"
+	"math/big"
 	"slices"
 	"strings"
+	"time"
 
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	certMeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
@@ -33,6 +41,7 @@ import (
 	securityv1 "github.com/openshift/api/security/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	authzv1 "k8s.io/api/authorization/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -55,6 +64,7 @@ type TestResources struct {
 	OpenShift                  bool
 	ReportReplicas             int32
 	TargetNamespaces           []string
+	AllNamespaces              bool
 	EnableAudit                *bool
 	InsightsURL                string
 	DisableAgentHostnameVerify bool
@@ -62,6 +72,7 @@ type TestResources struct {
 	DatabaseSecret             *corev1.Secret
 	StorageSecret              *corev1.Secret
 	LogLevel                   string
+	ServiceCA                  *TestCA
 }
 
 func NewTestScheme() *runtime.Scheme {
@@ -117,6 +128,7 @@ func (r *TestResources) newCryostatSpec() operatorv1beta2.CryostatSpec {
 	}
 	spec := operatorv1beta2.CryostatSpec{
 		TargetNamespaces:  r.TargetNamespaces,
+		AllNamespaces:     r.AllNamespaces,
 		EnableCertManager: &certManager,
 		ReportOptions:     reportOptions,
 	}
@@ -144,11 +156,11 @@ func (r *TestResources) NewCryostatWithSecrets() *model.CryostatInstance {
 	cr := r.NewCryostat()
 	cr.Spec.TrustedCertSecrets = []operatorv1beta2.CertificateSecret{
 		{
-			SecretName:     "testCert1",
+			SecretName:     "test-cert1",
 			CertificateKey: &[]string{testCertKey}[0],
 		},
 		{
-			SecretName: "testCert2",
+			SecretName: "test-cert2",
 		},
 	}
 	return cr
@@ -158,11 +170,11 @@ func (r *TestResources) NewCryostatWithTrustedCertConfigMaps() *model.CryostatIn
 	cr := r.NewCryostat()
 	cr.Spec.TrustedCertSecrets = []operatorv1beta2.CertificateSecret{
 		{
-			ConfigMapName:  "testCertCM1",
+			ConfigMapName:  "test-cert-cm1",
 			CertificateKey: &[]string{testCertKey}[0],
 		},
 		{
-			ConfigMapName: "testCertCM2",
+			ConfigMapName: "test-cert-cm2",
 		},
 	}
 	return cr
@@ -172,11 +184,11 @@ func (r *TestResources) NewCryostatWithTemplates() *model.CryostatInstance {
 	cr := r.NewCryostat()
 	cr.Spec.EventTemplates = []operatorv1beta2.TemplateConfigMap{
 		{
-			ConfigMapName: "templateCM1",
+			ConfigMapName: "template-cm1",
 			Filename:      "template.jfc",
 		},
 		{
-			ConfigMapName: "templateCM2",
+			ConfigMapName: "template-cm2",
 			Filename:      "other-template.jfc",
 		},
 	}
@@ -187,11 +199,11 @@ func (r *TestResources) NewCryostatWithAutomatedRules() *model.CryostatInstance
 	cr := r.NewCryostat()
 	cr.Spec.AutomatedRules = []operatorv1beta2.AutomatedRuleConfigMap{
 		{
-			ConfigMapName: "ruleCM1",
+			ConfigMapName: "rule-cm1",
 			Filename:      "rule.json",
 		},
 		{
-			ConfigMapName: "ruleCM2",
+			ConfigMapName: "rule-cm2",
 			Filename:      "other-rule.json",
 		},
 	}
@@ -202,11 +214,11 @@ func (r *TestResources) NewCryostatWithProbeTemplates() *model.CryostatInstance
 	cr := r.NewCryostat()
 	cr.Spec.ProbeTemplates = []operatorv1beta2.ProbeTemplateConfigMap{
 		{
-			ConfigMapName: "probeTemplateCM1",
+			ConfigMapName: "probe-template-cm1",
 			Filename:      "template.xml",
 		},
 		{
-			ConfigMapName: "probeTemplateCM2",
+			ConfigMapName: "probe-template-cm2",
 			Filename:      "other-template.xml",
 		},
 	}
@@ -299,6 +311,120 @@ func (r *TestResources) NewCryostatWithExternalS3(secretName string) *model.Cryo
 	return cr
 }
 
+func (r *TestResources) NewCryostatWithExternalDatabase() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	port := int32(6543)
+	databaseName := "cryostat-db"
+	username := "cryostat-user"
+	sslMode := "verify-full"
+	cr.Spec.DatabaseOptions = &operatorv1beta2.DatabaseOptions{
+		External: &operatorv1beta2.ExternalDatabaseOptions{
+			Host:         "postgres.example.com",
+			Port:         &port,
+			DatabaseName: &databaseName,
+			Username:     &username,
+			SSLMode:      &sslMode,
+			CACertificate: &operatorv1beta2.CertificateSecret{
+				SecretName: "external-db-ca",
+			},
+			PasswordSecret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "external-db-creds",
+				},
+				Key: "password",
+			},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithExternalDatabaseDefaults() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.DatabaseOptions = &operatorv1beta2.DatabaseOptions{
+		External: &operatorv1beta2.ExternalDatabaseOptions{
+			Host: "postgres.example.com",
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithDatabaseBackup() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	retention := int32(3)
+	cr.Spec.DatabaseOptions = &operatorv1beta2.DatabaseOptions{
+		Backup: &operatorv1beta2.DatabaseBackupOptions{
+			Schedule:  "0 2 * * *",
+			Retention: &retention,
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithDatabaseBackupExternalS3(secretName string) *model.CryostatInstance {
+	cr := r.NewCryostatWithExternalS3(secretName)
+	bucketName := "my-backups"
+	cr.Spec.DatabaseOptions = &operatorv1beta2.DatabaseOptions{
+		Backup: &operatorv1beta2.DatabaseBackupOptions{
+			Schedule:   "@daily",
+			BucketName: &bucketName,
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatRestore() *operatorv1beta2.CryostatRestore {
+	return &operatorv1beta2.CryostatRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "restore",
+			Namespace: r.Namespace,
+		},
+		Spec: operatorv1beta2.CryostatRestoreSpec{
+			CryostatName: r.Name,
+			BackupName:   "20260102T030405Z.dump",
+		},
+	}
+}
+
+func (r *TestResources) NewDatabaseBackupCronJob() *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name + "-database-backup",
+			Namespace: r.Namespace,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 2 * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app":       r.Name,
+						"component": "database-backup",
+					},
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:    r.Name + "-database-backup",
+									Image:   "quay.io/cryostat/cryostat-db:latest",
+									Command: []string{"/bin/sh", "-c", "pg_dump"},
+									Env: []corev1.EnvVar{
+										{
+											Name:  "PGHOST",
+											Value: fmt.Sprintf("%s-database.%s.svc.cluster.local", r.Name, r.Namespace),
+										},
+									},
+								},
+							},
+							RestartPolicy: corev1.RestartPolicyNever,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func (r *TestResources) NewCryostatWithCustomizedStorageBucketNames() *model.CryostatInstance {
 	cr := r.NewCryostat()
 	providerUrl := "https://example.com:1234"
@@ -1129,6 +1255,14 @@ func (r *TestResources) NewCryostatWithDatabaseSecretProvided() *model.CryostatI
 	return cr
 }
 
+func (r *TestResources) NewCryostatWithCredentialRotation() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.CredentialRotation = &operatorv1beta2.CredentialRotationOptions{
+		Interval: metav1.Duration{Duration: 2160 * time.Hour},
+	}
+	return cr
+}
+
 func (r *TestResources) NewCryostatWithAdditionalMetadata() *model.CryostatInstance {
 	cr := r.NewCryostat()
 	cr.Spec.OperandMetadata = &operatorv1beta2.OperandMetadata{
@@ -1192,6 +1326,108 @@ func (r *TestResources) NewCryostatWithLoggingOptions() *model.CryostatInstance
 	return cr
 }
 
+func (r *TestResources) NewCryostatWithPodTemplateOverrides() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+		Core: &operatorv1beta2.PodTemplateOverrides{
+			Containers: []corev1.Container{
+				{
+					Name: r.Name,
+					Env: []corev1.EnvVar{
+						{Name: "JAVA_OPTS_APPEND", Value: "-XX:+UseG1GC"},
+					},
+				},
+				r.NewLogShipperContainer(),
+			},
+		},
+		Grafana: &operatorv1beta2.PodTemplateOverrides{
+			Containers: []corev1.Container{
+				{
+					Name: r.Name + "-grafana",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "custom-ca", MountPath: "/etc/custom-ca", ReadOnly: true},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				r.NewCustomCAVolume(),
+			},
+		},
+		Database: &operatorv1beta2.PodTemplateOverrides{
+			InitContainers: []corev1.Container{
+				{
+					Name:    "init-db",
+					Image:   "example.com/init:latest",
+					Command: []string{"/bin/true"},
+				},
+			},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewLogShipperContainer() corev1.Container {
+	return corev1.Container{
+		Name:  "log-shipper",
+		Image: "example.com/log-shipper:latest",
+		Ports: []corev1.ContainerPort{
+			{ContainerPort: 9000},
+		},
+	}
+}
+
+func (r *TestResources) NewCustomCAVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: "custom-ca",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "custom-ca"},
+			},
+		},
+	}
+}
+
+// Digest used to pin images in tests
+const ImageDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func (r *TestResources) NewCryostatWithImageOverrides() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	pullNever := corev1.PullNever
+	cr.Spec.Images = &operatorv1beta2.ImageConfigList{
+		Core: &operatorv1beta2.ImageConfig{
+			Image: "mirror.example.com/cryostat/cryostat:canary",
+		},
+		Database: &operatorv1beta2.ImageConfig{
+			Digest: ImageDigest,
+		},
+		AgentInit: &operatorv1beta2.ImageConfig{
+			Image:      "mirror.example.com/cryostat/cryostat-agent-init:2.0.0",
+			Digest:     ImageDigest,
+			PullPolicy: &pullNever,
+		},
+	}
+	cr.Spec.ImagePullSecrets = r.NewImagePullSecrets()
+	return cr
+}
+
+func (r *TestResources) NewImagePullSecrets() []corev1.LocalObjectReference {
+	return []corev1.LocalObjectReference{
+		{Name: "mirror-pull-secret"},
+	}
+}
+
+func (r *TestResources) NewCustomCAConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "custom-ca",
+			Namespace: r.Namespace,
+		},
+		Data: map[string]string{
+			"ca.crt": "custom-ca-data",
+		},
+	}
+}
+
 func (r *TestResources) NewCryostatService() *corev1.Service {
 	appProtocol := "http"
 	if r.TLS {
@@ -1228,8 +1464,27 @@ func (r *TestResources) NewCryostatService() *corev1.Service {
 	}
 }
 
+func (r *TestResources) newTargetNamespacesPeer() netv1.NetworkPolicyPeer {
+	if r.AllNamespaces {
+		return netv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{},
+		}
+	}
+	return netv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      "kubernetes.io/metadata.name",
+					Operator: "In",
+					Values:   r.TargetNamespaces,
+				},
+			},
+		},
+	}
+}
+
 func (r *TestResources) NewCryostatIngressNetworkPolicy() *netv1.NetworkPolicy {
-	return &netv1.NetworkPolicy{
+	policy := &netv1.NetworkPolicy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-internal-ingress", r.Name),
 			Namespace: r.Namespace,
@@ -1263,32 +1518,25 @@ func (r *TestResources) NewCryostatIngressNetworkPolicy() *netv1.NetworkPolicy {
 						},
 					},
 				},
+			},
+		},
+	}
+	if r.AllNamespaces || len(r.TargetNamespaces) > 0 {
+		policy.Spec.Ingress = append(policy.Spec.Ingress, netv1.NetworkPolicyIngressRule{
+			From: []netv1.NetworkPolicyPeer{
+				r.newTargetNamespacesPeer(),
+			},
+			Ports: []netv1.NetworkPolicyPort{
 				{
-					From: []netv1.NetworkPolicyPeer{
-						{
-							NamespaceSelector: &metav1.LabelSelector{
-								MatchExpressions: []metav1.LabelSelectorRequirement{
-									{
-										Key:      "kubernetes.io/metadata.name",
-										Operator: "In",
-										Values:   r.TargetNamespaces,
-									},
-								},
-							},
-						},
-					},
-					Ports: []netv1.NetworkPolicyPort{
-						{
-							Port: &intstr.IntOrString{IntVal: 8282},
-						},
-					},
+					Port: &intstr.IntOrString{IntVal: 8282},
 				},
 			},
-		},
+		})
 	}
+	return policy
 }
 
-func (r *TestResources) NewCryostatEgressNetworkPolicy() *netv1.NetworkPolicy {
+func (r *TestResources) NewCryostatEgressNetworkPolicy(apiServerAddress string) *netv1.NetworkPolicy {
 	return &netv1.NetworkPolicy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-internal-egress", r.Name),
@@ -1327,7 +1575,7 @@ func (r *TestResources) NewCryostatEgressNetworkPolicy() *netv1.NetworkPolicy {
 					To: []netv1.NetworkPolicyPeer{
 						{
 							IPBlock: &netv1.IPBlock{
-								CIDR: "127.0.0.1/32",
+								CIDR: apiServerAddress + "/32",
 							},
 						},
 					},
@@ -1369,6 +1617,19 @@ func (r *TestResources) NewDatabaseIngressNetworkPolicy() *netv1.NetworkPolicy {
 								},
 							},
 						},
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"kubernetes.io/metadata.name": r.Namespace,
+								},
+							},
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"app":       r.Name,
+									"component": "database-backup",
+								},
+							},
+						},
 					},
 					Ports: []netv1.NetworkPolicyPort{
 						{
@@ -1427,6 +1688,19 @@ func (r *TestResources) NewStorageIngressNetworkPolicy() *netv1.NetworkPolicy {
 								},
 							},
 						},
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"kubernetes.io/metadata.name": r.Namespace,
+								},
+							},
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"app":       r.Name,
+									"component": "database-backup",
+								},
+							},
+						},
 					},
 					Ports: []netv1.NetworkPolicyPort{
 						{
@@ -1895,6 +2169,30 @@ func (r *TestResources) NewExternalStorageSecret(name string) *corev1.Secret {
 	}
 }
 
+func (r *TestResources) NewExternalDatabaseSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "external-db-creds",
+			Namespace: r.Namespace,
+		},
+		Data: map[string][]byte{
+			"password": []byte("external-db-password"),
+		},
+	}
+}
+
+func (r *TestResources) NewExternalDatabaseCASecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "external-db-ca",
+			Namespace: r.Namespace,
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey: []byte("external-db-ca-data"),
+		},
+	}
+}
+
 func (r *TestResources) NewStorageSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2197,7 +2495,7 @@ func (r *TestResources) NewAgentCert(namespace string) *certv1.Certificate {
 
 func (r *TestResources) NewCertSecret(cert *certv1.Certificate) *corev1.Secret {
 	// The secret's data isn't important, we simply need it to exist
-	return &corev1.Secret{
+	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cert.Spec.SecretName,
 			Namespace: cert.Namespace,
@@ -2207,6 +2505,287 @@ func (r *TestResources) NewCertSecret(cert *certv1.Certificate) *corev1.Secret {
 			corev1.TLSPrivateKeyKey: []byte(cert.Name + "-key"),
 		},
 	}
+	// The external issuer includes its CA with issued certificates
+	if cert.Spec.IssuerRef.Name == ExternalIssuerName {
+		secret.Data[certMeta.TLSCAKey] = r.NewExternalIssuerCABytes()
+	}
+	return secret
+}
+
+// ExternalIssuerName is the name of the issuer used by NewCryostatWithIssuerRef
+const ExternalIssuerName = "vault-issuer"
+
+func (r *TestResources) NewCryostatWithIssuerRef(kind string) *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.TLS = &operatorv1beta2.TLSOptions{
+		IssuerRef: &operatorv1beta2.IssuerReference{
+			Name: ExternalIssuerName,
+			Kind: kind,
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewExternalIssuer() *certv1.Issuer {
+	return &certv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ExternalIssuerName,
+			Namespace: r.Namespace,
+		},
+		Spec: certv1.IssuerSpec{
+			IssuerConfig: certv1.IssuerConfig{
+				Vault: &certv1.VaultIssuer{
+					Server: "https://vault.example.com",
+					Path:   "pki/sign/cryostat",
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewExternalIssuerCABytes() []byte {
+	return []byte(ExternalIssuerName + "-ca-bytes")
+}
+
+// TestCA is a certificate authority used to issue certificates provided by the user
+type TestCA struct {
+	Cert    *x509.Certificate
+	Key     *ecdsa.PrivateKey
+	CertPEM []byte
+}
+
+func NewTestCA() *TestCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	return &TestCA{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func (r *TestResources) NewCryostatWithProvidedCertificates() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.TLS = &operatorv1beta2.TLSOptions{
+		Certificates: &operatorv1beta2.TLSCertificates{
+			CoreSecretName:       "core-tls",
+			DatabaseSecretName:   "database-tls",
+			StorageSecretName:    "storage-tls",
+			ReportsSecretName:    "reports-tls",
+			AgentProxySecretName: "agent-proxy-tls",
+			AgentSecretName:      "agent-tls",
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithBuiltInCA() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	provider := operatorv1beta2.TLSProviderBuiltIn
+	cr.Spec.TLS = &operatorv1beta2.TLSOptions{
+		Provider: &provider,
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithServiceCA() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	provider := operatorv1beta2.TLSProviderServiceCA
+	cr.Spec.TLS = &operatorv1beta2.TLSOptions{
+		Provider: &provider,
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithOIDC() *model.CryostatInstance {
+	cr := r.NewCryostatWithIngress()
+	cr.Spec.AuthorizationOptions = &operatorv1beta2.AuthorizationOptions{
+		OIDC: &operatorv1beta2.OIDCConfig{
+			IssuerURL: "https://keycloak.example.com/realms/cryostat",
+			ClientID:  "cryostat",
+			ClientSecret: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "cryostat-oidc",
+				},
+				Key: "client-secret",
+			},
+			AllowedGroups: []string{"cryostat-users"},
+			AllowedEmails: []string{"alice@example.com", "bob@example.com"},
+			CACertificate: &operatorv1beta2.CertificateSecret{
+				ConfigMapName: "keycloak-ca",
+			},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithRoles() *model.CryostatInstance {
+	cr := r.NewCryostatWithIngress()
+	cr.Spec.AuthorizationOptions = &operatorv1beta2.AuthorizationOptions{
+		Roles: []operatorv1beta2.AccessRole{
+			{
+				Name:                "viewer",
+				Groups:              []string{"developers", "qa.team"},
+				AllowedMethods:      []string{"GET", "HEAD"},
+				AllowedPathPrefixes: []string{"/api/v4/recordings", "/api/v4/reports/"},
+			},
+			{
+				Name:   "admin",
+				Groups: []string{"cryostat-admins"},
+			},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewOIDCClientSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cryostat-oidc",
+			Namespace: r.Namespace,
+		},
+		Data: map[string][]byte{
+			"client-secret": []byte("oidc_client_secret"),
+		},
+	}
+}
+
+func (r *TestResources) NewOIDCCAConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "keycloak-ca",
+			Namespace: r.Namespace,
+		},
+		Data: map[string]string{
+			"service-ca.crt": "keycloak_ca",
+		},
+	}
+}
+
+func (r *TestResources) NewCryostatWithTLSSecurityProfile(profileType configv1.TLSProfileType) *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.TLS = &operatorv1beta2.TLSOptions{
+		SecurityProfile: &configv1.TLSSecurityProfile{
+			Type: profileType,
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithPrivateKey() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.TLS = &operatorv1beta2.TLSOptions{
+		PrivateKey: &operatorv1beta2.TLSPrivateKey{
+			Algorithm:      operatorv1beta2.TLSPrivateKeyAlgorithmECDSA,
+			Size:           &[]int32{384}[0],
+			RotationPolicy: "Always",
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCertificateLifetimes() *operatorv1beta2.CertificateLifetimes {
+	return &operatorv1beta2.CertificateLifetimes{
+		CA: &operatorv1beta2.CertificateLifetime{
+			Duration: &metav1.Duration{Duration: 8760 * time.Hour},
+		},
+		Server: &operatorv1beta2.CertificateLifetime{
+			Duration:    &metav1.Duration{Duration: 720 * time.Hour},
+			RenewBefore: &metav1.Duration{Duration: 240 * time.Hour},
+		},
+		Agent: &operatorv1beta2.CertificateLifetime{
+			Duration: &metav1.Duration{Duration: 360 * time.Hour},
+		},
+	}
+}
+
+// NewProvidedTLSSecrets returns the secrets referenced by NewCryostatWithProvidedCertificates,
+// containing certificates issued by the CA for the DNS names expected by the operator
+func (r *TestResources) NewProvidedTLSSecrets(ca *TestCA) []*corev1.Secret {
+	agentDNSNames := []string{}
+	for _, ns := range r.TargetNamespaces {
+		agentDNSNames = append(agentDNSNames, fmt.Sprintf("*.%s.%s.svc", r.GetAgentServiceName(), ns))
+	}
+	return []*corev1.Secret{
+		r.NewProvidedTLSSecret("core-tls", ca, r.serviceDNSNames(r.Name)...),
+		r.NewProvidedTLSSecret("database-tls", ca, r.serviceDNSNames(r.Name+"-database")...),
+		r.NewProvidedTLSSecret("storage-tls", ca, r.serviceDNSNames(r.Name+"-storage")...),
+		r.NewProvidedTLSSecret("reports-tls", ca, r.serviceDNSNames(r.Name+"-reports")...),
+		r.NewProvidedTLSSecret("agent-proxy-tls", ca, r.serviceDNSNames(r.Name+"-agent")...),
+		r.NewProvidedTLSSecret("agent-tls", ca, agentDNSNames...),
+	}
+}
+
+func (r *TestResources) serviceDNSNames(svcName string) []string {
+	return []string{
+		svcName,
+		fmt.Sprintf("%s.%s.svc", svcName, r.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", svcName, r.Namespace),
+	}
+}
+
+func (r *TestResources) NewProvidedTLSSecret(name string, ca *TestCA, dnsNames ...string) *corev1.Secret {
+	return r.newTLSSecret(name, ca, time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour), dnsNames...)
+}
+
+// NewExpiredTLSSecret returns a secret containing a certificate that expired an hour ago
+func (r *TestResources) NewExpiredTLSSecret(name string, ca *TestCA, dnsNames ...string) *corev1.Secret {
+	return r.newTLSSecret(name, ca, time.Now().Add(-48*time.Hour), time.Now().Add(-time.Hour), dnsNames...)
+}
+
+func (r *TestResources) newTLSSecret(name string, ca *TestCA, notBefore time.Time, notAfter time.Time,
+	dnsNames ...string) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     dnsNames,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, key.Public(), ca.Key)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: r.Namespace,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+			certMeta.TLSCAKey:       ca.CertPEM,
+		},
+	}
+}
+
+// NewServingCertSecret returns the secret that the OpenShift service CA creates for a service
+// annotated with the secret name
+func (r *TestResources) NewServingCertSecret(svc *corev1.Service, name string) *corev1.Secret {
+	secret := r.NewProvidedTLSSecret(name, r.ServiceCA, r.serviceDNSNames(svc.Name)...)
+	delete(secret.Data, certMeta.TLSCAKey)
+	secret.Annotations = map[string]string{
+		"service.beta.openshift.io/originating-service-name": svc.Name,
+	}
+	return secret
 }
 
 func (r *TestResources) NewSelfSignedIssuer() *certv1.Issuer {
@@ -3019,7 +3598,8 @@ func (r *TestResources) NewReportsEnvironmentVariables(resources *corev1.Resourc
 	}
 	opts := fmt.Sprintf("-XX:+PrintCommandLineFlags -XX:ActiveProcessorCount=%d -Dorg.openjdk.jmc.flightrecorder.parser.singlethreaded=%t", cpus, cpus < 2)
 	if r.TLS {
-		opts += " -Dquarkus.http.tls-configuration-name=https -Dquarkus.tls.https.reload-period=1h -Dquarkus.tls.https.key-store.pem.0.cert=/var/run/secrets/operator.cryostat.io/cryostat-reports-tls/tls.crt -Dquarkus.tls.https.key-store.pem.0.key=/var/run/secrets/operator.cryostat.io/cryostat-reports-tls/tls.key"
+		opts += " -Dquarkus.http.tls-configuration-name=https -Dquarkus.tls.https.reload-period=1h -Dquarkus.tls.https.key-store.pem.0.cert=/var/run/secrets/operator.cryostat.io/cryostat-reports-tls/tls.crt -Dquarkus.tls.https.key-store.pem.0.key=/var/run/secrets/operator.cryostat.io/cryostat-reports-tls/tls.key" +
+			" -Dquarkus.tls.https.protocols=TLSv1.2,TLSv1.3 -Dquarkus.tls.https.cipher-suites=TLS_AES_128_GCM_SHA256,TLS_AES_256_GCM_SHA384,TLS_CHACHA20_POLY1305_SHA256,TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,TLS_DHE_RSA_WITH_AES_128_GCM_SHA256,TLS_DHE_RSA_WITH_AES_256_GCM_SHA384"
 	}
 	envs := []corev1.EnvVar{
 		{
@@ -3279,10 +3859,14 @@ func (r *TestResources) NewTargetDiscoveryEnvVars(hasPortConfig bool, builtInDis
 		return envs
 	}
 
+	discoveryNamespaces := strings.Join(r.TargetNamespaces, ",")
+	if r.AllNamespaces {
+		discoveryNamespaces = "*"
+	}
 	envs = append(envs,
 		corev1.EnvVar{
 			Name:  "CRYOSTAT_DISCOVERY_KUBERNETES_NAMESPACES",
-			Value: strings.Join(r.TargetNamespaces, ","),
+			Value: discoveryNamespaces,
 		},
 	)
 
@@ -3392,6 +3976,13 @@ func (r *TestResources) NewAuthProxyArguments(authOptions *operatorv1beta2.Autho
 			"--https-address=0.0.0.0:4180",
 			fmt.Sprintf("--tls-cert=/var/run/secrets/operator.cryostat.io/%s/%s", r.Name+"-tls", corev1.TLSCertKey),
 			fmt.Sprintf("--tls-key=/var/run/secrets/operator.cryostat.io/%s/%s", r.Name+"-tls", corev1.TLSPrivateKeyKey),
+			"--tls-min-version=VersionTLS12",
+			"--tls-cipher-suite=TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+			"--tls-cipher-suite=TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+			"--tls-cipher-suite=TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+			"--tls-cipher-suite=TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			"--tls-cipher-suite=TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+			"--tls-cipher-suite=TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
 		)
 	} else {
 		args = append(args,
@@ -3569,15 +4160,15 @@ func (r *TestResources) NewReportsVolumeMounts() []corev1.VolumeMount {
 func (r *TestResources) NewVolumeMountsWithProbeTemplates() []corev1.VolumeMount {
 	return append(r.NewCoreVolumeMounts(),
 		corev1.VolumeMount{
-			Name:      "probe-template-probeTemplateCM1",
+			Name:      "probe-template-probe-template-cm1",
 			ReadOnly:  true,
-			MountPath: "/opt/cryostat.d/probes.d/probeTemplateCM1_template.xml",
+			MountPath: "/opt/cryostat.d/probes.d/probe-template-cm1_template.xml",
 			SubPath:   "template.xml",
 		},
 		corev1.VolumeMount{
-			Name:      "probe-template-probeTemplateCM2",
+			Name:      "probe-template-probe-template-cm2",
 			ReadOnly:  true,
-			MountPath: "/opt/cryostat.d/probes.d/probeTemplateCM2_other-template.xml",
+			MountPath: "/opt/cryostat.d/probes.d/probe-template-cm2_other-template.xml",
 			SubPath:   "other-template.xml",
 		})
 }
@@ -3585,15 +4176,15 @@ func (r *TestResources) NewVolumeMountsWithProbeTemplates() []corev1.VolumeMount
 func (r *TestResources) NewVolumeMountsWithTemplates() []corev1.VolumeMount {
 	return append(r.NewCoreVolumeMounts(),
 		corev1.VolumeMount{
-			Name:      "template-templateCM1",
+			Name:      "template-template-cm1",
 			ReadOnly:  true,
-			MountPath: "/opt/cryostat.d/templates.d/templateCM1_template.jfc",
+			MountPath: "/opt/cryostat.d/templates.d/template-cm1_template.jfc",
 			SubPath:   "template.jfc",
 		},
 		corev1.VolumeMount{
-			Name:      "template-templateCM2",
+			Name:      "template-template-cm2",
 			ReadOnly:  true,
-			MountPath: "/opt/cryostat.d/templates.d/templateCM2_other-template.jfc",
+			MountPath: "/opt/cryostat.d/templates.d/template-cm2_other-template.jfc",
 			SubPath:   "other-template.jfc",
 		})
 }
@@ -3601,15 +4192,15 @@ func (r *TestResources) NewVolumeMountsWithTemplates() []corev1.VolumeMount {
 func (r *TestResources) NewVolumeMountsWithRules() []corev1.VolumeMount {
 	return append(r.NewCoreVolumeMounts(),
 		corev1.VolumeMount{
-			Name:      "rule-ruleCM1",
+			Name:      "rule-rule-cm1",
 			ReadOnly:  true,
-			MountPath: "/opt/cryostat.d/rules.d/ruleCM1_rule.json",
+			MountPath: "/opt/cryostat.d/rules.d/rule-cm1_rule.json",
 			SubPath:   "rule.json",
 		},
 		corev1.VolumeMount{
-			Name:      "rule-ruleCM2",
+			Name:      "rule-rule-cm2",
 			ReadOnly:  true,
-			MountPath: "/opt/cryostat.d/rules.d/ruleCM2_other-rule.json",
+			MountPath: "/opt/cryostat.d/rules.d/rule-cm2_other-rule.json",
 			SubPath:   "other-rule.json",
 		})
 }
@@ -3868,9 +4459,7 @@ func (r *TestResources) OtherDeployment() *appsv1.Deployment {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      r.Name,
 					Namespace: r.Namespace,
-					Labels: map[string]string{
-						"app": "something-app",
-					},
+					Labels:    r.NewMainDeploymentSelector().MatchLabels,
 				},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
@@ -3921,12 +4510,12 @@ func (r *TestResources) NewVolumesWithSecrets() []corev1.Volume {
 		{
 			Secret: &corev1.SecretProjection{
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: "testCert1",
+					Name: "test-cert1",
 				},
 				Items: []corev1.KeyToPath{
 					{
 						Key:  testCertKey,
-						Path: "secret_testCert1_test.crt",
+						Path: "secret_test-cert1_test.crt",
 						Mode: &mode,
 					},
 				},
@@ -3935,12 +4524,12 @@ func (r *TestResources) NewVolumesWithSecrets() []corev1.Volume {
 		{
 			Secret: &corev1.SecretProjection{
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: "testCert2",
+					Name: "test-cert2",
 				},
 				Items: []corev1.KeyToPath{
 					{
 						Key:  "tls.crt",
-						Path: "secret_testCert2_tls.crt",
+						Path: "secret_test-cert2_tls.crt",
 						Mode: &mode,
 					},
 				},
@@ -3955,12 +4544,12 @@ func (r *TestResources) NewVolumesWithTrustedCertConfigMaps() []corev1.Volume {
 		{
 			ConfigMap: &corev1.ConfigMapProjection{
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: "testCertCM1",
+					Name: "test-cert-cm1",
 				},
 				Items: []corev1.KeyToPath{
 					{
 						Key:  testCertKey,
-						Path: "configmap_testCertCM1_test.crt",
+						Path: "configmap_test-cert-cm1_test.crt",
 						Mode: &mode,
 					},
 				},
@@ -3969,12 +4558,12 @@ func (r *TestResources) NewVolumesWithTrustedCertConfigMaps() []corev1.Volume {
 		{
 			ConfigMap: &corev1.ConfigMapProjection{
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: "testCertCM2",
+					Name: "test-cert-cm2",
 				},
 				Items: []corev1.KeyToPath{
 					{
 						Key:  operatorv1beta2.DefaultConfigMapCertificateKey,
-						Path: "configmap_testCertCM2_service-ca.crt",
+						Path: "configmap_test-cert-cm2_service-ca.crt",
 						Mode: &mode,
 					},
 				},
@@ -3987,11 +4576,11 @@ func (r *TestResources) NewVolumesWithTemplates() []corev1.Volume {
 	mode := int32(0440)
 	return append(r.NewVolumes(),
 		corev1.Volume{
-			Name: "template-templateCM1",
+			Name: "template-template-cm1",
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "templateCM1",
+						Name: "template-cm1",
 					},
 					Items: []corev1.KeyToPath{
 						{
@@ -4004,11 +4593,11 @@ func (r *TestResources) NewVolumesWithTemplates() []corev1.Volume {
 			},
 		},
 		corev1.Volume{
-			Name: "template-templateCM2",
+			Name: "template-template-cm2",
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "templateCM2",
+						Name: "template-cm2",
 					},
 					Items: []corev1.KeyToPath{
 						{
@@ -4026,11 +4615,11 @@ func (r *TestResources) NewVolumesWithRules() []corev1.Volume {
 	mode := int32(0440)
 	return append(r.NewVolumes(),
 		corev1.Volume{
-			Name: "rule-ruleCM1",
+			Name: "rule-rule-cm1",
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "ruleCM1",
+						Name: "rule-cm1",
 					},
 					Items: []corev1.KeyToPath{
 						{
@@ -4043,11 +4632,11 @@ func (r *TestResources) NewVolumesWithRules() []corev1.Volume {
 			},
 		},
 		corev1.Volume{
-			Name: "rule-ruleCM2",
+			Name: "rule-rule-cm2",
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "ruleCM2",
+						Name: "rule-cm2",
 					},
 					Items: []corev1.KeyToPath{
 						{
@@ -4065,11 +4654,11 @@ func (r *TestResources) NewVolumesWithProbeTemplates() []corev1.Volume {
 	mode := int32(0440)
 	return append(r.NewVolumes(),
 		corev1.Volume{
-			Name: "probe-template-probeTemplateCM1",
+			Name: "probe-template-probe-template-cm1",
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "probeTemplateCM1",
+						Name: "probe-template-cm1",
 					},
 					Items: []corev1.KeyToPath{
 						{
@@ -4082,11 +4671,11 @@ func (r *TestResources) NewVolumesWithProbeTemplates() []corev1.Volume {
 			},
 		},
 		corev1.Volume{
-			Name: "probe-template-probeTemplateCM2",
+			Name: "probe-template-probe-template-cm2",
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "probeTemplateCM2",
+						Name: "probe-template-cm2",
 					},
 					Items: []corev1.KeyToPath{
 						{
@@ -4353,6 +4942,41 @@ func (r *TestResources) NewDatabaseVolumes() []corev1.Volume {
 	return volumes
 }
 
+func (r *TestResources) NewDatabaseUpgradeVolumes() []corev1.Volume {
+	return []corev1.Volume{
+		{
+			Name: r.Name + "-database",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: r.Name + "-database",
+				},
+			},
+		},
+		{
+			Name: "upgrade",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					// Bounded by the size of the database PVC
+					SizeLimit: &[]resource.Quantity{resource.MustParse("500Mi")}[0],
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewDatabaseUpgradeVolumeMounts() []corev1.VolumeMount {
+	return []corev1.VolumeMount{
+		{
+			Name:      r.Name + "-database",
+			MountPath: "/var/lib/pgsql",
+		},
+		{
+			Name:      "upgrade",
+			MountPath: "/upgrade",
+		},
+	}
+}
+
 func (r *TestResources) NewStorageVolumes() []corev1.Volume {
 	volumes := []corev1.Volume{
 		{
@@ -4381,6 +5005,159 @@ func (r *TestResources) NewStorageVolumes() []corev1.Volume {
 	return volumes
 }
 
+func (r *TestResources) NewDatabaseBackupEnvironmentVariables() []corev1.EnvVar {
+	optional := false
+	scheme := "http"
+	if r.TLS {
+		scheme = "https"
+	}
+	envs := []corev1.EnvVar{
+		{
+			Name:  "PGHOST",
+			Value: fmt.Sprintf("%s-database.%s.svc.cluster.local", r.Name, r.Namespace),
+		},
+		{
+			Name:  "PGPORT",
+			Value: "5432",
+		},
+		{
+			Name:  "PGUSER",
+			Value: "cryostat",
+		},
+		{
+			Name:  "PGDATABASE",
+			Value: "cryostat",
+		},
+		{
+			Name: "PGPASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: r.Name + "-db",
+					},
+					Key:      "CONNECTION_KEY",
+					Optional: &optional,
+				},
+			},
+		},
+		{
+			Name: "S3_ACCESS_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: r.Name + "-storage",
+					},
+					Key:      "ACCESS_KEY",
+					Optional: &optional,
+				},
+			},
+		},
+		{
+			Name: "S3_SECRET_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: r.Name + "-storage",
+					},
+					Key:      "SECRET_KEY",
+					Optional: &optional,
+				},
+			},
+		},
+		{
+			Name:  "S3_BUCKET_URL",
+			Value: fmt.Sprintf("%s://%s-storage.%s.svc.cluster.local:8333/cryostat-database-backups", scheme, r.Name, r.Namespace),
+		},
+		{
+			Name:  "S3_REGION",
+			Value: "us-east-1",
+		},
+		{
+			Name:  "S3_TLS_TRUST_ALL",
+			Value: "false",
+		},
+		{
+			Name:  "BACKUP_PREFIX",
+			Value: fmt.Sprintf("%s/%s/", r.Namespace, r.Name),
+		},
+		{
+			Name:  "BACKUP_PREFIX_QUERY",
+			Value: fmt.Sprintf("%s%%2F%s%%2F", r.Namespace, r.Name),
+		},
+		{
+			Name:  "BACKUP_RETENTION",
+			Value: "3",
+		},
+	}
+	if r.TLS {
+		envs = append(envs,
+			corev1.EnvVar{
+				Name:  "PGSSLMODE",
+				Value: "verify-full",
+			},
+			corev1.EnvVar{
+				Name:  "PGSSLROOTCERT",
+				Value: "/var/run/secrets/operator.cryostat.io/database-backup-tls/ca.crt",
+			},
+			corev1.EnvVar{
+				Name:  "CURL_CA_BUNDLE",
+				Value: "/var/run/secrets/operator.cryostat.io/database-backup-tls/ca.crt",
+			})
+	}
+	return envs
+}
+
+func (r *TestResources) NewDatabaseBackupVolumes() []corev1.Volume {
+	readOnlyMode := int32(0440)
+	volumes := []corev1.Volume{
+		{
+			Name: "backup",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	if r.TLS {
+		volumes = append(volumes, corev1.Volume{
+			Name: "database-backup-tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: r.Name + "-database-tls",
+					Items: []corev1.KeyToPath{
+						{
+							Key:  "ca.crt",
+							Path: "ca.crt",
+							Mode: &readOnlyMode,
+						},
+					},
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+func (r *TestResources) NewDatabaseBackupVolumeMounts() []corev1.VolumeMount {
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      "backup",
+			MountPath: "/backup",
+		},
+	}
+	if r.TLS {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      "database-backup-tls",
+			MountPath: "/var/run/secrets/operator.cryostat.io/database-backup-tls",
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+func (r *TestResources) NewDatabaseBackupPodSecurityContext() *corev1.PodSecurityContext {
+	return r.commonDefaultPodSecurityContext(nil)
+}
+
 func (r *TestResources) commonDefaultPodSecurityContext(fsGroup *int64) *corev1.PodSecurityContext {
 	nonRoot := true
 	var seccompProfile *corev1.SeccompProfile
@@ -4553,10 +5330,9 @@ func (r *TestResources) OtherCoreRoute() *routev1.Route {
 				TargetPort: intstr.FromInt(1234),
 			},
 			TLS: &routev1.TLSConfig{
-				Termination:              routev1.TLSTerminationEdge,
-				Certificate:              "foo",
-				Key:                      "bar",
-				DestinationCACertificate: "baz",
+				Termination: routev1.TLSTerminationEdge,
+				Certificate: "foo",
+				Key:         "bar",
 			},
 		},
 	}
@@ -4735,7 +5511,7 @@ func (r *TestResources) OtherServiceAccount() *corev1.ServiceAccount {
 				"other": customLabelValue,
 			},
 			Annotations: map[string]string{
-				"hello": "world",
+				"hello": customAnnotationValue,
 			},
 		},
 		ImagePullSecrets: []corev1.LocalObjectReference{
@@ -4889,6 +5665,10 @@ func (r *TestResources) NewClusterRoleBinding() *rbacv1.ClusterRoleBinding {
 	return &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: r.getClusterUniqueName(),
+			Labels: map[string]string{
+				"operator.cryostat.io/name":      r.Name,
+				"operator.cryostat.io/namespace": r.Namespace,
+			},
 		},
 		Subjects: []rbacv1.Subject{
 			{
@@ -4905,6 +5685,30 @@ func (r *TestResources) NewClusterRoleBinding() *rbacv1.ClusterRoleBinding {
 	}
 }
 
+func (r *TestResources) NewAllNamespacesClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cryostat-all-namespaces-" + r.clusterUniqueSuffix(""),
+			Labels: map[string]string{
+				"operator.cryostat.io/name":      r.Name,
+				"operator.cryostat.io/namespace": r.Namespace,
+			},
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      r.Name,
+				Namespace: r.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "cryostat-operator-cryostat-namespaced",
+		},
+	}
+}
+
 func (r *TestResources) OtherClusterRoleBinding() *rbacv1.ClusterRoleBinding {
 	return &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
@@ -4931,7 +5735,7 @@ func (r *TestResources) OtherClusterRoleBinding() *rbacv1.ClusterRoleBinding {
 func (r *TestResources) NewProbeTemplateConfigMap() *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "probeTemplateCM1",
+			Name:      "probe-template-cm1",
 			Namespace: r.Namespace,
 		},
 		Data: map[string]string{
@@ -4943,7 +5747,7 @@ func (r *TestResources) NewProbeTemplateConfigMap() *corev1.ConfigMap {
 func (r *TestResources) NewOtherProbeTemplateConfigMap() *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "probeTemplateCM2",
+			Name:      "probe-template-cm2",
 			Namespace: r.Namespace,
 		},
 		Data: map[string]string{
@@ -4955,7 +5759,7 @@ func (r *TestResources) NewOtherProbeTemplateConfigMap() *corev1.ConfigMap {
 func (r *TestResources) NewTemplateConfigMap() *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "templateCM1",
+			Name:      "template-cm1",
 			Namespace: r.Namespace,
 		},
 		Data: map[string]string{
@@ -4985,7 +5789,7 @@ func (r *TestResources) NewAnotherDeclarativeCredentialSecret() *corev1.Secret {
 func (r *TestResources) NewOtherTemplateConfigMap() *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "templateCM2",
+			Name:      "template-cm2",
 			Namespace: r.Namespace,
 		},
 		Data: map[string]string{
@@ -4997,7 +5801,7 @@ func (r *TestResources) NewOtherTemplateConfigMap() *corev1.ConfigMap {
 func (r *TestResources) NewRuleConfigMap() *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "ruleCM1",
+			Name:      "rule-cm1",
 			Namespace: r.Namespace,
 		},
 		Data: map[string]string{
@@ -5009,7 +5813,7 @@ func (r *TestResources) NewRuleConfigMap() *corev1.ConfigMap {
 func (r *TestResources) NewOtherRuleConfigMap() *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "ruleCM2",
+			Name:      "rule-cm2",
 			Namespace: r.Namespace,
 		},
 		Data: map[string]string{
@@ -5026,6 +5830,27 @@ func (r *TestResources) NewNamespace() *corev1.Namespace {
 	}
 }
 
+func (r *TestResources) NewAgentPod(namespace string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "agent-pod",
+			Namespace: namespace,
+			Labels: map[string]string{
+				"cryostat.io/name":      r.Name,
+				"cryostat.io/namespace": r.Namespace,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "app",
+					Image: "example.com/app:latest",
+				},
+			},
+		},
+	}
+}
+
 func (r *TestResources) NewOtherNamespace(name string) *corev1.Namespace {
 	return &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5095,6 +5920,14 @@ func (r *TestResources) NewApiServer() *configv1.APIServer {
 	}
 }
 
+func (r *TestResources) NewApiServerWithTLSSecurityProfile(profileType configv1.TLSProfileType) *configv1.APIServer {
+	apiServer := r.NewApiServer()
+	apiServer.Spec.TLSSecurityProfile = &configv1.TLSSecurityProfile{
+		Type: profileType,
+	}
+	return apiServer
+}
+
 func (r *TestResources) NewApiServerWithApplicationURL() *configv1.APIServer {
 	return &configv1.APIServer{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5334,9 +6167,10 @@ http {
 
 		ssl_dhparam /etc/nginx-cryostat/dhparam.pem;
 
-		# intermediate configuration
+		# TLS security profile
 		ssl_protocols TLSv1.2 TLSv1.3;
-		ssl_ciphers ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384:ECDHE-ECDSA-CHACHA20-POLY1305:ECDHE-RSA-CHACHA20-POLY1305:DHE-RSA-AES128-GCM-SHA256:DHE-RSA-AES256-GCM-SHA384:DHE-RSA-CHACHA20-POLY1305;
+		ssl_ciphers ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384:ECDHE-ECDSA-CHACHA20-POLY1305:ECDHE-RSA-CHACHA20-POLY1305:DHE-RSA-AES128-GCM-SHA256:DHE-RSA-AES256-GCM-SHA384;
+		ssl_conf_command Ciphersuites TLS_AES_128_GCM_SHA256:TLS_AES_256_GCM_SHA384:TLS_CHACHA20_POLY1305_SHA256;
 		ssl_prefer_server_ciphers off;
 
 		# HSTS (ngx_http_headers_module is required) (63072000 seconds)
@@ -5575,7 +6409,16 @@ var alphaConfigTLS = `{
       },
       "Cert": {
         "fromFile": "/var/run/secrets/operator.cryostat.io/%s-tls/tls.crt"
-      }
+      },
+      "MinVersion": "TLS1.2",
+      "CipherSuites": [
+        "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+        "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+        "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+        "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+        "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+        "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"
+      ]
     }
   },
   "upstreamConfig": {
@@ -32,6 +32,7 @@ const grafanaImageEnv = "GRAFANA_IMG"
 const reportsImageEnv = "REPORTS_IMG"
 const storageImageEnv = "STORAGE_IMG"
 const databaseImageEnv = "DATABASE_IMG"
+const databaseMajorVersionEnv = "DATABASE_MAJOR_VERSION"
 const agentProxyImageEnv = "AGENT_PROXY_IMG"
 const agentInitImageEnv = "AGENT_INIT_IMG"
 
@@ -51,6 +52,7 @@ func main() {
 		ReportsImageTag             string
 		StorageImageTag             string
 		DatabaseImageTag            string
+		DatabaseMajorVersion        string
 		AgentProxyImageTag          string
 		AgentInitImageTag           string
 	}{
@@ -64,6 +66,7 @@ func main() {
 		ReportsImageTag:             getEnvVar(reportsImageEnv),
 		StorageImageTag:             getEnvVar(storageImageEnv),
 		DatabaseImageTag:            getEnvVar(databaseImageEnv),
+		DatabaseMajorVersion:        getEnvVar(databaseMajorVersionEnv),
 		AgentProxyImageTag:          getEnvVar(agentProxyImageEnv),
 		AgentInitImageTag:           getEnvVar(agentInitImageEnv),
 	}
@@ -122,6 +125,9 @@ const DefaultStorageImageTag = "{{ .StorageImageTag }}"
 // Default image tag for the Database image
 const DefaultDatabaseImageTag = "{{ .DatabaseImageTag }}"
 
+// PostgreSQL major version of the default Database image
+const DefaultDatabaseMajorVersion = "{{ .DatabaseMajorVersion }}"
+
 // Default image tag for the agent proxy image
 const DefaultAgentProxyImageTag = "{{ .AgentProxyImageTag }}"
 
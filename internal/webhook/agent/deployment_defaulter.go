@@ -61,8 +61,8 @@ func (r *deploymentMutator) Default(ctx context.Context, obj runtime.Object) err
 		return err
 	}
 
-	// Check if this deployment is within a target namespace of the CR
-	if !slices.Contains(cr.Status.TargetNamespaces, deployment.Namespace) {
+	// Check if this deployment is within a target namespace of the CR, unless the CR targets all namespaces
+	if !cr.Spec.AllNamespaces && !slices.Contains(cr.Status.TargetNamespaces, deployment.Namespace) {
 		return fmt.Errorf("deployment's namespace \"%s\" is not a target namespace of Cryostat \"%s\" in \"%s\"",
 			deployment.Namespace, cr.Name, cr.Namespace)
 	}
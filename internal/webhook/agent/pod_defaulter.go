@@ -25,6 +25,7 @@ import (
 	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
 	"github.com/go-logr/logr"
+	configv1 "github.com/openshift/api/config/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,6 +38,8 @@ import (
 
 type podMutator struct {
 	client client.Client
+	// Informer-backed reader for cluster-scoped configuration
+	cache  client.Reader
 	log    *logr.Logger
 	gvk    *schema.GroupVersionKind
 	config *AgentWebhookConfig
@@ -88,15 +91,15 @@ func (r *podMutator) Default(ctx context.Context, obj runtime.Object) error {
 		return err
 	}
 
-	// Check if this pod is within a target namespace of the CR
-	if !slices.Contains(cr.Status.TargetNamespaces, pod.Namespace) {
+	// Check if this pod is within a target namespace of the CR, unless the CR targets all namespaces
+	if !cr.Spec.AllNamespaces && !slices.Contains(cr.Status.TargetNamespaces, pod.Namespace) {
 		return fmt.Errorf("pod's namespace \"%s\" is not a target namespace of Cryostat \"%s\" in \"%s\"",
 			pod.Namespace, cr.Name, cr.Namespace)
 	}
 
 	// Check whether TLS is enabled for this CR
 	crModel := model.FromCryostat(cr)
-	tlsEnabled := r.IsCertManagerEnabled(crModel)
+	tlsEnabled := r.IsTLSEnabled(crModel)
 
 	// Select target container
 	container, err := getTargetContainer(pod)
@@ -136,11 +139,15 @@ func (r *podMutator) Default(ctx context.Context, obj runtime.Object) error {
 
 	// Add init container
 	nonRoot := true
-	imageTag := r.getImageTag()
+	var imageConfig *operatorv1beta2.ImageConfig
+	if cr.Spec.Images != nil {
+		imageConfig = cr.Spec.Images.AgentInit
+	}
+	imageTag := common.ResolveImage(imageConfig, r.getImageTag())
 	pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
 		Name:            "cryostat-agent-init",
 		Image:           imageTag,
-		ImagePullPolicy: common.GetPullPolicy(imageTag),
+		ImagePullPolicy: common.GetImagePullPolicy(imageConfig, imageTag),
 		Command:         []string{"cp", "-v", "/cryostat/agent/cryostat-agent-shaded.jar", constants.AgentJarPath},
 		VolumeMounts: []corev1.VolumeMount{
 			{
@@ -159,6 +166,13 @@ func (r *podMutator) Default(ctx context.Context, obj runtime.Object) error {
 		Resources: *getResourceRequirements(crModel),
 	})
 
+	// Add pull secrets for the init container image
+	for _, secret := range cr.Spec.ImagePullSecrets {
+		if !slices.Contains(pod.Spec.ImagePullSecrets, secret) {
+			pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, secret)
+		}
+	}
+
 	// Add emptyDir volume to copy agent into, and mount it
 	sizeLimit := resource.MustParse(agentMaxSizeBytes)
 	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
@@ -377,7 +391,7 @@ func (r *podMutator) Default(ctx context.Context, obj runtime.Object) error {
 			},
 			corev1.EnvVar{
 				Name:  "CRYOSTAT_AGENT_WEBSERVER_TLS_KEY_TYPE",
-				Value: "RSA",
+				Value: agentKeyType(crModel),
 			},
 			corev1.EnvVar{
 				Name:  "CRYOSTAT_AGENT_WEBSERVER_TLS_KEY_ALIAS",
@@ -393,8 +407,18 @@ func (r *podMutator) Default(ctx context.Context, obj runtime.Object) error {
 			})
 	}
 
-	if r.config.FIPSEnabled {
-		// Force usage for TLSv1.3 for FIPS compatibility
+	// Force usage for TLSv1.3 for FIPS compatibility, or when required by the TLS security profile
+	tls13 := r.config.FIPSEnabled
+	if tlsEnabled && !tls13 {
+		profile, err := common.TLSSecurityProfile(ctx, r.cache, crModel, r.config.IsOpenShift, r.config.FIPSEnabled)
+		if err != nil {
+			// Inject the agent regardless, rather than leaving the pod without it
+			r.log.Error(err, "failed to look up the cluster's TLS security profile, using the default profile")
+			profile = common.DefaultTLSSecurityProfile(crModel, r.config.FIPSEnabled)
+		}
+		tls13 = profile.MinTLSVersion == configv1.VersionTLS13
+	}
+	if tls13 {
 		container.Env = append(container.Env,
 			corev1.EnvVar{
 				Name:  "CRYOSTAT_AGENT_WEBCLIENT_TLS_VERSION",
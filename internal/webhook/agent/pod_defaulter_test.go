@@ -181,6 +181,45 @@ var _ = Describe("PodDefaulter", func() {
 				ExpectPod()
 			})
 
+			Context("with provided TLS certificates", func() {
+				BeforeEach(func() {
+					cr := t.NewCryostatWithProvidedCertificates()
+					// Provided certificates take the place of cert-manager
+					certManager := false
+					cr.Spec.EnableCertManager = &certManager
+					t.objs = append(t.objs, cr.Object)
+					originalPod = t.NewPod()
+					expectedPod = t.NewMutatedPod()
+				})
+
+				ExpectPod()
+			})
+
+			Context("with the built-in CA", func() {
+				BeforeEach(func() {
+					cr := t.NewCryostatWithBuiltInCA()
+					// The built-in CA takes the place of cert-manager
+					certManager := false
+					cr.Spec.EnableCertManager = &certManager
+					t.objs = append(t.objs, cr.Object)
+					originalPod = t.NewPod()
+					expectedPod = t.NewMutatedPod()
+				})
+
+				ExpectPod()
+			})
+
+			Context("with ECDSA private keys", func() {
+				BeforeEach(func() {
+					cr := t.NewCryostatWithPrivateKey()
+					t.objs = append(t.objs, cr.Object)
+					originalPod = t.NewPod()
+					expectedPod = t.NewMutatedPodKeyType("EC")
+				})
+
+				ExpectPod()
+			})
+
 			Context("With Smart Triggers", func() {
 				BeforeEach(func() {
 					t.objs = append(t.objs, t.NewCryostat().Object)
@@ -234,6 +273,17 @@ var _ = Describe("PodDefaulter", func() {
 				ExpectPod()
 			})
 
+			Context("in a non-target namespace with all namespaces enabled", func() {
+				BeforeEach(func() {
+					t.AllNamespaces = true
+					t.objs = append(t.objs, t.NewCryostat().Object)
+					originalPod = t.NewPodOtherNamespace(otherNS)
+					expectedPod = t.NewMutatedPodOtherNamespace(otherNS)
+				})
+
+				ExpectPod()
+			})
+
 			Context("with no name label", func() {
 				BeforeEach(func() {
 					t.objs = append(t.objs, t.NewCryostat().Object)
@@ -318,6 +368,27 @@ var _ = Describe("PodDefaulter", func() {
 				})
 			})
 
+			Context("with image overrides in the Cryostat CR", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithImageOverrides().Object)
+					originalPod = t.NewPod()
+					expectedPod = t.NewMutatedPod()
+				})
+
+				It("should use the overridden image", func() {
+					actual := t.getPod(expectedPod)
+					Expect(actual.Spec.InitContainers).To(HaveLen(1))
+					container := actual.Spec.InitContainers[0]
+					Expect(container.Image).To(Equal("mirror.example.com/cryostat/cryostat-agent-init@" + test.ImageDigest))
+					Expect(container.ImagePullPolicy).To(Equal(corev1.PullNever))
+				})
+
+				It("should add image pull secrets", func() {
+					actual := t.getPod(expectedPod)
+					Expect(actual.Spec.ImagePullSecrets).To(ConsistOf(t.NewImagePullSecrets()))
+				})
+			})
+
 			Context("with a custom gateway port", func() {
 				BeforeEach(func() {
 					t.objs = append(t.objs, t.NewCryostatWithAgentGatewaySvc().Object)
@@ -566,6 +637,24 @@ var _ = Describe("PodDefaulter", func() {
 
 				ExpectPod()
 			})
+
+			Context("on OpenShift without a cluster TLS security profile", func() {
+				BeforeEach(func() {
+					// The APIServer type is not available in this environment,
+					// so the default profile should be used instead
+					agentWebhookConfig.IsOpenShift = true
+
+					t.objs = append(t.objs, t.NewCryostat().Object)
+					originalPod = t.NewPod()
+					expectedPod = t.NewMutatedPod()
+				})
+
+				AfterEach(func() {
+					agentWebhookConfig.IsOpenShift = false
+				})
+
+				ExpectPod()
+			})
 		})
 
 		Context("with a missing Cryostat CR", func() {
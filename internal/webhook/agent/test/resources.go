@@ -408,6 +408,7 @@ type mutatedPodOptions struct {
 	harvesterExitSize int32
 	smartTriggers     string
 	scheme            string
+	keyType           string
 	resources         *corev1.ResourceRequirements
 	// Function to produce mutated container array
 	containersFunc func(*AgentWebhookTestResources, *mutatedPodOptions) []corev1.Container
@@ -444,6 +445,9 @@ func (r *AgentWebhookTestResources) setDefaultMutatedPodOptions(options *mutated
 	if len(options.javaOptionsName) == 0 {
 		options.javaOptionsName = "JAVA_TOOL_OPTIONS"
 	}
+	if len(options.keyType) == 0 {
+		options.keyType = "RSA"
+	}
 	if options.resources == nil {
 		options.resources = &corev1.ResourceRequirements{
 			Requests: corev1.ResourceList{
@@ -469,6 +473,12 @@ func (r *AgentWebhookTestResources) NewMutatedPod() *corev1.Pod {
 	return r.newMutatedPod(&mutatedPodOptions{})
 }
 
+func (r *AgentWebhookTestResources) NewMutatedPodKeyType(keyType string) *corev1.Pod {
+	return r.newMutatedPod(&mutatedPodOptions{
+		keyType: keyType,
+	})
+}
+
 func (r *AgentWebhookTestResources) NewMutatedPodJavaToolOptions() *corev1.Pod {
 	return r.newMutatedPod(&mutatedPodOptions{
 		javaOptionsValue: "-Dexisting=var ",
@@ -858,7 +868,7 @@ func (r *AgentWebhookTestResources) newMutatedContainer(original *corev1.Contain
 			},
 			{
 				Name:  "CRYOSTAT_AGENT_WEBSERVER_TLS_KEY_TYPE",
-				Value: "RSA",
+				Value: options.keyType,
 			},
 			{
 				Name:  "CRYOSTAT_AGENT_WEBSERVER_TLS_KEY_ALIAS",
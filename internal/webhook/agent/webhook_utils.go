@@ -23,6 +23,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controller/common"
 	"github.com/cryostatio/cryostat-operator/internal/controller/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controller/model"
@@ -39,6 +40,15 @@ func cryostatURL(cr *model.CryostatInstance, tls bool) string {
 		getAgentGatewayHTTPPort(cr))
 }
 
+func agentKeyType(cr *model.CryostatInstance) string {
+	// Java key algorithm name for the private key of the agent's certificate
+	opts := common.TLSPrivateKeyOptions(cr)
+	if opts != nil && opts.Algorithm == operatorv1beta2.TLSPrivateKeyAlgorithmECDSA {
+		return "EC"
+	}
+	return "RSA"
+}
+
 func getAgentGatewayHTTPPort(cr *model.CryostatInstance) int32 {
 	port := constants.AgentProxyContainerPort
 	if cr.Spec.ServiceOptions != nil && cr.Spec.ServiceOptions.AgentGatewayConfig != nil &&
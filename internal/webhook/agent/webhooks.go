@@ -44,6 +44,7 @@ type AgentWebhook interface {
 type AgentWebhookConfig struct {
 	InitImageTag *string
 	FIPSEnabled  bool
+	IsOpenShift  bool
 	common.OSUtils
 }
 
@@ -81,6 +82,7 @@ func (r *agentWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 	webhook := admission.WithCustomDefaulter(mgr.GetScheme(), &corev1.Pod{}, &podMutator{
 		client: mgr.GetClient(),
+		cache:  mgr.GetCache(),
 		config: r.AgentWebhookConfig,
 		log:    &podWebhookLog,
 		gvk:    &gvk,
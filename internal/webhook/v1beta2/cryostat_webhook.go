@@ -28,15 +28,19 @@ var cryostatlog = logf.Log.WithName("cryostat-resource")
 // +kubebuilder:webhook:path=/mutate-operator-cryostat-io-v1beta2-cryostat,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.cryostat.io,resources=cryostats,verbs=create;update,versions=v1beta2,name=mcryostat.kb.io,admissionReviewVersions=v1
 // +kubebuilder:webhook:path=/validate-operator-cryostat-io-v1beta2-cryostat,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.cryostat.io,resources=cryostats,verbs=create;update,versions=v1beta2,name=vcryostat.kb.io,admissionReviewVersions=v1
 
-func SetupWebhookWithManager(mgr ctrl.Manager, apiType runtime.Object) error {
+// CryostatWebhookConfig contains the platform details used to validate Cryostats
+type CryostatWebhookConfig struct {
+	IsOpenShift bool
+}
+
+func SetupWebhookWithManager(mgr ctrl.Manager, apiType runtime.Object, config *CryostatWebhookConfig) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(apiType).
 		WithValidator(&cryostatValidator{
 			client: mgr.GetClient(),
+			config: config,
 			log:    &cryostatlog,
 		}).
-		WithDefaulter(&cryostatDefaulter{
-			log: &cryostatlog,
-		}).
+		WithDefaulter(NewCryostatDefaulter(&cryostatlog)).
 		Complete()
 }
@@ -30,6 +30,14 @@ type cryostatDefaulter struct {
 
 var _ admission.CustomDefaulter = &cryostatDefaulter{}
 
+// NewCryostatDefaulter returns a defaulter that applies the same defaults
+// as the mutating webhook, for use outside of the webhook server
+func NewCryostatDefaulter(log *logr.Logger) admission.CustomDefaulter {
+	return &cryostatDefaulter{
+		log: log,
+	}
+}
+
 // Default applies default values to a Cryostat
 func (r *cryostatDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	cr, ok := obj.(*operatorv1beta2.Cryostat)
@@ -38,7 +46,7 @@ func (r *cryostatDefaulter) Default(ctx context.Context, obj runtime.Object) err
 	}
 	r.log.Info("defaulting Cryostat", "name", cr.Name, "namespace", cr.Namespace)
 
-	if cr.Spec.TargetNamespaces == nil {
+	if cr.Spec.TargetNamespaces == nil && cr.Spec.TargetNamespaceSelector == nil && !cr.Spec.AllNamespaces {
 		r.log.Info("defaulting target namespaces", "name", cr.Name, "namespace", cr.Namespace)
 		cr.Spec.TargetNamespaces = []string{cr.Namespace}
 	}
@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -107,6 +108,34 @@ var _ = Describe("CryostatDefaulter", func() {
 		})
 	})
 
+	Context("with target namespace selector", func() {
+		BeforeEach(func() {
+			cr := t.NewCryostat()
+			cr.Spec.TargetNamespaceSelector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"cryostat": "enabled"},
+			}
+			t.objs = append(t.objs, cr.Object)
+		})
+
+		It("should not set default target namespace", func() {
+			result := t.getCryostatInstance()
+			Expect(result.TargetNamespaces).To(BeEmpty())
+		})
+	})
+
+	Context("with all namespaces enabled", func() {
+		BeforeEach(func() {
+			cr := t.NewCryostat()
+			cr.Spec.AllNamespaces = true
+			t.objs = append(t.objs, cr.Object)
+		})
+
+		It("should not set default target namespace", func() {
+			result := t.getCryostatInstance()
+			Expect(result.TargetNamespaces).To(BeEmpty())
+		})
+	})
+
 	Context("without audit setting", func() {
 		BeforeEach(func() {
 			t.objs = append(t.objs, t.NewCryostat().Object)
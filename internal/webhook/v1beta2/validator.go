@@ -17,18 +17,26 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"slices"
 
 	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	resources "github.com/cryostatio/cryostat-operator/internal/controller/common/resource_definitions"
 	"github.com/go-logr/logr"
 	authnv1 "k8s.io/api/authentication/v1"
 	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 type cryostatValidator struct {
 	client client.Client
+	config *CryostatWebhookConfig
 	log    *logr.Logger
 }
 
@@ -63,6 +71,9 @@ func NewErrNotPermitted(operation string, namespace string) *ErrNotPermitted {
 }
 
 func (e *ErrNotPermitted) Error() string {
+	if len(e.namespace) == 0 {
+		return fmt.Sprintf("unable to %s Cryostat: user is not permitted to create a Cryostat in all namespaces", e.operation)
+	}
 	return fmt.Sprintf("unable to %s Cryostat: user is not permitted to create a Cryostat in namespace %s", e.operation, e.namespace)
 }
 
@@ -75,6 +86,14 @@ func (r *cryostatValidator) validate(ctx context.Context, obj runtime.Object, op
 	}
 	r.log.Info(fmt.Sprintf("validate %s", op), "name", cr.Name, "namespace", cr.Namespace)
 
+	errs := validatePodTemplateOverrides(cr)
+	errs = append(errs, validateAuthorizationOptions(cr, r.config.IsOpenShift)...)
+	errs = append(errs, validateTargetNamespaceSelector(cr)...)
+	errs = append(errs, validateDatabaseMajorVersion(cr)...)
+	if len(errs) > 0 {
+		return nil, kerrors.NewInvalid(operatorv1beta2.GroupVersion.WithKind("Cryostat").GroupKind(), cr.Name, errs)
+	}
+
 	// Look up the user who made this request
 	req, err := admission.RequestFromContext(ctx)
 	if err != nil {
@@ -84,7 +103,13 @@ func (r *cryostatValidator) validate(ctx context.Context, obj runtime.Object, op
 
 	// Check that for each target namespace, the user has permissions
 	// to create a Cryostat CR in that namespace
-	for _, namespace := range cr.Spec.TargetNamespaces {
+	namespaces := cr.Spec.TargetNamespaces
+	if cr.Spec.AllNamespaces || cr.Spec.TargetNamespaceSelector != nil {
+		// The CR may target any namespace, so the user must have
+		// permission to create a Cryostat CR in all namespaces
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	for _, namespace := range namespaces {
 		sar := &authzv1.SubjectAccessReview{
 			Spec: authzv1.SubjectAccessReviewSpec{
 				User:   userInfo.Username,
@@ -114,6 +139,174 @@ func (r *cryostatValidator) validate(ctx context.Context, obj runtime.Object, op
 	return nil, nil
 }
 
+// validatePodTemplateOverrides checks that the pod template overrides do not replace
+// or conflict with containers, ports and volumes owned by the operator
+func validatePodTemplateOverrides(cr *operatorv1beta2.Cryostat) field.ErrorList {
+	errs := field.ErrorList{}
+	if cr.Spec.PodTemplateOverrides == nil {
+		return errs
+	}
+	basePath := field.NewPath("spec", "podTemplateOverrides")
+	targets, err := resources.NewPodTemplateOverridesTargets(cr.Name, cr.Spec.PodTemplateOverrides,
+		cr.Spec.DeclarativeCredentials)
+	if err != nil {
+		return append(errs, field.InternalError(basePath, err))
+	}
+	for _, target := range targets {
+		if target.Overrides == nil {
+			continue
+		}
+		path := basePath.Child(target.Section)
+		for i, container := range target.Overrides.Containers {
+			containerPath := path.Child("containers").Index(i)
+			switch {
+			case container.Name == target.ContainerName:
+				// Merged with the operator-owned container, whose ports are managed by the operator
+				if len(container.Ports) > 0 {
+					errs = append(errs, field.Forbidden(containerPath.Child("ports"),
+						fmt.Sprintf("ports of container %s are managed by the operator", container.Name)))
+				}
+			case operatorContainerSection(targets, container.Name) != "":
+				errs = append(errs, field.Invalid(containerPath.Child("name"), container.Name,
+					fmt.Sprintf("container name is reserved by the operator, use spec.podTemplateOverrides.%s to override it",
+						operatorContainerSection(targets, container.Name))))
+			default:
+				errs = append(errs, validateSidecarPorts(containerPath, container, target.PodPorts)...)
+			}
+		}
+		for i, container := range target.Overrides.InitContainers {
+			if operatorContainerSection(targets, container.Name) != "" {
+				errs = append(errs, field.Invalid(path.Child("initContainers").Index(i).Child("name"), container.Name,
+					"container name is reserved by the operator"))
+			}
+		}
+		for i, volume := range target.Overrides.Volumes {
+			if target.IsReservedVolumeName(volume.Name) {
+				errs = append(errs, field.Invalid(path.Child("volumes").Index(i).Child("name"), volume.Name,
+					"volume name is reserved by the operator"))
+			}
+		}
+	}
+	return errs
+}
+
+// validateAuthorizationOptions checks that the OpenID Connect provider and roles, if configured,
+// can be used by the auth proxy on this platform
+func validateAuthorizationOptions(cr *operatorv1beta2.Cryostat, openShift bool) field.ErrorList {
+	errs := field.ErrorList{}
+	if cr.Spec.AuthorizationOptions == nil {
+		return errs
+	}
+	basePath := field.NewPath("spec", "authorizationOptions")
+	if len(cr.Spec.AuthorizationOptions.Roles) > 0 {
+		if openShift {
+			// The OpenShift OAuth proxy does not forward the groups of the user
+			errs = append(errs, field.Forbidden(basePath.Child("roles"), "roles are not supported on OpenShift"))
+		} else if cr.Spec.AuthorizationOptions.OIDC == nil {
+			// The groups of users come from the ID tokens of the provider. Users signing in with
+			// basic authentication have no groups, so none of their requests would be allowed.
+			errs = append(errs, field.Forbidden(basePath.Child("roles"),
+				"roles require an OpenID Connect provider"))
+		}
+	}
+	if cr.Spec.AuthorizationOptions.OIDC == nil {
+		return errs
+	}
+	oidc := cr.Spec.AuthorizationOptions.OIDC
+	path := basePath.Child("oidc")
+	// The OpenShift OAuth proxy only authenticates users with OpenShift
+	if openShift {
+		return append(errs, field.Forbidden(path, "OpenID Connect is not supported on OpenShift"))
+	}
+	// The provider redirects users back to the host of the Cryostat ingress after signing in
+	if !hasIngressHost(cr) {
+		errs = append(errs, field.Required(field.NewPath("spec", "networkOptions", "coreConfig", "ingressSpec"),
+			"an ingress with a host is required for OpenID Connect"))
+	}
+
+	issuer, err := url.Parse(oidc.IssuerURL)
+	if err != nil || (issuer.Scheme != "https" && issuer.Scheme != "http") || len(issuer.Host) == 0 {
+		errs = append(errs, field.Invalid(path.Child("issuerURL"), oidc.IssuerURL,
+			"issuer URL must be an absolute http or https URL"))
+	}
+	if len(oidc.ClientSecret.Name) == 0 {
+		errs = append(errs, field.Required(path.Child("clientSecret", "name"),
+			"name of the secret containing the client secret must be specified"))
+	}
+	if len(oidc.Scopes) > 0 && !slices.Contains(oidc.Scopes, "openid") {
+		errs = append(errs, field.Invalid(path.Child("scopes"), oidc.Scopes,
+			`scopes must include "openid"`))
+	}
+	// Otherwise, every user of the provider would be allowed
+	if len(oidc.AllowedGroups) == 0 && len(oidc.AllowedEmails) == 0 && len(oidc.AllowedEmailDomains) == 0 {
+		errs = append(errs, field.Required(path.Child("allowedEmailDomains"),
+			`at least one of allowedGroups, allowedEmails or allowedEmailDomains must be specified, use "*" to allow any email domain`))
+	}
+	return errs
+}
+
+func hasIngressHost(cr *operatorv1beta2.Cryostat) bool {
+	if cr.Spec.NetworkOptions == nil || cr.Spec.NetworkOptions.CoreConfig == nil ||
+		cr.Spec.NetworkOptions.CoreConfig.IngressSpec == nil {
+		return false
+	}
+	rules := cr.Spec.NetworkOptions.CoreConfig.IngressSpec.Rules
+	return len(rules) > 0 && len(rules[0].Host) > 0
+}
+
+// validateTargetNamespaceSelector checks that the target namespace selector can be
+// converted into a selector, so it can later be used to list namespaces
+func validateTargetNamespaceSelector(cr *operatorv1beta2.Cryostat) field.ErrorList {
+	errs := field.ErrorList{}
+	if cr.Spec.TargetNamespaceSelector == nil {
+		return errs
+	}
+	_, err := metav1.LabelSelectorAsSelector(cr.Spec.TargetNamespaceSelector)
+	if err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "targetNamespaceSelector"),
+			cr.Spec.TargetNamespaceSelector, err.Error()))
+	}
+	return errs
+}
+
+// validateDatabaseMajorVersion checks that the PostgreSQL major version is specified when the
+// managed database image is overridden, since the operator cannot otherwise tell when the
+// existing data must be upgraded for the image
+func validateDatabaseMajorVersion(cr *operatorv1beta2.Cryostat) field.ErrorList {
+	errs := field.ErrorList{}
+	if cr.Spec.Images == nil || cr.Spec.Images.Database == nil || len(cr.Spec.Images.Database.Image) == 0 {
+		return errs
+	}
+	options := cr.Spec.DatabaseOptions
+	if options != nil && (options.External != nil || options.MajorVersion != nil) {
+		return errs
+	}
+	return append(errs, field.Required(field.NewPath("spec", "databaseOptions", "majorVersion"),
+		"the PostgreSQL major version is required when overriding the database image"))
+}
+
+func validateSidecarPorts(path *field.Path, container corev1.Container, reserved []int32) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, port := range container.Ports {
+		if slices.Contains(reserved, port.ContainerPort) {
+			errs = append(errs, field.Invalid(path.Child("ports").Index(i).Child("containerPort"), port.ContainerPort,
+				"port is already used by a container managed by the operator"))
+		}
+	}
+	return errs
+}
+
+// operatorContainerSection returns the pod template overrides section for the
+// operator-owned container with the given name, or an empty string if there is none
+func operatorContainerSection(targets []resources.PodTemplateOverridesTarget, name string) string {
+	for _, target := range targets {
+		if target.ContainerName == name {
+			return target.Section
+		}
+	}
+	return ""
+}
+
 func translateExtra(extra map[string]authnv1.ExtraValue) map[string]authzv1.ExtraValue {
 	var result map[string]authzv1.ExtraValue
 	if extra == nil {
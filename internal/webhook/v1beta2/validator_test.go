@@ -28,6 +28,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -126,6 +127,45 @@ var _ = Describe("CryostatValidator", func() {
 			})
 		})
 
+		Context("creates a Cryostat with a target namespace selector", func() {
+			BeforeEach(func() {
+				cr.Spec.TargetNamespaceSelector = &metav1.LabelSelector{
+					MatchLabels: map[string]string{"cryostat": "enabled"},
+				}
+			})
+
+			It("should allow the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		Context("creates a Cryostat with an invalid target namespace selector", func() {
+			BeforeEach(func() {
+				cr.Spec.TargetNamespaceSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "cryostat", Operator: metav1.LabelSelectorOpIn},
+					},
+				}
+			})
+
+			It("should deny the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.targetNamespaceSelector")
+			})
+		})
+
+		Context("creates a Cryostat with all namespaces enabled", func() {
+			BeforeEach(func() {
+				cr.Spec.AllNamespaces = true
+			})
+
+			It("should allow the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
 		Context("creates a Cryostat with invalid trusted certificate entries", func() {
 			BeforeEach(func() {
 				cr.Spec.TrustedCertSecrets = []operatorv1beta2.CertificateSecret{
@@ -154,6 +194,464 @@ var _ = Describe("CryostatValidator", func() {
 				expectErrInvalidTrustedCertEntry(err)
 			})
 		})
+
+		Context("creates a Cryostat with pod template overrides", func() {
+			BeforeEach(func() {
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					Core: &operatorv1beta2.PodTemplateOverrides{
+						Containers: []corev1.Container{
+							{
+								Name: cr.Name,
+								Env:  []corev1.EnvVar{{Name: "JAVA_OPTS_APPEND", Value: "-XX:+UseG1GC"}},
+							},
+							{
+								Name:  "log-shipper",
+								Image: "example.com/log-shipper:latest",
+								Ports: []corev1.ContainerPort{{ContainerPort: 9000}},
+							},
+						},
+					},
+					Grafana: &operatorv1beta2.PodTemplateOverrides{
+						Containers: []corev1.Container{
+							{Name: cr.Name + "-grafana"},
+						},
+					},
+				}
+			})
+
+			It("should allow the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		Context("creates a Cryostat overriding the ports of an operator container", func() {
+			BeforeEach(func() {
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					Storage: &operatorv1beta2.PodTemplateOverrides{
+						Containers: []corev1.Container{
+							{
+								Name:  cr.Name + "-storage",
+								Ports: []corev1.ContainerPort{{ContainerPort: 9333}},
+							},
+						},
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.podTemplateOverrides.storage.containers[0].ports")
+			})
+		})
+
+		Context("creates a Cryostat overriding an operator container from another section", func() {
+			BeforeEach(func() {
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					Core: &operatorv1beta2.PodTemplateOverrides{
+						Containers: []corev1.Container{
+							{Name: cr.Name + "-auth-proxy"},
+						},
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.podTemplateOverrides.core.containers[0].name")
+			})
+		})
+
+		Context("creates a Cryostat with a sidecar using an operator port", func() {
+			BeforeEach(func() {
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					Database: &operatorv1beta2.PodTemplateOverrides{
+						Containers: []corev1.Container{
+							{
+								Name:  "exporter",
+								Image: "example.com/exporter:latest",
+								Ports: []corev1.ContainerPort{{ContainerPort: 5432}},
+							},
+						},
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.podTemplateOverrides.database.containers[0].ports[0].containerPort")
+			})
+		})
+
+		Context("creates a Cryostat with a sidecar using the authorization port", func() {
+			BeforeEach(func() {
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					Core: &operatorv1beta2.PodTemplateOverrides{
+						Containers: []corev1.Container{
+							{
+								Name:  "log-shipper",
+								Image: "example.com/log-shipper:latest",
+								Ports: []corev1.ContainerPort{{ContainerPort: 8283}},
+							},
+						},
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.podTemplateOverrides.core.containers[0].ports[0].containerPort")
+			})
+		})
+
+		Context("creates a Cryostat with an init container using an operator container name", func() {
+			BeforeEach(func() {
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					Reports: &operatorv1beta2.PodTemplateOverrides{
+						InitContainers: []corev1.Container{
+							{
+								Name:  cr.Name + "-reports",
+								Image: "example.com/init:latest",
+							},
+						},
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.podTemplateOverrides.reports.initContainers[0].name")
+			})
+		})
+
+		Context("creates a Cryostat with a volume using an operator volume name", func() {
+			BeforeEach(func() {
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					AgentProxy: &operatorv1beta2.PodTemplateOverrides{
+						Volumes: []corev1.Volume{
+							{
+								Name: "cert-secrets",
+								VolumeSource: corev1.VolumeSource{
+									EmptyDir: &corev1.EmptyDirVolumeSource{},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.podTemplateOverrides.agentProxy.volumes[0].name")
+			})
+		})
+
+		Context("creates a Cryostat with a volume using the name of a disabled operator volume", func() {
+			BeforeEach(func() {
+				// Added by the operator once an OpenID Connect provider with a CA is configured
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					AuthProxy: &operatorv1beta2.PodTemplateOverrides{
+						Volumes: []corev1.Volume{
+							{
+								Name: "oidc-ca",
+								VolumeSource: corev1.VolumeSource{
+									EmptyDir: &corev1.EmptyDirVolumeSource{},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.podTemplateOverrides.authProxy.volumes[0].name")
+			})
+		})
+
+		Context("creates a Cryostat with a volume using an event template volume name", func() {
+			BeforeEach(func() {
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					Core: &operatorv1beta2.PodTemplateOverrides{
+						Volumes: []corev1.Volume{
+							{
+								Name: "template-custom",
+								VolumeSource: corev1.VolumeSource{
+									EmptyDir: &corev1.EmptyDirVolumeSource{},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.podTemplateOverrides.core.volumes[0].name")
+			})
+		})
+
+		Context("creates a Cryostat with a volume using the database's volume name", func() {
+			BeforeEach(func() {
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					Database: &operatorv1beta2.PodTemplateOverrides{
+						Volumes: []corev1.Volume{
+							{
+								Name: cr.Name + "-database",
+								VolumeSource: corev1.VolumeSource{
+									EmptyDir: &corev1.EmptyDirVolumeSource{},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.podTemplateOverrides.database.volumes[0].name")
+			})
+		})
+
+		Context("creates a Cryostat with a volume using a declarative credential's volume name", func() {
+			BeforeEach(func() {
+				cr.Spec.DeclarativeCredentials = []operatorv1beta2.DeclarativeCredential{
+					{
+						SecretName: "my-credentials",
+					},
+				}
+				cr.Spec.PodTemplateOverrides = &operatorv1beta2.PodTemplateOverridesList{
+					Core: &operatorv1beta2.PodTemplateOverrides{
+						Volumes: []corev1.Volume{
+							{
+								Name: "my-credentials",
+								VolumeSource: corev1.VolumeSource{
+									EmptyDir: &corev1.EmptyDirVolumeSource{},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.podTemplateOverrides.core.volumes[0].name")
+			})
+		})
+
+		Context("creates a Cryostat with an OpenID Connect provider", func() {
+			BeforeEach(func() {
+				cr = t.NewCryostatWithIngress()
+				cr.Spec.AuthorizationOptions = &operatorv1beta2.AuthorizationOptions{
+					OIDC: newOIDCConfig(),
+				}
+			})
+
+			It("should allow the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		Context("creates a Cryostat with an invalid OpenID Connect issuer URL", func() {
+			BeforeEach(func() {
+				cr = t.NewCryostatWithIngress()
+				oidc := newOIDCConfig()
+				oidc.IssuerURL = "keycloak.example.com/realms/cryostat"
+				cr.Spec.AuthorizationOptions = &operatorv1beta2.AuthorizationOptions{
+					OIDC: oidc,
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.authorizationOptions.oidc.issuerURL")
+			})
+		})
+
+		Context("creates a Cryostat with OpenID Connect scopes missing openid", func() {
+			BeforeEach(func() {
+				cr = t.NewCryostatWithIngress()
+				oidc := newOIDCConfig()
+				oidc.Scopes = []string{"email", "profile"}
+				cr.Spec.AuthorizationOptions = &operatorv1beta2.AuthorizationOptions{
+					OIDC: oidc,
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.authorizationOptions.oidc.scopes")
+			})
+		})
+
+		Context("creates a Cryostat with an OpenID Connect client secret without a name", func() {
+			BeforeEach(func() {
+				cr = t.NewCryostatWithIngress()
+				oidc := newOIDCConfig()
+				oidc.ClientSecret.Name = ""
+				cr.Spec.AuthorizationOptions = &operatorv1beta2.AuthorizationOptions{
+					OIDC: oidc,
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.authorizationOptions.oidc.clientSecret.name")
+			})
+		})
+
+		Context("creates a Cryostat with an OpenID Connect provider that allows any user", func() {
+			BeforeEach(func() {
+				cr = t.NewCryostatWithIngress()
+				oidc := newOIDCConfig()
+				oidc.AllowedEmailDomains = nil
+				cr.Spec.AuthorizationOptions = &operatorv1beta2.AuthorizationOptions{
+					OIDC: oidc,
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.authorizationOptions.oidc.allowedEmailDomains")
+			})
+
+			Context("explicitly", func() {
+				BeforeEach(func() {
+					cr.Spec.AuthorizationOptions.OIDC.AllowedEmailDomains = []string{"*"}
+				})
+
+				It("should allow the request", func() {
+					err := t.client.Create(ctx, cr.Object)
+					Expect(err).ToNot(HaveOccurred())
+				})
+			})
+		})
+
+		Context("creates a Cryostat with an OpenID Connect provider without an ingress", func() {
+			BeforeEach(func() {
+				cr.Spec.AuthorizationOptions = &operatorv1beta2.AuthorizationOptions{
+					OIDC: newOIDCConfig(),
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.networkOptions.coreConfig.ingressSpec")
+			})
+		})
+
+		Context("creates a Cryostat with an OpenID Connect provider on OpenShift", func() {
+			BeforeEach(func() {
+				cr = t.NewCryostatWithIngress()
+				cr.Spec.AuthorizationOptions = &operatorv1beta2.AuthorizationOptions{
+					OIDC: newOIDCConfig(),
+				}
+				webhookConfig.IsOpenShift = true
+			})
+
+			AfterEach(func() {
+				webhookConfig.IsOpenShift = false
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.authorizationOptions.oidc")
+				Expect(err.Error()).To(ContainSubstring("not supported on OpenShift"))
+			})
+		})
+
+		Context("creates a Cryostat with roles", func() {
+			BeforeEach(func() {
+				cr = t.NewCryostatWithIngress()
+				cr.Spec.AuthorizationOptions = &operatorv1beta2.AuthorizationOptions{
+					OIDC: newOIDCConfig(),
+					Roles: []operatorv1beta2.AccessRole{
+						{
+							Name:   "admin",
+							Groups: []string{"cryostat-admins"},
+						},
+					},
+				}
+			})
+
+			It("should allow the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Context("without authentication", func() {
+				BeforeEach(func() {
+					cr.Spec.AuthorizationOptions.OIDC = nil
+				})
+
+				It("should reject the request", func() {
+					err := t.client.Create(ctx, cr.Object)
+					expectErrInvalidField(err, "spec.authorizationOptions.roles")
+					Expect(err.Error()).To(ContainSubstring("require an OpenID Connect provider"))
+				})
+			})
+
+			Context("with only basic authentication", func() {
+				BeforeEach(func() {
+					cr.Spec.AuthorizationOptions.OIDC = nil
+					cr.Spec.AuthorizationOptions.BasicAuth = &operatorv1beta2.SecretFile{
+						SecretName: &[]string{"cryostat-htpasswd"}[0],
+						Filename:   &[]string{"users"}[0],
+					}
+				})
+
+				It("should reject the request", func() {
+					err := t.client.Create(ctx, cr.Object)
+					expectErrInvalidField(err, "spec.authorizationOptions.roles")
+					Expect(err.Error()).To(ContainSubstring("require an OpenID Connect provider"))
+				})
+			})
+
+			Context("on OpenShift", func() {
+				BeforeEach(func() {
+					webhookConfig.IsOpenShift = true
+				})
+
+				AfterEach(func() {
+					webhookConfig.IsOpenShift = false
+				})
+
+				It("should reject the request", func() {
+					err := t.client.Create(ctx, cr.Object)
+					expectErrInvalidField(err, "spec.authorizationOptions.roles")
+				})
+			})
+		})
+
+		Context("creates a Cryostat with a database image", func() {
+			BeforeEach(func() {
+				cr.Spec.Images = &operatorv1beta2.ImageConfigList{
+					Database: &operatorv1beta2.ImageConfig{
+						Image: "my/database-image:17",
+					},
+				}
+			})
+
+			It("should reject the request", func() {
+				err := t.client.Create(ctx, cr.Object)
+				expectErrInvalidField(err, "spec.databaseOptions.majorVersion")
+			})
+
+			Context("with its major version", func() {
+				BeforeEach(func() {
+					cr.Spec.DatabaseOptions = &operatorv1beta2.DatabaseOptions{
+						MajorVersion: &[]string{"17"}[0],
+					}
+				})
+
+				It("should allow the request", func() {
+					err := t.client.Create(ctx, cr.Object)
+					Expect(err).ToNot(HaveOccurred())
+				})
+			})
+		})
 	})
 
 	Context("unauthorized user", func() {
@@ -201,6 +699,38 @@ var _ = Describe("CryostatValidator", func() {
 			})
 		})
 
+		Context("creates a Cryostat with a target namespace selector", func() {
+			BeforeEach(func() {
+				// User may create a Cryostat in the install namespace,
+				// but not in all namespaces
+				cr.Spec.TargetNamespaces = []string{t.Namespace}
+				cr.Spec.TargetNamespaceSelector = &metav1.LabelSelector{
+					MatchLabels: map[string]string{"cryostat": "enabled"},
+				}
+			})
+
+			It("should deny the request", func() {
+				err := saClient.Create(ctx, cr.Object)
+				Expect(err).To((HaveOccurred()))
+				expectErrNotPermitted(err, "create", metav1.NamespaceAll)
+			})
+		})
+
+		Context("creates a Cryostat with all namespaces enabled", func() {
+			BeforeEach(func() {
+				// User may create a Cryostat in the install namespace,
+				// but not in all namespaces
+				cr.Spec.TargetNamespaces = []string{t.Namespace}
+				cr.Spec.AllNamespaces = true
+			})
+
+			It("should deny the request", func() {
+				err := saClient.Create(ctx, cr.Object)
+				Expect(err).To((HaveOccurred()))
+				expectErrNotPermitted(err, "create", metav1.NamespaceAll)
+			})
+		})
+
 		Context("deletes a Cryostat", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, cr.Object)
@@ -226,3 +756,22 @@ func expectErrInvalidTrustedCertEntry(actual error) {
 	Expect(actual.Error()).To(ContainSubstring("spec.trustedCertSecrets[0]"))
 	Expect(actual.Error()).To(ContainSubstring("exactly one of secretName or configMapName must be specified"))
 }
+
+func expectErrInvalidField(actual error, path string) {
+	Expect(kerrors.IsInvalid(actual)).To(BeTrue(), "expected Invalid API error")
+	Expect(actual.Error()).To(ContainSubstring(path))
+}
+
+func newOIDCConfig() *operatorv1beta2.OIDCConfig {
+	return &operatorv1beta2.OIDCConfig{
+		IssuerURL: "https://keycloak.example.com/realms/cryostat",
+		ClientID:  "cryostat",
+		ClientSecret: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: "cryostat-oidc",
+			},
+			Key: "client-secret",
+		},
+		AllowedEmailDomains: []string{"example.com"},
+	}
+}
@@ -52,6 +52,7 @@ var testEnv *envtest.Environment
 var ctx context.Context
 var cancel context.CancelFunc
 var k8sScheme *runtime.Scheme
+var webhookConfig *webhook.CryostatWebhookConfig
 
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
@@ -108,7 +109,8 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = webhook.SetupWebhookWithManager(mgr, &operatorv1beta2.Cryostat{})
+	webhookConfig = &webhook.CryostatWebhookConfig{}
+	err = webhook.SetupWebhookWithManager(mgr, &operatorv1beta2.Cryostat{}, webhookConfig)
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:webhook